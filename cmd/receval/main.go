@@ -0,0 +1,116 @@
+// Command receval compares the recommender algorithms offline.
+//
+// It loads review data from the database configured in .env, a JSON fixture, or generates a synthetic dataset
+// into an in-memory SQLite database, splits it into training and test data, and reports precision@k, recall@k,
+// NDCG@k, catalog coverage and intra-list diversity for each algorithm:
+//
+//	go run ./cmd/receval -source synthetic -split loo -k 10 -algorithms user,item,content,mf,user+content
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/anirudhgray/mood-harbour-backend/config"
+	"github.com/anirudhgray/mood-harbour-backend/infra/database"
+	"github.com/anirudhgray/mood-harbour-backend/infra/logger"
+	"github.com/anirudhgray/mood-harbour-backend/utils/recommender"
+	"github.com/anirudhgray/mood-harbour-backend/utils/recommender/evaluation"
+)
+
+func main() {
+	source := flag.String("source", "synthetic", "where to load review data from: synthetic, json or db")
+	fixture := flag.String("fixture", "", "JSON fixture to load, with -source json")
+	writeFixture := flag.String("write-fixture", "", "write the loaded dataset to this JSON fixture")
+	splitStrategy := flag.String("split", evaluation.LeaveOneOutSplit, "how to split the data: loo (leave one out) or time")
+	testFraction := flag.Float64("test-fraction", 0.2, "fraction of the newest reviews held out, with -split time")
+	k := flag.Int("k", 10, "number of recommendations per user")
+	algorithms := flag.String("algorithms", strings.Join([]string{
+		recommender.UserBasedAlgorithm,
+		recommender.ItemBasedAlgorithm,
+		recommender.ContentBasedAlgorithm,
+		recommender.PopularAlgorithm,
+		recommender.FactorizationAlgorithm,
+		recommender.DefaultAlgorithm,
+	}, ","), "comma separated recommender algorithms to evaluate")
+
+	hyperparameters := recommender.DefaultHyperparameters
+	flag.IntVar(&hyperparameters.Factors, "mf-factors", hyperparameters.Factors, "matrix factorization vector length")
+	flag.IntVar(&hyperparameters.Epochs, "mf-epochs", hyperparameters.Epochs, "matrix factorization training epochs")
+	flag.Float64Var(&hyperparameters.LearningRate, "mf-learning-rate", hyperparameters.LearningRate, "matrix factorization learning rate")
+	flag.Float64Var(&hyperparameters.Regularization, "mf-regularization", hyperparameters.Regularization, "matrix factorization L2 regularization")
+	flag.IntVar(&hyperparameters.NegativeSamples, "mf-negative-samples", hyperparameters.NegativeSamples, "matrix factorization negative samples per interaction")
+
+	generatorConfig := evaluation.DefaultGeneratorConfig
+	flag.IntVar(&generatorConfig.Users, "users", generatorConfig.Users, "synthetic users")
+	flag.IntVar(&generatorConfig.Resources, "resources", generatorConfig.Resources, "synthetic resources")
+	flag.IntVar(&generatorConfig.ReviewsPerUser, "reviews-per-user", generatorConfig.ReviewsPerUser, "average synthetic reviews per user")
+	flag.Float64Var(&generatorConfig.Noise, "noise", generatorConfig.Noise, "chance of a synthetic review going against the user's interests")
+	flag.Int64Var(&generatorConfig.Seed, "seed", generatorConfig.Seed, "synthetic data seed")
+	flag.Parse()
+
+	dataset, err := loadDataset(*source, *fixture, generatorConfig)
+	if err != nil {
+		logger.Fatalf("Failed to load %s dataset: %v", *source, err)
+	}
+
+	if *writeFixture != "" {
+		if err := evaluation.SaveJSON(dataset, *writeFixture); err != nil {
+			logger.Fatalf("Failed to write fixture: %v", err)
+		}
+	}
+
+	split, err := evaluation.NewSplit(*splitStrategy, dataset, *testFraction)
+	if err != nil {
+		logger.Fatalf("%v", err)
+	}
+
+	fmt.Printf("%d resources, %d reviews, %d bookmarks, %d test users (%s split)\n\n",
+		len(dataset.Resources), len(dataset.Reviews), len(dataset.Bookmarks), len(split.Test), *splitStrategy)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "algorithm\tusers\tprecision@%d\trecall@%d\tndcg@%d\tcoverage\tdiversity\n", *k, *k, *k)
+	for _, algorithm := range strings.Split(*algorithms, ",") {
+		report, err := evaluation.Evaluate(strings.TrimSpace(algorithm), split, *k, hyperparameters)
+		if err != nil {
+			logger.Fatalf("Failed to evaluate %s: %v", algorithm, err)
+		}
+		fmt.Fprintf(w, "%s\t%d\t%.4f\t%.4f\t%.4f\t%.4f\t%.4f\n",
+			report.Algorithm, report.Users, report.Precision, report.Recall, report.NDCG, report.Coverage, report.Diversity)
+	}
+	w.Flush()
+}
+
+func loadDataset(source, fixture string, generatorConfig evaluation.GeneratorConfig) (evaluation.Dataset, error) {
+	switch source {
+	case "synthetic":
+		// goes through an in-memory SQLite database, the same way the db source reads production data
+		db, err := evaluation.OpenMemoryDB()
+		if err != nil {
+			return evaluation.Dataset{}, err
+		}
+		if err := evaluation.SaveDB(db, evaluation.Generate(generatorConfig)); err != nil {
+			return evaluation.Dataset{}, err
+		}
+		return evaluation.LoadDB(db)
+	case "json":
+		if fixture == "" {
+			return evaluation.Dataset{}, fmt.Errorf("-fixture is required with -source json")
+		}
+		return evaluation.LoadJSON(fixture)
+	case "db":
+		if err := config.SetupConfig(); err != nil {
+			return evaluation.Dataset{}, err
+		}
+		masterDSN, replicaDSN := config.DbConfiguration()
+		if err := database.DbConnection(masterDSN, replicaDSN); err != nil {
+			return evaluation.Dataset{}, err
+		}
+		return evaluation.LoadDB(database.DB)
+	default:
+		return evaluation.Dataset{}, fmt.Errorf("unknown source %q", source)
+	}
+}
@@ -0,0 +1,59 @@
+package config
+
+import (
+	"github.com/anirudhgray/mood-harbour-backend/utils/recommender"
+	"github.com/spf13/viper"
+)
+
+// SetDefaults sets the defaults of settings which are read while handling requests and running jobs. Setting viper's
+// defaults isn't safe while other goroutines read them, so it's done once at startup rather than where they're read.
+func SetDefaults() {
+	// sessions
+	viper.SetDefault("ACCESS_TOKEN_MINUTE_LIFESPAN", 15)
+	viper.SetDefault("REFRESH_TOKEN_DAY_LIFESPAN", 30)
+
+	// emailed codes
+	viper.SetDefault("OTP_VERIFY_EMAIL_TTL_MINUTES", 1440)
+	viper.SetDefault("OTP_RESET_PASSWORD_TTL_MINUTES", 15)
+	viper.SetDefault("OTP_DELETE_ACCOUNT_TTL_MINUTES", 15)
+	viper.SetDefault("OTP_MAX_ATTEMPTS", 5)
+	viper.SetDefault("OTP_PURGE_INTERVAL_MINUTES", 60)
+
+	// failed login and code throttling
+	viper.SetDefault("THROTTLE_FREE_ATTEMPTS", 5)
+	viper.SetDefault("THROTTLE_BACKOFF_SECONDS", 2)
+	viper.SetDefault("THROTTLE_LOCKOUT_ATTEMPTS", 10)
+	viper.SetDefault("THROTTLE_LOCKOUT_MINUTES", 15)
+	viper.SetDefault("THROTTLE_WINDOW_HOURS", 24)
+	viper.SetDefault("THROTTLE_IP_MULTIPLIER", 4)
+
+	// two-factor authentication
+	viper.SetDefault("TOTP_ISSUER", "Mood Harbour")
+	viper.SetDefault("MFA_RATE_LIMIT", 10)
+
+	// passkeys
+	viper.SetDefault("WEBAUTHN_RP_ID", "localhost")
+	viper.SetDefault("WEBAUTHN_RP_NAME", "Mood Harbour")
+	viper.SetDefault("WEBAUTHN_ORIGINS", "http://localhost:3000")
+	viper.SetDefault("PASSKEY_LOGIN_RATE_LIMIT", 10)
+
+	// link checks
+	viper.SetDefault("LINK_CHECK_INTERVAL_MINUTES", 60)
+	viper.SetDefault("LINK_CHECK_RECHECK_HOURS", 24)
+	viper.SetDefault("LINK_CHECK_BATCH_SIZE", 100)
+	viper.SetDefault("LINK_CHECK_MAX_FAILURES", 3)
+
+	// recommendations
+	viper.SetDefault("RECOMMENDER_ALGORITHM", recommender.DefaultAlgorithm)
+	viper.SetDefault("RECOMMENDATION_FULL_INTERVAL_MINUTES", 360)
+	viper.SetDefault("RECOMMENDATION_STALE_INTERVAL_SECONDS", 60)
+	viper.SetDefault("RECOMMENDATION_STALE_BATCH_SIZE", 200)
+	viper.SetDefault("RECOMMENDATION_MF_TRAIN_INTERVAL_MINUTES", 1440)
+	viper.SetDefault("RECOMMENDATION_MOOD_WINDOW_HOURS", 24)
+	viper.SetDefault("RECOMMENDATION_MOOD_WEIGHT", 0.5)
+	viper.SetDefault("RECOMMENDATION_MOOD_TAG_BOOST", 0.2)
+	viper.SetDefault("RECOMMENDATION_FALLBACK_POPULAR", 0.7)
+	viper.SetDefault("RECOMMENDATION_FALLBACK_CURATED", 0.3)
+	viper.SetDefault("RECOMMENDATION_DIVERSITY_LAMBDA", 0.7)
+	viper.SetDefault("RECOMMENDATION_MF_KEEP_VERSIONS", 3)
+}
@@ -2,15 +2,17 @@ package controllers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/url"
+	"strconv"
 
 	"github.com/anirudhgray/mood-harbour-backend/config"
 	"github.com/anirudhgray/mood-harbour-backend/infra/logger"
 	"github.com/anirudhgray/mood-harbour-backend/models"
 	"github.com/anirudhgray/mood-harbour-backend/repository"
 	"github.com/anirudhgray/mood-harbour-backend/services"
-	"github.com/anirudhgray/mood-harbour-backend/utils/token"
+	"github.com/anirudhgray/mood-harbour-backend/utils/password"
 	"github.com/gin-gonic/gin"
 	"github.com/spf13/viper"
 	"golang.org/x/oauth2"
@@ -18,9 +20,6 @@ import (
 
 type UserController struct {
 	userRepo         *repository.UserRepository
-	forgotRepo       *repository.ForgotPasswordRepository
-	verifRepo        *repository.VerificationEntryRepository
-	deletionRepo     *repository.DeletionConfirmationRepository
 	passwordAuthRepo *repository.PasswordAuthRepository
 	authProviderRepo *repository.AuthProviderRepository
 
@@ -29,12 +28,9 @@ type UserController struct {
 
 func NewUserController(authService services.AuthServiceInterface) *UserController {
 	userRepo := repository.NewUserRepository()
-	forgotRepo := repository.NewForgotPasswordRepository()
-	verifRepo := repository.NewVerificationEntryRepository()
-	deletionRepo := repository.NewDeletionConfirmationRepository()
 	passwordAuthRepo := repository.NewPasswordAuthRepository()
 	authProviderRepo := repository.NewAuthProviderRepository()
-	return &UserController{userRepo, forgotRepo, verifRepo, deletionRepo, passwordAuthRepo, authProviderRepo, authService}
+	return &UserController{userRepo, passwordAuthRepo, authProviderRepo, authService}
 }
 
 // RegisterUser handles user registration.
@@ -52,15 +48,18 @@ func (uc *UserController) RegisterUser(c *gin.Context) {
 		return
 	}
 
-	createdUser, err := uc.authService.RegisterUser(registerData.Email, registerData.Name, registerData.ProfileImage, registerData.Password)
+	// the response is the same whether or not the email was already registered, whose owner is mailed instead
+	_, err := uc.authService.RegisterUser(registerData.Email, registerData.Name, registerData.ProfileImage, registerData.Password)
+	if weakPassword(c, err) {
+		return
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "creation-error", "message": err.Error()})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "creation-error", "message": "Failed to register, please try again."})
 		logger.Errorf("Failed to create user: %v", err)
 		return
 	}
 
-	c.JSON(http.StatusCreated, createdUser)
-	logger.Infof("New User Created.")
+	c.JSON(http.StatusAccepted, gin.H{"message": "Check your email to verify your account."})
 }
 
 // Login handles user login.
@@ -75,13 +74,95 @@ func (uc *UserController) Login(c *gin.Context) {
 		return
 	}
 
-	token, user, err := uc.authService.LoginUser(loginData.Email, loginData.Password)
+	result, err := uc.authService.LoginUser(loginData.Email, loginData.Password, clientInfo(c))
+	if throttled(c, err) {
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "credentials-error", "message": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"token": token, "user": user})
+	c.JSON(http.StatusOK, loginResponse(result))
+}
+
+// Refresh exchanges a refresh token for a new access token and refresh token.
+func (uc *UserController) Refresh(c *gin.Context) {
+	var refreshData struct {
+		RefreshToken string `json:"refresh_token" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&refreshData); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "message": "Improper JSON."})
+		return
+	}
+
+	tokens, err := uc.authService.RefreshTokens(refreshData.RefreshToken, clientInfo(c))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid-refresh-token", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, tokens)
+}
+
+// Logout ends the current session.
+func (uc *UserController) Logout(c *gin.Context) {
+	session, _ := c.Get("session")
+	currentSession := session.(*models.Session)
+
+	if err := uc.authService.RevokeSession(currentSession.UserID, currentSession.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "logout-error", "message": "Error while logging out."})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Logged out."})
+}
+
+// GetSessions lists the devices the user is logged in on.
+func (uc *UserController) GetSessions(c *gin.Context) {
+	session, _ := c.Get("session")
+	currentSession := session.(*models.Session)
+
+	sessions, err := uc.authService.GetSessions(currentSession.UserID, currentSession.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "session-fetch-error", "message": "Error while fetching sessions."})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"sessions": sessions})
+}
+
+// RevokeSession logs the user out of one of their devices.
+func (uc *UserController) RevokeSession(c *gin.Context) {
+	session, _ := c.Get("session")
+	currentSession := session.(*models.Session)
+
+	sessionID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid-id", "message": "Invalid session ID."})
+		return
+	}
+
+	if err := uc.authService.RevokeSession(currentSession.UserID, uint(sessionID)); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "session-not-found", "message": "No such active session."})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Session revoked."})
+}
+
+// RevokeAllSessions logs the user out of every device, including this one.
+func (uc *UserController) RevokeAllSessions(c *gin.Context) {
+	session, _ := c.Get("session")
+	currentSession := session.(*models.Session)
+
+	if err := uc.authService.RevokeAllSessions(currentSession.UserID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "logout-error", "message": "Error while logging out."})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Logged out everywhere."})
 }
 
 // RequestVerificationAgain handles request to resend verification email.
@@ -102,7 +183,10 @@ func (uc *UserController) VerifyEmail(c *gin.Context) {
 	email := c.Query("email")
 	otp := c.Query("otp")
 
-	err := uc.authService.VerifyEmail(email, otp)
+	err := uc.authService.VerifyEmail(email, otp, clientInfo(c))
+	if throttled(c, err) {
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
 		return
@@ -160,13 +244,7 @@ func (uc *UserController) GoogleCallback(c *gin.Context) {
 	var emptyProviderEntry models.AuthProvider
 	if authProvider != emptyProviderEntry { // i.e., found
 		user, _ := uc.userRepo.GetUserByID(authProvider.UserID)
-		jwt, err := token.GenerateToken(user)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to log in."})
-			return
-		}
-		c.Redirect(http.StatusSeeOther, viper.GetString("FRONTEND_SOCIAL_REDIRECT")+"?token="+jwt)
-		c.JSON(http.StatusOK, gin.H{"token": jwt, "user": user})
+		uc.redirectSocialLogin(c, user)
 		return
 	}
 
@@ -189,14 +267,72 @@ func (uc *UserController) GoogleCallback(c *gin.Context) {
 		return
 	}
 
-	jwt, err := token.GenerateToken(user)
+	uc.redirectSocialLogin(c, user)
+}
+
+// redirectSocialLogin redirects to the frontend with a code to exchange for the login with ExchangeSocialLoginCode.
+// Tokens aren't put in the URL themselves, from where they'd end up in logs, browser history and Referer headers.
+func (uc *UserController) redirectSocialLogin(c *gin.Context, user models.User) {
+	code, err := uc.authService.IssueSocialLoginCode(user)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to log in."})
 		return
 	}
+	c.Redirect(http.StatusSeeOther, viper.GetString("FRONTEND_SOCIAL_REDIRECT")+"?code="+url.QueryEscape(code))
+}
+
+// ExchangeSocialLoginCode completes a social login with the code the frontend was redirected with, responding like Login.
+func (uc *UserController) ExchangeSocialLoginCode(c *gin.Context) {
+	var exchangeData struct {
+		Code string `json:"code" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&exchangeData); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "message": "Improper JSON."})
+		return
+	}
 
-	c.Redirect(http.StatusSeeOther, viper.GetString("FRONTEND_SOCIAL_REDIRECT")+"?token="+jwt)
-	c.JSON(http.StatusOK, gin.H{"token": jwt, "user": user})
+	result, err := uc.authService.ExchangeSocialLoginCode(exchangeData.Code, clientInfo(c))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "social-login-error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, loginResponse(result))
+}
+
+// clientInfo describes the device making the request.
+func clientInfo(c *gin.Context) models.ClientInfo {
+	return models.ClientInfo{UserAgent: c.Request.UserAgent(), IP: c.ClientIP()}
+}
+
+// throttled responds with 429 Too Many Requests if the error is a services.ThrottledError.
+func throttled(c *gin.Context, err error) bool {
+	var throttledErr *services.ThrottledError
+	if !errors.As(err, &throttledErr) {
+		return false
+	}
+	c.Header("Retry-After", strconv.Itoa(throttledErr.RetrySeconds()))
+	c.JSON(http.StatusTooManyRequests, gin.H{"error": "too-many-attempts", "message": err.Error()})
+	return true
+}
+
+// weakPassword responds with 400 Bad Request, and every way the password doesn't meet the policy, if the error is a
+// password.PolicyError.
+func weakPassword(c *gin.Context, err error) bool {
+	var policyErr *password.PolicyError
+	if !errors.As(err, &policyErr) {
+		return false
+	}
+	c.JSON(http.StatusBadRequest, gin.H{"error": "weak-password", "message": err.Error(), "reasons": policyErr.Violations})
+	return true
+}
+
+// loginResponse is the response to a login: the tokens, or the MFA challenge to complete with MFALogin.
+func loginResponse(result models.LoginResult) gin.H {
+	if result.MFARequired {
+		return gin.H{"mfa_required": true, "mfa_token": result.MFAToken, "expires_at": result.MFAExpires}
+	}
+	return gin.H{"token": result.Tokens.AccessToken, "refresh_token": result.Tokens.RefreshToken, "expires_at": result.Tokens.ExpiresAt, "user": result.User}
 }
 
 // ForgotPasswordRequest handles forgot password requests by sending a mail with an OTP
@@ -225,7 +361,10 @@ func (uc *UserController) SetNewPassword(c *gin.Context) {
 	email := c.Query("email")
 	otp := c.Query("otp")
 
-	err := uc.authService.SetNewPassword(email, otp, forgotPasswordInput.NewPassword)
+	err := uc.authService.SetNewPassword(email, otp, forgotPasswordInput.NewPassword, clientInfo(c))
+	if throttled(c, err) || weakPassword(c, err) {
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
 		return
@@ -248,8 +387,13 @@ func (uc *UserController) ResetPassword(c *gin.Context) {
 
 	user, _ := c.Get("user")
 	currentUser := user.(*models.User)
+	session, _ := c.Get("session")
+	currentSession := session.(*models.Session)
 
-	err := uc.authService.ResetPassword(*currentUser, resetPasswordInput.OldPassword, resetPasswordInput.NewPassword)
+	err := uc.authService.ResetPassword(*currentUser, currentSession.ID, resetPasswordInput.OldPassword, resetPasswordInput.NewPassword)
+	if weakPassword(c, err) {
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
 		return
@@ -282,7 +426,10 @@ func (uc *UserController) DeleteAccount(c *gin.Context) {
 	email := c.Query("email")
 	otp := c.Query("otp")
 
-	err := uc.authService.DeleteAccount(email, otp)
+	err := uc.authService.DeleteAccount(email, otp, clientInfo(c))
+	if throttled(c, err) {
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
 		return
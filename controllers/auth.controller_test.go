@@ -10,6 +10,7 @@ import (
 
 	"github.com/anirudhgray/mood-harbour-backend/mocks"
 	"github.com/anirudhgray/mood-harbour-backend/models"
+	"github.com/anirudhgray/mood-harbour-backend/utils/password"
 	"github.com/gin-gonic/gin"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
@@ -72,16 +73,47 @@ func TestUserController_RegisterUser(t *testing.T) {
 	w := httptest.NewRecorder()
 	r.ServeHTTP(w, req)
 
-	// Assert the response
-	assert.Equal(t, http.StatusCreated, w.Code)
-	// You can also parse the response body to validate the result
-	var responseUser struct {
-		Email        string `json:"email"`
-		Name         string `json:"name"`
-		Password     string `json:"password"`
-		ProfileImage string `json:"profile_image"`
+	// Assert the response, which is the same whether or not the email was already registered
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	var response struct {
+		Message string `json:"message"`
+	}
+	err = json.NewDecoder(w.Body).Decode(&response)
+	assert.NoError(t, err)
+	assert.Equal(t, "Check your email to verify your account.", response.Message)
+}
+
+func TestUserController_RegisterUser_WeakPassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockAuthServiceInterface(ctrl)
+	r := gin.Default()
+	userController := NewUserController(mockService)
+	r.POST("/auth/register", userController.RegisterUser)
+
+	violations := []password.Violation{
+		{Code: password.ViolationTooShort, Message: "must be at least 8 characters long"},
+		{Code: password.ViolationBreached, Message: "has appeared in a data breach or is too common, choose another one"},
+	}
+	mockService.EXPECT().RegisterUser("test@test.com", "Test User", "", "pwd").Return(models.User{}, &password.PolicyError{Violations: violations})
+
+	reqBody, err := json.Marshal(map[string]string{"email": "test@test.com", "name": "Test User", "password": "pwd"})
+	assert.NoError(t, err)
+	req, _ := http.NewRequest("POST", "/auth/register", bytes.NewBuffer(reqBody))
+	req.Header.Set("Content-Type", "application/json")
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	// every reason is returned for the client to show
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	var response struct {
+		Error   string               `json:"error"`
+		Reasons []password.Violation `json:"reasons"`
 	}
-	err = json.NewDecoder(w.Body).Decode(&responseUser)
+	err = json.NewDecoder(w.Body).Decode(&response)
 	assert.NoError(t, err)
-	assert.Equal(t, user.Email, responseUser.Email)
+	assert.Equal(t, "weak-password", response.Error)
+	assert.Equal(t, violations, response.Reasons)
 }
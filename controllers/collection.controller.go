@@ -0,0 +1,321 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/anirudhgray/mood-harbour-backend/models"
+	"github.com/anirudhgray/mood-harbour-backend/services"
+	"github.com/gin-gonic/gin"
+)
+
+type CollectionController struct {
+	collectionService services.CollectionServiceInterface
+}
+
+// NewCollectionController creates a new CollectionController
+func NewCollectionController(collectionService services.CollectionServiceInterface) *CollectionController {
+	return &CollectionController{collectionService: collectionService}
+}
+
+// BookmarkResource handles bookmarking a resource.
+func (cc *CollectionController) BookmarkResource(c *gin.Context) {
+	user, _ := c.Get("user")
+	userID := user.(*models.User).ID
+	resourceID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid-id", "message": "Invalid resource ID."})
+		return
+	}
+
+	err = cc.collectionService.BookmarkResource(userID, uint(resourceID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "creation-error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Resource bookmarked successfully."})
+}
+
+// RemoveBookmark handles removing a bookmark.
+func (cc *CollectionController) RemoveBookmark(c *gin.Context) {
+	user, _ := c.Get("user")
+	userID := user.(*models.User).ID
+	resourceID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid-id", "message": "Invalid resource ID."})
+		return
+	}
+
+	err = cc.collectionService.RemoveBookmark(userID, uint(resourceID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "delete-error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Bookmark removed successfully."})
+}
+
+// GetBookmarks handles getting all the resources bookmarked by the current user.
+func (cc *CollectionController) GetBookmarks(c *gin.Context) {
+	user, _ := c.Get("user")
+	userID := user.(*models.User).ID
+
+	resources, err := cc.collectionService.GetBookmarkedResources(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "fetch-error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resources)
+}
+
+// CreateCollection handles collection creation.
+func (cc *CollectionController) CreateCollection(c *gin.Context) {
+	var collectionData struct {
+		Name        string `json:"name"`
+		Description string `json:"description"`
+		Public      bool   `json:"public"`
+	}
+
+	if err := c.ShouldBindJSON(&collectionData); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "message": "Improper JSON."})
+		return
+	}
+
+	user, _ := c.Get("user")
+	userID := user.(*models.User).ID
+
+	collectionResponse, err := cc.collectionService.CreateCollection(userID, collectionData.Name, collectionData.Description, collectionData.Public)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "creation-error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, collectionResponse)
+}
+
+// GetUserCollections handles getting all the collections created by the current user.
+func (cc *CollectionController) GetUserCollections(c *gin.Context) {
+	user, _ := c.Get("user")
+	userID := user.(*models.User).ID
+
+	collections, err := cc.collectionService.GetUserCollections(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "fetch-error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, collections)
+}
+
+// GetPublicCollections handles getting all the public collections.
+func (cc *CollectionController) GetPublicCollections(c *gin.Context) {
+	collections, err := cc.collectionService.GetPublicCollections()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "fetch-error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, collections)
+}
+
+// GetFollowedCollections handles getting all the collections followed by the current user.
+func (cc *CollectionController) GetFollowedCollections(c *gin.Context) {
+	user, _ := c.Get("user")
+	userID := user.(*models.User).ID
+
+	collections, err := cc.collectionService.GetFollowedCollections(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "fetch-error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, collections)
+}
+
+// GetCollection handles getting a single collection.
+func (cc *CollectionController) GetCollection(c *gin.Context) {
+	user, _ := c.Get("user")
+	userID := user.(*models.User).ID
+	collectionID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid-id", "message": "Invalid collection ID."})
+		return
+	}
+
+	collectionResponse, err := cc.collectionService.GetCollection(userID, uint(collectionID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "fetch-error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, collectionResponse)
+}
+
+// UpdateCollection handles collection updates.
+func (cc *CollectionController) UpdateCollection(c *gin.Context) {
+	user, _ := c.Get("user")
+	userID := user.(*models.User).ID
+	collectionID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid-id", "message": "Invalid collection ID."})
+		return
+	}
+
+	var collectionData struct {
+		Name        string `json:"name"`
+		Description string `json:"description"`
+		Public      bool   `json:"public"`
+	}
+
+	if err := c.ShouldBindJSON(&collectionData); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "message": "Improper JSON."})
+		return
+	}
+
+	collectionResponse, err := cc.collectionService.UpdateCollection(userID, uint(collectionID), collectionData.Name, collectionData.Description, collectionData.Public)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "update-error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, collectionResponse)
+}
+
+// DeleteCollection handles deleting a collection.
+func (cc *CollectionController) DeleteCollection(c *gin.Context) {
+	user, _ := c.Get("user")
+	userID := user.(*models.User).ID
+	collectionID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid-id", "message": "Invalid collection ID."})
+		return
+	}
+
+	err = cc.collectionService.DeleteCollection(userID, uint(collectionID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "delete-error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Collection deleted successfully."})
+}
+
+// AddToCollection handles adding a resource to the end of a collection.
+func (cc *CollectionController) AddToCollection(c *gin.Context) {
+	user, _ := c.Get("user")
+	userID := user.(*models.User).ID
+	collectionID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid-id", "message": "Invalid collection ID."})
+		return
+	}
+
+	var itemData struct {
+		ResourceID uint `json:"resource_id"`
+	}
+
+	if err := c.ShouldBindJSON(&itemData); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "message": "Improper JSON."})
+		return
+	}
+
+	err = cc.collectionService.AddToCollection(userID, uint(collectionID), itemData.ResourceID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "creation-error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Resource added to collection successfully."})
+}
+
+// RemoveFromCollection handles removing a resource from a collection.
+func (cc *CollectionController) RemoveFromCollection(c *gin.Context) {
+	user, _ := c.Get("user")
+	userID := user.(*models.User).ID
+	collectionID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid-id", "message": "Invalid collection ID."})
+		return
+	}
+	resourceID, err := strconv.ParseUint(c.Param("resource_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid-id", "message": "Invalid resource ID."})
+		return
+	}
+
+	err = cc.collectionService.RemoveFromCollection(userID, uint(collectionID), uint(resourceID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "delete-error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Resource removed from collection successfully."})
+}
+
+// ReorderCollection handles reordering the resources in a collection.
+func (cc *CollectionController) ReorderCollection(c *gin.Context) {
+	user, _ := c.Get("user")
+	userID := user.(*models.User).ID
+	collectionID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid-id", "message": "Invalid collection ID."})
+		return
+	}
+
+	var orderData struct {
+		ResourceIDs []uint `json:"resource_ids"`
+	}
+
+	if err := c.ShouldBindJSON(&orderData); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "message": "Improper JSON."})
+		return
+	}
+
+	err = cc.collectionService.ReorderCollection(userID, uint(collectionID), orderData.ResourceIDs)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "update-error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Collection reordered successfully."})
+}
+
+// FollowCollection handles following a public collection.
+func (cc *CollectionController) FollowCollection(c *gin.Context) {
+	user, _ := c.Get("user")
+	userID := user.(*models.User).ID
+	collectionID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid-id", "message": "Invalid collection ID."})
+		return
+	}
+
+	err = cc.collectionService.FollowCollection(userID, uint(collectionID))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "follow-error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Collection followed successfully."})
+}
+
+// UnfollowCollection handles unfollowing a collection.
+func (cc *CollectionController) UnfollowCollection(c *gin.Context) {
+	user, _ := c.Get("user")
+	userID := user.(*models.User).ID
+	collectionID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid-id", "message": "Invalid collection ID."})
+		return
+	}
+
+	err = cc.collectionService.UnfollowCollection(userID, uint(collectionID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "follow-error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Collection unfollowed successfully."})
+}
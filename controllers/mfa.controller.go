@@ -0,0 +1,130 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/anirudhgray/mood-harbour-backend/models"
+	"github.com/gin-gonic/gin"
+)
+
+// codeInput is a TOTP code, or a recovery code where one is accepted.
+type codeInput struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// MFALogin completes a login with the MFA token from Login and a TOTP code or recovery code.
+func (uc *UserController) MFALogin(c *gin.Context) {
+	var mfaData struct {
+		MFAToken string `json:"mfa_token" binding:"required"`
+		Code     string `json:"code" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&mfaData); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "message": "Improper JSON."})
+		return
+	}
+
+	result, err := uc.authService.CompleteMFALogin(mfaData.MFAToken, mfaData.Code, clientInfo(c))
+	if throttled(c, err) {
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "mfa-error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, loginResponse(result))
+}
+
+// GetMFAStatus shows whether the user has two-factor authentication enabled.
+func (uc *UserController) GetMFAStatus(c *gin.Context) {
+	user, _ := c.Get("user")
+	currentUser := user.(*models.User)
+
+	status, err := uc.authService.GetMFAStatus(currentUser.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "fetch-error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
+
+// EnrollTOTP starts enabling two-factor authentication, returning the secret for the user's authenticator app.
+func (uc *UserController) EnrollTOTP(c *gin.Context) {
+	user, _ := c.Get("user")
+	currentUser := user.(*models.User)
+
+	enrollment, err := uc.authService.BeginTOTPEnrollment(*currentUser)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "mfa-error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, enrollment)
+}
+
+// ConfirmTOTP enables two-factor authentication with a first code from the user's authenticator app, returning their recovery codes.
+func (uc *UserController) ConfirmTOTP(c *gin.Context) {
+	var input codeInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "message": "Improper JSON."})
+		return
+	}
+
+	user, _ := c.Get("user")
+	currentUser := user.(*models.User)
+
+	codes, err := uc.authService.ConfirmTOTPEnrollment(*currentUser, input.Code)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "mfa-error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Two-factor authentication enabled.", "recovery_codes": codes})
+}
+
+// DisableTOTP turns off two-factor authentication, given a current code or a recovery code.
+func (uc *UserController) DisableTOTP(c *gin.Context) {
+	var input codeInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "message": "Improper JSON."})
+		return
+	}
+
+	user, _ := c.Get("user")
+	currentUser := user.(*models.User)
+
+	if err := uc.authService.DisableTOTP(*currentUser, input.Code, clientInfo(c)); err != nil {
+		if throttled(c, err) {
+			return
+		}
+		c.JSON(http.StatusForbidden, gin.H{"error": "mfa-error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Two-factor authentication disabled."})
+}
+
+// RegenerateRecoveryCodes replaces the user's recovery codes, given a current code or a recovery code.
+func (uc *UserController) RegenerateRecoveryCodes(c *gin.Context) {
+	var input codeInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "message": "Improper JSON."})
+		return
+	}
+
+	user, _ := c.Get("user")
+	currentUser := user.(*models.User)
+
+	codes, err := uc.authService.RegenerateRecoveryCodes(*currentUser, input.Code, clientInfo(c))
+	if err != nil {
+		if throttled(c, err) {
+			return
+		}
+		c.JSON(http.StatusForbidden, gin.H{"error": "mfa-error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"recovery_codes": codes})
+}
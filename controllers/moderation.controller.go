@@ -0,0 +1,133 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/anirudhgray/mood-harbour-backend/models"
+	"github.com/anirudhgray/mood-harbour-backend/services"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type ModerationController struct {
+	moderationService services.ModerationServiceInterface
+}
+
+// NewModerationController creates a new ModerationController
+func NewModerationController(moderationService services.ModerationServiceInterface) *ModerationController {
+	return &ModerationController{moderationService: moderationService}
+}
+
+// ReportResource handles a user flagging a resource for moderation.
+func (mc *ModerationController) ReportResource(c *gin.Context) {
+	mc.reportContent(c, models.ResourceTarget)
+}
+
+// ReportReview handles a user flagging a review for moderation.
+func (mc *ModerationController) ReportReview(c *gin.Context) {
+	mc.reportContent(c, models.ReviewTarget)
+}
+
+func (mc *ModerationController) reportContent(c *gin.Context, targetType models.ReportTargetType) {
+	user, _ := c.Get("user")
+	userID := user.(*models.User).ID
+	targetIDStr := c.Param("id")
+	targetID, err := strconv.ParseUint(targetIDStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid-id", "message": "Invalid " + string(targetType) + " ID."})
+		return
+	}
+
+	var reportData struct {
+		Reason string `json:"reason"`
+	}
+
+	if err := c.ShouldBindJSON(&reportData); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "message": "Improper JSON."})
+		return
+	}
+
+	err = mc.moderationService.ReportContent(userID, targetType, uint(targetID), reportData.Reason)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "not-found", "message": "No such " + string(targetType) + "."})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "report-error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Thank you, a moderator will look into your report."})
+}
+
+// GetModerationQueue gets all flagged content waiting for a moderator, of the kinds the user can moderate.
+func (mc *ModerationController) GetModerationQueue(c *gin.Context) {
+	user, _ := c.Get("user")
+	moderator := user.(*models.User)
+
+	queue, err := mc.moderationService.GetModerationQueue()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "fetch-error", "message": err.Error()})
+		return
+	}
+
+	allowed := make([]models.ModerationQueueItem, 0, len(queue))
+	for _, item := range queue {
+		if moderator.Can(models.ModeratePermission(item.TargetType)) {
+			allowed = append(allowed, item)
+		}
+	}
+
+	c.JSON(http.StatusOK, allowed)
+}
+
+// ApproveContent publishes a flagged or pending resource or review.
+func (mc *ModerationController) ApproveContent(c *gin.Context) {
+	mc.moderateContent(c, models.ApproveAction)
+}
+
+// HideContent hides a flagged resource or review from the catalog and recommendations.
+func (mc *ModerationController) HideContent(c *gin.Context) {
+	mc.moderateContent(c, models.HideAction)
+}
+
+// DeleteContent deletes a flagged resource or review.
+func (mc *ModerationController) DeleteContent(c *gin.Context) {
+	mc.moderateContent(c, models.DeleteAction)
+}
+
+func (mc *ModerationController) moderateContent(c *gin.Context, action models.ModerationAction) {
+	user, _ := c.Get("user")
+	moderator := user.(*models.User)
+	targetType := models.ReportTargetType(c.Param("type"))
+	targetIDStr := c.Param("id")
+	targetID, err := strconv.ParseUint(targetIDStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid-id", "message": "Invalid content ID."})
+		return
+	}
+
+	if targetType != models.ResourceTarget && targetType != models.ReviewTarget {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid-type", "message": "Content type must be resource or review."})
+		return
+	}
+
+	if !moderator.Can(models.ModeratePermission(targetType)) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "auth", "message": "You are not authorized to moderate " + string(targetType) + "s."})
+		return
+	}
+
+	err = mc.moderationService.ModerateContent(moderator.ID, targetType, uint(targetID), action)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "not-found", "message": "No such " + string(targetType) + "."})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "moderation-error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Content moderated successfully."})
+}
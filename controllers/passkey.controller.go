@@ -0,0 +1,109 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/anirudhgray/mood-harbour-backend/models"
+	"github.com/anirudhgray/mood-harbour-backend/utils/webauthn"
+	"github.com/gin-gonic/gin"
+)
+
+// BeginPasskeyLogin gets the options to pass to navigator.credentials.get to log in with a passkey.
+func (uc *UserController) BeginPasskeyLogin(c *gin.Context) {
+	options, err := uc.authService.BeginPasskeyLogin()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "passkey-error", "message": "Error while starting passkey login."})
+		return
+	}
+
+	c.JSON(http.StatusOK, options)
+}
+
+// FinishPasskeyLogin logs in with the credential returned by navigator.credentials.get.
+func (uc *UserController) FinishPasskeyLogin(c *gin.Context) {
+	var response webauthn.AssertionResponse
+	if err := c.ShouldBindJSON(&response); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "message": "Improper JSON."})
+		return
+	}
+
+	result, err := uc.authService.FinishPasskeyLogin(response, clientInfo(c))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "passkey-error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, loginResponse(result))
+}
+
+// GetPasskeys lists the user's passkeys.
+func (uc *UserController) GetPasskeys(c *gin.Context) {
+	user, _ := c.Get("user")
+	currentUser := user.(*models.User)
+
+	passkeys, err := uc.authService.GetPasskeys(currentUser.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "fetch-error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"passkeys": passkeys})
+}
+
+// BeginPasskeyRegistration gets the options to pass to navigator.credentials.create to register a passkey.
+func (uc *UserController) BeginPasskeyRegistration(c *gin.Context) {
+	user, _ := c.Get("user")
+	currentUser := user.(*models.User)
+
+	options, err := uc.authService.BeginPasskeyRegistration(*currentUser)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "passkey-error", "message": "Error while starting passkey registration."})
+		return
+	}
+
+	c.JSON(http.StatusOK, options)
+}
+
+// FinishPasskeyRegistration registers the credential returned by navigator.credentials.create, with an optional name.
+func (uc *UserController) FinishPasskeyRegistration(c *gin.Context) {
+	var registrationData struct {
+		Name       string                       `json:"name"`
+		Credential webauthn.AttestationResponse `json:"credential" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&registrationData); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "message": "Improper JSON."})
+		return
+	}
+
+	user, _ := c.Get("user")
+	currentUser := user.(*models.User)
+
+	passkey, err := uc.authService.FinishPasskeyRegistration(*currentUser, registrationData.Name, registrationData.Credential)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "passkey-error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, passkey)
+}
+
+// DeletePasskey removes one of the user's passkeys.
+func (uc *UserController) DeletePasskey(c *gin.Context) {
+	passkeyID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid-id", "message": "Invalid passkey ID."})
+		return
+	}
+
+	user, _ := c.Get("user")
+	currentUser := user.(*models.User)
+
+	if err := uc.authService.DeletePasskey(*currentUser, uint(passkeyID)); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "passkey-not-found", "message": "No such passkey."})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Passkey removed."})
+}
@@ -1,55 +1,234 @@
 package controllers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/anirudhgray/mood-harbour-backend/models"
-	"github.com/anirudhgray/mood-harbour-backend/utils/recommender"
+	"github.com/anirudhgray/mood-harbour-backend/services"
 	"github.com/gin-gonic/gin"
 )
 
-func GenerateRecommendations(c *gin.Context) {
+type RecommendationController struct {
+	recommendationService services.RecommendationServiceInterface
+}
+
+func NewRecommendationController(recommendationService services.RecommendationServiceInterface) *RecommendationController {
+	return &RecommendationController{recommendationService: recommendationService}
+}
+
+// RecommendationsPerPage is the page size of /v1/resource/get-reccs.
+const RecommendationsPerPage = 20
+
+// GenerateRecommendations returns a page of the user's precomputed recommendations, along with when they were computed.
+// The optional mood query parameter overrides the mood the recommendations are ranked for, which is otherwise the user's latest logged mood.
+func (rc *RecommendationController) GenerateRecommendations(c *gin.Context) {
 	currentUser, _ := c.Get("user")
 	user := currentUser.(*models.User)
 	userID := user.ID
 
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 
-	likes, dislikes, err := recommender.GetUserLikesDislikes(userID)
+	var mood models.MoodType
+	if moodStr := c.Query("mood"); moodStr != "" {
+		moodInt, err := strconv.Atoi(moodStr)
+		if err != nil || models.MoodType(moodInt) < models.Angry || models.MoodType(moodInt) > models.Excited {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid-mood-type", "message": "Invalid mood type."})
+			return
+		}
+		mood = models.MoodType(moodInt)
+	}
+
+	recommendationPage, err := rc.recommendationService.GetRecommendations(userID, page, RecommendationsPerPage, mood)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "fetch-error", "message": err.Error()})
+		return
+	}
+
+	var message string
+	if len(recommendationPage.Recommendations) > 0 {
+		message = "Here are some recommendations for you."
+	} else if recommendationPage.ComputedAt == nil {
+		message = "Your recommendations are being prepared. Please check back in a minute."
+	} else {
+		message = "Could not get any recommendations for you. Maybe try going through the discover page and reviewing some resources?."
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":         message,
+		"recommendations": recommendationPage.Recommendations,
+		"page":            recommendationPage.Page,
+		"total":           recommendationPage.Total,
+		"computed_at":     recommendationPage.ComputedAt,
+		"stale":           recommendationPage.Stale,
+		"mood":            recommendationPage.Mood,
+	})
+}
+
+// DismissRecommendation handles dismissing a recommended resource, e.g. because the user isn't interested in it.
+func (rc *RecommendationController) DismissRecommendation(c *gin.Context) {
+	var dismissalData struct {
+		Reason models.DismissalReason `json:"reason"`
+	}
+
+	if err := c.ShouldBindJSON(&dismissalData); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "message": "Improper JSON."})
+		return
+	}
+	if dismissalData.Reason < models.NotInterested || dismissalData.Reason > models.AlreadySeen {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid-reason", "message": "Invalid dismissal reason."})
+		return
+	}
+
+	user, _ := c.Get("user")
+	userID := user.(*models.User).ID
+	resourceID, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid-id", "message": "Invalid resource ID."})
 		return
 	}
 
-	otherUsers, err := recommender.GetUsersWithSimilarInteractions(likes, dislikes, userID)
+	err = rc.recommendationService.DismissRecommendation(userID, uint(resourceID), dismissalData.Reason)
+	if errors.Is(err, services.ErrResourceNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "not-found", "message": err.Error()})
+		return
+	}
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "creation-error", "message": err.Error()})
 		return
 	}
 
-	similarities, err := recommender.CalculateSimilaritiesWithOtherUsers(userID, otherUsers, likes, dislikes)
+	c.JSON(http.StatusCreated, gin.H{"message": "Recommendation dismissed successfully."})
+}
+
+// UndoDismissal handles undoing the dismissal of a recommended resource.
+func (rc *RecommendationController) UndoDismissal(c *gin.Context) {
+	user, _ := c.Get("user")
+	userID := user.(*models.User).ID
+	resourceID, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid-id", "message": "Invalid resource ID."})
 		return
 	}
 
-	unreviewed, err := recommender.GetUnreviewedResources(userID)
+	err = rc.recommendationService.UndoDismissal(userID, uint(resourceID))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "delete-error", "message": err.Error()})
 		return
 	}
 
-	recprobs := recommender.CalculateRecommendationProbabilities(userID, unreviewed, similarities)
+	c.JSON(http.StatusOK, gin.H{"message": "Dismissal undone successfully."})
+}
 
-	recommendedResources := recommender.GetRecommendedResourcesSortedAndPaginated(recprobs, page, 20)
+// GetDismissals handles getting the recommendations dismissed by the current user.
+func (rc *RecommendationController) GetDismissals(c *gin.Context) {
+	user, _ := c.Get("user")
+	userID := user.(*models.User).ID
 
-	var message string
-	if len(recommendedResources) > 0 {
-		message = "Here are some recommendations for you."
-	} else {
-		message = "Could not get any recommendations for you. Maybe try going through the discover page and reviewing some resources?."
+	dismissals, err := rc.recommendationService.GetDismissals(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "fetch-error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dismissals)
+}
+
+// CurateResource handles adding a resource to the curated resources recommended to everyone.
+func (rc *RecommendationController) CurateResource(c *gin.Context) {
+	user, _ := c.Get("user")
+	adminID := user.(*models.User).ID
+	resourceID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid-id", "message": "Invalid resource ID."})
+		return
+	}
+
+	err = rc.recommendationService.CurateResource(adminID, uint(resourceID))
+	if errors.Is(err, services.ErrResourceNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "not-found", "message": err.Error()})
+		return
+	}
+	if errors.Is(err, services.ErrAlreadyCurated) {
+		c.JSON(http.StatusConflict, gin.H{"error": "already-curated", "message": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "creation-error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Resource curated successfully."})
+}
+
+// UncurateResource handles removing a resource from the curated resources.
+func (rc *RecommendationController) UncurateResource(c *gin.Context) {
+	resourceID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid-id", "message": "Invalid resource ID."})
+		return
+	}
+
+	err = rc.recommendationService.UncurateResource(uint(resourceID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "delete-error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Resource removed from curated resources successfully."})
+}
+
+// GetCuratedResources handles getting the curated resources.
+func (rc *RecommendationController) GetCuratedResources(c *gin.Context) {
+	resources, err := rc.recommendationService.GetCuratedResources()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "fetch-error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resources)
+}
+
+// GetFactorizationModels handles listing the stored versions of the matrix factorization model.
+func (rc *RecommendationController) GetFactorizationModels(c *gin.Context) {
+	factorizationModels, err := rc.recommendationService.GetFactorizationModels()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "fetch-error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, factorizationModels)
+}
+
+// TrainFactorizationModel handles queueing the training of a new version of the matrix factorization model straight away,
+// rather than waiting for the scheduled training job. The new version shows up in GetFactorizationModels once it's trained.
+func (rc *RecommendationController) TrainFactorizationModel(c *gin.Context) {
+	if err := rc.recommendationService.QueueFactorizationTraining(); err != nil {
+		if errors.Is(err, services.ErrTrainingInProgress) {
+			c.JSON(http.StatusConflict, gin.H{"error": "training-in-progress", "message": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "training-error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "Training started."})
+}
+
+// ActivateFactorizationModel handles switching to another version of the matrix factorization model, e.g. to roll back.
+func (rc *RecommendationController) ActivateFactorizationModel(c *gin.Context) {
+	modelID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid-id", "message": "Invalid model ID."})
+		return
+	}
+
+	err = rc.recommendationService.ActivateFactorizationModel(uint(modelID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "update-error", "message": err.Error()})
+		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": message, "recommendations": recommendedResources})
+	c.JSON(http.StatusOK, gin.H{"message": "Model activated successfully."})
 }
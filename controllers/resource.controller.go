@@ -1,12 +1,14 @@
 package controllers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/anirudhgray/mood-harbour-backend/models"
 	"github.com/anirudhgray/mood-harbour-backend/services"
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 type ResourceController struct {
@@ -73,6 +75,68 @@ func (mc *ResourceController) GetResourceByID(c *gin.Context) {
 	c.JSON(http.StatusOK, resourceResponse)
 }
 
+// UpdateResourceEntry handles updating a resource entry. Only the owner or an admin can update a resource.
+func (mc *ResourceController) UpdateResourceEntry(c *gin.Context) {
+	user, _ := c.Get("user")
+	userID := user.(*models.User).ID
+	resourceIDStr := c.Param("id")
+	resourceID, err := strconv.ParseUint(resourceIDStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid-id", "message": "Invalid resource ID."})
+		return
+	}
+
+	var resourceData struct {
+		Title     string `json:"title"`
+		Content   string `json:"content"`
+		URL       string `json:"url"`
+		External  bool   `json:"external"`
+		AdminPost bool   `json:"admin_post"`
+	}
+
+	if err := c.ShouldBindJSON(&resourceData); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "message": "Improper JSON."})
+		return
+	}
+
+	resourceResponse, err := mc.resourceService.UpdateResource(uint(resourceID), userID, resourceData.Title, resourceData.Content, resourceData.URL, resourceData.External, resourceData.AdminPost)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "update-error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resourceResponse)
+}
+
+// SetResourceMoodTags handles replacing the moods a resource is tagged for. Only the owner or an admin can tag a resource.
+func (mc *ResourceController) SetResourceMoodTags(c *gin.Context) {
+	user, _ := c.Get("user")
+	userID := user.(*models.User).ID
+	resourceIDStr := c.Param("id")
+	resourceID, err := strconv.ParseUint(resourceIDStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid-id", "message": "Invalid resource ID."})
+		return
+	}
+
+	var tagData struct {
+		Moods []models.MoodType `json:"moods"`
+	}
+
+	if err := c.ShouldBindJSON(&tagData); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "message": "Improper JSON."})
+		return
+	}
+
+	resourceResponse, err := mc.resourceService.SetResourceMoodTags(uint(resourceID), userID, tagData.Moods)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "update-error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resourceResponse)
+}
+
 // DeleteResourceEntry handles deleting a resource entry. Only the owner or an admin can delete a resource.
 func (mc *ResourceController) DeleteResourceEntry(c *gin.Context) {
 	user, _ := c.Get("user")
@@ -122,3 +186,102 @@ func (mc *ResourceController) AddReview(c *gin.Context) {
 
 	c.JSON(http.StatusCreated, gin.H{"message": "Review added successfully."})
 }
+
+// GetResourceReviews gets a page of reviews for a resource, most helpful first.
+func (mc *ResourceController) GetResourceReviews(c *gin.Context) {
+	resourceIDStr := c.Param("id")
+	resourceID, err := strconv.ParseUint(resourceIDStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid-id", "message": "Invalid resource ID."})
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", strconv.Itoa(services.DefaultReviewsPerPage)))
+	if perPage > 50 {
+		perPage = 50
+	}
+
+	reviewPage, err := mc.resourceService.GetResourceReviews(uint(resourceID), page, perPage)
+	if errors.Is(err, services.ErrResourceNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "not-found", "message": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "fetch-error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, reviewPage)
+}
+
+// GetMyReviews gets all the reviews written by the current user, along with their helpfulness votes.
+func (mc *ResourceController) GetMyReviews(c *gin.Context) {
+	user, _ := c.Get("user")
+	userID := user.(*models.User).ID
+
+	reviews, err := mc.resourceService.GetUserReviews(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "fetch-error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, reviews)
+}
+
+// VoteOnReview marks a review as helpful or unhelpful for the current user.
+func (mc *ResourceController) VoteOnReview(c *gin.Context) {
+	user, _ := c.Get("user")
+	userID := user.(*models.User).ID
+	reviewIDStr := c.Param("id")
+	reviewID, err := strconv.ParseUint(reviewIDStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid-id", "message": "Invalid review ID."})
+		return
+	}
+
+	var voteData struct {
+		Helpful *bool `json:"helpful"`
+	}
+
+	if err := c.ShouldBindJSON(&voteData); err != nil || voteData.Helpful == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid-vote", "message": "Improper JSON, helpful must be true or false."})
+		return
+	}
+
+	err = mc.resourceService.VoteOnReview(uint(reviewID), userID, *voteData.Helpful)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "not-found", "message": "No such review."})
+		return
+	}
+	if errors.Is(err, services.ErrSelfVote) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "self-vote", "message": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "vote-error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Vote recorded successfully."})
+}
+
+// RemoveReviewVote removes the current user's helpfulness vote on a review.
+func (mc *ResourceController) RemoveReviewVote(c *gin.Context) {
+	user, _ := c.Get("user")
+	userID := user.(*models.User).ID
+	reviewIDStr := c.Param("id")
+	reviewID, err := strconv.ParseUint(reviewIDStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid-id", "message": "Invalid review ID."})
+		return
+	}
+
+	err = mc.resourceService.RemoveReviewVote(uint(reviewID), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "delete-error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Vote removed successfully."})
+}
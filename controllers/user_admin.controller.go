@@ -0,0 +1,164 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/anirudhgray/mood-harbour-backend/models"
+	"github.com/anirudhgray/mood-harbour-backend/services"
+	"github.com/gin-gonic/gin"
+)
+
+// MaxUsersPerPage caps the page size of the admin user listing.
+const MaxUsersPerPage = 100
+
+type UserAdminController struct {
+	userAdminService services.UserAdminServiceInterface
+}
+
+// NewUserAdminController creates a new UserAdminController
+func NewUserAdminController(userAdminService services.UserAdminServiceInterface) *UserAdminController {
+	return &UserAdminController{userAdminService: userAdminService}
+}
+
+// GetUsers gets a page of users, searchable by name or email with ?q= and filterable by ?role=, ?disabled= and ?verified=.
+func (uc *UserAdminController) GetUsers(c *gin.Context) {
+	role := models.Role(c.Query("role"))
+	if role != "" && !role.Valid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid-filter", "message": "Unknown role."})
+		return
+	}
+	disabled, errDisabled := boolQuery(c, "disabled")
+	verified, errVerified := boolQuery(c, "verified")
+	if errDisabled != nil || errVerified != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid-filter", "message": "disabled and verified must be true or false."})
+		return
+	}
+	filter := models.UserFilter{Query: c.Query("q"), Role: role, Disabled: disabled, Verified: verified}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", strconv.Itoa(services.DefaultUsersPerPage)))
+	if perPage > MaxUsersPerPage {
+		perPage = MaxUsersPerPage
+	}
+
+	userPage, err := uc.userAdminService.GetUsers(filter, page, perPage)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "fetch-error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, userPage)
+}
+
+// GetUser gets a single user.
+func (uc *UserAdminController) GetUser(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid-id", "message": "Invalid user ID."})
+		return
+	}
+
+	user, err := uc.userAdminService.GetUser(uint(userID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "not-found", "message": "User not found."})
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+// DisableUser disables a user, logging them out everywhere.
+func (uc *UserAdminController) DisableUser(c *gin.Context) {
+	uc.updateUser(c, func(adminID, userID uint) (models.User, error) {
+		return uc.userAdminService.SetDisabled(adminID, userID, true)
+	})
+}
+
+// EnableUser re-enables a disabled user.
+func (uc *UserAdminController) EnableUser(c *gin.Context) {
+	uc.updateUser(c, func(adminID, userID uint) (models.User, error) {
+		return uc.userAdminService.SetDisabled(adminID, userID, false)
+	})
+}
+
+// SetUserRole assigns a user a role.
+func (uc *UserAdminController) SetUserRole(c *gin.Context) {
+	var roleData struct {
+		Role models.Role `json:"role" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&roleData); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "message": "Improper JSON."})
+		return
+	}
+
+	uc.updateUser(c, func(adminID, userID uint) (models.User, error) {
+		return uc.userAdminService.SetRole(adminID, userID, roleData.Role)
+	})
+}
+
+// GetRoles lists the roles and the permissions each one grants.
+func (uc *UserAdminController) GetRoles(c *gin.Context) {
+	roles := make([]gin.H, 0, len(models.Roles))
+	for _, role := range models.Roles {
+		roles = append(roles, gin.H{"role": role, "permissions": models.RolePermissions[role]})
+	}
+
+	c.JSON(http.StatusOK, gin.H{"roles": roles})
+}
+
+// VerifyUser marks a user's email as verified.
+func (uc *UserAdminController) VerifyUser(c *gin.Context) {
+	uc.updateUser(c, func(adminID, userID uint) (models.User, error) {
+		return uc.userAdminService.VerifyUser(userID)
+	})
+}
+
+// SendPasswordReset mails a user a password reset OTP.
+func (uc *UserAdminController) SendPasswordReset(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid-id", "message": "Invalid user ID."})
+		return
+	}
+
+	if err := uc.userAdminService.SendPasswordReset(uint(userID)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "mail", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Forgot Password mail sent."})
+}
+
+// updateUser applies an admin action to the user in the path, responding with the updated user.
+func (uc *UserAdminController) updateUser(c *gin.Context, action func(adminID, userID uint) (models.User, error)) {
+	admin, _ := c.Get("user")
+	adminID := admin.(*models.User).ID
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid-id", "message": "Invalid user ID."})
+		return
+	}
+
+	user, err := action(adminID, uint(userID))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "update-error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+// boolQuery parses an optional true/false query parameter, nil if it's missing.
+func boolQuery(c *gin.Context, name string) (*bool, error) {
+	value, ok := c.GetQuery(name)
+	if !ok {
+		return nil, nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
@@ -0,0 +1,87 @@
+package jobs
+
+import (
+	"time"
+
+	"github.com/anirudhgray/mood-harbour-backend/infra/logger"
+	"github.com/anirudhgray/mood-harbour-backend/repository"
+	"github.com/anirudhgray/mood-harbour-backend/services"
+	"github.com/anirudhgray/mood-harbour-backend/utils/linkpreview"
+	"github.com/anirudhgray/mood-harbour-backend/utils/preference"
+	"github.com/anirudhgray/mood-harbour-backend/utils/recommender"
+	"github.com/spf13/viper"
+)
+
+// StartJobs schedules all background jobs. Must be called after the database connection is set up.
+func StartJobs() {
+	// fail fast rather than on every run of the recommendation jobs
+	if _, err := recommender.New(viper.GetString("RECOMMENDER_ALGORITHM"), recommender.Sources{}); err != nil {
+		logger.Fatalf("Invalid RECOMMENDER_ALGORITHM: %v", err)
+	}
+	if _, err := preference.NewModelFromConfig(); err != nil {
+		logger.Fatalf("Invalid recommendation preference model: %v", err)
+	}
+	if _, err := recommender.NewHyperparametersFromConfig(); err != nil {
+		logger.Fatalf("Invalid matrix factorization hyperparameters: %v", err)
+	}
+
+	resourceRepo := repository.NewResourceRepository()
+	recommendationRepo := repository.NewRecommendationRepository()
+	moodRepo := repository.NewMoodRepository()
+	oneTimeTokenRepo := repository.NewOneTimeTokenRepository()
+	mfaRepo := repository.NewMFARepository()
+	passkeyRepo := repository.NewPasskeyRepository()
+	sessionRepo := repository.NewSessionRepository()
+
+	recommendationService := services.NewRecommendationService(recommendationRepo, resourceRepo, moodRepo, viper.GetInt("RECOMMENDATION_MAX_PER_USER"), viper.GetString("RECOMMENDER_ALGORITHM"))
+
+	linkChecker := NewLinkChecker(
+		resourceRepo,
+		linkpreview.NewFetcherFromConfig(),
+		time.Duration(viper.GetInt("LINK_CHECK_RECHECK_HOURS"))*time.Hour,
+		viper.GetInt("LINK_CHECK_BATCH_SIZE"),
+		viper.GetInt("LINK_CHECK_MAX_FAILURES"),
+	)
+	Schedule("link-check", time.Duration(viper.GetInt("LINK_CHECK_INTERVAL_MINUTES"))*time.Minute, linkChecker.Run)
+
+	Schedule("recommendations-full", time.Duration(viper.GetInt("RECOMMENDATION_FULL_INTERVAL_MINUTES"))*time.Minute, recommendationService.RecomputeAll)
+
+	staleBatchSize := viper.GetInt("RECOMMENDATION_STALE_BATCH_SIZE")
+	Schedule("recommendations-stale", time.Duration(viper.GetInt("RECOMMENDATION_STALE_INTERVAL_SECONDS"))*time.Second, func() error {
+		return recommendationService.RecomputeStale(staleBatchSize)
+	})
+
+	// the matrix factorization model is only trained when it's used, admins can still train one by hand to try it out
+	if recommender.Uses(viper.GetString("RECOMMENDER_ALGORITHM"), recommender.FactorizationAlgorithm) {
+		Schedule("recommendations-train", time.Duration(viper.GetInt("RECOMMENDATION_MF_TRAIN_INTERVAL_MINUTES"))*time.Minute, func() error {
+			_, err := recommendationService.TrainFactorizationModel()
+			return err
+		})
+	}
+
+	// logins leave behind challenges and codes which are only good for a few minutes, so they're purged along with the tokens
+	Schedule("one-time-token-purge", time.Duration(viper.GetInt("OTP_PURGE_INTERVAL_MINUTES"))*time.Minute, func() error {
+		now := time.Now()
+		deleted, err := oneTimeTokenRepo.PurgeOneTimeTokens(now)
+		if err != nil {
+			return err
+		}
+		logger.Debugf("Purged %d expired or used one-time tokens", deleted)
+
+		if deleted, err = mfaRepo.PurgeMFAChallenges(now); err != nil {
+			return err
+		}
+		logger.Debugf("Purged %d expired or used two-factor challenges", deleted)
+
+		if deleted, err = passkeyRepo.PurgePasskeyChallenges(now); err != nil {
+			return err
+		}
+		logger.Debugf("Purged %d expired or used passkey challenges", deleted)
+
+		if deleted, err = sessionRepo.PurgeSocialLoginCodes(now); err != nil {
+			return err
+		}
+		logger.Debugf("Purged %d expired or exchanged social login codes", deleted)
+		return nil
+	})
+}
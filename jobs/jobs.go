@@ -0,0 +1,46 @@
+package jobs
+
+import (
+	"time"
+
+	"github.com/anirudhgray/mood-harbour-backend/infra/logger"
+)
+
+// Schedule runs fn every interval in a background goroutine, starting after the first interval has passed.
+// Errors are logged and don't stop the schedule. Calling the returned function stops the job.
+func Schedule(name string, interval time.Duration, fn func() error) (stop func()) {
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				run(name, fn)
+			case <-done:
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+
+	logger.Infof("Scheduled job %s every %s", name, interval)
+
+	return func() { close(done) }
+}
+
+// run calls fn once, logging any error or panic instead of taking down the server.
+func run(name string, fn func() error) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Errorf("Job %s panicked: %v", name, r)
+		}
+	}()
+
+	start := time.Now()
+	if err := fn(); err != nil {
+		logger.Errorf("Job %s failed: %v", name, err)
+		return
+	}
+	logger.Debugf("Job %s finished in %s", name, time.Since(start))
+}
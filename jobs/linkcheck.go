@@ -0,0 +1,54 @@
+package jobs
+
+import (
+	"time"
+
+	"github.com/anirudhgray/mood-harbour-backend/infra/logger"
+	"github.com/anirudhgray/mood-harbour-backend/models"
+	"github.com/anirudhgray/mood-harbour-backend/repository"
+	"github.com/anirudhgray/mood-harbour-backend/utils/linkpreview"
+)
+
+// LinkChecker periodically re-fetches external resource links and marks them dead after repeated failures.
+type LinkChecker struct {
+	resourceRepo repository.ResourceRepositoryInterface
+	fetcher      linkpreview.Fetcher
+	recheckAfter time.Duration // How long a link is trusted before it is checked again
+	batchSize    int           // Maximum number of links checked per run
+	maxFailures  int           // Consecutive failures after which a link is marked dead
+}
+
+func NewLinkChecker(resourceRepo repository.ResourceRepositoryInterface, fetcher linkpreview.Fetcher, recheckAfter time.Duration, batchSize, maxFailures int) *LinkChecker {
+	return &LinkChecker{resourceRepo, fetcher, recheckAfter, batchSize, maxFailures}
+}
+
+// Run checks the batch of least recently checked external links.
+func (lc *LinkChecker) Run() error {
+	now := time.Now()
+	resources, err := lc.resourceRepo.GetResourcesForLinkCheck(now.Add(-lc.recheckAfter), lc.batchSize)
+	if err != nil {
+		return err
+	}
+
+	dead := 0
+	for _, resource := range resources {
+		status := models.LinkAlive
+		failures := 0
+
+		if _, err := lc.fetcher.Fetch(resource.URL); err != nil {
+			failures = resource.LinkFailures + 1
+			status = resource.LinkStatus
+			if failures >= lc.maxFailures {
+				status = models.LinkDead
+				dead++
+			}
+		}
+
+		if err := lc.resourceRepo.UpdateLinkStatus(resource.ID, status, failures, now); err != nil {
+			return err
+		}
+	}
+
+	logger.Infof("Checked %d resource links, %d dead", len(resources), dead)
+	return nil
+}
@@ -0,0 +1,51 @@
+package jobs
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/anirudhgray/mood-harbour-backend/mocks"
+	"github.com/anirudhgray/mood-harbour-backend/models"
+	"github.com/anirudhgray/mood-harbour-backend/utils/linkpreview"
+	"github.com/golang/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func TestLinkChecker_Run(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/alive", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte("<title>Still here</title>"))
+	})
+	mux.HandleFunc("/gone", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "gone", http.StatusGone)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	mockResourceRepo := mocks.NewMockResourceRepositoryInterface(ctrl)
+	lc := NewLinkChecker(mockResourceRepo, linkpreview.NewHTTPFetcher(time.Second, 1024, true), time.Hour, 10, 3)
+
+	resources := []models.Resource{
+		// a link that failed before but works again is reset
+		{Model: gorm.Model{ID: 1}, URL: server.URL + "/alive", External: true, LinkStatus: models.LinkAlive, LinkFailures: 2},
+		// a first failure doesn't mark the link dead yet
+		{Model: gorm.Model{ID: 2}, URL: server.URL + "/gone", External: true, LinkStatus: models.LinkAlive, LinkFailures: 0},
+		// the third failure in a row does
+		{Model: gorm.Model{ID: 3}, URL: server.URL + "/gone", External: true, LinkStatus: models.LinkAlive, LinkFailures: 2},
+	}
+
+	mockResourceRepo.EXPECT().GetResourcesForLinkCheck(gomock.Any(), 10).Return(resources, nil)
+	mockResourceRepo.EXPECT().UpdateLinkStatus(uint(1), models.LinkAlive, 0, gomock.Any()).Return(nil)
+	mockResourceRepo.EXPECT().UpdateLinkStatus(uint(2), models.LinkAlive, 1, gomock.Any()).Return(nil)
+	mockResourceRepo.EXPECT().UpdateLinkStatus(uint(3), models.LinkDead, 3, gomock.Any()).Return(nil)
+
+	if err := lc.Run(); err != nil {
+		t.Errorf("Run returned an error: %v", err)
+	}
+}
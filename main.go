@@ -6,8 +6,10 @@ import (
 	"github.com/anirudhgray/mood-harbour-backend/config"
 	"github.com/anirudhgray/mood-harbour-backend/infra/database"
 	"github.com/anirudhgray/mood-harbour-backend/infra/logger"
+	"github.com/anirudhgray/mood-harbour-backend/jobs"
 	"github.com/anirudhgray/mood-harbour-backend/migrations"
 	"github.com/anirudhgray/mood-harbour-backend/routers"
+	"github.com/anirudhgray/mood-harbour-backend/utils/password"
 	"github.com/spf13/viper"
 )
 
@@ -21,9 +23,17 @@ func main() {
 	if err := config.SetupConfig(); err != nil {
 		logger.Fatalf("config SetupConfig() error: %s", err)
 	}
+	config.SetDefaults()
 
 	config.InitialiseOAuthGoogle()
 
+	// fail fast rather than on the first registration or login
+	hasher, err := password.NewHasherFromConfig()
+	if err != nil {
+		logger.Fatalf("Invalid password hasher: %v", err)
+	}
+	password.SetHasher(hasher)
+
 	masterDSN, replicaDSN := config.DbConfiguration()
 
 	if err := database.DbConnection(masterDSN, replicaDSN); err != nil {
@@ -32,6 +42,8 @@ func main() {
 	//later separate migration
 	migrations.Migrate()
 
+	jobs.StartJobs()
+
 	router := routers.SetupRoute()
 	logger.Fatalf("%v", router.Run(config.ServerConfig()))
 
@@ -2,29 +2,163 @@ package migrations
 
 import (
 	"github.com/anirudhgray/mood-harbour-backend/infra/database"
+	"github.com/anirudhgray/mood-harbour-backend/infra/logger"
 	"github.com/anirudhgray/mood-harbour-backend/models"
+	"github.com/anirudhgray/mood-harbour-backend/repository"
+	"github.com/anirudhgray/mood-harbour-backend/utils/markdown"
+	"gorm.io/gorm"
 )
 
 // Migrate Add list of model add for migrations
 func Migrate() {
 	var migrationModels = []interface{}{
 		&models.User{},
-		&models.VerificationEntry{},
-		&models.ForgotPassword{},
-		&models.DeletionConfirmation{},
+		&models.OneTimeToken{},
 		&models.PasswordAuth{},
 		&models.AuthProvider{},
+		&models.Session{},
+		&models.RefreshToken{},
+		&models.TOTPFactor{},
+		&models.RecoveryCode{},
+		&models.MFAChallenge{},
+		&models.SocialLoginCode{},
+		&models.Passkey{},
+		&models.PasskeyChallenge{},
+		&models.AuthThrottle{},
 		&models.Mood{},
 		&models.MoodAttribute{},
 		&models.Attribute{},
 		&models.Resource{},
 		&models.Review{},
+		&models.ReviewVote{},
+		&models.ResourceMoodTag{},
+		&models.Report{},
+		&models.Bookmark{},
+		&models.Collection{},
+		&models.CollectionItem{},
+		&models.CollectionFollow{},
+		&models.UserSimilarity{},
+		&models.UserRecommendation{},
+		&models.RecommendationStatus{},
+		&models.RecommendationDismissal{},
+		&models.CuratedResource{},
+		&models.FactorizationModel{},
+		&models.UserFactor{},
+		&models.ResourceFactor{},
 	}
 	err := database.DB.AutoMigrate(migrationModels...)
 	if err != nil {
 		return
 	}
 
+	backfillRenderedMarkdown()
+	backfillHelpfulnessScores()
+	mapAdminsToRoles()
+	dropPlaintextOTPTables()
+
 	// Remove the 'Password' field from the 'users' table
 	// database.DB.Migrator().DropColumn(&models.User{}, "password")
 }
+
+// mapAdminsToRoles gives users who were admins before roles were added the admin role, and drops the old admin flag.
+func mapAdminsToRoles() {
+	if !database.DB.Migrator().HasColumn(&models.User{}, "admin") {
+		return
+	}
+
+	if err := database.DB.Model(&models.User{}).Where("admin = ?", true).Update("role", models.AdminRole).Error; err != nil {
+		logger.Errorf("Failed to map admins to roles: %v", err)
+		return
+	}
+	if err := database.DB.Migrator().DropColumn(&models.User{}, "admin"); err != nil {
+		logger.Errorf("Failed to drop the admin column: %v", err)
+	}
+}
+
+// backfillHelpfulnessScores stores the helpfulness scores of reviews voted on before scores were stored on reviews. Only
+// reviews with a helpful vote can score above 0, so those still at 0 are the ones which haven't been scored yet.
+func backfillHelpfulnessScores() {
+	var reviewIDs []uint
+	err := database.DB.Model(&models.ReviewVote{}).
+		Where("helpful = ? AND review_id IN (?)", true, database.DB.Model(&models.Review{}).Select("id").Where("helpfulness_score = 0")).
+		Distinct().
+		Pluck("review_id", &reviewIDs).Error
+	if err != nil {
+		logger.Errorf("Failed to find reviews to score: %v", err)
+		return
+	}
+
+	for _, reviewID := range reviewIDs {
+		if err := repository.UpdateHelpfulnessScore(database.DB, reviewID); err != nil {
+			logger.Errorf("Failed to backfill helpfulness score of review %d: %v", reviewID, err)
+			return
+		}
+	}
+}
+
+// dropPlaintextOTPTables drops the tables which stored codes in plaintext before one-time tokens replaced them.
+// Codes sent before then stop working, users can request new ones.
+func dropPlaintextOTPTables() {
+	for _, table := range []string{"verification_entries", "forgot_passwords", "deletion_confirmations"} {
+		if !database.DB.Migrator().HasTable(table) {
+			continue
+		}
+		if err := database.DB.Migrator().DropTable(table); err != nil {
+			logger.Errorf("Failed to drop the %s table: %v", table, err)
+		}
+	}
+}
+
+// backfillRenderedMarkdown renders the sanitized HTML for resources, reviews and mood notes created before Markdown support was added.
+func backfillRenderedMarkdown() {
+	var resources []models.Resource
+	err := database.DB.Where("content <> '' AND (content_html IS NULL OR content_html = '')").FindInBatches(&resources, 100, func(tx *gorm.DB, batch int) error {
+		for _, resource := range resources {
+			contentHTML, err := markdown.Render(resource.Content)
+			if err != nil {
+				return err
+			}
+			if err := tx.Model(&models.Resource{}).Where("id = ?", resource.ID).Update("content_html", contentHTML).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	}).Error
+	if err != nil {
+		logger.Errorf("Failed to backfill resource HTML: %v", err)
+	}
+
+	var reviews []models.Review
+	err = database.DB.Where("content <> '' AND (content_html IS NULL OR content_html = '')").FindInBatches(&reviews, 100, func(tx *gorm.DB, batch int) error {
+		for _, review := range reviews {
+			contentHTML, err := markdown.Render(review.Content)
+			if err != nil {
+				return err
+			}
+			if err := tx.Model(&models.Review{}).Where("id = ?", review.ID).Update("content_html", contentHTML).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	}).Error
+	if err != nil {
+		logger.Errorf("Failed to backfill review HTML: %v", err)
+	}
+
+	var moods []models.Mood
+	err = database.DB.Where("notes <> '' AND (notes_html IS NULL OR notes_html = '')").FindInBatches(&moods, 100, func(tx *gorm.DB, batch int) error {
+		for _, mood := range moods {
+			notesHTML, err := markdown.Render(mood.Notes)
+			if err != nil {
+				return err
+			}
+			if err := tx.Model(&models.Mood{}).Where("id = ?", mood.ID).Update("notes_html", notesHTML).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	}).Error
+	if err != nil {
+		logger.Errorf("Failed to backfill mood notes HTML: %v", err)
+	}
+}
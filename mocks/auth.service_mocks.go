@@ -8,6 +8,7 @@ import (
 	reflect "reflect"
 
 	models "github.com/anirudhgray/mood-harbour-backend/models"
+	webauthn "github.com/anirudhgray/mood-harbour-backend/utils/webauthn"
 	gomock "github.com/golang/mock/gomock"
 )
 
@@ -34,18 +35,166 @@ func (m *MockAuthServiceInterface) EXPECT() *MockAuthServiceInterfaceMockRecorde
 	return m.recorder
 }
 
+// BeginPasskeyLogin mocks base method.
+func (m *MockAuthServiceInterface) BeginPasskeyLogin() (webauthn.RequestOptions, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BeginPasskeyLogin")
+	ret0, _ := ret[0].(webauthn.RequestOptions)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BeginPasskeyLogin indicates an expected call of BeginPasskeyLogin.
+func (mr *MockAuthServiceInterfaceMockRecorder) BeginPasskeyLogin() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeginPasskeyLogin", reflect.TypeOf((*MockAuthServiceInterface)(nil).BeginPasskeyLogin))
+}
+
+// BeginPasskeyRegistration mocks base method.
+func (m *MockAuthServiceInterface) BeginPasskeyRegistration(user models.User) (webauthn.CreationOptions, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BeginPasskeyRegistration", user)
+	ret0, _ := ret[0].(webauthn.CreationOptions)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BeginPasskeyRegistration indicates an expected call of BeginPasskeyRegistration.
+func (mr *MockAuthServiceInterfaceMockRecorder) BeginPasskeyRegistration(user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeginPasskeyRegistration", reflect.TypeOf((*MockAuthServiceInterface)(nil).BeginPasskeyRegistration), user)
+}
+
+// BeginTOTPEnrollment mocks base method.
+func (m *MockAuthServiceInterface) BeginTOTPEnrollment(user models.User) (models.TOTPEnrollment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BeginTOTPEnrollment", user)
+	ret0, _ := ret[0].(models.TOTPEnrollment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BeginTOTPEnrollment indicates an expected call of BeginTOTPEnrollment.
+func (mr *MockAuthServiceInterfaceMockRecorder) BeginTOTPEnrollment(user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeginTOTPEnrollment", reflect.TypeOf((*MockAuthServiceInterface)(nil).BeginTOTPEnrollment), user)
+}
+
+// CompleteMFALogin mocks base method.
+func (m *MockAuthServiceInterface) CompleteMFALogin(mfaToken, code string, client models.ClientInfo) (models.LoginResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteMFALogin", mfaToken, code, client)
+	ret0, _ := ret[0].(models.LoginResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CompleteMFALogin indicates an expected call of CompleteMFALogin.
+func (mr *MockAuthServiceInterfaceMockRecorder) CompleteMFALogin(mfaToken, code, client interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteMFALogin", reflect.TypeOf((*MockAuthServiceInterface)(nil).CompleteMFALogin), mfaToken, code, client)
+}
+
+// ConfirmTOTPEnrollment mocks base method.
+func (m *MockAuthServiceInterface) ConfirmTOTPEnrollment(user models.User, code string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfirmTOTPEnrollment", user, code)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConfirmTOTPEnrollment indicates an expected call of ConfirmTOTPEnrollment.
+func (mr *MockAuthServiceInterfaceMockRecorder) ConfirmTOTPEnrollment(user, code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmTOTPEnrollment", reflect.TypeOf((*MockAuthServiceInterface)(nil).ConfirmTOTPEnrollment), user, code)
+}
+
 // DeleteAccount mocks base method.
-func (m *MockAuthServiceInterface) DeleteAccount(email, otp string) error {
+func (m *MockAuthServiceInterface) DeleteAccount(email, otp string, client models.ClientInfo) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteAccount", email, otp)
+	ret := m.ctrl.Call(m, "DeleteAccount", email, otp, client)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteAccount indicates an expected call of DeleteAccount.
-func (mr *MockAuthServiceInterfaceMockRecorder) DeleteAccount(email, otp interface{}) *gomock.Call {
+func (mr *MockAuthServiceInterfaceMockRecorder) DeleteAccount(email, otp, client interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAccount", reflect.TypeOf((*MockAuthServiceInterface)(nil).DeleteAccount), email, otp, client)
+}
+
+// DeletePasskey mocks base method.
+func (m *MockAuthServiceInterface) DeletePasskey(user models.User, passkeyID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePasskey", user, passkeyID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeletePasskey indicates an expected call of DeletePasskey.
+func (mr *MockAuthServiceInterfaceMockRecorder) DeletePasskey(user, passkeyID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePasskey", reflect.TypeOf((*MockAuthServiceInterface)(nil).DeletePasskey), user, passkeyID)
+}
+
+// DisableTOTP mocks base method.
+func (m *MockAuthServiceInterface) DisableTOTP(user models.User, code string, client models.ClientInfo) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisableTOTP", user, code, client)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DisableTOTP indicates an expected call of DisableTOTP.
+func (mr *MockAuthServiceInterfaceMockRecorder) DisableTOTP(user, code, client interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableTOTP", reflect.TypeOf((*MockAuthServiceInterface)(nil).DisableTOTP), user, code, client)
+}
+
+// ExchangeSocialLoginCode mocks base method.
+func (m *MockAuthServiceInterface) ExchangeSocialLoginCode(code string, client models.ClientInfo) (models.LoginResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExchangeSocialLoginCode", code, client)
+	ret0, _ := ret[0].(models.LoginResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExchangeSocialLoginCode indicates an expected call of ExchangeSocialLoginCode.
+func (mr *MockAuthServiceInterfaceMockRecorder) ExchangeSocialLoginCode(code, client interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExchangeSocialLoginCode", reflect.TypeOf((*MockAuthServiceInterface)(nil).ExchangeSocialLoginCode), code, client)
+}
+
+// FinishPasskeyLogin mocks base method.
+func (m *MockAuthServiceInterface) FinishPasskeyLogin(response webauthn.AssertionResponse, client models.ClientInfo) (models.LoginResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinishPasskeyLogin", response, client)
+	ret0, _ := ret[0].(models.LoginResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FinishPasskeyLogin indicates an expected call of FinishPasskeyLogin.
+func (mr *MockAuthServiceInterfaceMockRecorder) FinishPasskeyLogin(response, client interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishPasskeyLogin", reflect.TypeOf((*MockAuthServiceInterface)(nil).FinishPasskeyLogin), response, client)
+}
+
+// FinishPasskeyRegistration mocks base method.
+func (m *MockAuthServiceInterface) FinishPasskeyRegistration(user models.User, name string, response webauthn.AttestationResponse) (models.PasskeyView, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinishPasskeyRegistration", user, name, response)
+	ret0, _ := ret[0].(models.PasskeyView)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FinishPasskeyRegistration indicates an expected call of FinishPasskeyRegistration.
+func (mr *MockAuthServiceInterfaceMockRecorder) FinishPasskeyRegistration(user, name, response interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAccount", reflect.TypeOf((*MockAuthServiceInterface)(nil).DeleteAccount), email, otp)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishPasskeyRegistration", reflect.TypeOf((*MockAuthServiceInterface)(nil).FinishPasskeyRegistration), user, name, response)
 }
 
 // ForgotPasswordRequest mocks base method.
@@ -62,20 +211,109 @@ func (mr *MockAuthServiceInterfaceMockRecorder) ForgotPasswordRequest(email inte
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForgotPasswordRequest", reflect.TypeOf((*MockAuthServiceInterface)(nil).ForgotPasswordRequest), email)
 }
 
-// LoginUser mocks base method.
-func (m *MockAuthServiceInterface) LoginUser(email, password string) (string, models.User, error) {
+// GetMFAStatus mocks base method.
+func (m *MockAuthServiceInterface) GetMFAStatus(userID uint) (models.MFAStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMFAStatus", userID)
+	ret0, _ := ret[0].(models.MFAStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMFAStatus indicates an expected call of GetMFAStatus.
+func (mr *MockAuthServiceInterfaceMockRecorder) GetMFAStatus(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMFAStatus", reflect.TypeOf((*MockAuthServiceInterface)(nil).GetMFAStatus), userID)
+}
+
+// GetPasskeys mocks base method.
+func (m *MockAuthServiceInterface) GetPasskeys(userID uint) ([]models.PasskeyView, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPasskeys", userID)
+	ret0, _ := ret[0].([]models.PasskeyView)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPasskeys indicates an expected call of GetPasskeys.
+func (mr *MockAuthServiceInterfaceMockRecorder) GetPasskeys(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPasskeys", reflect.TypeOf((*MockAuthServiceInterface)(nil).GetPasskeys), userID)
+}
+
+// GetSessions mocks base method.
+func (m *MockAuthServiceInterface) GetSessions(userID, currentSessionID uint) ([]models.SessionView, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "LoginUser", email, password)
+	ret := m.ctrl.Call(m, "GetSessions", userID, currentSessionID)
+	ret0, _ := ret[0].([]models.SessionView)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSessions indicates an expected call of GetSessions.
+func (mr *MockAuthServiceInterfaceMockRecorder) GetSessions(userID, currentSessionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSessions", reflect.TypeOf((*MockAuthServiceInterface)(nil).GetSessions), userID, currentSessionID)
+}
+
+// IssueSocialLoginCode mocks base method.
+func (m *MockAuthServiceInterface) IssueSocialLoginCode(user models.User) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IssueSocialLoginCode", user)
 	ret0, _ := ret[0].(string)
-	ret1, _ := ret[1].(models.User)
-	ret2, _ := ret[2].(error)
-	return ret0, ret1, ret2
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IssueSocialLoginCode indicates an expected call of IssueSocialLoginCode.
+func (mr *MockAuthServiceInterfaceMockRecorder) IssueSocialLoginCode(user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IssueSocialLoginCode", reflect.TypeOf((*MockAuthServiceInterface)(nil).IssueSocialLoginCode), user)
+}
+
+// LoginUser mocks base method.
+func (m *MockAuthServiceInterface) LoginUser(email, password string, client models.ClientInfo) (models.LoginResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoginUser", email, password, client)
+	ret0, _ := ret[0].(models.LoginResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // LoginUser indicates an expected call of LoginUser.
-func (mr *MockAuthServiceInterfaceMockRecorder) LoginUser(email, password interface{}) *gomock.Call {
+func (mr *MockAuthServiceInterfaceMockRecorder) LoginUser(email, password, client interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoginUser", reflect.TypeOf((*MockAuthServiceInterface)(nil).LoginUser), email, password, client)
+}
+
+// RefreshTokens mocks base method.
+func (m *MockAuthServiceInterface) RefreshTokens(refreshToken string, client models.ClientInfo) (models.AuthTokens, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshTokens", refreshToken, client)
+	ret0, _ := ret[0].(models.AuthTokens)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RefreshTokens indicates an expected call of RefreshTokens.
+func (mr *MockAuthServiceInterfaceMockRecorder) RefreshTokens(refreshToken, client interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshTokens", reflect.TypeOf((*MockAuthServiceInterface)(nil).RefreshTokens), refreshToken, client)
+}
+
+// RegenerateRecoveryCodes mocks base method.
+func (m *MockAuthServiceInterface) RegenerateRecoveryCodes(user models.User, code string, client models.ClientInfo) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegenerateRecoveryCodes", user, code, client)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RegenerateRecoveryCodes indicates an expected call of RegenerateRecoveryCodes.
+func (mr *MockAuthServiceInterfaceMockRecorder) RegenerateRecoveryCodes(user, code, client interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoginUser", reflect.TypeOf((*MockAuthServiceInterface)(nil).LoginUser), email, password)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegenerateRecoveryCodes", reflect.TypeOf((*MockAuthServiceInterface)(nil).RegenerateRecoveryCodes), user, code, client)
 }
 
 // RegisterUser mocks base method.
@@ -122,43 +360,86 @@ func (mr *MockAuthServiceInterfaceMockRecorder) RequestVerificationAgain(email i
 }
 
 // ResetPassword mocks base method.
-func (m *MockAuthServiceInterface) ResetPassword(user models.User, oldPassword, newPassword string) error {
+func (m *MockAuthServiceInterface) ResetPassword(user models.User, currentSessionID uint, oldPassword, newPassword string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ResetPassword", user, oldPassword, newPassword)
+	ret := m.ctrl.Call(m, "ResetPassword", user, currentSessionID, oldPassword, newPassword)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // ResetPassword indicates an expected call of ResetPassword.
-func (mr *MockAuthServiceInterfaceMockRecorder) ResetPassword(user, oldPassword, newPassword interface{}) *gomock.Call {
+func (mr *MockAuthServiceInterfaceMockRecorder) ResetPassword(user, currentSessionID, oldPassword, newPassword interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetPassword", reflect.TypeOf((*MockAuthServiceInterface)(nil).ResetPassword), user, oldPassword, newPassword)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetPassword", reflect.TypeOf((*MockAuthServiceInterface)(nil).ResetPassword), user, currentSessionID, oldPassword, newPassword)
+}
+
+// RevokeAllSessions mocks base method.
+func (m *MockAuthServiceInterface) RevokeAllSessions(userID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAllSessions", userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeAllSessions indicates an expected call of RevokeAllSessions.
+func (mr *MockAuthServiceInterfaceMockRecorder) RevokeAllSessions(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAllSessions", reflect.TypeOf((*MockAuthServiceInterface)(nil).RevokeAllSessions), userID)
+}
+
+// RevokeSession mocks base method.
+func (m *MockAuthServiceInterface) RevokeSession(userID, sessionID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeSession", userID, sessionID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeSession indicates an expected call of RevokeSession.
+func (mr *MockAuthServiceInterfaceMockRecorder) RevokeSession(userID, sessionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeSession", reflect.TypeOf((*MockAuthServiceInterface)(nil).RevokeSession), userID, sessionID)
 }
 
 // SetNewPassword mocks base method.
-func (m *MockAuthServiceInterface) SetNewPassword(email, otp, newPassword string) error {
+func (m *MockAuthServiceInterface) SetNewPassword(email, otp, newPassword string, client models.ClientInfo) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SetNewPassword", email, otp, newPassword)
+	ret := m.ctrl.Call(m, "SetNewPassword", email, otp, newPassword, client)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // SetNewPassword indicates an expected call of SetNewPassword.
-func (mr *MockAuthServiceInterfaceMockRecorder) SetNewPassword(email, otp, newPassword interface{}) *gomock.Call {
+func (mr *MockAuthServiceInterfaceMockRecorder) SetNewPassword(email, otp, newPassword, client interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetNewPassword", reflect.TypeOf((*MockAuthServiceInterface)(nil).SetNewPassword), email, otp, newPassword, client)
+}
+
+// SocialLogin mocks base method.
+func (m *MockAuthServiceInterface) SocialLogin(user models.User, client models.ClientInfo) (models.LoginResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SocialLogin", user, client)
+	ret0, _ := ret[0].(models.LoginResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SocialLogin indicates an expected call of SocialLogin.
+func (mr *MockAuthServiceInterfaceMockRecorder) SocialLogin(user, client interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetNewPassword", reflect.TypeOf((*MockAuthServiceInterface)(nil).SetNewPassword), email, otp, newPassword)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SocialLogin", reflect.TypeOf((*MockAuthServiceInterface)(nil).SocialLogin), user, client)
 }
 
 // VerifyEmail mocks base method.
-func (m *MockAuthServiceInterface) VerifyEmail(email, otp string) error {
+func (m *MockAuthServiceInterface) VerifyEmail(email, otp string, client models.ClientInfo) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "VerifyEmail", email, otp)
+	ret := m.ctrl.Call(m, "VerifyEmail", email, otp, client)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // VerifyEmail indicates an expected call of VerifyEmail.
-func (mr *MockAuthServiceInterfaceMockRecorder) VerifyEmail(email, otp interface{}) *gomock.Call {
+func (mr *MockAuthServiceInterfaceMockRecorder) VerifyEmail(email, otp, client interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyEmail", reflect.TypeOf((*MockAuthServiceInterface)(nil).VerifyEmail), email, otp)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyEmail", reflect.TypeOf((*MockAuthServiceInterface)(nil).VerifyEmail), email, otp, client)
 }
@@ -0,0 +1,280 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: repository/collection.repository.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	models "github.com/anirudhgray/mood-harbour-backend/models"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockCollectionRepositoryInterface is a mock of CollectionRepositoryInterface interface.
+type MockCollectionRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockCollectionRepositoryInterfaceMockRecorder
+}
+
+// MockCollectionRepositoryInterfaceMockRecorder is the mock recorder for MockCollectionRepositoryInterface.
+type MockCollectionRepositoryInterfaceMockRecorder struct {
+	mock *MockCollectionRepositoryInterface
+}
+
+// NewMockCollectionRepositoryInterface creates a new mock instance.
+func NewMockCollectionRepositoryInterface(ctrl *gomock.Controller) *MockCollectionRepositoryInterface {
+	mock := &MockCollectionRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockCollectionRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCollectionRepositoryInterface) EXPECT() *MockCollectionRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// AddCollectionItem mocks base method.
+func (m *MockCollectionRepositoryInterface) AddCollectionItem(item *models.CollectionItem) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddCollectionItem", item)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddCollectionItem indicates an expected call of AddCollectionItem.
+func (mr *MockCollectionRepositoryInterfaceMockRecorder) AddCollectionItem(item interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddCollectionItem", reflect.TypeOf((*MockCollectionRepositoryInterface)(nil).AddCollectionItem), item)
+}
+
+// CountCollectionFollowers mocks base method.
+func (m *MockCollectionRepositoryInterface) CountCollectionFollowers(collectionID uint) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountCollectionFollowers", collectionID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountCollectionFollowers indicates an expected call of CountCollectionFollowers.
+func (mr *MockCollectionRepositoryInterfaceMockRecorder) CountCollectionFollowers(collectionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountCollectionFollowers", reflect.TypeOf((*MockCollectionRepositoryInterface)(nil).CountCollectionFollowers), collectionID)
+}
+
+// CreateBookmark mocks base method.
+func (m *MockCollectionRepositoryInterface) CreateBookmark(bookmark *models.Bookmark) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBookmark", bookmark)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateBookmark indicates an expected call of CreateBookmark.
+func (mr *MockCollectionRepositoryInterfaceMockRecorder) CreateBookmark(bookmark interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBookmark", reflect.TypeOf((*MockCollectionRepositoryInterface)(nil).CreateBookmark), bookmark)
+}
+
+// CreateCollection mocks base method.
+func (m *MockCollectionRepositoryInterface) CreateCollection(collection *models.Collection) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCollection", collection)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateCollection indicates an expected call of CreateCollection.
+func (mr *MockCollectionRepositoryInterfaceMockRecorder) CreateCollection(collection interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCollection", reflect.TypeOf((*MockCollectionRepositoryInterface)(nil).CreateCollection), collection)
+}
+
+// DeleteBookmark mocks base method.
+func (m *MockCollectionRepositoryInterface) DeleteBookmark(userID, resourceID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteBookmark", userID, resourceID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteBookmark indicates an expected call of DeleteBookmark.
+func (mr *MockCollectionRepositoryInterfaceMockRecorder) DeleteBookmark(userID, resourceID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBookmark", reflect.TypeOf((*MockCollectionRepositoryInterface)(nil).DeleteBookmark), userID, resourceID)
+}
+
+// DeleteCollection mocks base method.
+func (m *MockCollectionRepositoryInterface) DeleteCollection(collectionID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCollection", collectionID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCollection indicates an expected call of DeleteCollection.
+func (mr *MockCollectionRepositoryInterfaceMockRecorder) DeleteCollection(collectionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCollection", reflect.TypeOf((*MockCollectionRepositoryInterface)(nil).DeleteCollection), collectionID)
+}
+
+// FollowCollection mocks base method.
+func (m *MockCollectionRepositoryInterface) FollowCollection(follow *models.CollectionFollow) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FollowCollection", follow)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FollowCollection indicates an expected call of FollowCollection.
+func (mr *MockCollectionRepositoryInterfaceMockRecorder) FollowCollection(follow interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FollowCollection", reflect.TypeOf((*MockCollectionRepositoryInterface)(nil).FollowCollection), follow)
+}
+
+// GetBookmarksByUserID mocks base method.
+func (m *MockCollectionRepositoryInterface) GetBookmarksByUserID(userID uint) ([]models.Bookmark, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBookmarksByUserID", userID)
+	ret0, _ := ret[0].([]models.Bookmark)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBookmarksByUserID indicates an expected call of GetBookmarksByUserID.
+func (mr *MockCollectionRepositoryInterfaceMockRecorder) GetBookmarksByUserID(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookmarksByUserID", reflect.TypeOf((*MockCollectionRepositoryInterface)(nil).GetBookmarksByUserID), userID)
+}
+
+// GetCollectionByID mocks base method.
+func (m *MockCollectionRepositoryInterface) GetCollectionByID(collectionID uint) (models.Collection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCollectionByID", collectionID)
+	ret0, _ := ret[0].(models.Collection)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCollectionByID indicates an expected call of GetCollectionByID.
+func (mr *MockCollectionRepositoryInterfaceMockRecorder) GetCollectionByID(collectionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCollectionByID", reflect.TypeOf((*MockCollectionRepositoryInterface)(nil).GetCollectionByID), collectionID)
+}
+
+// GetCollectionItems mocks base method.
+func (m *MockCollectionRepositoryInterface) GetCollectionItems(collectionID uint) ([]models.CollectionItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCollectionItems", collectionID)
+	ret0, _ := ret[0].([]models.CollectionItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCollectionItems indicates an expected call of GetCollectionItems.
+func (mr *MockCollectionRepositoryInterfaceMockRecorder) GetCollectionItems(collectionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCollectionItems", reflect.TypeOf((*MockCollectionRepositoryInterface)(nil).GetCollectionItems), collectionID)
+}
+
+// GetCollectionsByUserID mocks base method.
+func (m *MockCollectionRepositoryInterface) GetCollectionsByUserID(userID uint) ([]models.Collection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCollectionsByUserID", userID)
+	ret0, _ := ret[0].([]models.Collection)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCollectionsByUserID indicates an expected call of GetCollectionsByUserID.
+func (mr *MockCollectionRepositoryInterfaceMockRecorder) GetCollectionsByUserID(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCollectionsByUserID", reflect.TypeOf((*MockCollectionRepositoryInterface)(nil).GetCollectionsByUserID), userID)
+}
+
+// GetFollowedCollections mocks base method.
+func (m *MockCollectionRepositoryInterface) GetFollowedCollections(userID uint) ([]models.Collection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFollowedCollections", userID)
+	ret0, _ := ret[0].([]models.Collection)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFollowedCollections indicates an expected call of GetFollowedCollections.
+func (mr *MockCollectionRepositoryInterfaceMockRecorder) GetFollowedCollections(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFollowedCollections", reflect.TypeOf((*MockCollectionRepositoryInterface)(nil).GetFollowedCollections), userID)
+}
+
+// GetPublicCollections mocks base method.
+func (m *MockCollectionRepositoryInterface) GetPublicCollections() ([]models.Collection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPublicCollections")
+	ret0, _ := ret[0].([]models.Collection)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPublicCollections indicates an expected call of GetPublicCollections.
+func (mr *MockCollectionRepositoryInterfaceMockRecorder) GetPublicCollections() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPublicCollections", reflect.TypeOf((*MockCollectionRepositoryInterface)(nil).GetPublicCollections))
+}
+
+// RemoveCollectionItem mocks base method.
+func (m *MockCollectionRepositoryInterface) RemoveCollectionItem(collectionID, resourceID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveCollectionItem", collectionID, resourceID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveCollectionItem indicates an expected call of RemoveCollectionItem.
+func (mr *MockCollectionRepositoryInterfaceMockRecorder) RemoveCollectionItem(collectionID, resourceID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveCollectionItem", reflect.TypeOf((*MockCollectionRepositoryInterface)(nil).RemoveCollectionItem), collectionID, resourceID)
+}
+
+// ReorderCollectionItems mocks base method.
+func (m *MockCollectionRepositoryInterface) ReorderCollectionItems(collectionID uint, resourceIDs []uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReorderCollectionItems", collectionID, resourceIDs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReorderCollectionItems indicates an expected call of ReorderCollectionItems.
+func (mr *MockCollectionRepositoryInterfaceMockRecorder) ReorderCollectionItems(collectionID, resourceIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReorderCollectionItems", reflect.TypeOf((*MockCollectionRepositoryInterface)(nil).ReorderCollectionItems), collectionID, resourceIDs)
+}
+
+// UnfollowCollection mocks base method.
+func (m *MockCollectionRepositoryInterface) UnfollowCollection(collectionID, userID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnfollowCollection", collectionID, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnfollowCollection indicates an expected call of UnfollowCollection.
+func (mr *MockCollectionRepositoryInterfaceMockRecorder) UnfollowCollection(collectionID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnfollowCollection", reflect.TypeOf((*MockCollectionRepositoryInterface)(nil).UnfollowCollection), collectionID, userID)
+}
+
+// UpdateCollection mocks base method.
+func (m *MockCollectionRepositoryInterface) UpdateCollection(collection *models.Collection) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCollection", collection)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateCollection indicates an expected call of UpdateCollection.
+func (mr *MockCollectionRepositoryInterfaceMockRecorder) UpdateCollection(collection interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCollection", reflect.TypeOf((*MockCollectionRepositoryInterface)(nil).UpdateCollection), collection)
+}
@@ -0,0 +1,252 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: services/collection.service.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	models "github.com/anirudhgray/mood-harbour-backend/models"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockCollectionServiceInterface is a mock of CollectionServiceInterface interface.
+type MockCollectionServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockCollectionServiceInterfaceMockRecorder
+}
+
+// MockCollectionServiceInterfaceMockRecorder is the mock recorder for MockCollectionServiceInterface.
+type MockCollectionServiceInterfaceMockRecorder struct {
+	mock *MockCollectionServiceInterface
+}
+
+// NewMockCollectionServiceInterface creates a new mock instance.
+func NewMockCollectionServiceInterface(ctrl *gomock.Controller) *MockCollectionServiceInterface {
+	mock := &MockCollectionServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockCollectionServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCollectionServiceInterface) EXPECT() *MockCollectionServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// AddToCollection mocks base method.
+func (m *MockCollectionServiceInterface) AddToCollection(userID, collectionID, resourceID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddToCollection", userID, collectionID, resourceID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddToCollection indicates an expected call of AddToCollection.
+func (mr *MockCollectionServiceInterfaceMockRecorder) AddToCollection(userID, collectionID, resourceID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddToCollection", reflect.TypeOf((*MockCollectionServiceInterface)(nil).AddToCollection), userID, collectionID, resourceID)
+}
+
+// BookmarkResource mocks base method.
+func (m *MockCollectionServiceInterface) BookmarkResource(userID, resourceID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BookmarkResource", userID, resourceID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BookmarkResource indicates an expected call of BookmarkResource.
+func (mr *MockCollectionServiceInterfaceMockRecorder) BookmarkResource(userID, resourceID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BookmarkResource", reflect.TypeOf((*MockCollectionServiceInterface)(nil).BookmarkResource), userID, resourceID)
+}
+
+// CreateCollection mocks base method.
+func (m *MockCollectionServiceInterface) CreateCollection(userID uint, name, description string, public bool) (models.CollectionResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCollection", userID, name, description, public)
+	ret0, _ := ret[0].(models.CollectionResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCollection indicates an expected call of CreateCollection.
+func (mr *MockCollectionServiceInterfaceMockRecorder) CreateCollection(userID, name, description, public interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCollection", reflect.TypeOf((*MockCollectionServiceInterface)(nil).CreateCollection), userID, name, description, public)
+}
+
+// DeleteCollection mocks base method.
+func (m *MockCollectionServiceInterface) DeleteCollection(userID, collectionID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCollection", userID, collectionID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCollection indicates an expected call of DeleteCollection.
+func (mr *MockCollectionServiceInterfaceMockRecorder) DeleteCollection(userID, collectionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCollection", reflect.TypeOf((*MockCollectionServiceInterface)(nil).DeleteCollection), userID, collectionID)
+}
+
+// FollowCollection mocks base method.
+func (m *MockCollectionServiceInterface) FollowCollection(userID, collectionID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FollowCollection", userID, collectionID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FollowCollection indicates an expected call of FollowCollection.
+func (mr *MockCollectionServiceInterfaceMockRecorder) FollowCollection(userID, collectionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FollowCollection", reflect.TypeOf((*MockCollectionServiceInterface)(nil).FollowCollection), userID, collectionID)
+}
+
+// GetBookmarkedResources mocks base method.
+func (m *MockCollectionServiceInterface) GetBookmarkedResources(userID uint) ([]models.Resource, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBookmarkedResources", userID)
+	ret0, _ := ret[0].([]models.Resource)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBookmarkedResources indicates an expected call of GetBookmarkedResources.
+func (mr *MockCollectionServiceInterfaceMockRecorder) GetBookmarkedResources(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookmarkedResources", reflect.TypeOf((*MockCollectionServiceInterface)(nil).GetBookmarkedResources), userID)
+}
+
+// GetCollection mocks base method.
+func (m *MockCollectionServiceInterface) GetCollection(userID, collectionID uint) (models.CollectionResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCollection", userID, collectionID)
+	ret0, _ := ret[0].(models.CollectionResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCollection indicates an expected call of GetCollection.
+func (mr *MockCollectionServiceInterfaceMockRecorder) GetCollection(userID, collectionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCollection", reflect.TypeOf((*MockCollectionServiceInterface)(nil).GetCollection), userID, collectionID)
+}
+
+// GetFollowedCollections mocks base method.
+func (m *MockCollectionServiceInterface) GetFollowedCollections(userID uint) ([]models.CollectionResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFollowedCollections", userID)
+	ret0, _ := ret[0].([]models.CollectionResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFollowedCollections indicates an expected call of GetFollowedCollections.
+func (mr *MockCollectionServiceInterfaceMockRecorder) GetFollowedCollections(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFollowedCollections", reflect.TypeOf((*MockCollectionServiceInterface)(nil).GetFollowedCollections), userID)
+}
+
+// GetPublicCollections mocks base method.
+func (m *MockCollectionServiceInterface) GetPublicCollections() ([]models.CollectionResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPublicCollections")
+	ret0, _ := ret[0].([]models.CollectionResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPublicCollections indicates an expected call of GetPublicCollections.
+func (mr *MockCollectionServiceInterfaceMockRecorder) GetPublicCollections() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPublicCollections", reflect.TypeOf((*MockCollectionServiceInterface)(nil).GetPublicCollections))
+}
+
+// GetUserCollections mocks base method.
+func (m *MockCollectionServiceInterface) GetUserCollections(userID uint) ([]models.CollectionResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserCollections", userID)
+	ret0, _ := ret[0].([]models.CollectionResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserCollections indicates an expected call of GetUserCollections.
+func (mr *MockCollectionServiceInterfaceMockRecorder) GetUserCollections(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserCollections", reflect.TypeOf((*MockCollectionServiceInterface)(nil).GetUserCollections), userID)
+}
+
+// RemoveBookmark mocks base method.
+func (m *MockCollectionServiceInterface) RemoveBookmark(userID, resourceID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveBookmark", userID, resourceID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveBookmark indicates an expected call of RemoveBookmark.
+func (mr *MockCollectionServiceInterfaceMockRecorder) RemoveBookmark(userID, resourceID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveBookmark", reflect.TypeOf((*MockCollectionServiceInterface)(nil).RemoveBookmark), userID, resourceID)
+}
+
+// RemoveFromCollection mocks base method.
+func (m *MockCollectionServiceInterface) RemoveFromCollection(userID, collectionID, resourceID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveFromCollection", userID, collectionID, resourceID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveFromCollection indicates an expected call of RemoveFromCollection.
+func (mr *MockCollectionServiceInterfaceMockRecorder) RemoveFromCollection(userID, collectionID, resourceID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveFromCollection", reflect.TypeOf((*MockCollectionServiceInterface)(nil).RemoveFromCollection), userID, collectionID, resourceID)
+}
+
+// ReorderCollection mocks base method.
+func (m *MockCollectionServiceInterface) ReorderCollection(userID, collectionID uint, resourceIDs []uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReorderCollection", userID, collectionID, resourceIDs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReorderCollection indicates an expected call of ReorderCollection.
+func (mr *MockCollectionServiceInterfaceMockRecorder) ReorderCollection(userID, collectionID, resourceIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReorderCollection", reflect.TypeOf((*MockCollectionServiceInterface)(nil).ReorderCollection), userID, collectionID, resourceIDs)
+}
+
+// UnfollowCollection mocks base method.
+func (m *MockCollectionServiceInterface) UnfollowCollection(userID, collectionID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnfollowCollection", userID, collectionID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnfollowCollection indicates an expected call of UnfollowCollection.
+func (mr *MockCollectionServiceInterfaceMockRecorder) UnfollowCollection(userID, collectionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnfollowCollection", reflect.TypeOf((*MockCollectionServiceInterface)(nil).UnfollowCollection), userID, collectionID)
+}
+
+// UpdateCollection mocks base method.
+func (m *MockCollectionServiceInterface) UpdateCollection(userID, collectionID uint, name, description string, public bool) (models.CollectionResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCollection", userID, collectionID, name, description, public)
+	ret0, _ := ret[0].(models.CollectionResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateCollection indicates an expected call of UpdateCollection.
+func (mr *MockCollectionServiceInterfaceMockRecorder) UpdateCollection(userID, collectionID, name, description, public interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCollection", reflect.TypeOf((*MockCollectionServiceInterface)(nil).UpdateCollection), userID, collectionID, name, description, public)
+}
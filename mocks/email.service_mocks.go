@@ -1,6 +1,8 @@
 package mocks
 
 import (
+	"time"
+
 	"github.com/golang/mock/gomock"
 )
 
@@ -30,22 +32,22 @@ func (m *MockEmailService) GenericSendMail(subject string, content string, toEma
 }
 
 // SendRegistrationMail mocks the SendRegistrationMail method.
-func (m *MockEmailService) SendRegistrationMail(subject string, content string, toEmail string, userName string, newUser bool) error {
-	ret := m.ctrl.Call(m, "SendRegistrationMail", subject, content, toEmail, userName, newUser)
+func (m *MockEmailService) SendRegistrationMail(subject string, content string, toEmail string, userName string, otp string) error {
+	ret := m.ctrl.Call(m, "SendRegistrationMail", subject, content, toEmail, userName, otp)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // SendForgotPasswordMail mocks the SendForgotPasswordMail method.
-func (m *MockEmailService) SendForgotPasswordMail(toEmail string, userName string) error {
-	ret := m.ctrl.Call(m, "SendForgotPasswordMail", toEmail, userName)
+func (m *MockEmailService) SendForgotPasswordMail(toEmail string, userName string, otp string, validFor time.Duration) error {
+	ret := m.ctrl.Call(m, "SendForgotPasswordMail", toEmail, userName, otp, validFor)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // SendDeletionMail mocks the SendDeletionMail method.
-func (m *MockEmailService) SendDeletionMail(toEmail string, userName string) error {
-	ret := m.ctrl.Call(m, "SendDeletionMail", toEmail, userName)
+func (m *MockEmailService) SendDeletionMail(toEmail string, userName string, otp string, validFor time.Duration) error {
+	ret := m.ctrl.Call(m, "SendDeletionMail", toEmail, userName, otp, validFor)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
@@ -60,17 +62,17 @@ func (m *MockEmailServiceMockRecorder) GenericSendMail(subject string, content s
 	return m.mock.ctrl.RecordCall(m.mock, "GenericSendMail", subject, content, toEmail, userName)
 }
 
-// SendRegistrationMail mocks the SendRegistrationMail method.
-func (m *MockEmailServiceMockRecorder) SendRegistrationMail(subject string, content string, toEmail string, userName string, newUser bool) *gomock.Call {
-	return m.mock.ctrl.RecordCall(m.mock, "SendRegistrationMail", subject, content, toEmail, userName, newUser)
+// SendRegistrationMail mocks the SendRegistrationMail method. The code is random, so it's matched with a matcher.
+func (m *MockEmailServiceMockRecorder) SendRegistrationMail(subject string, content string, toEmail string, userName string, otp interface{}) *gomock.Call {
+	return m.mock.ctrl.RecordCall(m.mock, "SendRegistrationMail", subject, content, toEmail, userName, otp)
 }
 
 // SendForgotPasswordMail mocks the SendForgotPasswordMail method.
-func (m *MockEmailServiceMockRecorder) SendForgotPasswordMail(toEmail string, userName string) *gomock.Call {
-	return m.mock.ctrl.RecordCall(m.mock, "SendForgotPasswordMail", toEmail, userName)
+func (m *MockEmailServiceMockRecorder) SendForgotPasswordMail(toEmail string, userName string, otp interface{}, validFor interface{}) *gomock.Call {
+	return m.mock.ctrl.RecordCall(m.mock, "SendForgotPasswordMail", toEmail, userName, otp, validFor)
 }
 
 // SendDeletionMail mocks the SendDeletionMail method.
-func (m *MockEmailServiceMockRecorder) SendDeletionMail(toEmail string, userName string) *gomock.Call {
-	return m.mock.ctrl.RecordCall(m.mock, "SendDeletionMail", toEmail, userName)
+func (m *MockEmailServiceMockRecorder) SendDeletionMail(toEmail string, userName string, otp interface{}, validFor interface{}) *gomock.Call {
+	return m.mock.ctrl.RecordCall(m.mock, "SendDeletionMail", toEmail, userName, otp, validFor)
 }
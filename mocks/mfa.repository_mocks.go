@@ -0,0 +1,195 @@
+package mocks
+
+import (
+	"time"
+
+	"github.com/anirudhgray/mood-harbour-backend/models"
+	"github.com/golang/mock/gomock"
+)
+
+// MockMFARepository is a mock for MFARepositoryInterface.
+type MockMFARepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockMFARepositoryMockRecorder
+}
+
+// NewMockMFARepository creates a new mock for MFARepositoryInterface.
+func NewMockMFARepository(ctrl *gomock.Controller) *MockMFARepository {
+	mock := &MockMFARepository{ctrl: ctrl}
+	mock.recorder = &MockMFARepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT methods for expected calls with return values
+func (m *MockMFARepository) EXPECT() *MockMFARepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetTOTPFactor mocks the GetTOTPFactor method.
+func (m *MockMFARepository) GetTOTPFactor(userID uint) (models.TOTPFactor, error) {
+	ret := m.ctrl.Call(m, "GetTOTPFactor", userID)
+	factor, _ := ret[0].(models.TOTPFactor)
+	err, _ := ret[1].(error)
+	return factor, err
+}
+
+// SaveTOTPFactor mocks the SaveTOTPFactor method.
+func (m *MockMFARepository) SaveTOTPFactor(factor *models.TOTPFactor) error {
+	ret := m.ctrl.Call(m, "SaveTOTPFactor", factor)
+	err, _ := ret[0].(error)
+	return err
+}
+
+// ConfirmTOTPFactor mocks the ConfirmTOTPFactor method.
+func (m *MockMFARepository) ConfirmTOTPFactor(id uint, at time.Time) error {
+	ret := m.ctrl.Call(m, "ConfirmTOTPFactor", id, at)
+	err, _ := ret[0].(error)
+	return err
+}
+
+// UseTOTPStep mocks the UseTOTPStep method.
+func (m *MockMFARepository) UseTOTPStep(id uint, step int64) (bool, error) {
+	ret := m.ctrl.Call(m, "UseTOTPStep", id, step)
+	ok, _ := ret[0].(bool)
+	err, _ := ret[1].(error)
+	return ok, err
+}
+
+// DeleteTOTPFactor mocks the DeleteTOTPFactor method.
+func (m *MockMFARepository) DeleteTOTPFactor(userID uint) error {
+	ret := m.ctrl.Call(m, "DeleteTOTPFactor", userID)
+	err, _ := ret[0].(error)
+	return err
+}
+
+// ReplaceRecoveryCodes mocks the ReplaceRecoveryCodes method.
+func (m *MockMFARepository) ReplaceRecoveryCodes(userID uint, codes []models.RecoveryCode) error {
+	ret := m.ctrl.Call(m, "ReplaceRecoveryCodes", userID, codes)
+	err, _ := ret[0].(error)
+	return err
+}
+
+// UseRecoveryCode mocks the UseRecoveryCode method.
+func (m *MockMFARepository) UseRecoveryCode(userID uint, codeHash string, at time.Time) (bool, error) {
+	ret := m.ctrl.Call(m, "UseRecoveryCode", userID, codeHash, at)
+	ok, _ := ret[0].(bool)
+	err, _ := ret[1].(error)
+	return ok, err
+}
+
+// CountRecoveryCodes mocks the CountRecoveryCodes method.
+func (m *MockMFARepository) CountRecoveryCodes(userID uint) (int64, error) {
+	ret := m.ctrl.Call(m, "CountRecoveryCodes", userID)
+	count, _ := ret[0].(int64)
+	err, _ := ret[1].(error)
+	return count, err
+}
+
+// CreateMFAChallenge mocks the CreateMFAChallenge method.
+func (m *MockMFARepository) CreateMFAChallenge(challenge *models.MFAChallenge) error {
+	ret := m.ctrl.Call(m, "CreateMFAChallenge", challenge)
+	err, _ := ret[0].(error)
+	return err
+}
+
+// GetMFAChallengeByHash mocks the GetMFAChallengeByHash method.
+func (m *MockMFARepository) GetMFAChallengeByHash(tokenHash string) (models.MFAChallenge, error) {
+	ret := m.ctrl.Call(m, "GetMFAChallengeByHash", tokenHash)
+	challenge, _ := ret[0].(models.MFAChallenge)
+	err, _ := ret[1].(error)
+	return challenge, err
+}
+
+// RecordMFAChallengeAttempt mocks the RecordMFAChallengeAttempt method.
+func (m *MockMFARepository) RecordMFAChallengeAttempt(id uint, maxAttempts int) (bool, error) {
+	ret := m.ctrl.Call(m, "RecordMFAChallengeAttempt", id, maxAttempts)
+	ok, _ := ret[0].(bool)
+	err, _ := ret[1].(error)
+	return ok, err
+}
+
+// UseMFAChallenge mocks the UseMFAChallenge method.
+func (m *MockMFARepository) UseMFAChallenge(id uint, at time.Time) (bool, error) {
+	ret := m.ctrl.Call(m, "UseMFAChallenge", id, at)
+	ok, _ := ret[0].(bool)
+	err, _ := ret[1].(error)
+	return ok, err
+}
+
+// PurgeMFAChallenges mocks the PurgeMFAChallenges method.
+func (m *MockMFARepository) PurgeMFAChallenges(before time.Time) (int64, error) {
+	ret := m.ctrl.Call(m, "PurgeMFAChallenges", before)
+	deleted, _ := ret[0].(int64)
+	err, _ := ret[1].(error)
+	return deleted, err
+}
+
+// MockMFARepositoryMockRecorder is a recorder for the MockMFARepository.
+type MockMFARepositoryMockRecorder struct {
+	mock *MockMFARepository
+}
+
+// GetTOTPFactor mocks the GetTOTPFactor method.
+func (m *MockMFARepositoryMockRecorder) GetTOTPFactor(userID interface{}) *gomock.Call {
+	return m.mock.ctrl.RecordCall(m.mock, "GetTOTPFactor", userID)
+}
+
+// SaveTOTPFactor mocks the SaveTOTPFactor method.
+func (m *MockMFARepositoryMockRecorder) SaveTOTPFactor(factor interface{}) *gomock.Call {
+	return m.mock.ctrl.RecordCall(m.mock, "SaveTOTPFactor", factor)
+}
+
+// ConfirmTOTPFactor mocks the ConfirmTOTPFactor method.
+func (m *MockMFARepositoryMockRecorder) ConfirmTOTPFactor(id, at interface{}) *gomock.Call {
+	return m.mock.ctrl.RecordCall(m.mock, "ConfirmTOTPFactor", id, at)
+}
+
+// UseTOTPStep mocks the UseTOTPStep method.
+func (m *MockMFARepositoryMockRecorder) UseTOTPStep(id, step interface{}) *gomock.Call {
+	return m.mock.ctrl.RecordCall(m.mock, "UseTOTPStep", id, step)
+}
+
+// DeleteTOTPFactor mocks the DeleteTOTPFactor method.
+func (m *MockMFARepositoryMockRecorder) DeleteTOTPFactor(userID interface{}) *gomock.Call {
+	return m.mock.ctrl.RecordCall(m.mock, "DeleteTOTPFactor", userID)
+}
+
+// ReplaceRecoveryCodes mocks the ReplaceRecoveryCodes method.
+func (m *MockMFARepositoryMockRecorder) ReplaceRecoveryCodes(userID, codes interface{}) *gomock.Call {
+	return m.mock.ctrl.RecordCall(m.mock, "ReplaceRecoveryCodes", userID, codes)
+}
+
+// UseRecoveryCode mocks the UseRecoveryCode method.
+func (m *MockMFARepositoryMockRecorder) UseRecoveryCode(userID, codeHash, at interface{}) *gomock.Call {
+	return m.mock.ctrl.RecordCall(m.mock, "UseRecoveryCode", userID, codeHash, at)
+}
+
+// CountRecoveryCodes mocks the CountRecoveryCodes method.
+func (m *MockMFARepositoryMockRecorder) CountRecoveryCodes(userID interface{}) *gomock.Call {
+	return m.mock.ctrl.RecordCall(m.mock, "CountRecoveryCodes", userID)
+}
+
+// CreateMFAChallenge mocks the CreateMFAChallenge method.
+func (m *MockMFARepositoryMockRecorder) CreateMFAChallenge(challenge interface{}) *gomock.Call {
+	return m.mock.ctrl.RecordCall(m.mock, "CreateMFAChallenge", challenge)
+}
+
+// GetMFAChallengeByHash mocks the GetMFAChallengeByHash method.
+func (m *MockMFARepositoryMockRecorder) GetMFAChallengeByHash(tokenHash interface{}) *gomock.Call {
+	return m.mock.ctrl.RecordCall(m.mock, "GetMFAChallengeByHash", tokenHash)
+}
+
+// RecordMFAChallengeAttempt mocks the RecordMFAChallengeAttempt method.
+func (m *MockMFARepositoryMockRecorder) RecordMFAChallengeAttempt(id, maxAttempts interface{}) *gomock.Call {
+	return m.mock.ctrl.RecordCall(m.mock, "RecordMFAChallengeAttempt", id, maxAttempts)
+}
+
+// UseMFAChallenge mocks the UseMFAChallenge method.
+func (m *MockMFARepositoryMockRecorder) UseMFAChallenge(id, at interface{}) *gomock.Call {
+	return m.mock.ctrl.RecordCall(m.mock, "UseMFAChallenge", id, at)
+}
+
+// PurgeMFAChallenges mocks the PurgeMFAChallenges method.
+func (m *MockMFARepositoryMockRecorder) PurgeMFAChallenges(before interface{}) *gomock.Call {
+	return m.mock.ctrl.RecordCall(m.mock, "PurgeMFAChallenges", before)
+}
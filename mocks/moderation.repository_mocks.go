@@ -0,0 +1,93 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: repository/moderation.repository.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	models "github.com/anirudhgray/mood-harbour-backend/models"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockModerationRepositoryInterface is a mock of ModerationRepositoryInterface interface.
+type MockModerationRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockModerationRepositoryInterfaceMockRecorder
+}
+
+// MockModerationRepositoryInterfaceMockRecorder is the mock recorder for MockModerationRepositoryInterface.
+type MockModerationRepositoryInterfaceMockRecorder struct {
+	mock *MockModerationRepositoryInterface
+}
+
+// NewMockModerationRepositoryInterface creates a new mock instance.
+func NewMockModerationRepositoryInterface(ctrl *gomock.Controller) *MockModerationRepositoryInterface {
+	mock := &MockModerationRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockModerationRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockModerationRepositoryInterface) EXPECT() *MockModerationRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// CreateReport mocks base method.
+func (m *MockModerationRepositoryInterface) CreateReport(report *models.Report) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateReport", report)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateReport indicates an expected call of CreateReport.
+func (mr *MockModerationRepositoryInterfaceMockRecorder) CreateReport(report interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateReport", reflect.TypeOf((*MockModerationRepositoryInterface)(nil).CreateReport), report)
+}
+
+// GetOpenReports mocks base method.
+func (m *MockModerationRepositoryInterface) GetOpenReports() ([]models.Report, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOpenReports")
+	ret0, _ := ret[0].([]models.Report)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOpenReports indicates an expected call of GetOpenReports.
+func (mr *MockModerationRepositoryInterfaceMockRecorder) GetOpenReports() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOpenReports", reflect.TypeOf((*MockModerationRepositoryInterface)(nil).GetOpenReports))
+}
+
+// HasOpenReport mocks base method.
+func (m *MockModerationRepositoryInterface) HasOpenReport(targetType models.ReportTargetType, targetID, reportedBy uint) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasOpenReport", targetType, targetID, reportedBy)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HasOpenReport indicates an expected call of HasOpenReport.
+func (mr *MockModerationRepositoryInterfaceMockRecorder) HasOpenReport(targetType, targetID, reportedBy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasOpenReport", reflect.TypeOf((*MockModerationRepositoryInterface)(nil).HasOpenReport), targetType, targetID, reportedBy)
+}
+
+// ResolveReports mocks base method.
+func (m *MockModerationRepositoryInterface) ResolveReports(targetType models.ReportTargetType, targetID, resolvedBy uint, action models.ModerationAction) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveReports", targetType, targetID, resolvedBy, action)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResolveReports indicates an expected call of ResolveReports.
+func (mr *MockModerationRepositoryInterfaceMockRecorder) ResolveReports(targetType, targetID, resolvedBy, action interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveReports", reflect.TypeOf((*MockModerationRepositoryInterface)(nil).ResolveReports), targetType, targetID, resolvedBy, action)
+}
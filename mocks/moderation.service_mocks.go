@@ -0,0 +1,106 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: services/moderation.service.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	models "github.com/anirudhgray/mood-harbour-backend/models"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockModerationServiceInterface is a mock of ModerationServiceInterface interface.
+type MockModerationServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockModerationServiceInterfaceMockRecorder
+}
+
+// MockModerationServiceInterfaceMockRecorder is the mock recorder for MockModerationServiceInterface.
+type MockModerationServiceInterfaceMockRecorder struct {
+	mock *MockModerationServiceInterface
+}
+
+// NewMockModerationServiceInterface creates a new mock instance.
+func NewMockModerationServiceInterface(ctrl *gomock.Controller) *MockModerationServiceInterface {
+	mock := &MockModerationServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockModerationServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockModerationServiceInterface) EXPECT() *MockModerationServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// GetModerationQueue mocks base method.
+func (m *MockModerationServiceInterface) GetModerationQueue() ([]models.ModerationQueueItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetModerationQueue")
+	ret0, _ := ret[0].([]models.ModerationQueueItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetModerationQueue indicates an expected call of GetModerationQueue.
+func (mr *MockModerationServiceInterfaceMockRecorder) GetModerationQueue() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetModerationQueue", reflect.TypeOf((*MockModerationServiceInterface)(nil).GetModerationQueue))
+}
+
+// ModerateContent mocks base method.
+func (m *MockModerationServiceInterface) ModerateContent(moderatorID uint, targetType models.ReportTargetType, targetID uint, action models.ModerationAction) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ModerateContent", moderatorID, targetType, targetID, action)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ModerateContent indicates an expected call of ModerateContent.
+func (mr *MockModerationServiceInterfaceMockRecorder) ModerateContent(moderatorID, targetType, targetID, action interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ModerateContent", reflect.TypeOf((*MockModerationServiceInterface)(nil).ModerateContent), moderatorID, targetType, targetID, action)
+}
+
+// QueueForPreModeration mocks base method.
+func (m *MockModerationServiceInterface) QueueForPreModeration(resourceID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueueForPreModeration", resourceID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// QueueForPreModeration indicates an expected call of QueueForPreModeration.
+func (mr *MockModerationServiceInterfaceMockRecorder) QueueForPreModeration(resourceID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueueForPreModeration", reflect.TypeOf((*MockModerationServiceInterface)(nil).QueueForPreModeration), resourceID)
+}
+
+// ReportContent mocks base method.
+func (m *MockModerationServiceInterface) ReportContent(userID uint, targetType models.ReportTargetType, targetID uint, reason string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReportContent", userID, targetType, targetID, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReportContent indicates an expected call of ReportContent.
+func (mr *MockModerationServiceInterfaceMockRecorder) ReportContent(userID, targetType, targetID, reason interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReportContent", reflect.TypeOf((*MockModerationServiceInterface)(nil).ReportContent), userID, targetType, targetID, reason)
+}
+
+// ScreenContent mocks base method.
+func (m *MockModerationServiceInterface) ScreenContent(targetType models.ReportTargetType, targetID uint, text string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ScreenContent", targetType, targetID, text)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ScreenContent indicates an expected call of ScreenContent.
+func (mr *MockModerationServiceInterfaceMockRecorder) ScreenContent(targetType, targetID, text interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScreenContent", reflect.TypeOf((*MockModerationServiceInterface)(nil).ScreenContent), targetType, targetID, text)
+}
@@ -6,6 +6,7 @@ package mocks
 
 import (
 	reflect "reflect"
+	time "time"
 
 	models "github.com/anirudhgray/mood-harbour-backend/models"
 	gomock "github.com/golang/mock/gomock"
@@ -134,6 +135,21 @@ func (mr *MockMoodRepositoryInterfaceMockRecorder) GetAttributes(userID interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAttributes", reflect.TypeOf((*MockMoodRepositoryInterface)(nil).GetAttributes), userID)
 }
 
+// GetLatestMoodSince mocks base method.
+func (m *MockMoodRepositoryInterface) GetLatestMoodSince(userID uint, since time.Time) (models.Mood, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLatestMoodSince", userID, since)
+	ret0, _ := ret[0].(models.Mood)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLatestMoodSince indicates an expected call of GetLatestMoodSince.
+func (mr *MockMoodRepositoryInterfaceMockRecorder) GetLatestMoodSince(userID, since interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatestMoodSince", reflect.TypeOf((*MockMoodRepositoryInterface)(nil).GetLatestMoodSince), userID, since)
+}
+
 // GetMoodAttributesByMoodID mocks base method.
 func (m *MockMoodRepositoryInterface) GetMoodAttributesByMoodID(moodID uint) ([]models.MoodAttribute, error) {
 	m.ctrl.T.Helper()
@@ -224,6 +240,21 @@ func (mr *MockMoodRepositoryInterfaceMockRecorder) GetMoodsByUserIDAndMoodTypeAn
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMoodsByUserIDAndMoodTypeAndDateRange", reflect.TypeOf((*MockMoodRepositoryInterface)(nil).GetMoodsByUserIDAndMoodTypeAndDateRange), userID, moodType, startDate, endDate)
 }
 
+// GetMoodsByUserIDAndOrderedByCreatedAt mocks base method.
+func (m *MockMoodRepositoryInterface) GetMoodsByUserIDAndOrderedByCreatedAt(userID uint) ([]models.Mood, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMoodsByUserIDAndOrderedByCreatedAt", userID)
+	ret0, _ := ret[0].([]models.Mood)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMoodsByUserIDAndOrderedByCreatedAt indicates an expected call of GetMoodsByUserIDAndOrderedByCreatedAt.
+func (mr *MockMoodRepositoryInterfaceMockRecorder) GetMoodsByUserIDAndOrderedByCreatedAt(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMoodsByUserIDAndOrderedByCreatedAt", reflect.TypeOf((*MockMoodRepositoryInterface)(nil).GetMoodsByUserIDAndOrderedByCreatedAt), userID)
+}
+
 // UpdateMoodEntry mocks base method.
 func (m *MockMoodRepositoryInterface) UpdateMoodEntry(mood *models.Mood) error {
 	m.ctrl.T.Helper()
@@ -0,0 +1,119 @@
+package mocks
+
+import (
+	"time"
+
+	"github.com/anirudhgray/mood-harbour-backend/models"
+	"github.com/golang/mock/gomock"
+)
+
+// MockOneTimeTokenRepository is a mock for OneTimeTokenRepositoryInterface.
+type MockOneTimeTokenRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockOneTimeTokenRepositoryMockRecorder
+}
+
+// NewMockOneTimeTokenRepository creates a new mock for OneTimeTokenRepositoryInterface.
+func NewMockOneTimeTokenRepository(ctrl *gomock.Controller) *MockOneTimeTokenRepository {
+	mock := &MockOneTimeTokenRepository{ctrl: ctrl}
+	mock.recorder = &MockOneTimeTokenRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT methods for expected calls with return values
+func (m *MockOneTimeTokenRepository) EXPECT() *MockOneTimeTokenRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ReplaceOneTimeToken mocks the ReplaceOneTimeToken method.
+func (m *MockOneTimeTokenRepository) ReplaceOneTimeToken(oneTimeToken *models.OneTimeToken) error {
+	ret := m.ctrl.Call(m, "ReplaceOneTimeToken", oneTimeToken)
+	err, _ := ret[0].(error)
+	return err
+}
+
+// GetOneTimeToken mocks the GetOneTimeToken method.
+func (m *MockOneTimeTokenRepository) GetOneTimeToken(email string, purpose string) (models.OneTimeToken, error) {
+	ret := m.ctrl.Call(m, "GetOneTimeToken", email, purpose)
+	oneTimeToken, _ := ret[0].(models.OneTimeToken)
+	err, _ := ret[1].(error)
+	return oneTimeToken, err
+}
+
+// RecordOneTimeTokenAttempt mocks the RecordOneTimeTokenAttempt method.
+func (m *MockOneTimeTokenRepository) RecordOneTimeTokenAttempt(id uint, maxAttempts int) (bool, error) {
+	ret := m.ctrl.Call(m, "RecordOneTimeTokenAttempt", id, maxAttempts)
+	ok, _ := ret[0].(bool)
+	err, _ := ret[1].(error)
+	return ok, err
+}
+
+// UseOneTimeToken mocks the UseOneTimeToken method.
+func (m *MockOneTimeTokenRepository) UseOneTimeToken(id uint, at time.Time) (bool, error) {
+	ret := m.ctrl.Call(m, "UseOneTimeToken", id, at)
+	ok, _ := ret[0].(bool)
+	err, _ := ret[1].(error)
+	return ok, err
+}
+
+// DeleteOneTimeToken mocks the DeleteOneTimeToken method.
+func (m *MockOneTimeTokenRepository) DeleteOneTimeToken(email string, purpose string) error {
+	ret := m.ctrl.Call(m, "DeleteOneTimeToken", email, purpose)
+	err, _ := ret[0].(error)
+	return err
+}
+
+// DeleteUserOneTimeTokens mocks the DeleteUserOneTimeTokens method.
+func (m *MockOneTimeTokenRepository) DeleteUserOneTimeTokens(email string) error {
+	ret := m.ctrl.Call(m, "DeleteUserOneTimeTokens", email)
+	err, _ := ret[0].(error)
+	return err
+}
+
+// PurgeOneTimeTokens mocks the PurgeOneTimeTokens method.
+func (m *MockOneTimeTokenRepository) PurgeOneTimeTokens(before time.Time) (int64, error) {
+	ret := m.ctrl.Call(m, "PurgeOneTimeTokens", before)
+	deleted, _ := ret[0].(int64)
+	err, _ := ret[1].(error)
+	return deleted, err
+}
+
+// MockOneTimeTokenRepositoryMockRecorder is a recorder for the MockOneTimeTokenRepository.
+type MockOneTimeTokenRepositoryMockRecorder struct {
+	mock *MockOneTimeTokenRepository
+}
+
+// ReplaceOneTimeToken mocks the ReplaceOneTimeToken method.
+func (m *MockOneTimeTokenRepositoryMockRecorder) ReplaceOneTimeToken(oneTimeToken interface{}) *gomock.Call {
+	return m.mock.ctrl.RecordCall(m.mock, "ReplaceOneTimeToken", oneTimeToken)
+}
+
+// GetOneTimeToken mocks the GetOneTimeToken method.
+func (m *MockOneTimeTokenRepositoryMockRecorder) GetOneTimeToken(email, purpose interface{}) *gomock.Call {
+	return m.mock.ctrl.RecordCall(m.mock, "GetOneTimeToken", email, purpose)
+}
+
+// RecordOneTimeTokenAttempt mocks the RecordOneTimeTokenAttempt method.
+func (m *MockOneTimeTokenRepositoryMockRecorder) RecordOneTimeTokenAttempt(id, maxAttempts interface{}) *gomock.Call {
+	return m.mock.ctrl.RecordCall(m.mock, "RecordOneTimeTokenAttempt", id, maxAttempts)
+}
+
+// UseOneTimeToken mocks the UseOneTimeToken method.
+func (m *MockOneTimeTokenRepositoryMockRecorder) UseOneTimeToken(id, at interface{}) *gomock.Call {
+	return m.mock.ctrl.RecordCall(m.mock, "UseOneTimeToken", id, at)
+}
+
+// DeleteOneTimeToken mocks the DeleteOneTimeToken method.
+func (m *MockOneTimeTokenRepositoryMockRecorder) DeleteOneTimeToken(email, purpose interface{}) *gomock.Call {
+	return m.mock.ctrl.RecordCall(m.mock, "DeleteOneTimeToken", email, purpose)
+}
+
+// DeleteUserOneTimeTokens mocks the DeleteUserOneTimeTokens method.
+func (m *MockOneTimeTokenRepositoryMockRecorder) DeleteUserOneTimeTokens(email interface{}) *gomock.Call {
+	return m.mock.ctrl.RecordCall(m.mock, "DeleteUserOneTimeTokens", email)
+}
+
+// PurgeOneTimeTokens mocks the PurgeOneTimeTokens method.
+func (m *MockOneTimeTokenRepositoryMockRecorder) PurgeOneTimeTokens(before interface{}) *gomock.Call {
+	return m.mock.ctrl.RecordCall(m.mock, "PurgeOneTimeTokens", before)
+}
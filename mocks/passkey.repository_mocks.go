@@ -0,0 +1,143 @@
+package mocks
+
+import (
+	"time"
+
+	"github.com/anirudhgray/mood-harbour-backend/models"
+	"github.com/golang/mock/gomock"
+)
+
+// MockPasskeyRepository is a mock for PasskeyRepositoryInterface.
+type MockPasskeyRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockPasskeyRepositoryMockRecorder
+}
+
+// NewMockPasskeyRepository creates a new mock for PasskeyRepositoryInterface.
+func NewMockPasskeyRepository(ctrl *gomock.Controller) *MockPasskeyRepository {
+	mock := &MockPasskeyRepository{ctrl: ctrl}
+	mock.recorder = &MockPasskeyRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT methods for expected calls with return values
+func (m *MockPasskeyRepository) EXPECT() *MockPasskeyRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreatePasskey mocks the CreatePasskey method.
+func (m *MockPasskeyRepository) CreatePasskey(passkey *models.Passkey) error {
+	ret := m.ctrl.Call(m, "CreatePasskey", passkey)
+	err, _ := ret[0].(error)
+	return err
+}
+
+// GetPasskeyByCredentialID mocks the GetPasskeyByCredentialID method.
+func (m *MockPasskeyRepository) GetPasskeyByCredentialID(credentialID string) (models.Passkey, error) {
+	ret := m.ctrl.Call(m, "GetPasskeyByCredentialID", credentialID)
+	passkey, _ := ret[0].(models.Passkey)
+	err, _ := ret[1].(error)
+	return passkey, err
+}
+
+// GetUserPasskeys mocks the GetUserPasskeys method.
+func (m *MockPasskeyRepository) GetUserPasskeys(userID uint) ([]models.Passkey, error) {
+	ret := m.ctrl.Call(m, "GetUserPasskeys", userID)
+	passkeys, _ := ret[0].([]models.Passkey)
+	err, _ := ret[1].(error)
+	return passkeys, err
+}
+
+// UpdatePasskeyUsage mocks the UpdatePasskeyUsage method.
+func (m *MockPasskeyRepository) UpdatePasskeyUsage(id uint, signCount uint32, at time.Time) error {
+	ret := m.ctrl.Call(m, "UpdatePasskeyUsage", id, signCount, at)
+	err, _ := ret[0].(error)
+	return err
+}
+
+// DeletePasskey mocks the DeletePasskey method.
+func (m *MockPasskeyRepository) DeletePasskey(userID uint, passkeyID uint) error {
+	ret := m.ctrl.Call(m, "DeletePasskey", userID, passkeyID)
+	err, _ := ret[0].(error)
+	return err
+}
+
+// DeleteUserPasskeys mocks the DeleteUserPasskeys method.
+func (m *MockPasskeyRepository) DeleteUserPasskeys(userID uint) error {
+	ret := m.ctrl.Call(m, "DeleteUserPasskeys", userID)
+	err, _ := ret[0].(error)
+	return err
+}
+
+// CreatePasskeyChallenge mocks the CreatePasskeyChallenge method.
+func (m *MockPasskeyRepository) CreatePasskeyChallenge(challenge *models.PasskeyChallenge) error {
+	ret := m.ctrl.Call(m, "CreatePasskeyChallenge", challenge)
+	err, _ := ret[0].(error)
+	return err
+}
+
+// UsePasskeyChallenge mocks the UsePasskeyChallenge method.
+func (m *MockPasskeyRepository) UsePasskeyChallenge(challenge string, purpose string, at time.Time) (models.PasskeyChallenge, error) {
+	ret := m.ctrl.Call(m, "UsePasskeyChallenge", challenge, purpose, at)
+	stored, _ := ret[0].(models.PasskeyChallenge)
+	err, _ := ret[1].(error)
+	return stored, err
+}
+
+// PurgePasskeyChallenges mocks the PurgePasskeyChallenges method.
+func (m *MockPasskeyRepository) PurgePasskeyChallenges(before time.Time) (int64, error) {
+	ret := m.ctrl.Call(m, "PurgePasskeyChallenges", before)
+	deleted, _ := ret[0].(int64)
+	err, _ := ret[1].(error)
+	return deleted, err
+}
+
+// MockPasskeyRepositoryMockRecorder is a recorder for the MockPasskeyRepository.
+type MockPasskeyRepositoryMockRecorder struct {
+	mock *MockPasskeyRepository
+}
+
+// CreatePasskey mocks the CreatePasskey method.
+func (m *MockPasskeyRepositoryMockRecorder) CreatePasskey(passkey interface{}) *gomock.Call {
+	return m.mock.ctrl.RecordCall(m.mock, "CreatePasskey", passkey)
+}
+
+// GetPasskeyByCredentialID mocks the GetPasskeyByCredentialID method.
+func (m *MockPasskeyRepositoryMockRecorder) GetPasskeyByCredentialID(credentialID interface{}) *gomock.Call {
+	return m.mock.ctrl.RecordCall(m.mock, "GetPasskeyByCredentialID", credentialID)
+}
+
+// GetUserPasskeys mocks the GetUserPasskeys method.
+func (m *MockPasskeyRepositoryMockRecorder) GetUserPasskeys(userID interface{}) *gomock.Call {
+	return m.mock.ctrl.RecordCall(m.mock, "GetUserPasskeys", userID)
+}
+
+// UpdatePasskeyUsage mocks the UpdatePasskeyUsage method.
+func (m *MockPasskeyRepositoryMockRecorder) UpdatePasskeyUsage(id, signCount, at interface{}) *gomock.Call {
+	return m.mock.ctrl.RecordCall(m.mock, "UpdatePasskeyUsage", id, signCount, at)
+}
+
+// DeletePasskey mocks the DeletePasskey method.
+func (m *MockPasskeyRepositoryMockRecorder) DeletePasskey(userID, passkeyID interface{}) *gomock.Call {
+	return m.mock.ctrl.RecordCall(m.mock, "DeletePasskey", userID, passkeyID)
+}
+
+// DeleteUserPasskeys mocks the DeleteUserPasskeys method.
+func (m *MockPasskeyRepositoryMockRecorder) DeleteUserPasskeys(userID interface{}) *gomock.Call {
+	return m.mock.ctrl.RecordCall(m.mock, "DeleteUserPasskeys", userID)
+}
+
+// CreatePasskeyChallenge mocks the CreatePasskeyChallenge method.
+func (m *MockPasskeyRepositoryMockRecorder) CreatePasskeyChallenge(challenge interface{}) *gomock.Call {
+	return m.mock.ctrl.RecordCall(m.mock, "CreatePasskeyChallenge", challenge)
+}
+
+// UsePasskeyChallenge mocks the UsePasskeyChallenge method.
+func (m *MockPasskeyRepositoryMockRecorder) UsePasskeyChallenge(challenge, purpose, at interface{}) *gomock.Call {
+	return m.mock.ctrl.RecordCall(m.mock, "UsePasskeyChallenge", challenge, purpose, at)
+}
+
+// PurgePasskeyChallenges mocks the PurgePasskeyChallenges method.
+func (m *MockPasskeyRepositoryMockRecorder) PurgePasskeyChallenges(before interface{}) *gomock.Call {
+	return m.mock.ctrl.RecordCall(m.mock, "PurgePasskeyChallenges", before)
+}
@@ -0,0 +1,449 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: repository/recommendation.repository.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+	time "time"
+
+	models "github.com/anirudhgray/mood-harbour-backend/models"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockRecommendationRepositoryInterface is a mock of RecommendationRepositoryInterface interface.
+type MockRecommendationRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockRecommendationRepositoryInterfaceMockRecorder
+}
+
+// MockRecommendationRepositoryInterfaceMockRecorder is the mock recorder for MockRecommendationRepositoryInterface.
+type MockRecommendationRepositoryInterfaceMockRecorder struct {
+	mock *MockRecommendationRepositoryInterface
+}
+
+// NewMockRecommendationRepositoryInterface creates a new mock instance.
+func NewMockRecommendationRepositoryInterface(ctrl *gomock.Controller) *MockRecommendationRepositoryInterface {
+	mock := &MockRecommendationRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockRecommendationRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRecommendationRepositoryInterface) EXPECT() *MockRecommendationRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// ActivateFactorizationModel mocks base method.
+func (m *MockRecommendationRepositoryInterface) ActivateFactorizationModel(modelID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ActivateFactorizationModel", modelID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ActivateFactorizationModel indicates an expected call of ActivateFactorizationModel.
+func (mr *MockRecommendationRepositoryInterfaceMockRecorder) ActivateFactorizationModel(modelID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ActivateFactorizationModel", reflect.TypeOf((*MockRecommendationRepositoryInterface)(nil).ActivateFactorizationModel), modelID)
+}
+
+// CreateCuratedResource mocks base method.
+func (m *MockRecommendationRepositoryInterface) CreateCuratedResource(curated *models.CuratedResource) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCuratedResource", curated)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCuratedResource indicates an expected call of CreateCuratedResource.
+func (mr *MockRecommendationRepositoryInterfaceMockRecorder) CreateCuratedResource(curated interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCuratedResource", reflect.TypeOf((*MockRecommendationRepositoryInterface)(nil).CreateCuratedResource), curated)
+}
+
+// CreateDismissal mocks base method.
+func (m *MockRecommendationRepositoryInterface) CreateDismissal(dismissal *models.RecommendationDismissal) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateDismissal", dismissal)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateDismissal indicates an expected call of CreateDismissal.
+func (mr *MockRecommendationRepositoryInterfaceMockRecorder) CreateDismissal(dismissal interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDismissal", reflect.TypeOf((*MockRecommendationRepositoryInterface)(nil).CreateDismissal), dismissal)
+}
+
+// DeleteCuratedResource mocks base method.
+func (m *MockRecommendationRepositoryInterface) DeleteCuratedResource(resourceID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCuratedResource", resourceID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCuratedResource indicates an expected call of DeleteCuratedResource.
+func (mr *MockRecommendationRepositoryInterfaceMockRecorder) DeleteCuratedResource(resourceID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCuratedResource", reflect.TypeOf((*MockRecommendationRepositoryInterface)(nil).DeleteCuratedResource), resourceID)
+}
+
+// DeleteDismissal mocks base method.
+func (m *MockRecommendationRepositoryInterface) DeleteDismissal(userID, resourceID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteDismissal", userID, resourceID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteDismissal indicates an expected call of DeleteDismissal.
+func (mr *MockRecommendationRepositoryInterfaceMockRecorder) DeleteDismissal(userID, resourceID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDismissal", reflect.TypeOf((*MockRecommendationRepositoryInterface)(nil).DeleteDismissal), userID, resourceID)
+}
+
+// DeleteUserRecommendation mocks base method.
+func (m *MockRecommendationRepositoryInterface) DeleteUserRecommendation(userID, resourceID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserRecommendation", userID, resourceID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUserRecommendation indicates an expected call of DeleteUserRecommendation.
+func (mr *MockRecommendationRepositoryInterfaceMockRecorder) DeleteUserRecommendation(userID, resourceID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserRecommendation", reflect.TypeOf((*MockRecommendationRepositoryInterface)(nil).DeleteUserRecommendation), userID, resourceID)
+}
+
+// GetActiveFactorizationModel mocks base method.
+func (m *MockRecommendationRepositoryInterface) GetActiveFactorizationModel() (models.FactorizationModel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActiveFactorizationModel")
+	ret0, _ := ret[0].(models.FactorizationModel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActiveFactorizationModel indicates an expected call of GetActiveFactorizationModel.
+func (mr *MockRecommendationRepositoryInterfaceMockRecorder) GetActiveFactorizationModel() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveFactorizationModel", reflect.TypeOf((*MockRecommendationRepositoryInterface)(nil).GetActiveFactorizationModel))
+}
+
+// GetBookmarks mocks base method.
+func (m *MockRecommendationRepositoryInterface) GetBookmarks() ([]models.Bookmark, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBookmarks")
+	ret0, _ := ret[0].([]models.Bookmark)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBookmarks indicates an expected call of GetBookmarks.
+func (mr *MockRecommendationRepositoryInterfaceMockRecorder) GetBookmarks() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookmarks", reflect.TypeOf((*MockRecommendationRepositoryInterface)(nil).GetBookmarks))
+}
+
+// GetCuratedResourceIDs mocks base method.
+func (m *MockRecommendationRepositoryInterface) GetCuratedResourceIDs() ([]uint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCuratedResourceIDs")
+	ret0, _ := ret[0].([]uint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCuratedResourceIDs indicates an expected call of GetCuratedResourceIDs.
+func (mr *MockRecommendationRepositoryInterfaceMockRecorder) GetCuratedResourceIDs() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCuratedResourceIDs", reflect.TypeOf((*MockRecommendationRepositoryInterface)(nil).GetCuratedResourceIDs))
+}
+
+// GetDismissals mocks base method.
+func (m *MockRecommendationRepositoryInterface) GetDismissals() ([]models.RecommendationDismissal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDismissals")
+	ret0, _ := ret[0].([]models.RecommendationDismissal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDismissals indicates an expected call of GetDismissals.
+func (mr *MockRecommendationRepositoryInterfaceMockRecorder) GetDismissals() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDismissals", reflect.TypeOf((*MockRecommendationRepositoryInterface)(nil).GetDismissals))
+}
+
+// GetDismissalsByUserID mocks base method.
+func (m *MockRecommendationRepositoryInterface) GetDismissalsByUserID(userID uint) ([]models.RecommendationDismissal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDismissalsByUserID", userID)
+	ret0, _ := ret[0].([]models.RecommendationDismissal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDismissalsByUserID indicates an expected call of GetDismissalsByUserID.
+func (mr *MockRecommendationRepositoryInterfaceMockRecorder) GetDismissalsByUserID(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDismissalsByUserID", reflect.TypeOf((*MockRecommendationRepositoryInterface)(nil).GetDismissalsByUserID), userID)
+}
+
+// GetFactorizationModels mocks base method.
+func (m *MockRecommendationRepositoryInterface) GetFactorizationModels() ([]models.FactorizationModel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFactorizationModels")
+	ret0, _ := ret[0].([]models.FactorizationModel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFactorizationModels indicates an expected call of GetFactorizationModels.
+func (mr *MockRecommendationRepositoryInterfaceMockRecorder) GetFactorizationModels() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFactorizationModels", reflect.TypeOf((*MockRecommendationRepositoryInterface)(nil).GetFactorizationModels))
+}
+
+// GetFactors mocks base method.
+func (m *MockRecommendationRepositoryInterface) GetFactors(modelID uint) ([]models.UserFactor, []models.ResourceFactor, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFactors", modelID)
+	ret0, _ := ret[0].([]models.UserFactor)
+	ret1, _ := ret[1].([]models.ResourceFactor)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetFactors indicates an expected call of GetFactors.
+func (mr *MockRecommendationRepositoryInterfaceMockRecorder) GetFactors(modelID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFactors", reflect.TypeOf((*MockRecommendationRepositoryInterface)(nil).GetFactors), modelID)
+}
+
+// GetMoodReviews mocks base method.
+func (m *MockRecommendationRepositoryInterface) GetMoodReviews(resourceIDs, userIDs []uint) ([]models.Review, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMoodReviews", resourceIDs, userIDs)
+	ret0, _ := ret[0].([]models.Review)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMoodReviews indicates an expected call of GetMoodReviews.
+func (mr *MockRecommendationRepositoryInterfaceMockRecorder) GetMoodReviews(resourceIDs, userIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMoodReviews", reflect.TypeOf((*MockRecommendationRepositoryInterface)(nil).GetMoodReviews), resourceIDs, userIDs)
+}
+
+// GetPublishedResources mocks base method.
+func (m *MockRecommendationRepositoryInterface) GetPublishedResources() ([]models.Resource, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPublishedResources")
+	ret0, _ := ret[0].([]models.Resource)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPublishedResources indicates an expected call of GetPublishedResources.
+func (mr *MockRecommendationRepositoryInterfaceMockRecorder) GetPublishedResources() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPublishedResources", reflect.TypeOf((*MockRecommendationRepositoryInterface)(nil).GetPublishedResources))
+}
+
+// GetPublishedReviews mocks base method.
+func (m *MockRecommendationRepositoryInterface) GetPublishedReviews() ([]models.Review, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPublishedReviews")
+	ret0, _ := ret[0].([]models.Review)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPublishedReviews indicates an expected call of GetPublishedReviews.
+func (mr *MockRecommendationRepositoryInterfaceMockRecorder) GetPublishedReviews() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPublishedReviews", reflect.TypeOf((*MockRecommendationRepositoryInterface)(nil).GetPublishedReviews))
+}
+
+// GetRecommendationStatus mocks base method.
+func (m *MockRecommendationRepositoryInterface) GetRecommendationStatus(userID uint) (models.RecommendationStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRecommendationStatus", userID)
+	ret0, _ := ret[0].(models.RecommendationStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRecommendationStatus indicates an expected call of GetRecommendationStatus.
+func (mr *MockRecommendationRepositoryInterfaceMockRecorder) GetRecommendationStatus(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecommendationStatus", reflect.TypeOf((*MockRecommendationRepositoryInterface)(nil).GetRecommendationStatus), userID)
+}
+
+// GetResourceIDsWithMoodTag mocks base method.
+func (m *MockRecommendationRepositoryInterface) GetResourceIDsWithMoodTag(resourceIDs []uint, mood models.MoodType) ([]uint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetResourceIDsWithMoodTag", resourceIDs, mood)
+	ret0, _ := ret[0].([]uint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetResourceIDsWithMoodTag indicates an expected call of GetResourceIDsWithMoodTag.
+func (mr *MockRecommendationRepositoryInterfaceMockRecorder) GetResourceIDsWithMoodTag(resourceIDs, mood interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetResourceIDsWithMoodTag", reflect.TypeOf((*MockRecommendationRepositoryInterface)(nil).GetResourceIDsWithMoodTag), resourceIDs, mood)
+}
+
+// GetReviewsByUserIDs mocks base method.
+func (m *MockRecommendationRepositoryInterface) GetReviewsByUserIDs(userIDs []uint) ([]models.Review, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReviewsByUserIDs", userIDs)
+	ret0, _ := ret[0].([]models.Review)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReviewsByUserIDs indicates an expected call of GetReviewsByUserIDs.
+func (mr *MockRecommendationRepositoryInterfaceMockRecorder) GetReviewsByUserIDs(userIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReviewsByUserIDs", reflect.TypeOf((*MockRecommendationRepositoryInterface)(nil).GetReviewsByUserIDs), userIDs)
+}
+
+// GetStaleUserIDs mocks base method.
+func (m *MockRecommendationRepositoryInterface) GetStaleUserIDs(limit int) ([]uint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStaleUserIDs", limit)
+	ret0, _ := ret[0].([]uint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStaleUserIDs indicates an expected call of GetStaleUserIDs.
+func (mr *MockRecommendationRepositoryInterfaceMockRecorder) GetStaleUserIDs(limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStaleUserIDs", reflect.TypeOf((*MockRecommendationRepositoryInterface)(nil).GetStaleUserIDs), limit)
+}
+
+// GetUserIDsWhoReviewedResource mocks base method.
+func (m *MockRecommendationRepositoryInterface) GetUserIDsWhoReviewedResource(resourceID uint) ([]uint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserIDsWhoReviewedResource", resourceID)
+	ret0, _ := ret[0].([]uint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserIDsWhoReviewedResource indicates an expected call of GetUserIDsWhoReviewedResource.
+func (mr *MockRecommendationRepositoryInterfaceMockRecorder) GetUserIDsWhoReviewedResource(resourceID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserIDsWhoReviewedResource", reflect.TypeOf((*MockRecommendationRepositoryInterface)(nil).GetUserIDsWhoReviewedResource), resourceID)
+}
+
+// GetUserIDsWithStatus mocks base method.
+func (m *MockRecommendationRepositoryInterface) GetUserIDsWithStatus() ([]uint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserIDsWithStatus")
+	ret0, _ := ret[0].([]uint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserIDsWithStatus indicates an expected call of GetUserIDsWithStatus.
+func (mr *MockRecommendationRepositoryInterfaceMockRecorder) GetUserIDsWithStatus() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserIDsWithStatus", reflect.TypeOf((*MockRecommendationRepositoryInterface)(nil).GetUserIDsWithStatus))
+}
+
+// GetUserRecommendations mocks base method.
+func (m *MockRecommendationRepositoryInterface) GetUserRecommendations(userID uint, offset, limit int) ([]models.UserRecommendation, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserRecommendations", userID, offset, limit)
+	ret0, _ := ret[0].([]models.UserRecommendation)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetUserRecommendations indicates an expected call of GetUserRecommendations.
+func (mr *MockRecommendationRepositoryInterfaceMockRecorder) GetUserRecommendations(userID, offset, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserRecommendations", reflect.TypeOf((*MockRecommendationRepositoryInterface)(nil).GetUserRecommendations), userID, offset, limit)
+}
+
+// GetUserSimilarities mocks base method.
+func (m *MockRecommendationRepositoryInterface) GetUserSimilarities(userID uint) ([]models.UserSimilarity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserSimilarities", userID)
+	ret0, _ := ret[0].([]models.UserSimilarity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserSimilarities indicates an expected call of GetUserSimilarities.
+func (mr *MockRecommendationRepositoryInterfaceMockRecorder) GetUserSimilarities(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserSimilarities", reflect.TypeOf((*MockRecommendationRepositoryInterface)(nil).GetUserSimilarities), userID)
+}
+
+// MarkUsersStale mocks base method.
+func (m *MockRecommendationRepositoryInterface) MarkUsersStale(userIDs []uint, at time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkUsersStale", userIDs, at)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkUsersStale indicates an expected call of MarkUsersStale.
+func (mr *MockRecommendationRepositoryInterfaceMockRecorder) MarkUsersStale(userIDs, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkUsersStale", reflect.TypeOf((*MockRecommendationRepositoryInterface)(nil).MarkUsersStale), userIDs, at)
+}
+
+// PruneFactorizationModels mocks base method.
+func (m *MockRecommendationRepositoryInterface) PruneFactorizationModels(keep int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PruneFactorizationModels", keep)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PruneFactorizationModels indicates an expected call of PruneFactorizationModels.
+func (mr *MockRecommendationRepositoryInterfaceMockRecorder) PruneFactorizationModels(keep interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PruneFactorizationModels", reflect.TypeOf((*MockRecommendationRepositoryInterface)(nil).PruneFactorizationModels), keep)
+}
+
+// SaveFactorizationModel mocks base method.
+func (m *MockRecommendationRepositoryInterface) SaveFactorizationModel(model *models.FactorizationModel, userFactors []models.UserFactor, resourceFactors []models.ResourceFactor) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveFactorizationModel", model, userFactors, resourceFactors)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveFactorizationModel indicates an expected call of SaveFactorizationModel.
+func (mr *MockRecommendationRepositoryInterfaceMockRecorder) SaveFactorizationModel(model, userFactors, resourceFactors interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveFactorizationModel", reflect.TypeOf((*MockRecommendationRepositoryInterface)(nil).SaveFactorizationModel), model, userFactors, resourceFactors)
+}
+
+// SaveUserRecommendations mocks base method.
+func (m *MockRecommendationRepositoryInterface) SaveUserRecommendations(userID uint, similarities []models.UserSimilarity, recommendations []models.UserRecommendation, startedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveUserRecommendations", userID, similarities, recommendations, startedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveUserRecommendations indicates an expected call of SaveUserRecommendations.
+func (mr *MockRecommendationRepositoryInterfaceMockRecorder) SaveUserRecommendations(userID, similarities, recommendations, startedAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveUserRecommendations", reflect.TypeOf((*MockRecommendationRepositoryInterface)(nil).SaveUserRecommendations), userID, similarities, recommendations, startedAt)
+}
@@ -0,0 +1,252 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: services/recommendation.service.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	models "github.com/anirudhgray/mood-harbour-backend/models"
+	recommender "github.com/anirudhgray/mood-harbour-backend/utils/recommender"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockRecommendationServiceInterface is a mock of RecommendationServiceInterface interface.
+type MockRecommendationServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockRecommendationServiceInterfaceMockRecorder
+}
+
+// MockRecommendationServiceInterfaceMockRecorder is the mock recorder for MockRecommendationServiceInterface.
+type MockRecommendationServiceInterfaceMockRecorder struct {
+	mock *MockRecommendationServiceInterface
+}
+
+// NewMockRecommendationServiceInterface creates a new mock instance.
+func NewMockRecommendationServiceInterface(ctrl *gomock.Controller) *MockRecommendationServiceInterface {
+	mock := &MockRecommendationServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockRecommendationServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRecommendationServiceInterface) EXPECT() *MockRecommendationServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// ActivateFactorizationModel mocks base method.
+func (m *MockRecommendationServiceInterface) ActivateFactorizationModel(modelID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ActivateFactorizationModel", modelID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ActivateFactorizationModel indicates an expected call of ActivateFactorizationModel.
+func (mr *MockRecommendationServiceInterfaceMockRecorder) ActivateFactorizationModel(modelID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ActivateFactorizationModel", reflect.TypeOf((*MockRecommendationServiceInterface)(nil).ActivateFactorizationModel), modelID)
+}
+
+// CurateResource mocks base method.
+func (m *MockRecommendationServiceInterface) CurateResource(adminID, resourceID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CurateResource", adminID, resourceID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CurateResource indicates an expected call of CurateResource.
+func (mr *MockRecommendationServiceInterfaceMockRecorder) CurateResource(adminID, resourceID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CurateResource", reflect.TypeOf((*MockRecommendationServiceInterface)(nil).CurateResource), adminID, resourceID)
+}
+
+// CurrentMood mocks base method.
+func (m *MockRecommendationServiceInterface) CurrentMood(userID uint) (models.MoodType, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CurrentMood", userID)
+	ret0, _ := ret[0].(models.MoodType)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CurrentMood indicates an expected call of CurrentMood.
+func (mr *MockRecommendationServiceInterfaceMockRecorder) CurrentMood(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CurrentMood", reflect.TypeOf((*MockRecommendationServiceInterface)(nil).CurrentMood), userID)
+}
+
+// DismissRecommendation mocks base method.
+func (m *MockRecommendationServiceInterface) DismissRecommendation(userID, resourceID uint, reason models.DismissalReason) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DismissRecommendation", userID, resourceID, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DismissRecommendation indicates an expected call of DismissRecommendation.
+func (mr *MockRecommendationServiceInterfaceMockRecorder) DismissRecommendation(userID, resourceID, reason interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DismissRecommendation", reflect.TypeOf((*MockRecommendationServiceInterface)(nil).DismissRecommendation), userID, resourceID, reason)
+}
+
+// GetCuratedResources mocks base method.
+func (m *MockRecommendationServiceInterface) GetCuratedResources() ([]models.Resource, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCuratedResources")
+	ret0, _ := ret[0].([]models.Resource)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCuratedResources indicates an expected call of GetCuratedResources.
+func (mr *MockRecommendationServiceInterfaceMockRecorder) GetCuratedResources() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCuratedResources", reflect.TypeOf((*MockRecommendationServiceInterface)(nil).GetCuratedResources))
+}
+
+// GetDismissals mocks base method.
+func (m *MockRecommendationServiceInterface) GetDismissals(userID uint) ([]models.DismissalResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDismissals", userID)
+	ret0, _ := ret[0].([]models.DismissalResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDismissals indicates an expected call of GetDismissals.
+func (mr *MockRecommendationServiceInterfaceMockRecorder) GetDismissals(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDismissals", reflect.TypeOf((*MockRecommendationServiceInterface)(nil).GetDismissals), userID)
+}
+
+// GetFactorizationModels mocks base method.
+func (m *MockRecommendationServiceInterface) GetFactorizationModels() ([]models.FactorizationModel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFactorizationModels")
+	ret0, _ := ret[0].([]models.FactorizationModel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFactorizationModels indicates an expected call of GetFactorizationModels.
+func (mr *MockRecommendationServiceInterfaceMockRecorder) GetFactorizationModels() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFactorizationModels", reflect.TypeOf((*MockRecommendationServiceInterface)(nil).GetFactorizationModels))
+}
+
+// GetRecommendations mocks base method.
+func (m *MockRecommendationServiceInterface) GetRecommendations(userID uint, page, perPage int, mood models.MoodType) (recommender.RecommendationPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRecommendations", userID, page, perPage, mood)
+	ret0, _ := ret[0].(recommender.RecommendationPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRecommendations indicates an expected call of GetRecommendations.
+func (mr *MockRecommendationServiceInterfaceMockRecorder) GetRecommendations(userID, page, perPage, mood interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecommendations", reflect.TypeOf((*MockRecommendationServiceInterface)(nil).GetRecommendations), userID, page, perPage, mood)
+}
+
+// MarkInteractionStale mocks base method.
+func (m *MockRecommendationServiceInterface) MarkInteractionStale(userID, resourceID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkInteractionStale", userID, resourceID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkInteractionStale indicates an expected call of MarkInteractionStale.
+func (mr *MockRecommendationServiceInterfaceMockRecorder) MarkInteractionStale(userID, resourceID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkInteractionStale", reflect.TypeOf((*MockRecommendationServiceInterface)(nil).MarkInteractionStale), userID, resourceID)
+}
+
+// QueueFactorizationTraining mocks base method.
+func (m *MockRecommendationServiceInterface) QueueFactorizationTraining() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueueFactorizationTraining")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// QueueFactorizationTraining indicates an expected call of QueueFactorizationTraining.
+func (mr *MockRecommendationServiceInterfaceMockRecorder) QueueFactorizationTraining() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueueFactorizationTraining", reflect.TypeOf((*MockRecommendationServiceInterface)(nil).QueueFactorizationTraining))
+}
+
+// RecomputeAll mocks base method.
+func (m *MockRecommendationServiceInterface) RecomputeAll() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecomputeAll")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecomputeAll indicates an expected call of RecomputeAll.
+func (mr *MockRecommendationServiceInterfaceMockRecorder) RecomputeAll() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecomputeAll", reflect.TypeOf((*MockRecommendationServiceInterface)(nil).RecomputeAll))
+}
+
+// RecomputeStale mocks base method.
+func (m *MockRecommendationServiceInterface) RecomputeStale(limit int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecomputeStale", limit)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecomputeStale indicates an expected call of RecomputeStale.
+func (mr *MockRecommendationServiceInterfaceMockRecorder) RecomputeStale(limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecomputeStale", reflect.TypeOf((*MockRecommendationServiceInterface)(nil).RecomputeStale), limit)
+}
+
+// TrainFactorizationModel mocks base method.
+func (m *MockRecommendationServiceInterface) TrainFactorizationModel() (models.FactorizationModel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TrainFactorizationModel")
+	ret0, _ := ret[0].(models.FactorizationModel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TrainFactorizationModel indicates an expected call of TrainFactorizationModel.
+func (mr *MockRecommendationServiceInterfaceMockRecorder) TrainFactorizationModel() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TrainFactorizationModel", reflect.TypeOf((*MockRecommendationServiceInterface)(nil).TrainFactorizationModel))
+}
+
+// UncurateResource mocks base method.
+func (m *MockRecommendationServiceInterface) UncurateResource(resourceID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UncurateResource", resourceID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UncurateResource indicates an expected call of UncurateResource.
+func (mr *MockRecommendationServiceInterfaceMockRecorder) UncurateResource(resourceID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UncurateResource", reflect.TypeOf((*MockRecommendationServiceInterface)(nil).UncurateResource), resourceID)
+}
+
+// UndoDismissal mocks base method.
+func (m *MockRecommendationServiceInterface) UndoDismissal(userID, resourceID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UndoDismissal", userID, resourceID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UndoDismissal indicates an expected call of UndoDismissal.
+func (mr *MockRecommendationServiceInterfaceMockRecorder) UndoDismissal(userID, resourceID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UndoDismissal", reflect.TypeOf((*MockRecommendationServiceInterface)(nil).UndoDismissal), userID, resourceID)
+}
@@ -0,0 +1,69 @@
+package mocks
+
+import (
+	"time"
+
+	"github.com/anirudhgray/mood-harbour-backend/models"
+	"github.com/golang/mock/gomock"
+)
+
+// MockRefreshTokenRepository is a mock for RefreshTokenRepositoryInterface.
+type MockRefreshTokenRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRefreshTokenRepositoryMockRecorder
+}
+
+// NewMockRefreshTokenRepository creates a new mock for RefreshTokenRepositoryInterface.
+func NewMockRefreshTokenRepository(ctrl *gomock.Controller) *MockRefreshTokenRepository {
+	mock := &MockRefreshTokenRepository{ctrl: ctrl}
+	mock.recorder = &MockRefreshTokenRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT methods for expected calls with return values
+func (m *MockRefreshTokenRepository) EXPECT() *MockRefreshTokenRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateRefreshToken mocks the CreateRefreshToken method.
+func (m *MockRefreshTokenRepository) CreateRefreshToken(refreshToken *models.RefreshToken) error {
+	ret := m.ctrl.Call(m, "CreateRefreshToken", refreshToken)
+	err, _ := ret[0].(error)
+	return err
+}
+
+// GetRefreshTokenByHash mocks the GetRefreshTokenByHash method.
+func (m *MockRefreshTokenRepository) GetRefreshTokenByHash(tokenHash string) (models.RefreshToken, error) {
+	ret := m.ctrl.Call(m, "GetRefreshTokenByHash", tokenHash)
+	refreshToken, _ := ret[0].(models.RefreshToken)
+	err, _ := ret[1].(error)
+	return refreshToken, err
+}
+
+// MarkRefreshTokenUsed mocks the MarkRefreshTokenUsed method.
+func (m *MockRefreshTokenRepository) MarkRefreshTokenUsed(id uint, at time.Time) (bool, error) {
+	ret := m.ctrl.Call(m, "MarkRefreshTokenUsed", id, at)
+	ok, _ := ret[0].(bool)
+	err, _ := ret[1].(error)
+	return ok, err
+}
+
+// MockRefreshTokenRepositoryMockRecorder is a recorder for the MockRefreshTokenRepository.
+type MockRefreshTokenRepositoryMockRecorder struct {
+	mock *MockRefreshTokenRepository
+}
+
+// CreateRefreshToken mocks the CreateRefreshToken method.
+func (m *MockRefreshTokenRepositoryMockRecorder) CreateRefreshToken(refreshToken interface{}) *gomock.Call {
+	return m.mock.ctrl.RecordCall(m.mock, "CreateRefreshToken", refreshToken)
+}
+
+// GetRefreshTokenByHash mocks the GetRefreshTokenByHash method.
+func (m *MockRefreshTokenRepositoryMockRecorder) GetRefreshTokenByHash(tokenHash interface{}) *gomock.Call {
+	return m.mock.ctrl.RecordCall(m.mock, "GetRefreshTokenByHash", tokenHash)
+}
+
+// MarkRefreshTokenUsed mocks the MarkRefreshTokenUsed method.
+func (m *MockRefreshTokenRepositoryMockRecorder) MarkRefreshTokenUsed(id, at interface{}) *gomock.Call {
+	return m.mock.ctrl.RecordCall(m.mock, "MarkRefreshTokenUsed", id, at)
+}
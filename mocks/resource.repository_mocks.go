@@ -0,0 +1,416 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: repository/resource.repository.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+	time "time"
+
+	models "github.com/anirudhgray/mood-harbour-backend/models"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockResourceRepositoryInterface is a mock of ResourceRepositoryInterface interface.
+type MockResourceRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockResourceRepositoryInterfaceMockRecorder
+}
+
+// MockResourceRepositoryInterfaceMockRecorder is the mock recorder for MockResourceRepositoryInterface.
+type MockResourceRepositoryInterfaceMockRecorder struct {
+	mock *MockResourceRepositoryInterface
+}
+
+// NewMockResourceRepositoryInterface creates a new mock instance.
+func NewMockResourceRepositoryInterface(ctrl *gomock.Controller) *MockResourceRepositoryInterface {
+	mock := &MockResourceRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockResourceRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockResourceRepositoryInterface) EXPECT() *MockResourceRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// AddReview mocks base method.
+func (m *MockResourceRepositoryInterface) AddReview(resourceID uint, review *models.Review) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddReview", resourceID, review)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddReview indicates an expected call of AddReview.
+func (mr *MockResourceRepositoryInterfaceMockRecorder) AddReview(resourceID, review interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddReview", reflect.TypeOf((*MockResourceRepositoryInterface)(nil).AddReview), resourceID, review)
+}
+
+// CreateResource mocks base method.
+func (m *MockResourceRepositoryInterface) CreateResource(resource *models.Resource) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateResource", resource)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateResource indicates an expected call of CreateResource.
+func (mr *MockResourceRepositoryInterfaceMockRecorder) CreateResource(resource interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateResource", reflect.TypeOf((*MockResourceRepositoryInterface)(nil).CreateResource), resource)
+}
+
+// DeleteResource mocks base method.
+func (m *MockResourceRepositoryInterface) DeleteResource(resourceID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteResource", resourceID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteResource indicates an expected call of DeleteResource.
+func (mr *MockResourceRepositoryInterfaceMockRecorder) DeleteResource(resourceID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteResource", reflect.TypeOf((*MockResourceRepositoryInterface)(nil).DeleteResource), resourceID)
+}
+
+// DeleteReview mocks base method.
+func (m *MockResourceRepositoryInterface) DeleteReview(reviewID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteReview", reviewID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteReview indicates an expected call of DeleteReview.
+func (mr *MockResourceRepositoryInterfaceMockRecorder) DeleteReview(reviewID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteReview", reflect.TypeOf((*MockResourceRepositoryInterface)(nil).DeleteReview), reviewID)
+}
+
+// DeleteReviewVote mocks base method.
+func (m *MockResourceRepositoryInterface) DeleteReviewVote(reviewID, userID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteReviewVote", reviewID, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteReviewVote indicates an expected call of DeleteReviewVote.
+func (mr *MockResourceRepositoryInterfaceMockRecorder) DeleteReviewVote(reviewID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteReviewVote", reflect.TypeOf((*MockResourceRepositoryInterface)(nil).DeleteReviewVote), reviewID, userID)
+}
+
+// GetAdminResources mocks base method.
+func (m *MockResourceRepositoryInterface) GetAdminResources() ([]models.Resource, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAdminResources")
+	ret0, _ := ret[0].([]models.Resource)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAdminResources indicates an expected call of GetAdminResources.
+func (mr *MockResourceRepositoryInterfaceMockRecorder) GetAdminResources() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAdminResources", reflect.TypeOf((*MockResourceRepositoryInterface)(nil).GetAdminResources))
+}
+
+// GetResourceByID mocks base method.
+func (m *MockResourceRepositoryInterface) GetResourceByID(resourceID uint) (models.Resource, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetResourceByID", resourceID)
+	ret0, _ := ret[0].(models.Resource)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetResourceByID indicates an expected call of GetResourceByID.
+func (mr *MockResourceRepositoryInterfaceMockRecorder) GetResourceByID(resourceID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetResourceByID", reflect.TypeOf((*MockResourceRepositoryInterface)(nil).GetResourceByID), resourceID)
+}
+
+// GetResourceByURL mocks base method.
+func (m *MockResourceRepositoryInterface) GetResourceByURL(url string) (models.Resource, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetResourceByURL", url)
+	ret0, _ := ret[0].(models.Resource)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetResourceByURL indicates an expected call of GetResourceByURL.
+func (mr *MockResourceRepositoryInterfaceMockRecorder) GetResourceByURL(url interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetResourceByURL", reflect.TypeOf((*MockResourceRepositoryInterface)(nil).GetResourceByURL), url)
+}
+
+// GetResourceMoodTags mocks base method.
+func (m *MockResourceRepositoryInterface) GetResourceMoodTags(resourceID uint) ([]models.MoodType, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetResourceMoodTags", resourceID)
+	ret0, _ := ret[0].([]models.MoodType)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetResourceMoodTags indicates an expected call of GetResourceMoodTags.
+func (mr *MockResourceRepositoryInterfaceMockRecorder) GetResourceMoodTags(resourceID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetResourceMoodTags", reflect.TypeOf((*MockResourceRepositoryInterface)(nil).GetResourceMoodTags), resourceID)
+}
+
+// GetResourceMoodTagsByIDs mocks base method.
+func (m *MockResourceRepositoryInterface) GetResourceMoodTagsByIDs(resourceIDs []uint) (map[uint][]models.MoodType, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetResourceMoodTagsByIDs", resourceIDs)
+	ret0, _ := ret[0].(map[uint][]models.MoodType)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetResourceMoodTagsByIDs indicates an expected call of GetResourceMoodTagsByIDs.
+func (mr *MockResourceRepositoryInterfaceMockRecorder) GetResourceMoodTagsByIDs(resourceIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetResourceMoodTagsByIDs", reflect.TypeOf((*MockResourceRepositoryInterface)(nil).GetResourceMoodTagsByIDs), resourceIDs)
+}
+
+// GetResources mocks base method.
+func (m *MockResourceRepositoryInterface) GetResources() ([]models.Resource, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetResources")
+	ret0, _ := ret[0].([]models.Resource)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetResources indicates an expected call of GetResources.
+func (mr *MockResourceRepositoryInterfaceMockRecorder) GetResources() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetResources", reflect.TypeOf((*MockResourceRepositoryInterface)(nil).GetResources))
+}
+
+// GetResourcesByIDs mocks base method.
+func (m *MockResourceRepositoryInterface) GetResourcesByIDs(resourceIDs []uint) ([]models.Resource, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetResourcesByIDs", resourceIDs)
+	ret0, _ := ret[0].([]models.Resource)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetResourcesByIDs indicates an expected call of GetResourcesByIDs.
+func (mr *MockResourceRepositoryInterfaceMockRecorder) GetResourcesByIDs(resourceIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetResourcesByIDs", reflect.TypeOf((*MockResourceRepositoryInterface)(nil).GetResourcesByIDs), resourceIDs)
+}
+
+// GetResourcesByUserID mocks base method.
+func (m *MockResourceRepositoryInterface) GetResourcesByUserID(userID uint) ([]models.Resource, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetResourcesByUserID", userID)
+	ret0, _ := ret[0].([]models.Resource)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetResourcesByUserID indicates an expected call of GetResourcesByUserID.
+func (mr *MockResourceRepositoryInterfaceMockRecorder) GetResourcesByUserID(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetResourcesByUserID", reflect.TypeOf((*MockResourceRepositoryInterface)(nil).GetResourcesByUserID), userID)
+}
+
+// GetResourcesForLinkCheck mocks base method.
+func (m *MockResourceRepositoryInterface) GetResourcesForLinkCheck(checkedBefore time.Time, limit int) ([]models.Resource, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetResourcesForLinkCheck", checkedBefore, limit)
+	ret0, _ := ret[0].([]models.Resource)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetResourcesForLinkCheck indicates an expected call of GetResourcesForLinkCheck.
+func (mr *MockResourceRepositoryInterfaceMockRecorder) GetResourcesForLinkCheck(checkedBefore, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetResourcesForLinkCheck", reflect.TypeOf((*MockResourceRepositoryInterface)(nil).GetResourcesForLinkCheck), checkedBefore, limit)
+}
+
+// GetReviewByID mocks base method.
+func (m *MockResourceRepositoryInterface) GetReviewByID(reviewID uint) (models.Review, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReviewByID", reviewID)
+	ret0, _ := ret[0].(models.Review)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReviewByID indicates an expected call of GetReviewByID.
+func (mr *MockResourceRepositoryInterfaceMockRecorder) GetReviewByID(reviewID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReviewByID", reflect.TypeOf((*MockResourceRepositoryInterface)(nil).GetReviewByID), reviewID)
+}
+
+// GetReviewPageByResourceID mocks base method.
+func (m *MockResourceRepositoryInterface) GetReviewPageByResourceID(resourceID uint, offset, limit int) ([]models.Review, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReviewPageByResourceID", resourceID, offset, limit)
+	ret0, _ := ret[0].([]models.Review)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetReviewPageByResourceID indicates an expected call of GetReviewPageByResourceID.
+func (mr *MockResourceRepositoryInterfaceMockRecorder) GetReviewPageByResourceID(resourceID, offset, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReviewPageByResourceID", reflect.TypeOf((*MockResourceRepositoryInterface)(nil).GetReviewPageByResourceID), resourceID, offset, limit)
+}
+
+// GetReviewVoteCounts mocks base method.
+func (m *MockResourceRepositoryInterface) GetReviewVoteCounts(reviewIDs []uint) (map[uint]models.ReviewVoteCount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReviewVoteCounts", reviewIDs)
+	ret0, _ := ret[0].(map[uint]models.ReviewVoteCount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReviewVoteCounts indicates an expected call of GetReviewVoteCounts.
+func (mr *MockResourceRepositoryInterfaceMockRecorder) GetReviewVoteCounts(reviewIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReviewVoteCounts", reflect.TypeOf((*MockResourceRepositoryInterface)(nil).GetReviewVoteCounts), reviewIDs)
+}
+
+// GetReviewsByResourceIDs mocks base method.
+func (m *MockResourceRepositoryInterface) GetReviewsByResourceIDs(resourceIDs []uint) ([]models.Review, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReviewsByResourceIDs", resourceIDs)
+	ret0, _ := ret[0].([]models.Review)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReviewsByResourceIDs indicates an expected call of GetReviewsByResourceIDs.
+func (mr *MockResourceRepositoryInterfaceMockRecorder) GetReviewsByResourceIDs(resourceIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReviewsByResourceIDs", reflect.TypeOf((*MockResourceRepositoryInterface)(nil).GetReviewsByResourceIDs), resourceIDs)
+}
+
+// GetReviewsByUserID mocks base method.
+func (m *MockResourceRepositoryInterface) GetReviewsByUserID(userID uint) ([]models.Review, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReviewsByUserID", userID)
+	ret0, _ := ret[0].([]models.Review)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReviewsByUserID indicates an expected call of GetReviewsByUserID.
+func (mr *MockResourceRepositoryInterfaceMockRecorder) GetReviewsByUserID(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReviewsByUserID", reflect.TypeOf((*MockResourceRepositoryInterface)(nil).GetReviewsByUserID), userID)
+}
+
+// SetResourceMoodTags mocks base method.
+func (m *MockResourceRepositoryInterface) SetResourceMoodTags(resourceID uint, moods []models.MoodType) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetResourceMoodTags", resourceID, moods)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetResourceMoodTags indicates an expected call of SetResourceMoodTags.
+func (mr *MockResourceRepositoryInterfaceMockRecorder) SetResourceMoodTags(resourceID, moods interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetResourceMoodTags", reflect.TypeOf((*MockResourceRepositoryInterface)(nil).SetResourceMoodTags), resourceID, moods)
+}
+
+// SetResourceStatus mocks base method.
+func (m *MockResourceRepositoryInterface) SetResourceStatus(resourceID uint, status models.ModerationStatus) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetResourceStatus", resourceID, status)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetResourceStatus indicates an expected call of SetResourceStatus.
+func (mr *MockResourceRepositoryInterfaceMockRecorder) SetResourceStatus(resourceID, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetResourceStatus", reflect.TypeOf((*MockResourceRepositoryInterface)(nil).SetResourceStatus), resourceID, status)
+}
+
+// SetReviewStatus mocks base method.
+func (m *MockResourceRepositoryInterface) SetReviewStatus(reviewID uint, status models.ModerationStatus) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetReviewStatus", reviewID, status)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetReviewStatus indicates an expected call of SetReviewStatus.
+func (mr *MockResourceRepositoryInterfaceMockRecorder) SetReviewStatus(reviewID, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetReviewStatus", reflect.TypeOf((*MockResourceRepositoryInterface)(nil).SetReviewStatus), reviewID, status)
+}
+
+// UpdateLinkStatus mocks base method.
+func (m *MockResourceRepositoryInterface) UpdateLinkStatus(resourceID uint, status models.LinkStatus, failures int, checkedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLinkStatus", resourceID, status, failures, checkedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateLinkStatus indicates an expected call of UpdateLinkStatus.
+func (mr *MockResourceRepositoryInterfaceMockRecorder) UpdateLinkStatus(resourceID, status, failures, checkedAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLinkStatus", reflect.TypeOf((*MockResourceRepositoryInterface)(nil).UpdateLinkStatus), resourceID, status, failures, checkedAt)
+}
+
+// UpdateResource mocks base method.
+func (m *MockResourceRepositoryInterface) UpdateResource(resource *models.Resource) (models.Resource, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateResource", resource)
+	ret0, _ := ret[0].(models.Resource)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateResource indicates an expected call of UpdateResource.
+func (mr *MockResourceRepositoryInterfaceMockRecorder) UpdateResource(resource interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateResource", reflect.TypeOf((*MockResourceRepositoryInterface)(nil).UpdateResource), resource)
+}
+
+// UpdateReview mocks base method.
+func (m *MockResourceRepositoryInterface) UpdateReview(review *models.Review) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateReview", review)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateReview indicates an expected call of UpdateReview.
+func (mr *MockResourceRepositoryInterfaceMockRecorder) UpdateReview(review interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateReview", reflect.TypeOf((*MockResourceRepositoryInterface)(nil).UpdateReview), review)
+}
+
+// UpsertReviewVote mocks base method.
+func (m *MockResourceRepositoryInterface) UpsertReviewVote(vote *models.ReviewVote) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertReviewVote", vote)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertReviewVote indicates an expected call of UpsertReviewVote.
+func (mr *MockResourceRepositoryInterfaceMockRecorder) UpsertReviewVote(vote interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertReviewVote", reflect.TypeOf((*MockResourceRepositoryInterface)(nil).UpsertReviewVote), vote)
+}
@@ -0,0 +1,64 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: repository/review_data.repository.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockReviewDataRepositoryInterface is a mock of ReviewDataRepositoryInterface interface.
+type MockReviewDataRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockReviewDataRepositoryInterfaceMockRecorder
+}
+
+// MockReviewDataRepositoryInterfaceMockRecorder is the mock recorder for MockReviewDataRepositoryInterface.
+type MockReviewDataRepositoryInterfaceMockRecorder struct {
+	mock *MockReviewDataRepositoryInterface
+}
+
+// NewMockReviewDataRepositoryInterface creates a new mock instance.
+func NewMockReviewDataRepositoryInterface(ctrl *gomock.Controller) *MockReviewDataRepositoryInterface {
+	mock := &MockReviewDataRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockReviewDataRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReviewDataRepositoryInterface) EXPECT() *MockReviewDataRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// GetResourcePreferences mocks base method.
+func (m *MockReviewDataRepositoryInterface) GetResourcePreferences(resourceID uint) (map[uint]float64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetResourcePreferences", resourceID)
+	ret0, _ := ret[0].(map[uint]float64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetResourcePreferences indicates an expected call of GetResourcePreferences.
+func (mr *MockReviewDataRepositoryInterfaceMockRecorder) GetResourcePreferences(resourceID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetResourcePreferences", reflect.TypeOf((*MockReviewDataRepositoryInterface)(nil).GetResourcePreferences), resourceID)
+}
+
+// GetUserPreferences mocks base method.
+func (m *MockReviewDataRepositoryInterface) GetUserPreferences(userID uint) (map[uint]float64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserPreferences", userID)
+	ret0, _ := ret[0].(map[uint]float64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserPreferences indicates an expected call of GetUserPreferences.
+func (mr *MockReviewDataRepositoryInterfaceMockRecorder) GetUserPreferences(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserPreferences", reflect.TypeOf((*MockReviewDataRepositoryInterface)(nil).GetUserPreferences), userID)
+}
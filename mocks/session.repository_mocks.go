@@ -0,0 +1,181 @@
+package mocks
+
+import (
+	"time"
+
+	"github.com/anirudhgray/mood-harbour-backend/models"
+	"github.com/golang/mock/gomock"
+)
+
+// MockSessionRepository is a mock for SessionRepositoryInterface.
+type MockSessionRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockSessionRepositoryMockRecorder
+}
+
+// NewMockSessionRepository creates a new mock for SessionRepositoryInterface.
+func NewMockSessionRepository(ctrl *gomock.Controller) *MockSessionRepository {
+	mock := &MockSessionRepository{ctrl: ctrl}
+	mock.recorder = &MockSessionRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT methods for expected calls with return values
+func (m *MockSessionRepository) EXPECT() *MockSessionRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateSession mocks the CreateSession method.
+func (m *MockSessionRepository) CreateSession(session *models.Session) error {
+	ret := m.ctrl.Call(m, "CreateSession", session)
+	err, _ := ret[0].(error)
+	return err
+}
+
+// GetSessionByJTI mocks the GetSessionByJTI method.
+func (m *MockSessionRepository) GetSessionByJTI(jti string) (models.Session, error) {
+	ret := m.ctrl.Call(m, "GetSessionByJTI", jti)
+	session, _ := ret[0].(models.Session)
+	err, _ := ret[1].(error)
+	return session, err
+}
+
+// GetSessionByID mocks the GetSessionByID method.
+func (m *MockSessionRepository) GetSessionByID(id uint) (models.Session, error) {
+	ret := m.ctrl.Call(m, "GetSessionByID", id)
+	session, _ := ret[0].(models.Session)
+	err, _ := ret[1].(error)
+	return session, err
+}
+
+// GetActiveSessions mocks the GetActiveSessions method.
+func (m *MockSessionRepository) GetActiveSessions(userID uint) ([]models.Session, error) {
+	ret := m.ctrl.Call(m, "GetActiveSessions", userID)
+	sessions, _ := ret[0].([]models.Session)
+	err, _ := ret[1].(error)
+	return sessions, err
+}
+
+// TouchSession mocks the TouchSession method.
+func (m *MockSessionRepository) TouchSession(id uint, at time.Time) error {
+	ret := m.ctrl.Call(m, "TouchSession", id, at)
+	err, _ := ret[0].(error)
+	return err
+}
+
+// ExtendSession mocks the ExtendSession method.
+func (m *MockSessionRepository) ExtendSession(id uint, client models.ClientInfo, at, expiresAt time.Time) error {
+	ret := m.ctrl.Call(m, "ExtendSession", id, client, at, expiresAt)
+	err, _ := ret[0].(error)
+	return err
+}
+
+// RevokeSession mocks the RevokeSession method.
+func (m *MockSessionRepository) RevokeSession(userID, sessionID uint, at time.Time) error {
+	ret := m.ctrl.Call(m, "RevokeSession", userID, sessionID, at)
+	err, _ := ret[0].(error)
+	return err
+}
+
+// RevokeUserSessions mocks the RevokeUserSessions method.
+func (m *MockSessionRepository) RevokeUserSessions(userID, exceptSessionID uint, at time.Time) error {
+	ret := m.ctrl.Call(m, "RevokeUserSessions", userID, exceptSessionID, at)
+	err, _ := ret[0].(error)
+	return err
+}
+
+// CreateSocialLoginCode mocks the CreateSocialLoginCode method.
+func (m *MockSessionRepository) CreateSocialLoginCode(code *models.SocialLoginCode) error {
+	ret := m.ctrl.Call(m, "CreateSocialLoginCode", code)
+	err, _ := ret[0].(error)
+	return err
+}
+
+// GetSocialLoginCodeByHash mocks the GetSocialLoginCodeByHash method.
+func (m *MockSessionRepository) GetSocialLoginCodeByHash(codeHash string) (models.SocialLoginCode, error) {
+	ret := m.ctrl.Call(m, "GetSocialLoginCodeByHash", codeHash)
+	code, _ := ret[0].(models.SocialLoginCode)
+	err, _ := ret[1].(error)
+	return code, err
+}
+
+// UseSocialLoginCode mocks the UseSocialLoginCode method.
+func (m *MockSessionRepository) UseSocialLoginCode(id uint, at time.Time) (bool, error) {
+	ret := m.ctrl.Call(m, "UseSocialLoginCode", id, at)
+	ok, _ := ret[0].(bool)
+	err, _ := ret[1].(error)
+	return ok, err
+}
+
+// PurgeSocialLoginCodes mocks the PurgeSocialLoginCodes method.
+func (m *MockSessionRepository) PurgeSocialLoginCodes(before time.Time) (int64, error) {
+	ret := m.ctrl.Call(m, "PurgeSocialLoginCodes", before)
+	deleted, _ := ret[0].(int64)
+	err, _ := ret[1].(error)
+	return deleted, err
+}
+
+// MockSessionRepositoryMockRecorder is a recorder for the MockSessionRepository.
+type MockSessionRepositoryMockRecorder struct {
+	mock *MockSessionRepository
+}
+
+// CreateSession mocks the CreateSession method.
+func (m *MockSessionRepositoryMockRecorder) CreateSession(session interface{}) *gomock.Call {
+	return m.mock.ctrl.RecordCall(m.mock, "CreateSession", session)
+}
+
+// GetSessionByJTI mocks the GetSessionByJTI method.
+func (m *MockSessionRepositoryMockRecorder) GetSessionByJTI(jti interface{}) *gomock.Call {
+	return m.mock.ctrl.RecordCall(m.mock, "GetSessionByJTI", jti)
+}
+
+// GetSessionByID mocks the GetSessionByID method.
+func (m *MockSessionRepositoryMockRecorder) GetSessionByID(id interface{}) *gomock.Call {
+	return m.mock.ctrl.RecordCall(m.mock, "GetSessionByID", id)
+}
+
+// GetActiveSessions mocks the GetActiveSessions method.
+func (m *MockSessionRepositoryMockRecorder) GetActiveSessions(userID interface{}) *gomock.Call {
+	return m.mock.ctrl.RecordCall(m.mock, "GetActiveSessions", userID)
+}
+
+// TouchSession mocks the TouchSession method.
+func (m *MockSessionRepositoryMockRecorder) TouchSession(id, at interface{}) *gomock.Call {
+	return m.mock.ctrl.RecordCall(m.mock, "TouchSession", id, at)
+}
+
+// ExtendSession mocks the ExtendSession method.
+func (m *MockSessionRepositoryMockRecorder) ExtendSession(id, client, at, expiresAt interface{}) *gomock.Call {
+	return m.mock.ctrl.RecordCall(m.mock, "ExtendSession", id, client, at, expiresAt)
+}
+
+// RevokeSession mocks the RevokeSession method.
+func (m *MockSessionRepositoryMockRecorder) RevokeSession(userID, sessionID, at interface{}) *gomock.Call {
+	return m.mock.ctrl.RecordCall(m.mock, "RevokeSession", userID, sessionID, at)
+}
+
+// RevokeUserSessions mocks the RevokeUserSessions method.
+func (m *MockSessionRepositoryMockRecorder) RevokeUserSessions(userID, exceptSessionID, at interface{}) *gomock.Call {
+	return m.mock.ctrl.RecordCall(m.mock, "RevokeUserSessions", userID, exceptSessionID, at)
+}
+
+// CreateSocialLoginCode mocks the CreateSocialLoginCode method.
+func (m *MockSessionRepositoryMockRecorder) CreateSocialLoginCode(code interface{}) *gomock.Call {
+	return m.mock.ctrl.RecordCall(m.mock, "CreateSocialLoginCode", code)
+}
+
+// GetSocialLoginCodeByHash mocks the GetSocialLoginCodeByHash method.
+func (m *MockSessionRepositoryMockRecorder) GetSocialLoginCodeByHash(codeHash interface{}) *gomock.Call {
+	return m.mock.ctrl.RecordCall(m.mock, "GetSocialLoginCodeByHash", codeHash)
+}
+
+// UseSocialLoginCode mocks the UseSocialLoginCode method.
+func (m *MockSessionRepositoryMockRecorder) UseSocialLoginCode(id, at interface{}) *gomock.Call {
+	return m.mock.ctrl.RecordCall(m.mock, "UseSocialLoginCode", id, at)
+}
+
+// PurgeSocialLoginCodes mocks the PurgeSocialLoginCodes method.
+func (m *MockSessionRepositoryMockRecorder) PurgeSocialLoginCodes(before interface{}) *gomock.Call {
+	return m.mock.ctrl.RecordCall(m.mock, "PurgeSocialLoginCodes", before)
+}
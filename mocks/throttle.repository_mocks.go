@@ -0,0 +1,81 @@
+package mocks
+
+import (
+	"time"
+
+	"github.com/anirudhgray/mood-harbour-backend/models"
+	"github.com/golang/mock/gomock"
+)
+
+// MockThrottleRepository is a mock for ThrottleRepositoryInterface.
+type MockThrottleRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockThrottleRepositoryMockRecorder
+}
+
+// NewMockThrottleRepository creates a new mock for ThrottleRepositoryInterface.
+func NewMockThrottleRepository(ctrl *gomock.Controller) *MockThrottleRepository {
+	mock := &MockThrottleRepository{ctrl: ctrl}
+	mock.recorder = &MockThrottleRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT methods for expected calls with return values
+func (m *MockThrottleRepository) EXPECT() *MockThrottleRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetThrottles mocks the GetThrottles method.
+func (m *MockThrottleRepository) GetThrottles(subjects []string) ([]models.AuthThrottle, error) {
+	ret := m.ctrl.Call(m, "GetThrottles", subjects)
+	throttles, _ := ret[0].([]models.AuthThrottle)
+	err, _ := ret[1].(error)
+	return throttles, err
+}
+
+// RecordFailure mocks the RecordFailure method.
+func (m *MockThrottleRepository) RecordFailure(subject string, at time.Time, forgetBefore time.Time) (models.AuthThrottle, error) {
+	ret := m.ctrl.Call(m, "RecordFailure", subject, at, forgetBefore)
+	throttle, _ := ret[0].(models.AuthThrottle)
+	err, _ := ret[1].(error)
+	return throttle, err
+}
+
+// LockThrottle mocks the LockThrottle method.
+func (m *MockThrottleRepository) LockThrottle(subject string, until time.Time) error {
+	ret := m.ctrl.Call(m, "LockThrottle", subject, until)
+	err, _ := ret[0].(error)
+	return err
+}
+
+// ResetThrottle mocks the ResetThrottle method.
+func (m *MockThrottleRepository) ResetThrottle(subject string) error {
+	ret := m.ctrl.Call(m, "ResetThrottle", subject)
+	err, _ := ret[0].(error)
+	return err
+}
+
+// MockThrottleRepositoryMockRecorder is a recorder for the MockThrottleRepository.
+type MockThrottleRepositoryMockRecorder struct {
+	mock *MockThrottleRepository
+}
+
+// GetThrottles mocks the GetThrottles method.
+func (m *MockThrottleRepositoryMockRecorder) GetThrottles(subjects interface{}) *gomock.Call {
+	return m.mock.ctrl.RecordCall(m.mock, "GetThrottles", subjects)
+}
+
+// RecordFailure mocks the RecordFailure method.
+func (m *MockThrottleRepositoryMockRecorder) RecordFailure(subject, at, forgetBefore interface{}) *gomock.Call {
+	return m.mock.ctrl.RecordCall(m.mock, "RecordFailure", subject, at, forgetBefore)
+}
+
+// LockThrottle mocks the LockThrottle method.
+func (m *MockThrottleRepositoryMockRecorder) LockThrottle(subject, until interface{}) *gomock.Call {
+	return m.mock.ctrl.RecordCall(m.mock, "LockThrottle", subject, until)
+}
+
+// ResetThrottle mocks the ResetThrottle method.
+func (m *MockThrottleRepositoryMockRecorder) ResetThrottle(subject interface{}) *gomock.Call {
+	return m.mock.ctrl.RecordCall(m.mock, "ResetThrottle", subject)
+}
@@ -0,0 +1,76 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: services/throttle.service.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockThrottleServiceInterface is a mock of ThrottleServiceInterface interface.
+type MockThrottleServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockThrottleServiceInterfaceMockRecorder
+}
+
+// MockThrottleServiceInterfaceMockRecorder is the mock recorder for MockThrottleServiceInterface.
+type MockThrottleServiceInterfaceMockRecorder struct {
+	mock *MockThrottleServiceInterface
+}
+
+// NewMockThrottleServiceInterface creates a new mock instance.
+func NewMockThrottleServiceInterface(ctrl *gomock.Controller) *MockThrottleServiceInterface {
+	mock := &MockThrottleServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockThrottleServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockThrottleServiceInterface) EXPECT() *MockThrottleServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Check mocks base method.
+func (m *MockThrottleServiceInterface) Check(action, email, ip string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Check", action, email, ip)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Check indicates an expected call of Check.
+func (mr *MockThrottleServiceInterfaceMockRecorder) Check(action, email, ip interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Check", reflect.TypeOf((*MockThrottleServiceInterface)(nil).Check), action, email, ip)
+}
+
+// RecordFailure mocks base method.
+func (m *MockThrottleServiceInterface) RecordFailure(action, email, ip string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordFailure", action, email, ip)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordFailure indicates an expected call of RecordFailure.
+func (mr *MockThrottleServiceInterfaceMockRecorder) RecordFailure(action, email, ip interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordFailure", reflect.TypeOf((*MockThrottleServiceInterface)(nil).RecordFailure), action, email, ip)
+}
+
+// Reset mocks base method.
+func (m *MockThrottleServiceInterface) Reset(action, email string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reset", action, email)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reset indicates an expected call of Reset.
+func (mr *MockThrottleServiceInterfaceMockRecorder) Reset(action, email interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reset", reflect.TypeOf((*MockThrottleServiceInterface)(nil).Reset), action, email)
+}
@@ -67,6 +67,29 @@ func (m *MockUserRepository) DeleteUserByID(userID uint) error {
 	return err
 }
 
+// SearchUsers mocks the SearchUsers method.
+func (m *MockUserRepository) SearchUsers(filter models.UserFilter, offset, limit int) ([]models.User, int64, error) {
+	ret := m.ctrl.Call(m, "SearchUsers", filter, offset, limit)
+	users, _ := ret[0].([]models.User) // Type assertion for []models.User
+	total, _ := ret[1].(int64)         // Type assertion for int64
+	err, _ := ret[2].(error)           // Type assertion for error
+	return users, total, err
+}
+
+// SetUserDisabled mocks the SetUserDisabled method.
+func (m *MockUserRepository) SetUserDisabled(userID uint, disabled bool) error {
+	ret := m.ctrl.Call(m, "SetUserDisabled", userID, disabled)
+	err, _ := ret[0].(error) // Type assertion for error
+	return err
+}
+
+// SetUserRole mocks the SetUserRole method.
+func (m *MockUserRepository) SetUserRole(userID uint, role models.Role) error {
+	ret := m.ctrl.Call(m, "SetUserRole", userID, role)
+	err, _ := ret[0].(error) // Type assertion for error
+	return err
+}
+
 // MockUserRepositoryMockRecorder is a recorder for the MockUserRepository.
 type MockUserRepositoryMockRecorder struct {
 	mock *MockUserRepository
@@ -101,3 +124,18 @@ func (m *MockUserRepositoryMockRecorder) SaveUser(user models.User) *gomock.Call
 func (m *MockUserRepositoryMockRecorder) DeleteUserByID(userID uint) *gomock.Call {
 	return m.mock.ctrl.RecordCall(m.mock, "DeleteUserByID", userID)
 }
+
+// SearchUsers mocks the SearchUsers method.
+func (m *MockUserRepositoryMockRecorder) SearchUsers(filter models.UserFilter, offset, limit int) *gomock.Call {
+	return m.mock.ctrl.RecordCall(m.mock, "SearchUsers", filter, offset, limit)
+}
+
+// SetUserDisabled mocks the SetUserDisabled method.
+func (m *MockUserRepositoryMockRecorder) SetUserDisabled(userID uint, disabled bool) *gomock.Call {
+	return m.mock.ctrl.RecordCall(m.mock, "SetUserDisabled", userID, disabled)
+}
+
+// SetUserRole mocks the SetUserRole method.
+func (m *MockUserRepositoryMockRecorder) SetUserRole(userID uint, role models.Role) *gomock.Call {
+	return m.mock.ctrl.RecordCall(m.mock, "SetUserRole", userID, role)
+}
@@ -0,0 +1,124 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: services/user_admin.service.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	models "github.com/anirudhgray/mood-harbour-backend/models"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockUserAdminServiceInterface is a mock of UserAdminServiceInterface interface.
+type MockUserAdminServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserAdminServiceInterfaceMockRecorder
+}
+
+// MockUserAdminServiceInterfaceMockRecorder is the mock recorder for MockUserAdminServiceInterface.
+type MockUserAdminServiceInterfaceMockRecorder struct {
+	mock *MockUserAdminServiceInterface
+}
+
+// NewMockUserAdminServiceInterface creates a new mock instance.
+func NewMockUserAdminServiceInterface(ctrl *gomock.Controller) *MockUserAdminServiceInterface {
+	mock := &MockUserAdminServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockUserAdminServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUserAdminServiceInterface) EXPECT() *MockUserAdminServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// GetUser mocks base method.
+func (m *MockUserAdminServiceInterface) GetUser(userID uint) (models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUser", userID)
+	ret0, _ := ret[0].(models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUser indicates an expected call of GetUser.
+func (mr *MockUserAdminServiceInterfaceMockRecorder) GetUser(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockUserAdminServiceInterface)(nil).GetUser), userID)
+}
+
+// GetUsers mocks base method.
+func (m *MockUserAdminServiceInterface) GetUsers(filter models.UserFilter, page, perPage int) (models.UserPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUsers", filter, page, perPage)
+	ret0, _ := ret[0].(models.UserPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUsers indicates an expected call of GetUsers.
+func (mr *MockUserAdminServiceInterfaceMockRecorder) GetUsers(filter, page, perPage interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsers", reflect.TypeOf((*MockUserAdminServiceInterface)(nil).GetUsers), filter, page, perPage)
+}
+
+// SendPasswordReset mocks base method.
+func (m *MockUserAdminServiceInterface) SendPasswordReset(userID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendPasswordReset", userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendPasswordReset indicates an expected call of SendPasswordReset.
+func (mr *MockUserAdminServiceInterfaceMockRecorder) SendPasswordReset(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendPasswordReset", reflect.TypeOf((*MockUserAdminServiceInterface)(nil).SendPasswordReset), userID)
+}
+
+// SetAdmin mocks base method.
+func (m *MockUserAdminServiceInterface) SetAdmin(adminID, userID uint, admin bool) (models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetAdmin", adminID, userID, admin)
+	ret0, _ := ret[0].(models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetAdmin indicates an expected call of SetAdmin.
+func (mr *MockUserAdminServiceInterfaceMockRecorder) SetAdmin(adminID, userID, admin interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAdmin", reflect.TypeOf((*MockUserAdminServiceInterface)(nil).SetAdmin), adminID, userID, admin)
+}
+
+// SetDisabled mocks base method.
+func (m *MockUserAdminServiceInterface) SetDisabled(adminID, userID uint, disabled bool) (models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetDisabled", adminID, userID, disabled)
+	ret0, _ := ret[0].(models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetDisabled indicates an expected call of SetDisabled.
+func (mr *MockUserAdminServiceInterfaceMockRecorder) SetDisabled(adminID, userID, disabled interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDisabled", reflect.TypeOf((*MockUserAdminServiceInterface)(nil).SetDisabled), adminID, userID, disabled)
+}
+
+// VerifyUser mocks base method.
+func (m *MockUserAdminServiceInterface) VerifyUser(userID uint) (models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyUser", userID)
+	ret0, _ := ret[0].(models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyUser indicates an expected call of VerifyUser.
+func (mr *MockUserAdminServiceInterfaceMockRecorder) VerifyUser(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyUser", reflect.TypeOf((*MockUserAdminServiceInterface)(nil).VerifyUser), userID)
+}
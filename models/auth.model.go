@@ -3,7 +3,7 @@ package models
 import (
 	"time"
 
-	"golang.org/x/crypto/bcrypt"
+	"github.com/anirudhgray/mood-harbour-backend/utils/password"
 	"gorm.io/gorm"
 )
 
@@ -13,25 +13,11 @@ type User struct {
 	Name         string `gorm:"size:255;not null;"`
 	ProfileImage string `gorm:"size:255;"`
 	Verified     bool   `gorm:"default:false"`
-	Admin        bool   `gorm:"default:false"` // TODO add admin route to disable user
-	Disabled     bool   `gorm:"default:false"` // TODO disabled user should not be able to login/access routes => edit middleware
+	Role         Role   `gorm:"size:32;not null;default:user;index"`
+	Disabled     bool   `gorm:"default:false"` // Disabled users can't log in, and their sessions are rejected
 
 }
 
-type DeletionConfirmation struct {
-	gorm.Model
-	Email     string `gorm:"unique"`
-	OTP       string
-	ValidTill time.Time
-}
-
-type ForgotPassword struct {
-	gorm.Model
-	Email     string `gorm:"unique"`
-	OTP       string
-	ValidTill time.Time
-}
-
 type PasswordAuth struct {
 	gorm.Model
 	Email    string `gorm:"size:255;not null;unique;"`
@@ -39,20 +25,34 @@ type PasswordAuth struct {
 	UserID   uint   `gorm:"unique;"`
 }
 
+// HashPassword replaces the plaintext Password with its hash, made by the configured hasher, see utils/password.
 func (pa *PasswordAuth) HashPassword() error {
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(pa.Password), bcrypt.DefaultCost)
+	hashedPassword, err := password.Hash(pa.Password)
 
 	if err != nil {
 		return err
 	}
-	pa.Password = string(hashedPassword)
+	pa.Password = hashedPassword
 	return nil
 }
 
-type VerificationEntry struct {
+// Purposes of one-time tokens. Each email has at most one token per purpose, a new one replaces the last.
+const (
+	OneTimeTokenVerifyEmail   = "verify-email"
+	OneTimeTokenResetPassword = "reset-password"
+	OneTimeTokenDeleteAccount = "delete-account"
+)
+
+// OneTimeToken is a code emailed to a user to confirm an action, stored hashed. It can be used once before it expires,
+// and only a few codes can be tried against it.
+type OneTimeToken struct {
 	gorm.Model
-	Email string `gorm:"unique"`
-	OTP   string
+	Email     string    `gorm:"size:255;not null;uniqueIndex:idx_one_time_token_email_purpose"`
+	Purpose   string    `gorm:"size:32;not null;uniqueIndex:idx_one_time_token_email_purpose"`
+	TokenHash string    `gorm:"size:64;not null"`
+	ExpiresAt time.Time `gorm:"not null;index"`
+	Attempts  int       `gorm:"not null;default:0"`
+	UsedAt    *time.Time
 }
 
 type AuthProvider struct {
@@ -61,3 +61,180 @@ type AuthProvider struct {
 	UserID       uint   // Foreign key to the User model
 	ProviderName string `gorm:"size:255;not null"`
 }
+
+// PasskeyProvider is the ProviderName of the AuthProvider of each passkey, whose ProviderKey is the credential ID.
+const PasskeyProvider = "passkey"
+
+// Passkey is a WebAuthn credential a user can log in with instead of their password, see utils/webauthn.
+type Passkey struct {
+	gorm.Model
+	UserID         uint   `gorm:"not null;index"`                // Foreign key to the User model
+	AuthProviderID uint   `gorm:"not null;uniqueIndex"`          // Foreign key to the AuthProvider model
+	CredentialID   string `gorm:"size:255;not null;uniqueIndex"` // Base64url encoded, as the ProviderKey of its AuthProvider
+	PublicKey      []byte `gorm:"not null"`                      // COSE_Key
+	SignCount      uint32
+	Name           string `gorm:"size:64"`
+	LastUsedAt     *time.Time
+}
+
+// PasskeyView is how a passkey is shown to its user.
+type PasskeyView struct {
+	ID         uint       `json:"id"`
+	Name       string     `json:"name"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+}
+
+// Purposes of passkey challenges.
+const (
+	PasskeyRegistration = "registration"
+	PasskeyLogin        = "login"
+)
+
+// PasskeyChallenge is a passkey registration or login in progress. Its challenge is signed by the authenticator,
+// and can be used once.
+type PasskeyChallenge struct {
+	gorm.Model
+	UserID    uint   `gorm:"index"`                        // The user registering a passkey, 0 for logins
+	Challenge string `gorm:"size:64;not null;uniqueIndex"` // Base64url encoded
+	Purpose   string `gorm:"size:16;not null"`
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+}
+
+// Session is a login on one device. Access tokens carry its JTI as their jti claim, and are only accepted while it's active,
+// so revoking a session logs the device out straight away.
+type Session struct {
+	gorm.Model
+	UserID     uint   `gorm:"not null;index"`               // Foreign key to the User model
+	JTI        string `gorm:"size:64;not null;uniqueIndex"` // Random, the jti claim of the session's access tokens
+	UserAgent  string `gorm:"size:255"`
+	IP         string `gorm:"size:64"`
+	LastSeenAt time.Time
+	ExpiresAt  time.Time // When its latest refresh token expires
+	RevokedAt  *time.Time
+}
+
+// SessionView is how a session is shown to its user.
+type SessionView struct {
+	ID         uint      `json:"id"`
+	UserAgent  string    `json:"user_agent"`
+	IP         string    `json:"ip"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	Current    bool      `json:"current"` // Whether it's the session making the request
+}
+
+// ClientInfo describes the device a session is created or refreshed from.
+type ClientInfo struct {
+	UserAgent string
+	IP        string
+}
+
+// RefreshToken is an opaque, single use token exchanged for a new access token and refresh token, see AuthService.RefreshTokens.
+// Only a hash of the token is stored. Every refresh token of a session descends from its login through a chain of rotations:
+// if a token which has already been used is presented again, the whole session is revoked.
+type RefreshToken struct {
+	gorm.Model
+	UserID    uint   `gorm:"not null;index"`               // Foreign key to the User model
+	SessionID uint   `gorm:"not null;index"`               // Foreign key to the Session model
+	TokenHash string `gorm:"size:64;not null;uniqueIndex"` // SHA-256 of the token, hex encoded
+	ExpiresAt time.Time
+	UsedAt    *time.Time // Set when the token is rotated
+}
+
+// AuthTokens are the tokens handed out on login and refresh. AccessToken is a short-lived JWT sent as a bearer token,
+// RefreshToken is exchanged for new tokens when it expires.
+type AuthTokens struct {
+	AccessToken  string    `json:"token"`
+	RefreshToken string    `json:"refresh_token"`
+	ExpiresAt    time.Time `json:"expires_at"` // When the access token expires
+}
+
+// LoginResult is the outcome of the first step of logging in. Users with two-factor authentication get an MFAToken instead of
+// Tokens, which is exchanged for them along with a code, see AuthService.CompleteMFALogin.
+type LoginResult struct {
+	Tokens      AuthTokens
+	User        User
+	MFARequired bool
+	MFAToken    string
+	MFAExpires  time.Time
+}
+
+// TOTPFactor is a user's authenticator app. It only protects their logins once confirmed with a first code.
+type TOTPFactor struct {
+	gorm.Model
+	UserID       uint   `gorm:"not null;uniqueIndex"` // Foreign key to the User model
+	Secret       string `gorm:"size:64;not null"`     // Base32 encoded
+	ConfirmedAt  *time.Time
+	LastUsedStep int64 // Time step of the last code accepted, so codes can't be replayed
+}
+
+// RecoveryCode is a single use code which stands in for a TOTP code when the user has lost their authenticator.
+// Only a hash of the code is stored.
+type RecoveryCode struct {
+	gorm.Model
+	UserID   uint   `gorm:"not null;index"`               // Foreign key to the User model
+	CodeHash string `gorm:"size:64;not null;uniqueIndex"` // SHA-256 of the normalized code, hex encoded
+	UsedAt   *time.Time
+}
+
+// MFAChallenge is a password login waiting for its second factor. Its opaque token is handed to the client in place of
+// AuthTokens, and only a hash of it is stored. It can be completed once, within a few attempts.
+type MFAChallenge struct {
+	gorm.Model
+	UserID    uint   `gorm:"not null;index"`               // Foreign key to the User model
+	TokenHash string `gorm:"size:64;not null;uniqueIndex"` // SHA-256 of the token, hex encoded
+	ExpiresAt time.Time
+	Attempts  int
+	UsedAt    *time.Time
+}
+
+// SocialLoginCode is a social login waiting for the frontend to pick it up. Its code is handed to the frontend in the redirect
+// after the provider's callback, in place of AuthTokens which would end up in logs and browser history. Only a hash of it is
+// stored, and it can be exchanged once, shortly after.
+type SocialLoginCode struct {
+	gorm.Model
+	UserID    uint      `gorm:"not null;index"`               // Foreign key to the User model
+	CodeHash  string    `gorm:"size:64;not null;uniqueIndex"` // SHA-256 of the code, hex encoded
+	ExpiresAt time.Time `gorm:"index"`
+	UsedAt    *time.Time
+}
+
+// MFAStatus describes a user's two-factor authentication.
+type MFAStatus struct {
+	TOTPEnabled       bool  `json:"totp_enabled"`
+	RecoveryCodesLeft int64 `json:"recovery_codes_left"`
+}
+
+// TOTPEnrollment is what a user needs to add their secret to an authenticator app.
+type TOTPEnrollment struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauth_uri"` // To be shown as a QR code
+}
+
+// AuthThrottle counts the recent failed attempts at guessing a password or OTP, for an account or an IP address.
+// Failures are forgotten after a while without any, see services.ThrottlePolicy.
+type AuthThrottle struct {
+	gorm.Model
+	Subject       string `gorm:"size:320;not null;uniqueIndex"` // Action and account or IP address, e.g. login:account:<email>
+	Failures      int    `gorm:"not null;default:0"`
+	LastFailureAt time.Time
+	LockedUntil   *time.Time
+}
+
+// UserFilter narrows down the users listed to admins. Nil flags match either value.
+type UserFilter struct {
+	Query    string // Part of the user's name or email, case insensitive
+	Role     Role   // Empty for any role
+	Disabled *bool
+	Verified *bool
+}
+
+// UserPage represents a single page of users, most recently registered first.
+type UserPage struct {
+	Users   []User `json:"users"`
+	Page    int    `json:"page"`
+	PerPage int    `json:"per_page"`
+	Total   int64  `json:"total"`
+}
@@ -0,0 +1,43 @@
+package models
+
+import "gorm.io/gorm"
+
+// Bookmark represents a resource saved by a user. Bookmarks also count as an implicit "like" for recommendations.
+type Bookmark struct {
+	gorm.Model
+	UserID     uint `gorm:"not null;uniqueIndex:idx_bookmarks_user_resource"` // Foreign key to the User model
+	ResourceID uint `gorm:"not null;uniqueIndex:idx_bookmarks_user_resource"` // Foreign key to the Resource model
+}
+
+// Collection represents a named, ordered list of resources put together by a user, e.g. "Panic toolkit".
+// Public collections can be viewed and followed by other users.
+type Collection struct {
+	gorm.Model
+	UserID      uint   `gorm:"not null;index"` // Foreign key to the User model
+	Name        string `gorm:"size:255;not null"`
+	Description string `gorm:"size:1000;"`
+	Public      bool   `gorm:"not null;default:false"`
+}
+
+// CollectionItem represents a resource in a collection. Many to many relationship between Collection and Resource.
+type CollectionItem struct {
+	gorm.Model
+	CollectionID uint `gorm:"not null;uniqueIndex:idx_collection_items_collection_resource"` // Foreign key to the Collection model
+	ResourceID   uint `gorm:"not null;uniqueIndex:idx_collection_items_collection_resource"` // Foreign key to the Resource model
+	Position     int  `gorm:"not null"`                                                      // Order of the resource within the collection, starting at 0
+}
+
+// CollectionFollow represents a user following another user's public collection.
+type CollectionFollow struct {
+	gorm.Model
+	CollectionID uint `gorm:"not null;uniqueIndex:idx_collection_follows_collection_user"` // Foreign key to the Collection model
+	UserID       uint `gorm:"not null;uniqueIndex:idx_collection_follows_collection_user"` // Foreign key to the User model
+}
+
+// CollectionResponse represents the response body for a single collection, with its resources in order.
+type CollectionResponse struct {
+	ID         uint       `json:"id"`
+	Collection Collection `json:"collection"`
+	Resources  []Resource `json:"resources"`
+	Followers  int64      `json:"followers"`
+}
@@ -0,0 +1,60 @@
+package models
+
+import "gorm.io/gorm"
+
+// ModerationStatus represents whether a resource or review is visible to users.
+// The moderation statuses are: Published, PendingModeration, Hidden.
+type ModerationStatus int
+
+// ReportTargetType is the kind of content a report is raised against.
+type ReportTargetType string
+
+// ReportStatus represents whether a report is still waiting in the moderation queue.
+type ReportStatus int
+
+// ModerationAction is the action a moderator took to resolve a report.
+type ModerationAction string
+
+const (
+	Published ModerationStatus = iota + 1
+	PendingModeration
+	Hidden
+)
+
+const (
+	ResourceTarget ReportTargetType = "resource"
+	ReviewTarget   ReportTargetType = "review"
+)
+
+const (
+	ReportOpen ReportStatus = iota + 1
+	ReportResolved
+)
+
+const (
+	ApproveAction ModerationAction = "approve"
+	HideAction    ModerationAction = "hide"
+	DeleteAction  ModerationAction = "delete"
+)
+
+// Report represents a flag raised against a resource or review, either by a user or automatically by the content filter.
+type Report struct {
+	gorm.Model
+	TargetType  ReportTargetType `gorm:"size:20;not null;index:idx_reports_target"`
+	TargetID    uint             `gorm:"not null;index:idx_reports_target"`
+	ReportedBy  uint             // Foreign key to the User model, 0 if the content was flagged automatically
+	Reason      string           `gorm:"size:1000;not null"`
+	AutoFlagged bool             `gorm:"not null;default:false"`
+	Status      ReportStatus     `gorm:"not null;default:1"`
+	ResolvedBy  uint             // Foreign key to the User model of the moderator who resolved the report
+	Resolution  ModerationAction `gorm:"size:20"`
+}
+
+// ModerationQueueItem represents a single piece of flagged content in the moderation queue, along with all open reports against it.
+type ModerationQueueItem struct {
+	TargetType ReportTargetType `json:"target_type"`
+	TargetID   uint             `json:"target_id"`
+	Resource   *Resource        `json:"resource,omitempty"`
+	Review     *Review          `json:"review,omitempty"`
+	Reports    []Report         `json:"reports"`
+}
@@ -24,9 +24,10 @@ const (
 // Mood represents a single mood entry made by a specific user.
 type Mood struct {
 	gorm.Model
-	UserID uint     `gorm:"not null"` // Foreign key to the User model
-	Mood   MoodType `gorm:"not null"`
-	Notes  string   `gorm:"size:255;"` // Notes are optional
+	UserID    uint     `gorm:"not null"` // Foreign key to the User model
+	Mood      MoodType `gorm:"not null"`
+	Notes     string   `gorm:"size:255;"` // Notes are optional, Markdown source
+	NotesHTML string   `gorm:"type:text"` // Sanitized HTML rendered from Notes
 }
 
 // Attribute represents a single attribute that can be associated with a mood entry. This can be anything you might want associated with a mood entry.
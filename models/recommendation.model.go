@@ -0,0 +1,123 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// UserSimilarity is a precomputed similarity coefficient between two users, see recommender.CalculateUserSimilarity.
+// Rows are replaced wholesale every time a user's recommendations are recomputed.
+type UserSimilarity struct {
+	UserID      uint    `gorm:"primaryKey;autoIncrement:false"`
+	OtherUserID uint    `gorm:"primaryKey;autoIncrement:false"`
+	Similarity  float64 `gorm:"not null"`
+	ComputedAt  time.Time
+}
+
+// UserRecommendation is a single precomputed recommendation for a user. Rank starts at 1 for the best recommendation.
+type UserRecommendation struct {
+	UserID      uint                   `gorm:"primaryKey;autoIncrement:false"`
+	ResourceID  uint                   `gorm:"primaryKey;autoIncrement:false"`
+	Probability float64                `gorm:"not null"`
+	Rank        int                    `gorm:"not null;index"`
+	Evidence    RecommendationEvidence `gorm:"serializer:json"`
+	ComputedAt  time.Time
+}
+
+// RecommendationEvidence records why a resource was recommended, see recommender.Explain.
+// Similar users stay anonymous: only how many there were and their similarity coefficients are kept.
+type RecommendationEvidence struct {
+	Algorithm            string    `json:"algorithm,omitempty"`
+	SimilarUsersLiked    int       `json:"similar_users_liked,omitempty"`
+	SimilarUsersDisliked int       `json:"similar_users_disliked,omitempty"`
+	TopSimilarities      []float64 `json:"top_similarities,omitempty"`  // Similarity coefficients of the most similar users who liked it
+	SimilarResources     []uint    `json:"similar_resources,omitempty"` // Resources the user liked which it is most similar to
+	MatchingTerms        []string  `json:"matching_terms,omitempty"`    // Words it shares with what the user liked
+	AverageRating        float64   `json:"average_rating,omitempty"`    // Bayesian average rating of a popular resource
+	Reviews              int       `json:"reviews,omitempty"`
+	RecentReviews        int       `json:"recent_reviews,omitempty"`
+	Mood                 MoodType  `json:"mood,omitempty"` // Set when re-ranked for the user's mood
+	MoodTagged           bool      `json:"mood_tagged,omitempty"`
+	SimilarMoodLikes     int       `json:"similar_mood_likes,omitempty"` // Similar users who liked it while in a similar mood
+}
+
+// RecommendationStatus tracks when a user's recommendations were last computed, and whether they are out of date.
+// A user is marked stale when they or someone who reviewed the same resource adds a review, and picked up by the stale recommendations job.
+type RecommendationStatus struct {
+	UserID     uint `gorm:"primaryKey;autoIncrement:false"`
+	ComputedAt *time.Time
+	Stale      bool `gorm:"not null;default:false;index"`
+	StaleSince *time.Time
+}
+
+// DismissalReason is why a user dismissed a recommendation.
+type DismissalReason int
+
+const (
+	NotInterested DismissalReason = iota + 1
+	AlreadySeen                   // Already seen elsewhere
+)
+
+// RecommendationDismissal is a resource the user doesn't want recommended, until they undo the dismissal.
+// It counts as a soft dislike for recommendations: a dislike for collaborative filtering, and half of one for content-based recommendations.
+// A review or bookmark of the same resource takes precedence.
+type RecommendationDismissal struct {
+	gorm.Model
+	UserID     uint            `gorm:"not null;uniqueIndex:idx_recommendation_dismissals_user_resource"` // Foreign key to the User model
+	ResourceID uint            `gorm:"not null;uniqueIndex:idx_recommendation_dismissals_user_resource"` // Foreign key to the Resource model
+	Reason     DismissalReason `gorm:"not null"`
+}
+
+// DismissalResponse represents a dismissed recommendation in the user's list of dismissals.
+type DismissalResponse struct {
+	Resource    Resource        `json:"resource"`
+	Reason      DismissalReason `json:"reason"`
+	DismissedAt time.Time       `json:"dismissed_at"`
+}
+
+// CuratedResource is a resource picked by an admin to be recommended to everyone, filling up personalised recommendations.
+type CuratedResource struct {
+	gorm.Model
+	ResourceID uint `gorm:"not null;uniqueIndex"` // Foreign key to the Resource model
+	CuratedBy  uint `gorm:"not null"`             // Foreign key to the User model of the admin
+}
+
+// FactorizationModel is a trained version of the matrix factorization recommender, see recommender.TrainFactorization.
+// Every training run adds a new version and activates it. Older versions are kept for a while so they can be activated again.
+type FactorizationModel struct {
+	gorm.Model
+	Active          bool                         `gorm:"not null;default:false;index"` // Only one version is active at a time
+	Hyperparameters FactorizationHyperparameters `gorm:"serializer:json"`
+	Users           int
+	Resources       int
+	Interactions    int
+	RMSE            float64 // Root mean squared error on the interactions it was trained on
+	TrainedAt       time.Time
+}
+
+// FactorizationHyperparameters configure training of the matrix factorization recommender.
+type FactorizationHyperparameters struct {
+	Factors         int     `json:"factors"` // Length of the user and resource vectors
+	Epochs          int     `json:"epochs"`  // Passes of stochastic gradient descent over the interactions
+	LearningRate    float64 `json:"learning_rate"`
+	Regularization  float64 `json:"regularization"`
+	NegativeSamples int     `json:"negative_samples"` // Resources a user hasn't interacted with sampled per interaction, as neutral preferences
+	Seed            int64   `json:"seed"`
+}
+
+// UserFactor is a user's bias and latent vector in a FactorizationModel.
+type UserFactor struct {
+	ModelID uint      `gorm:"primaryKey;autoIncrement:false"` // Foreign key to the FactorizationModel model
+	UserID  uint      `gorm:"primaryKey;autoIncrement:false"` // Foreign key to the User model
+	Bias    float64   `gorm:"not null"`
+	Vector  []float64 `gorm:"serializer:json"`
+}
+
+// ResourceFactor is a resource's bias and latent vector in a FactorizationModel.
+type ResourceFactor struct {
+	ModelID    uint      `gorm:"primaryKey;autoIncrement:false"` // Foreign key to the FactorizationModel model
+	ResourceID uint      `gorm:"primaryKey;autoIncrement:false"` // Foreign key to the Resource model
+	Bias       float64   `gorm:"not null"`
+	Vector     []float64 `gorm:"serializer:json"`
+}
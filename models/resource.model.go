@@ -1,17 +1,37 @@
 package models
 
-import "gorm.io/gorm"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 type Resource struct {
 	gorm.Model
-	CreatedBy uint   `gorm:"not null"` // Foreign key to the User model
-	Title     string `gorm:"size:255;not null"`
-	Content   string `gorm:"size:10000;not null"`
-	URL       string `gorm:"size:255;not null"`
-	External  bool   `gorm:"not null"` // True if the resource is external, false default
-	AdminPost bool   `gorm:"not null"` // True if the resource is posted by an admin, false default
+	CreatedBy          uint             `gorm:"not null"` // Foreign key to the User model
+	Title              string           `gorm:"size:255;not null"`
+	Content            string           `gorm:"size:10000;not null"`     // Markdown source
+	ContentHTML        string           `gorm:"type:text"`               // Sanitized HTML rendered from Content, safe to display as-is
+	URL                string           `gorm:"size:255;not null;index"` // Normalized, see linkpreview.NormalizeURL
+	External           bool             `gorm:"not null"`                // True if the resource is external, false default
+	AdminPost          bool             `gorm:"not null"`                // True if the resource is posted by an admin, false default
+	Status             ModerationStatus `gorm:"not null;default:1"`      // Only published resources are shown in the catalog and recommendations
+	PreviewTitle       string           `gorm:"size:255"`                // Open Graph or HTML title of the linked page
+	PreviewDescription string           `gorm:"size:1000"`               // Open Graph or HTML description of the linked page
+	PreviewImage       string           `gorm:"size:1000"`               // Open Graph image of the linked page
+	LinkStatus         LinkStatus       `gorm:"not null;default:1"`
+	LinkFailures       int              `gorm:"not null;default:0"` // Consecutive failed link checks
+	LinkCheckedAt      *time.Time
 }
 
+type LinkStatus int
+
+const (
+	LinkUnchecked LinkStatus = iota + 1
+	LinkAlive
+	LinkDead // Set after LINK_CHECK_MAX_FAILURES consecutive failed checks
+)
+
 type Rating int
 
 const (
@@ -24,14 +44,60 @@ const (
 
 type Review struct {
 	gorm.Model
-	ResourceID uint   `gorm:"not null"` // Foreign key to the Resource model
-	UserID     uint   `gorm:"not null"` // Foreign key to the User model
-	Content    string `gorm:"size:10000;not null"`
-	Rating     Rating `gorm:"not null"` // Rating out of 5
+	ResourceID   uint             `gorm:"not null"`            // Foreign key to the Resource model
+	UserID       uint             `gorm:"not null"`            // Foreign key to the User model
+	Content      string           `gorm:"size:10000;not null"` // Markdown source
+	ContentHTML  string           `gorm:"type:text"`           // Sanitized HTML rendered from Content
+	Rating       Rating           `gorm:"not null"`            // Rating out of 5
+	Status       ModerationStatus `gorm:"not null;default:1"`  // Only published reviews are shown and used for recommendations
+	ReviewerMood MoodType         `gorm:"not null;default:0"`  // The reviewer's latest mood when they wrote the review, 0 if they hadn't logged one recently
+	// Wilson lower bound of its helpfulness votes, kept up to date with them so a resource's reviews can be sorted and paged in SQL
+	HelpfulnessScore float64 `gorm:"not null;default:0;index" json:"-"`
+}
+
+// ResourceMoodTag marks a resource as suited for a mood, e.g. a breathing exercise for Angry. Used to boost recommendations.
+type ResourceMoodTag struct {
+	gorm.Model
+	ResourceID uint     `gorm:"not null;uniqueIndex:idx_resource_mood_tags_resource_mood"` // Foreign key to the Resource model
+	Mood       MoodType `gorm:"not null;uniqueIndex:idx_resource_mood_tags_resource_mood"`
+}
+
+// ReviewVote represents a user marking a review as helpful or unhelpful. A user can only have one vote per review.
+type ReviewVote struct {
+	gorm.Model
+	ReviewID uint `gorm:"not null;uniqueIndex:idx_review_votes_review_user"` // Foreign key to the Review model
+	UserID   uint `gorm:"not null;uniqueIndex:idx_review_votes_review_user"` // Foreign key to the User model
+	Helpful  bool `gorm:"not null"`
+}
+
+// ReviewVoteCount holds the aggregated helpfulness votes for a single review.
+type ReviewVoteCount struct {
+	ReviewID  uint
+	Helpful   int
+	Unhelpful int
+}
+
+// ReviewResponse represents a review along with its helpfulness votes and ranking score.
+type ReviewResponse struct {
+	ID             uint    `json:"id"`
+	Review         Review  `json:"review"`
+	HelpfulCount   int     `json:"helpful_count"`
+	UnhelpfulCount int     `json:"unhelpful_count"`
+	Score          float64 `json:"helpfulness_score"`
+}
+
+// ReviewPage represents a single page of reviews for a resource, sorted by helpfulness.
+type ReviewPage struct {
+	Reviews []ReviewResponse `json:"reviews"`
+	Page    int              `json:"page"`
+	PerPage int              `json:"per_page"`
+	Total   int              `json:"total"`
 }
 
 type ResourceResponse struct {
-	ID       uint     `json:"id"`
-	Resource Resource `json:"resource"`
-	Reviews  []Review `json:"reviews"`
+	ID          uint             `json:"id"`
+	Resource    Resource         `json:"resource"`
+	Reviews     []ReviewResponse `json:"reviews"` // First page of reviews, most helpful first
+	ReviewCount int              `json:"review_count"`
+	MoodTags    []MoodType       `json:"mood_tags"`
 }
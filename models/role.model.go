@@ -0,0 +1,71 @@
+package models
+
+// Role is what a user is allowed to do, as a set of permissions.
+type Role string
+
+// Permission allows a user to do something beyond what every user can do.
+type Permission string
+
+const (
+	UserRole      Role = "user"
+	ModeratorRole Role = "moderator"
+	ClinicianRole Role = "clinician"
+	AdminRole     Role = "admin"
+)
+
+const (
+	ModerateResources  Permission = "resource:moderate"   // Handle reported resources
+	ModerateReviews    Permission = "review:moderate"     // Handle reported reviews
+	ManageResources    Permission = "resource:manage"     // Edit, delete and tag anyone's resources
+	PublishResources   Permission = "resource:publish"    // Publish resources without pre-moderation
+	CurateResources    Permission = "resource:curate"     // Pick the curated resources recommended to everyone
+	ManageRecommender  Permission = "recommender:manage"  // Train and roll back recommender models
+	ViewUsers          Permission = "user:view"           // Search users and see their accounts
+	DisableUsers       Permission = "user:disable"        // Disable and re-enable users
+	VerifyUsers        Permission = "user:verify"         // Verify users' emails
+	ResetUserPasswords Permission = "user:reset-password" // Send users password reset mails
+	AssignRoles        Permission = "user:assign-role"    // Change users' roles
+)
+
+// Roles are all the roles, from least to most powerful.
+var Roles = []Role{UserRole, ModeratorRole, ClinicianRole, AdminRole}
+
+// RolePermissions are the permissions each role grants. Admins can do everything.
+var RolePermissions = map[Role][]Permission{
+	UserRole:      {},
+	ModeratorRole: {ModerateResources, ModerateReviews, ViewUsers},
+	ClinicianRole: {PublishResources, CurateResources},
+	AdminRole: {
+		ModerateResources, ModerateReviews, ManageResources, PublishResources, CurateResources, ManageRecommender,
+		ViewUsers, DisableUsers, VerifyUsers, ResetUserPasswords, AssignRoles,
+	},
+}
+
+// Valid checks whether the role is one of Roles.
+func (r Role) Valid() bool {
+	_, ok := RolePermissions[r]
+	return ok
+}
+
+// Has checks whether the role grants the permission.
+func (r Role) Has(permission Permission) bool {
+	for _, p := range RolePermissions[r] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// Can checks whether the user's role grants the permission.
+func (u User) Can(permission Permission) bool {
+	return u.Role.Has(permission)
+}
+
+// ModeratePermission is the permission needed to handle reports of the given kind of content.
+func ModeratePermission(targetType ReportTargetType) Permission {
+	if targetType == ReviewTarget {
+		return ModerateReviews
+	}
+	return ModerateResources
+}
@@ -1,10 +1,14 @@
 package repository
 
 import (
+	"strings"
+	"time"
+
 	"github.com/anirudhgray/mood-harbour-backend/infra/database" // Import your custom database package
 	"github.com/anirudhgray/mood-harbour-backend/infra/logger"
 	"github.com/anirudhgray/mood-harbour-backend/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // **UserRepository** //
@@ -24,6 +28,9 @@ type UserRepositoryInterface interface {
 	VerifyUserEmail(email string) error
 	SaveUser(user models.User) error
 	DeleteUserByID(userID uint) error
+	SearchUsers(filter models.UserFilter, offset, limit int) ([]models.User, int64, error)
+	SetUserDisabled(userID uint, disabled bool) error
+	SetUserRole(userID uint, role models.Role) error
 }
 
 func (ur *UserRepository) CreateUser(user models.User) error {
@@ -93,53 +100,112 @@ func (ur *UserRepository) DeleteUserByID(userID uint) error {
 	return nil
 }
 
+// SearchUsers gets a page of the users matching the filter, most recently registered first, along with the total number of matches.
+func (ur *UserRepository) SearchUsers(filter models.UserFilter, offset, limit int) ([]models.User, int64, error) {
+	query := ur.db.Model(&models.User{})
+	if filter.Query != "" {
+		pattern := "%" + strings.ToLower(filter.Query) + "%"
+		query = query.Where("LOWER(name) LIKE ? OR LOWER(email) LIKE ?", pattern, pattern)
+	}
+	if filter.Role != "" {
+		query = query.Where("role = ?", filter.Role)
+	}
+	if filter.Disabled != nil {
+		query = query.Where("disabled = ?", *filter.Disabled)
+	}
+	if filter.Verified != nil {
+		query = query.Where("verified = ?", *filter.Verified)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var users []models.User
+	err := query.Order("created_at desc, id desc").Offset(offset).Limit(limit).Find(&users).Error
+	return users, total, err
+}
+
+// SetUserDisabled disables or re-enables a user.
+func (ur *UserRepository) SetUserDisabled(userID uint, disabled bool) error {
+	return ur.db.Model(&models.User{}).Where("id = ?", userID).Update("disabled", disabled).Error
+}
+
+// SetUserRole changes a user's role.
+func (ur *UserRepository) SetUserRole(userID uint, role models.Role) error {
+	return ur.db.Model(&models.User{}).Where("id = ?", userID).Update("role", role).Error
+}
+
 // for testing purposes
 func (ur *UserRepository) SetDB(db *gorm.DB) {
 	ur.db = db
 }
 
-// **VerificationEntryRepository** //
+// **OneTimeTokenRepository** //
 
-type VerificationEntryRepository struct {
+type OneTimeTokenRepository struct {
 	db *gorm.DB
 }
 
-func NewVerificationEntryRepository() *VerificationEntryRepository {
-	return &VerificationEntryRepository{database.DB}
+func NewOneTimeTokenRepository() *OneTimeTokenRepository {
+	return &OneTimeTokenRepository{database.DB}
 }
 
-type VerificationRepositoryInterface interface {
-	CreateVerificationEntry(verificationEntry models.VerificationEntry) error
-	GetVerificationEntryByEmail(email string) (*models.VerificationEntry, error)
-	DeleteVerificationEntry(email string) error
+type OneTimeTokenRepositoryInterface interface {
+	ReplaceOneTimeToken(oneTimeToken *models.OneTimeToken) error
+	GetOneTimeToken(email, purpose string) (models.OneTimeToken, error)
+	RecordOneTimeTokenAttempt(id uint, maxAttempts int) (bool, error)
+	UseOneTimeToken(id uint, at time.Time) (bool, error)
+	DeleteOneTimeToken(email, purpose string) error
+	DeleteUserOneTimeTokens(email string) error
+	PurgeOneTimeTokens(before time.Time) (int64, error)
 }
 
-// CreateVerificationEntry creates a new verification entry
-func (ver *VerificationEntryRepository) CreateVerificationEntry(verificationEntry models.VerificationEntry) error {
-	if err := ver.db.Create(&verificationEntry).Error; err != nil {
-		logger.Errorf("DB: Error Creating VerificationEntry: %v", err)
-		return err
-	}
-	return nil
+// ReplaceOneTimeToken stores a new one-time token, replacing any token its email already has for the purpose.
+func (otr *OneTimeTokenRepository) ReplaceOneTimeToken(oneTimeToken *models.OneTimeToken) error {
+	return otr.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("email = ? AND purpose = ?", oneTimeToken.Email, oneTimeToken.Purpose).Delete(&models.OneTimeToken{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(oneTimeToken).Error
+	})
 }
 
-// GetVerificationEntryByEmail fetches a verification entry by email
-func (ver *VerificationEntryRepository) GetVerificationEntryByEmail(email string) (*models.VerificationEntry, error) {
-	var verificationEntry models.VerificationEntry
-	if err := ver.db.Where("email = ?", email).First(&verificationEntry).Error; err != nil {
-		logger.Errorf("DB: Error Getting VerificationEntry: %v", err)
-		return nil, err
-	}
-	return &verificationEntry, nil
+// GetOneTimeToken fetches the email's token for the purpose, used or not.
+func (otr *OneTimeTokenRepository) GetOneTimeToken(email, purpose string) (models.OneTimeToken, error) {
+	var oneTimeToken models.OneTimeToken
+	err := otr.db.Where("email = ? AND purpose = ?", email, purpose).First(&oneTimeToken).Error
+	return oneTimeToken, err
 }
 
-// DeleteVerificationEntry deletes a verification entry by email
-func (ver *VerificationEntryRepository) DeleteVerificationEntry(email string) error {
-	if err := ver.db.Where("email = ?", email).Unscoped().Delete(&models.VerificationEntry{}).Error; err != nil {
-		logger.Errorf("DB: Error Deleting VerificationEntry: %v", err)
-		return err
-	}
-	return nil
+// RecordOneTimeTokenAttempt counts an attempt at using a one-time token. Returns false if it was already used
+// or out of attempts, so codes can't be guessed by racing requests either.
+func (otr *OneTimeTokenRepository) RecordOneTimeTokenAttempt(id uint, maxAttempts int) (bool, error) {
+	result := otr.db.Model(&models.OneTimeToken{}).Where("id = ? AND used_at IS NULL AND attempts < ?", id, maxAttempts).Update("attempts", gorm.Expr("attempts + 1"))
+	return result.RowsAffected == 1, result.Error
+}
+
+// UseOneTimeToken marks a one-time token as used, unless it already was. Returns false if it was.
+func (otr *OneTimeTokenRepository) UseOneTimeToken(id uint, at time.Time) (bool, error) {
+	result := otr.db.Model(&models.OneTimeToken{}).Where("id = ? AND used_at IS NULL", id).Update("used_at", at)
+	return result.RowsAffected == 1, result.Error
+}
+
+// DeleteOneTimeToken deletes the email's token for the purpose, if it has one.
+func (otr *OneTimeTokenRepository) DeleteOneTimeToken(email, purpose string) error {
+	return otr.db.Unscoped().Where("email = ? AND purpose = ?", email, purpose).Delete(&models.OneTimeToken{}).Error
+}
+
+// DeleteUserOneTimeTokens deletes all of the email's tokens.
+func (otr *OneTimeTokenRepository) DeleteUserOneTimeTokens(email string) error {
+	return otr.db.Unscoped().Where("email = ?", email).Delete(&models.OneTimeToken{}).Error
+}
+
+// PurgeOneTimeTokens deletes the tokens which expired before the given time or were used, returning how many were deleted.
+func (otr *OneTimeTokenRepository) PurgeOneTimeTokens(before time.Time) (int64, error) {
+	result := otr.db.Unscoped().Where("expires_at < ? OR used_at IS NOT NULL", before).Delete(&models.OneTimeToken{})
+	return result.RowsAffected, result.Error
 }
 
 // **PasswordAuthRepository** //
@@ -189,88 +255,6 @@ func (par *PasswordAuthRepository) DeletePwdAuthItemByEmail(email string) error
 	return par.db.Where("email = ?", email).Unscoped().Delete(&models.PasswordAuth{}).Error
 }
 
-// **ForgotPasswordRepository** //
-
-type ForgotPasswordRepository struct {
-	db *gorm.DB
-}
-
-func NewForgotPasswordRepository() *ForgotPasswordRepository {
-	return &ForgotPasswordRepository{database.DB}
-}
-
-type ForgotPasswordRepositoryInterface interface {
-	CreateForgotPassword(forgotPassword models.ForgotPassword) error
-	GetForgotPasswordByEmail(email string) (*models.ForgotPassword, error)
-	DeleteForgotPasswordByEmail(email string) error
-}
-
-// CreateForgotPassword creates a new forgot password entry
-func (fpr *ForgotPasswordRepository) CreateForgotPassword(forgotPassword models.ForgotPassword) error {
-	if err := fpr.db.Create(&forgotPassword).Error; err != nil {
-		return err
-	}
-	return nil
-}
-
-// GetForgotPasswordByEmail fetches a forgot password entry by email
-func (fpr *ForgotPasswordRepository) GetForgotPasswordByEmail(email string) (*models.ForgotPassword, error) {
-	var forgotPassword models.ForgotPassword
-	if err := fpr.db.Where("email = ?", email).First(&forgotPassword).Error; err != nil {
-		return nil, err
-	}
-	return &forgotPassword, nil
-}
-
-// DeleteForgotPasswordByEmail deletes a forgot password entry by email
-func (fpr *ForgotPasswordRepository) DeleteForgotPasswordByEmail(email string) error {
-	if err := fpr.db.Where("email = ?", email).Unscoped().Delete(&models.ForgotPassword{}).Error; err != nil {
-		return err
-	}
-	return nil
-}
-
-// **DeletionConfirmationRepository** //
-
-type DeletionConfirmationRepository struct {
-	db *gorm.DB
-}
-
-func NewDeletionConfirmationRepository() *DeletionConfirmationRepository {
-	return &DeletionConfirmationRepository{database.DB}
-}
-
-type DeletionConfirmationRepositoryInterface interface {
-	CreateDeletionConfirmation(deletionConfirmation models.DeletionConfirmation) error
-	GetDeletionConfirmationByEmail(email string) (models.DeletionConfirmation, error)
-	DeleteDeletionConfirmationByEmail(email string) error
-}
-
-// CreateDeletionConfirmation creates a new deletion confirmation entry
-func (dcr *DeletionConfirmationRepository) CreateDeletionConfirmation(deletionConfirmation models.DeletionConfirmation) error {
-	if err := dcr.db.Create(&deletionConfirmation).Error; err != nil {
-		return err
-	}
-	return nil
-}
-
-// GetDeletionConfirmationByEmail fetches a deletion confirmation entry by email
-func (dcr *DeletionConfirmationRepository) GetDeletionConfirmationByEmail(email string) (models.DeletionConfirmation, error) {
-	var deletionConfirmation models.DeletionConfirmation
-	if err := dcr.db.Where("email = ?", email).First(&deletionConfirmation).Error; err != nil {
-		return deletionConfirmation, err
-	}
-	return deletionConfirmation, nil
-}
-
-// DeleteDeletionConfirmationByEmail deletes a deletion confirmation entry by email
-func (dcr *DeletionConfirmationRepository) DeleteDeletionConfirmationByEmail(email string) error {
-	if err := dcr.db.Where("email = ?", email).Unscoped().Delete(&models.DeletionConfirmation{}).Error; err != nil {
-		return err
-	}
-	return nil
-}
-
 // **AuthProviderRepository** //
 
 type AuthProviderRepository struct {
@@ -327,3 +311,422 @@ func (ar *AuthProviderRepository) DeleteAuthProvider(id uint) error {
 func (ar *AuthProviderRepository) DeleteAuthProviderByUserID(userID uint) error {
 	return ar.db.Where("user_id = ?", userID).Unscoped().Delete(&models.AuthProvider{}).Error
 }
+
+// **SessionRepository** //
+
+type SessionRepository struct {
+	db *gorm.DB
+}
+
+func NewSessionRepository() *SessionRepository {
+	return &SessionRepository{database.DB}
+}
+
+type SessionRepositoryInterface interface {
+	CreateSession(session *models.Session) error
+	GetSessionByJTI(jti string) (models.Session, error)
+	GetSessionByID(id uint) (models.Session, error)
+	GetActiveSessions(userID uint) ([]models.Session, error)
+	TouchSession(id uint, at time.Time) error
+	ExtendSession(id uint, client models.ClientInfo, at, expiresAt time.Time) error
+	RevokeSession(userID, sessionID uint, at time.Time) error
+	RevokeUserSessions(userID, exceptSessionID uint, at time.Time) error
+	CreateSocialLoginCode(code *models.SocialLoginCode) error
+	GetSocialLoginCodeByHash(codeHash string) (models.SocialLoginCode, error)
+	UseSocialLoginCode(id uint, at time.Time) (bool, error)
+	PurgeSocialLoginCodes(before time.Time) (int64, error)
+}
+
+// CreateSession stores a new session.
+func (sr *SessionRepository) CreateSession(session *models.Session) error {
+	return sr.db.Create(session).Error
+}
+
+// GetSessionByJTI fetches a session by the jti claim of its access tokens.
+func (sr *SessionRepository) GetSessionByJTI(jti string) (models.Session, error) {
+	var session models.Session
+	err := sr.db.Where("jti = ?", jti).First(&session).Error
+	return session, err
+}
+
+// GetSessionByID fetches a session by its ID.
+func (sr *SessionRepository) GetSessionByID(id uint) (models.Session, error) {
+	var session models.Session
+	err := sr.db.First(&session, id).Error
+	return session, err
+}
+
+// GetActiveSessions gets the user's sessions which are neither revoked nor expired, most recently seen first.
+func (sr *SessionRepository) GetActiveSessions(userID uint) ([]models.Session, error) {
+	var sessions []models.Session
+	err := sr.db.Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).Order("last_seen_at desc").Find(&sessions).Error
+	return sessions, err
+}
+
+// TouchSession records that a session was seen at the given time.
+func (sr *SessionRepository) TouchSession(id uint, at time.Time) error {
+	return sr.db.Model(&models.Session{}).Where("id = ?", id).Update("last_seen_at", at).Error
+}
+
+// ExtendSession records that a session was refreshed from the given device, until expiresAt.
+// Only the changed columns are written, so it can't undo a concurrent revocation.
+func (sr *SessionRepository) ExtendSession(id uint, client models.ClientInfo, at, expiresAt time.Time) error {
+	return sr.db.Model(&models.Session{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"user_agent":   client.UserAgent,
+		"ip":           client.IP,
+		"last_seen_at": at,
+		"expires_at":   expiresAt,
+	}).Error
+}
+
+// RevokeSession revokes one of the user's sessions. Returns gorm.ErrRecordNotFound if the user has no such active session.
+func (sr *SessionRepository) RevokeSession(userID, sessionID uint, at time.Time) error {
+	result := sr.db.Model(&models.Session{}).Where("id = ? AND user_id = ? AND revoked_at IS NULL", sessionID, userID).Update("revoked_at", at)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// RevokeUserSessions revokes all of the user's sessions except the given one, which may be 0 to revoke them all.
+func (sr *SessionRepository) RevokeUserSessions(userID, exceptSessionID uint, at time.Time) error {
+	return sr.db.Model(&models.Session{}).Where("user_id = ? AND id <> ? AND revoked_at IS NULL", userID, exceptSessionID).Update("revoked_at", at).Error
+}
+
+// CreateSocialLoginCode stores a new social login code.
+func (sr *SessionRepository) CreateSocialLoginCode(code *models.SocialLoginCode) error {
+	return sr.db.Create(code).Error
+}
+
+// GetSocialLoginCodeByHash fetches a social login code by its hash.
+func (sr *SessionRepository) GetSocialLoginCodeByHash(codeHash string) (models.SocialLoginCode, error) {
+	var code models.SocialLoginCode
+	err := sr.db.Where("code_hash = ?", codeHash).First(&code).Error
+	return code, err
+}
+
+// UseSocialLoginCode marks a social login code as exchanged, unless it already was. Returns false if it was.
+func (sr *SessionRepository) UseSocialLoginCode(id uint, at time.Time) (bool, error) {
+	result := sr.db.Model(&models.SocialLoginCode{}).Where("id = ? AND used_at IS NULL", id).Update("used_at", at)
+	return result.RowsAffected == 1, result.Error
+}
+
+// PurgeSocialLoginCodes deletes the codes which expired before the given time or were exchanged, returning how many were deleted.
+func (sr *SessionRepository) PurgeSocialLoginCodes(before time.Time) (int64, error) {
+	result := sr.db.Unscoped().Where("expires_at < ? OR used_at IS NOT NULL", before).Delete(&models.SocialLoginCode{})
+	return result.RowsAffected, result.Error
+}
+
+// **RefreshTokenRepository** //
+
+type RefreshTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewRefreshTokenRepository() *RefreshTokenRepository {
+	return &RefreshTokenRepository{database.DB}
+}
+
+type RefreshTokenRepositoryInterface interface {
+	CreateRefreshToken(refreshToken *models.RefreshToken) error
+	GetRefreshTokenByHash(tokenHash string) (models.RefreshToken, error)
+	MarkRefreshTokenUsed(id uint, at time.Time) (bool, error)
+}
+
+// CreateRefreshToken stores a new refresh token.
+func (rtr *RefreshTokenRepository) CreateRefreshToken(refreshToken *models.RefreshToken) error {
+	return rtr.db.Create(refreshToken).Error
+}
+
+// GetRefreshTokenByHash fetches a refresh token by the hash of the token.
+func (rtr *RefreshTokenRepository) GetRefreshTokenByHash(tokenHash string) (models.RefreshToken, error) {
+	var refreshToken models.RefreshToken
+	err := rtr.db.Where("token_hash = ?", tokenHash).First(&refreshToken).Error
+	return refreshToken, err
+}
+
+// MarkRefreshTokenUsed marks a refresh token as used, unless it already was. Returns false if it was,
+// so two requests racing to use the same token can't both succeed.
+func (rtr *RefreshTokenRepository) MarkRefreshTokenUsed(id uint, at time.Time) (bool, error) {
+	result := rtr.db.Model(&models.RefreshToken{}).Where("id = ? AND used_at IS NULL", id).Update("used_at", at)
+	return result.RowsAffected == 1, result.Error
+}
+
+// **MFARepository** //
+
+type MFARepository struct {
+	db *gorm.DB
+}
+
+func NewMFARepository() *MFARepository {
+	return &MFARepository{database.DB}
+}
+
+type MFARepositoryInterface interface {
+	GetTOTPFactor(userID uint) (models.TOTPFactor, error)
+	SaveTOTPFactor(factor *models.TOTPFactor) error
+	ConfirmTOTPFactor(id uint, at time.Time) error
+	UseTOTPStep(id uint, step int64) (bool, error)
+	DeleteTOTPFactor(userID uint) error
+	ReplaceRecoveryCodes(userID uint, codes []models.RecoveryCode) error
+	UseRecoveryCode(userID uint, codeHash string, at time.Time) (bool, error)
+	CountRecoveryCodes(userID uint) (int64, error)
+	CreateMFAChallenge(challenge *models.MFAChallenge) error
+	GetMFAChallengeByHash(tokenHash string) (models.MFAChallenge, error)
+	RecordMFAChallengeAttempt(id uint, maxAttempts int) (bool, error)
+	UseMFAChallenge(id uint, at time.Time) (bool, error)
+	PurgeMFAChallenges(before time.Time) (int64, error)
+}
+
+// GetTOTPFactor gets the user's TOTP factor, confirmed or not. It has an ID of 0 if they haven't got one.
+func (mr *MFARepository) GetTOTPFactor(userID uint) (models.TOTPFactor, error) {
+	var factor models.TOTPFactor
+	err := mr.db.Where("user_id = ?", userID).Limit(1).Find(&factor).Error
+	return factor, err
+}
+
+// SaveTOTPFactor creates or updates a TOTP factor.
+func (mr *MFARepository) SaveTOTPFactor(factor *models.TOTPFactor) error {
+	return mr.db.Save(factor).Error
+}
+
+// ConfirmTOTPFactor marks a TOTP factor as confirmed.
+func (mr *MFARepository) ConfirmTOTPFactor(id uint, at time.Time) error {
+	return mr.db.Model(&models.TOTPFactor{}).Where("id = ?", id).Update("confirmed_at", at).Error
+}
+
+// UseTOTPStep records that a code of the given time step was accepted, unless one of that step or a later one already was.
+// Returns false if so, so the same code can't be used twice, even by two requests racing.
+func (mr *MFARepository) UseTOTPStep(id uint, step int64) (bool, error) {
+	result := mr.db.Model(&models.TOTPFactor{}).Where("id = ? AND last_used_step < ?", id, step).Update("last_used_step", step)
+	return result.RowsAffected == 1, result.Error
+}
+
+// DeleteTOTPFactor deletes the user's TOTP factor and recovery codes, turning off their two-factor authentication.
+func (mr *MFARepository) DeleteTOTPFactor(userID uint) error {
+	return mr.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("user_id = ?", userID).Delete(&models.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Where("user_id = ?", userID).Delete(&models.TOTPFactor{}).Error
+	})
+}
+
+// ReplaceRecoveryCodes replaces all of the user's recovery codes, used or not, with new ones.
+func (mr *MFARepository) ReplaceRecoveryCodes(userID uint, codes []models.RecoveryCode) error {
+	return mr.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("user_id = ?", userID).Delete(&models.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		if len(codes) == 0 {
+			return nil
+		}
+		return tx.Create(&codes).Error
+	})
+}
+
+// UseRecoveryCode marks one of the user's recovery codes as used, returning false if they have no such unused code.
+func (mr *MFARepository) UseRecoveryCode(userID uint, codeHash string, at time.Time) (bool, error) {
+	result := mr.db.Model(&models.RecoveryCode{}).Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).Update("used_at", at)
+	return result.RowsAffected == 1, result.Error
+}
+
+// CountRecoveryCodes counts the user's unused recovery codes.
+func (mr *MFARepository) CountRecoveryCodes(userID uint) (int64, error) {
+	var count int64
+	err := mr.db.Model(&models.RecoveryCode{}).Where("user_id = ? AND used_at IS NULL", userID).Count(&count).Error
+	return count, err
+}
+
+// CreateMFAChallenge stores a new MFA challenge.
+func (mr *MFARepository) CreateMFAChallenge(challenge *models.MFAChallenge) error {
+	return mr.db.Create(challenge).Error
+}
+
+// GetMFAChallengeByHash fetches an MFA challenge by the hash of its token.
+func (mr *MFARepository) GetMFAChallengeByHash(tokenHash string) (models.MFAChallenge, error) {
+	var challenge models.MFAChallenge
+	err := mr.db.Where("token_hash = ?", tokenHash).First(&challenge).Error
+	return challenge, err
+}
+
+// RecordMFAChallengeAttempt counts an attempt at completing an MFA challenge. Returns false if it was already used
+// or out of attempts, so codes can't be guessed by racing requests either.
+func (mr *MFARepository) RecordMFAChallengeAttempt(id uint, maxAttempts int) (bool, error) {
+	result := mr.db.Model(&models.MFAChallenge{}).Where("id = ? AND used_at IS NULL AND attempts < ?", id, maxAttempts).Update("attempts", gorm.Expr("attempts + 1"))
+	return result.RowsAffected == 1, result.Error
+}
+
+// UseMFAChallenge marks an MFA challenge as completed, unless it already was. Returns false if it was.
+func (mr *MFARepository) UseMFAChallenge(id uint, at time.Time) (bool, error) {
+	result := mr.db.Model(&models.MFAChallenge{}).Where("id = ? AND used_at IS NULL", id).Update("used_at", at)
+	return result.RowsAffected == 1, result.Error
+}
+
+// PurgeMFAChallenges deletes the challenges which expired before the given time or were used, returning how many were deleted.
+func (mr *MFARepository) PurgeMFAChallenges(before time.Time) (int64, error) {
+	result := mr.db.Unscoped().Where("expires_at < ? OR used_at IS NOT NULL", before).Delete(&models.MFAChallenge{})
+	return result.RowsAffected, result.Error
+}
+
+// **PasskeyRepository** //
+
+type PasskeyRepository struct {
+	db *gorm.DB
+}
+
+func NewPasskeyRepository() *PasskeyRepository {
+	return &PasskeyRepository{database.DB}
+}
+
+type PasskeyRepositoryInterface interface {
+	CreatePasskey(passkey *models.Passkey) error
+	GetPasskeyByCredentialID(credentialID string) (models.Passkey, error)
+	GetUserPasskeys(userID uint) ([]models.Passkey, error)
+	UpdatePasskeyUsage(id uint, signCount uint32, at time.Time) error
+	DeletePasskey(userID, passkeyID uint) error
+	DeleteUserPasskeys(userID uint) error
+	CreatePasskeyChallenge(challenge *models.PasskeyChallenge) error
+	UsePasskeyChallenge(challenge, purpose string, at time.Time) (models.PasskeyChallenge, error)
+	PurgePasskeyChallenges(before time.Time) (int64, error)
+}
+
+// CreatePasskey stores a new passkey, along with its AuthProvider.
+func (pr *PasskeyRepository) CreatePasskey(passkey *models.Passkey) error {
+	return pr.db.Transaction(func(tx *gorm.DB) error {
+		authProvider := models.AuthProvider{ProviderName: models.PasskeyProvider, ProviderKey: passkey.CredentialID, UserID: passkey.UserID}
+		if err := tx.Create(&authProvider).Error; err != nil {
+			return err
+		}
+		passkey.AuthProviderID = authProvider.ID
+		return tx.Create(passkey).Error
+	})
+}
+
+// GetPasskeyByCredentialID fetches a passkey by its base64url encoded credential ID.
+func (pr *PasskeyRepository) GetPasskeyByCredentialID(credentialID string) (models.Passkey, error) {
+	var passkey models.Passkey
+	err := pr.db.Where("credential_id = ?", credentialID).First(&passkey).Error
+	return passkey, err
+}
+
+// GetUserPasskeys gets the user's passkeys, oldest first.
+func (pr *PasskeyRepository) GetUserPasskeys(userID uint) ([]models.Passkey, error) {
+	var passkeys []models.Passkey
+	err := pr.db.Where("user_id = ?", userID).Order("created_at asc, id asc").Find(&passkeys).Error
+	return passkeys, err
+}
+
+// UpdatePasskeyUsage records a login with a passkey, and its authenticator's new sign count.
+func (pr *PasskeyRepository) UpdatePasskeyUsage(id uint, signCount uint32, at time.Time) error {
+	return pr.db.Model(&models.Passkey{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"sign_count":   signCount,
+		"last_used_at": at,
+	}).Error
+}
+
+// DeletePasskey deletes one of the user's passkeys and its AuthProvider. Returns gorm.ErrRecordNotFound if the user has no such passkey.
+func (pr *PasskeyRepository) DeletePasskey(userID, passkeyID uint) error {
+	return pr.db.Transaction(func(tx *gorm.DB) error {
+		var passkey models.Passkey
+		if err := tx.Where("id = ? AND user_id = ?", passkeyID, userID).First(&passkey).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Delete(&models.AuthProvider{}, passkey.AuthProviderID).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Delete(&passkey).Error
+	})
+}
+
+// DeleteUserPasskeys deletes all of the user's passkeys. Their AuthProviders are deleted along with the user's others.
+func (pr *PasskeyRepository) DeleteUserPasskeys(userID uint) error {
+	return pr.db.Where("user_id = ?", userID).Unscoped().Delete(&models.Passkey{}).Error
+}
+
+// CreatePasskeyChallenge stores a new passkey challenge.
+func (pr *PasskeyRepository) CreatePasskeyChallenge(challenge *models.PasskeyChallenge) error {
+	return pr.db.Create(challenge).Error
+}
+
+// UsePasskeyChallenge fetches an unexpired challenge for the purpose and marks it used, so it can only be answered once,
+// even by two requests racing. Returns gorm.ErrRecordNotFound if there's no such challenge or it was already used.
+func (pr *PasskeyRepository) UsePasskeyChallenge(challenge, purpose string, at time.Time) (models.PasskeyChallenge, error) {
+	var stored models.PasskeyChallenge
+	if err := pr.db.Where("challenge = ? AND purpose = ? AND expires_at > ?", challenge, purpose, at).First(&stored).Error; err != nil {
+		return stored, err
+	}
+
+	result := pr.db.Model(&models.PasskeyChallenge{}).Where("id = ? AND used_at IS NULL", stored.ID).Update("used_at", at)
+	if result.Error != nil {
+		return stored, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return stored, gorm.ErrRecordNotFound
+	}
+	stored.UsedAt = &at
+	return stored, nil
+}
+
+// PurgePasskeyChallenges deletes the challenges which expired before the given time or were used, returning how many were deleted.
+func (pr *PasskeyRepository) PurgePasskeyChallenges(before time.Time) (int64, error) {
+	result := pr.db.Unscoped().Where("expires_at < ? OR used_at IS NOT NULL", before).Delete(&models.PasskeyChallenge{})
+	return result.RowsAffected, result.Error
+}
+
+// **ThrottleRepository** //
+
+type ThrottleRepository struct {
+	db *gorm.DB
+}
+
+func NewThrottleRepository() *ThrottleRepository {
+	return &ThrottleRepository{database.DB}
+}
+
+type ThrottleRepositoryInterface interface {
+	GetThrottles(subjects []string) ([]models.AuthThrottle, error)
+	RecordFailure(subject string, at, forgetBefore time.Time) (models.AuthThrottle, error)
+	LockThrottle(subject string, until time.Time) error
+	ResetThrottle(subject string) error
+}
+
+// GetThrottles gets the throttles of the subjects which have any.
+func (tr *ThrottleRepository) GetThrottles(subjects []string) ([]models.AuthThrottle, error) {
+	var throttles []models.AuthThrottle
+	err := tr.db.Where("subject IN ?", subjects).Find(&throttles).Error
+	return throttles, err
+}
+
+// RecordFailure counts a failed attempt for the subject, starting again from 1 if its last failure was before forgetBefore.
+// The count is incremented in the database, so concurrent attempts are all counted.
+func (tr *ThrottleRepository) RecordFailure(subject string, at, forgetBefore time.Time) (models.AuthThrottle, error) {
+	throttle := models.AuthThrottle{Subject: subject, Failures: 1, LastFailureAt: at}
+	err := tr.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "subject"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"failures":        gorm.Expr("CASE WHEN auth_throttles.last_failure_at < ? THEN 1 ELSE auth_throttles.failures + 1 END", forgetBefore),
+			"last_failure_at": at,
+			"updated_at":      at,
+		}),
+	}).Create(&throttle).Error
+	if err != nil {
+		return throttle, err
+	}
+
+	err = tr.db.Where("subject = ?", subject).First(&throttle).Error
+	return throttle, err
+}
+
+// LockThrottle stops the subject from making any attempts until the given time.
+func (tr *ThrottleRepository) LockThrottle(subject string, until time.Time) error {
+	return tr.db.Model(&models.AuthThrottle{}).Where("subject = ?", subject).Update("locked_until", until).Error
+}
+
+// ResetThrottle forgets the subject's failed attempts.
+func (tr *ThrottleRepository) ResetThrottle(subject string) error {
+	return tr.db.Unscoped().Where("subject = ?", subject).Delete(&models.AuthThrottle{}).Error
+}
@@ -1,86 +1,122 @@
 package repository
 
 import (
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/anirudhgray/mood-harbour-backend/models"
 	"github.com/anirudhgray/mood-harbour-backend/utils/test_utils"
+	"gorm.io/gorm"
 )
 
-func TestVerificationEntryRepository_CreateVerificationEntry(t *testing.T) {
+func TestOneTimeTokenRepository_ReplaceOneTimeToken(t *testing.T) {
 	db, err := test_utils.SetupTestDB()
 	if err != nil {
 		t.Fatalf("Failed to set up test database: %v", err)
 	}
-	defer db.Migrator().DropTable(&models.VerificationEntry{})
+	defer db.Migrator().DropTable(&models.OneTimeToken{}) // Drop the table after testing
 
-	// Create the VerificationEntry Repository with the test database
-	ver := NewVerificationEntryRepository()
-	ver.db = db
+	otr := NewOneTimeTokenRepository()
+	otr.db = db
 
-	// Create a test verification entry
-	verificationEntry := models.VerificationEntry{
-		Email: "test@example.com",
-		OTP:   "123456", // Change this to your desired OTP
+	expires := time.Now().Add(time.Minute)
+	for _, oneTimeToken := range []models.OneTimeToken{
+		{Email: "test@example.com", Purpose: models.OneTimeTokenVerifyEmail, TokenHash: "first", ExpiresAt: expires},
+		{Email: "test@example.com", Purpose: models.OneTimeTokenVerifyEmail, TokenHash: "second", ExpiresAt: expires},
+		{Email: "test@example.com", Purpose: models.OneTimeTokenResetPassword, TokenHash: "reset", ExpiresAt: expires},
+	} {
+		if err := otr.ReplaceOneTimeToken(&oneTimeToken); err != nil {
+			t.Fatalf("ReplaceOneTimeToken returned an error: %v", err)
+		}
 	}
 
-	// Test CreateVerificationEntry function
-	if err := ver.CreateVerificationEntry(verificationEntry); err != nil {
-		t.Errorf("CreateVerificationEntry returned an error: %v", err)
+	// a new token replaces the last one for the same purpose only
+	retrieved, err := otr.GetOneTimeToken("test@example.com", models.OneTimeTokenVerifyEmail)
+	if err != nil || retrieved.TokenHash != "second" {
+		t.Errorf("GetOneTimeToken = %+v, %v, expected the second token", retrieved, err)
+	}
+	if retrieved, err := otr.GetOneTimeToken("test@example.com", models.OneTimeTokenResetPassword); err != nil || retrieved.TokenHash != "reset" {
+		t.Errorf("GetOneTimeToken = %+v, %v, expected the reset token", retrieved, err)
 	}
 
-	// Test GetVerificationEntryByEmail function to retrieve the created verification entry
-	retrievedEntry, err := ver.GetVerificationEntryByEmail("test@example.com")
-	if err != nil {
-		t.Errorf("GetVerificationEntryByEmail returned an error: %v", err)
+	if err := otr.DeleteOneTimeToken("test@example.com", models.OneTimeTokenVerifyEmail); err != nil {
+		t.Fatalf("DeleteOneTimeToken returned an error: %v", err)
 	}
-	if retrievedEntry.Email != verificationEntry.Email {
-		t.Errorf("Expected entry email: %s, got: %s", verificationEntry.Email, retrievedEntry.Email)
+	if _, err := otr.GetOneTimeToken("test@example.com", models.OneTimeTokenVerifyEmail); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("Expected the token to be deleted, got %v", err)
 	}
-	if retrievedEntry.OTP != verificationEntry.OTP {
-		t.Errorf("Expected entry OTP: %s, got: %s", verificationEntry.OTP, retrievedEntry.OTP)
+	if err := otr.DeleteUserOneTimeTokens("test@example.com"); err != nil {
+		t.Fatalf("DeleteUserOneTimeTokens returned an error: %v", err)
+	}
+	if _, err := otr.GetOneTimeToken("test@example.com", models.OneTimeTokenResetPassword); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("Expected all of the user's tokens to be deleted, got %v", err)
 	}
 }
 
-func TestVerificationEntryRepository_DeleteVerificationEntry(t *testing.T) {
+func TestOneTimeTokenRepository_UseOneTimeToken(t *testing.T) {
 	db, err := test_utils.SetupTestDB()
 	if err != nil {
 		t.Fatalf("Failed to set up test database: %v", err)
 	}
-	defer db.Migrator().DropTable(&models.VerificationEntry{})
+	defer db.Migrator().DropTable(&models.OneTimeToken{}) // Drop the table after testing
 
-	// Create the VerificationEntry Repository with the test database
-	ver := NewVerificationEntryRepository()
-	ver.db = db
+	otr := NewOneTimeTokenRepository()
+	otr.db = db
 
-	// Create a test verification entry
-	verificationEntry := models.VerificationEntry{
-		Email: "test@example.com",
-		OTP:   "123456", // Change this to your desired OTP
+	oneTimeToken := models.OneTimeToken{Email: "test@example.com", Purpose: models.OneTimeTokenDeleteAccount, TokenHash: "hash", ExpiresAt: time.Now().Add(time.Minute)}
+	if err := otr.ReplaceOneTimeToken(&oneTimeToken); err != nil {
+		t.Fatalf("Failed to create a test OneTimeToken: %v", err)
 	}
 
-	// Create the verification entry in the database
-	if err := ver.CreateVerificationEntry(verificationEntry); err != nil {
-		t.Fatalf("Failed to create a test verification entry: %v", err)
+	for i := 0; i < 2; i++ {
+		if ok, err := otr.RecordOneTimeTokenAttempt(oneTimeToken.ID, 2); err != nil || !ok {
+			t.Errorf("RecordOneTimeTokenAttempt %d = %v, %v, expected true", i, ok, err)
+		}
+	}
+	if ok, err := otr.RecordOneTimeTokenAttempt(oneTimeToken.ID, 2); err != nil || ok {
+		t.Errorf("RecordOneTimeTokenAttempt = %v, %v out of attempts, expected false", ok, err)
 	}
 
-	// Retrieve the verification entry by Email
-	retrievedEntry, err := ver.GetVerificationEntryByEmail("test@example.com")
+	if ok, err := otr.UseOneTimeToken(oneTimeToken.ID, time.Now()); err != nil || !ok {
+		t.Errorf("UseOneTimeToken = %v, %v, expected true", ok, err)
+	}
+	if ok, err := otr.UseOneTimeToken(oneTimeToken.ID, time.Now()); err != nil || ok {
+		t.Errorf("UseOneTimeToken = %v, %v on a used token, expected false", ok, err)
+	}
+}
+
+func TestOneTimeTokenRepository_PurgeOneTimeTokens(t *testing.T) {
+	db, err := test_utils.SetupTestDB()
 	if err != nil {
-		t.Errorf("GetVerificationEntryByEmail returned an error: %v", err)
+		t.Fatalf("Failed to set up test database: %v", err)
 	}
+	defer db.Migrator().DropTable(&models.OneTimeToken{}) // Drop the table after testing
+
+	otr := NewOneTimeTokenRepository()
+	otr.db = db
 
-	// Delete the verification entry by email
-	if err := ver.DeleteVerificationEntry(retrievedEntry.Email); err != nil {
-		t.Errorf("DeleteVerificationEntry returned an error: %v", err)
+	now := time.Now()
+	used := models.OneTimeToken{Email: "used@example.com", Purpose: models.OneTimeTokenVerifyEmail, TokenHash: "used", ExpiresAt: now.Add(time.Hour)}
+	for _, oneTimeToken := range []*models.OneTimeToken{
+		{Email: "expired@example.com", Purpose: models.OneTimeTokenVerifyEmail, TokenHash: "expired", ExpiresAt: now.Add(-time.Minute)},
+		{Email: "live@example.com", Purpose: models.OneTimeTokenVerifyEmail, TokenHash: "live", ExpiresAt: now.Add(time.Hour)},
+		&used,
+	} {
+		if err := otr.ReplaceOneTimeToken(oneTimeToken); err != nil {
+			t.Fatalf("Failed to create a test OneTimeToken: %v", err)
+		}
+	}
+	if _, err := otr.UseOneTimeToken(used.ID, now); err != nil {
+		t.Fatalf("UseOneTimeToken returned an error: %v", err)
 	}
 
-	// Attempt to retrieve the verification entry to confirm deletion
-	_, err = ver.GetVerificationEntryByEmail("test@example.com")
-	if err == nil {
-		t.Error("Verification entry was not deleted, GetVerificationEntryByEmail returned no error")
-	} else if err.Error() != "record not found" {
-		t.Errorf("Expected 'record not found' error, got: %v", err)
+	deleted, err := otr.PurgeOneTimeTokens(now)
+	if err != nil || deleted != 2 {
+		t.Errorf("PurgeOneTimeTokens = %d, %v, expected the expired and used tokens", deleted, err)
+	}
+	if _, err := otr.GetOneTimeToken("live@example.com", models.OneTimeTokenVerifyEmail); err != nil {
+		t.Errorf("Expected the live token to be kept, got %v", err)
 	}
 }
 
@@ -407,142 +443,6 @@ func TestPasswordAuthRepository_UpdatePwdAuthItem(t *testing.T) {
 	}
 }
 
-func TestForgotPasswordRepository_CreateForgotPassword(t *testing.T) {
-	db, err := test_utils.SetupTestDB()
-	if err != nil {
-		t.Fatalf("Failed to set up test database: %v", err)
-	}
-	defer db.Migrator().DropTable(&models.ForgotPassword{})
-
-	// Create the ForgotPassword Repository with the test database
-	fpr := NewForgotPasswordRepository()
-	fpr.db = db
-
-	// Create a test forgot password entry
-	forgotPassword := models.ForgotPassword{
-		Email: "test@example.com",
-		OTP:   "123456",
-	}
-
-	// Test CreateForgotPassword function
-	if err := fpr.CreateForgotPassword(forgotPassword); err != nil {
-		t.Errorf("CreateForgotPassword returned an error: %v", err)
-	}
-
-	// Test GetForgotPasswordByEmail function to retrieve the created entry
-	retrievedForgotPassword, err := fpr.GetForgotPasswordByEmail("test@example.com")
-	if err != nil {
-		t.Errorf("GetForgotPasswordByEmail returned an error: %v", err)
-	}
-	if retrievedForgotPassword.Email != forgotPassword.Email {
-		t.Errorf("Expected email: %s, got: %s", forgotPassword.Email, retrievedForgotPassword.Email)
-	}
-}
-
-func TestForgotPasswordRepository_DeleteForgotPasswordByEmail(t *testing.T) {
-	db, err := test_utils.SetupTestDB()
-	if err != nil {
-		t.Fatalf("Failed to set up test database: %v", err)
-	}
-	defer db.Migrator().DropTable(&models.ForgotPassword{})
-
-	// Create the ForgotPassword Repository with the test database
-	fpr := NewForgotPasswordRepository()
-	fpr.db = db
-
-	// Create a test forgot password entry
-	forgotPassword := models.ForgotPassword{
-		Email: "test@example.com",
-		OTP:   "123456",
-	}
-
-	// Create the entry in the database
-	if err := fpr.CreateForgotPassword(forgotPassword); err != nil {
-		t.Fatalf("Failed to create a test forgot password entry: %v", err)
-	}
-
-	// Test DeleteForgotPasswordByEmail function
-	if err := fpr.DeleteForgotPasswordByEmail("test@example.com"); err != nil {
-		t.Errorf("DeleteForgotPasswordByEmail returned an error: %v", err)
-	}
-
-	// Attempt to retrieve the entry to confirm deletion
-	_, err = fpr.GetForgotPasswordByEmail("test@example.com")
-	if err == nil {
-		t.Error("Forgot password entry was not deleted, GetForgotPasswordByEmail returned no error")
-	} else if err.Error() != "record not found" {
-		t.Errorf("Expected 'record not found' error, got: %v", err)
-	}
-}
-
-func TestDeletionConfirmationRepository_CreateDeletionConfirmation(t *testing.T) {
-	db, err := test_utils.SetupTestDB()
-	if err != nil {
-		t.Fatalf("Failed to set up test database: %v", err)
-	}
-	defer db.Migrator().DropTable(&models.DeletionConfirmation{})
-
-	// Create the DeletionConfirmation Repository with the test database
-	dcr := NewDeletionConfirmationRepository()
-	dcr.db = db
-
-	// Create a test deletion confirmation entry
-	confirmation := models.DeletionConfirmation{
-		Email: "test@example.com",
-		OTP:   "123456",
-	}
-
-	// Test CreateDeletionConfirmation function
-	if err := dcr.CreateDeletionConfirmation(confirmation); err != nil {
-		t.Errorf("CreateDeletionConfirmation returned an error: %v", err)
-	}
-
-	// Test GetDeletionConfirmationByEmail function to retrieve the created entry
-	retrievedConfirmation, err := dcr.GetDeletionConfirmationByEmail("test@example.com")
-	if err != nil {
-		t.Errorf("GetDeletionConfirmationByEmail returned an error: %v", err)
-	}
-	if retrievedConfirmation.Email != confirmation.Email {
-		t.Errorf("Expected email: %s, got: %s", confirmation.Email, retrievedConfirmation.Email)
-	}
-}
-
-func TestDeletionConfirmationRepository_DeleteDeletionConfirmationByEmail(t *testing.T) {
-	db, err := test_utils.SetupTestDB()
-	if err != nil {
-		t.Fatalf("Failed to set up test database: %v", err)
-	}
-	defer db.Migrator().DropTable(&models.DeletionConfirmation{})
-
-	// Create the DeletionConfirmation Repository with the test database
-	dcr := NewDeletionConfirmationRepository()
-	dcr.db = db
-
-	// Create a test deletion confirmation entry
-	confirmation := models.DeletionConfirmation{
-		Email: "test@example.com",
-		OTP:   "123456",
-	}
-
-	// Create the entry in the database
-	if err := dcr.CreateDeletionConfirmation(confirmation); err != nil {
-		t.Fatalf("Failed to create a test deletion confirmation entry: %v", err)
-	}
-
-	// Test DeleteDeletionConfirmationByEmail function
-	if err := dcr.DeleteDeletionConfirmationByEmail("test@example.com"); err != nil {
-		t.Errorf("DeleteDeletionConfirmationByEmail returned an error: %v", err)
-	}
-
-	// Attempt to retrieve the entry to confirm deletion
-	_, err = dcr.GetDeletionConfirmationByEmail("test@example.com")
-	if err == nil {
-		t.Error("Deletion confirmation entry was not deleted, GetDeletionConfirmationByEmail returned no error")
-	} else if err.Error() != "record not found" {
-		t.Errorf("Expected 'record not found' error, got: %v", err)
-	}
-}
-
 func TestAuthProviderRepository_GetAuthProviderByProviderKey(t *testing.T) {
 	db, err := test_utils.SetupTestDB()
 	if err != nil {
@@ -737,3 +637,390 @@ func TestAuthProviderRepository_DeleteAuthProviderByUserID(t *testing.T) {
 		t.Errorf("Expected 'record not found' error, got: %v", err)
 	}
 }
+
+func TestRefreshTokenRepository_MarkRefreshTokenUsed(t *testing.T) {
+	db, err := test_utils.SetupTestDB()
+	if err != nil {
+		t.Fatalf("Failed to set up test database: %v", err)
+	}
+	defer db.Migrator().DropTable(&models.RefreshToken{}) // Drop the table after testing
+
+	rtr := NewRefreshTokenRepository()
+	rtr.db = db
+
+	refreshToken := models.RefreshToken{UserID: 1, SessionID: 1, TokenHash: "hash", ExpiresAt: time.Now().Add(time.Hour)}
+	if err := rtr.CreateRefreshToken(&refreshToken); err != nil {
+		t.Fatalf("Failed to create a test RefreshToken: %v", err)
+	}
+
+	// A token can only be marked used once
+	if ok, err := rtr.MarkRefreshTokenUsed(refreshToken.ID, time.Now()); err != nil || !ok {
+		t.Errorf("MarkRefreshTokenUsed = %v, %v, expected true", ok, err)
+	}
+	if ok, err := rtr.MarkRefreshTokenUsed(refreshToken.ID, time.Now()); err != nil || ok {
+		t.Errorf("MarkRefreshTokenUsed = %v, %v on a used token, expected false", ok, err)
+	}
+
+	retrieved, err := rtr.GetRefreshTokenByHash("hash")
+	if err != nil {
+		t.Fatalf("GetRefreshTokenByHash returned an error: %v", err)
+	}
+	if retrieved.UsedAt == nil {
+		t.Error("Expected the token to be marked used")
+	}
+}
+
+func TestSessionRepository_RevokeSessions(t *testing.T) {
+	db, err := test_utils.SetupTestDB()
+	if err != nil {
+		t.Fatalf("Failed to set up test database: %v", err)
+	}
+	defer db.Migrator().DropTable(&models.Session{}) // Drop the table after testing
+
+	sr := NewSessionRepository()
+	sr.db = db
+
+	now := time.Now()
+	current := models.Session{UserID: 1, JTI: "current", LastSeenAt: now, ExpiresAt: now.Add(time.Hour)}
+	other := models.Session{UserID: 1, JTI: "other", LastSeenAt: now.Add(-time.Minute), ExpiresAt: now.Add(time.Hour)}
+	expired := models.Session{UserID: 1, JTI: "expired", LastSeenAt: now.Add(-time.Hour), ExpiresAt: now.Add(-time.Minute)}
+	someoneElses := models.Session{UserID: 2, JTI: "someone-elses", LastSeenAt: now, ExpiresAt: now.Add(time.Hour)}
+	for _, session := range []*models.Session{&current, &other, &expired, &someoneElses} {
+		if err := sr.CreateSession(session); err != nil {
+			t.Fatalf("Failed to create a test Session: %v", err)
+		}
+	}
+
+	sessions, err := sr.GetActiveSessions(1)
+	if err != nil {
+		t.Fatalf("GetActiveSessions returned an error: %v", err)
+	}
+	if len(sessions) != 2 || sessions[0].ID != current.ID || sessions[1].ID != other.ID {
+		t.Errorf("Expected the current and other sessions, got %+v", sessions)
+	}
+
+	// Users can't revoke each other's sessions
+	if err := sr.RevokeSession(1, someoneElses.ID, now); err != gorm.ErrRecordNotFound {
+		t.Errorf("Expected gorm.ErrRecordNotFound revoking someone else's session, got %v", err)
+	}
+
+	// Revoking the other sessions leaves the current one alone
+	if err := sr.RevokeUserSessions(1, current.ID, now); err != nil {
+		t.Errorf("RevokeUserSessions returned an error: %v", err)
+	}
+	for jti, revoked := range map[string]bool{"current": false, "other": true, "someone-elses": false} {
+		session, err := sr.GetSessionByJTI(jti)
+		if err != nil {
+			t.Fatalf("GetSessionByJTI returned an error: %v", err)
+		}
+		if (session.RevokedAt != nil) != revoked {
+			t.Errorf("Session %s revoked = %v, expected %v", jti, session.RevokedAt != nil, revoked)
+		}
+	}
+
+	if err := sr.RevokeSession(1, current.ID, now); err != nil {
+		t.Errorf("RevokeSession returned an error: %v", err)
+	}
+	if sessions, _ := sr.GetActiveSessions(1); len(sessions) != 0 {
+		t.Errorf("Expected no active sessions, got %+v", sessions)
+	}
+}
+
+func TestUserRepository_SearchUsers(t *testing.T) {
+	db, err := test_utils.SetupTestDB()
+	if err != nil {
+		t.Fatalf("Failed to set up test database: %v", err)
+	}
+	defer db.Migrator().DropTable(&models.User{}) // Drop the table after testing
+
+	ur := NewUserRepository()
+	ur.db = db
+
+	for _, user := range []models.User{
+		{Email: "alice@example.com", Name: "Alice"},
+		{Email: "bob@example.com", Name: "Bob", Role: models.AdminRole},
+		{Email: "carol@example.com", Name: "Carol Alison"},
+	} {
+		if err := ur.CreateUser(user); err != nil {
+			t.Fatalf("Failed to create a test User: %v", err)
+		}
+	}
+	carol, _ := ur.GetUserByEmail("carol@example.com")
+	if err := ur.SetUserDisabled(carol.ID, true); err != nil {
+		t.Fatalf("SetUserDisabled returned an error: %v", err)
+	}
+
+	users, total, err := ur.SearchUsers(models.UserFilter{Query: "ALI"}, 0, 1)
+	if err != nil {
+		t.Fatalf("SearchUsers returned an error: %v", err)
+	}
+	if total != 2 || len(users) != 1 || users[0].Email != "carol@example.com" {
+		t.Errorf("Expected the first of 2 matches to be carol, got %d: %+v", total, users)
+	}
+
+	disabled := false
+	users, total, err = ur.SearchUsers(models.UserFilter{Query: "ali", Disabled: &disabled}, 0, 10)
+	if err != nil {
+		t.Fatalf("SearchUsers returned an error: %v", err)
+	}
+	if total != 1 || len(users) != 1 || users[0].Email != "alice@example.com" {
+		t.Errorf("Expected only alice, got %d: %+v", total, users)
+	}
+
+	users, _, _ = ur.SearchUsers(models.UserFilter{Role: models.AdminRole}, 0, 10)
+	if len(users) != 1 || users[0].Email != "bob@example.com" {
+		t.Errorf("Expected only bob, got %+v", users)
+	}
+}
+
+func TestMFARepository_TOTPFactor(t *testing.T) {
+	db, err := test_utils.SetupTestDB()
+	if err != nil {
+		t.Fatalf("Failed to set up test database: %v", err)
+	}
+	defer db.Migrator().DropTable(&models.TOTPFactor{}, &models.RecoveryCode{}) // Drop the tables after testing
+
+	mr := NewMFARepository()
+	mr.db = db
+
+	if factor, err := mr.GetTOTPFactor(1); err != nil || factor.ID != 0 {
+		t.Fatalf("GetTOTPFactor = %+v, %v, expected no factor", factor, err)
+	}
+
+	factor := models.TOTPFactor{UserID: 1, Secret: "SECRET"}
+	if err := mr.SaveTOTPFactor(&factor); err != nil {
+		t.Fatalf("Failed to save a test TOTPFactor: %v", err)
+	}
+
+	// Steps can only move forward
+	if ok, err := mr.UseTOTPStep(factor.ID, 10); err != nil || !ok {
+		t.Errorf("UseTOTPStep = %v, %v, expected true", ok, err)
+	}
+	if ok, err := mr.UseTOTPStep(factor.ID, 10); err != nil || ok {
+		t.Errorf("UseTOTPStep = %v, %v on a used step, expected false", ok, err)
+	}
+	if ok, err := mr.UseTOTPStep(factor.ID, 9); err != nil || ok {
+		t.Errorf("UseTOTPStep = %v, %v on an earlier step, expected false", ok, err)
+	}
+
+	if err := mr.ReplaceRecoveryCodes(1, []models.RecoveryCode{{UserID: 1, CodeHash: "a"}, {UserID: 1, CodeHash: "b"}}); err != nil {
+		t.Fatalf("ReplaceRecoveryCodes returned an error: %v", err)
+	}
+	if ok, err := mr.UseRecoveryCode(1, "a", time.Now()); err != nil || !ok {
+		t.Errorf("UseRecoveryCode = %v, %v, expected true", ok, err)
+	}
+	if ok, err := mr.UseRecoveryCode(1, "a", time.Now()); err != nil || ok {
+		t.Errorf("UseRecoveryCode = %v, %v on a used code, expected false", ok, err)
+	}
+	if ok, err := mr.UseRecoveryCode(2, "b", time.Now()); err != nil || ok {
+		t.Errorf("UseRecoveryCode = %v, %v on another user's code, expected false", ok, err)
+	}
+	if count, err := mr.CountRecoveryCodes(1); err != nil || count != 1 {
+		t.Errorf("CountRecoveryCodes = %d, %v, expected 1", count, err)
+	}
+
+	// Users can enroll again once they've disabled two-factor authentication
+	if err := mr.DeleteTOTPFactor(1); err != nil {
+		t.Fatalf("DeleteTOTPFactor returned an error: %v", err)
+	}
+	if count, err := mr.CountRecoveryCodes(1); err != nil || count != 0 {
+		t.Errorf("CountRecoveryCodes = %d, %v after disabling, expected 0", count, err)
+	}
+	if err := mr.SaveTOTPFactor(&models.TOTPFactor{UserID: 1, Secret: "NEWSECRET"}); err != nil {
+		t.Errorf("Failed to enroll again: %v", err)
+	}
+}
+
+func TestMFARepository_RecordMFAChallengeAttempt(t *testing.T) {
+	db, err := test_utils.SetupTestDB()
+	if err != nil {
+		t.Fatalf("Failed to set up test database: %v", err)
+	}
+	defer db.Migrator().DropTable(&models.MFAChallenge{}) // Drop the table after testing
+
+	mr := NewMFARepository()
+	mr.db = db
+
+	challenge := models.MFAChallenge{UserID: 1, TokenHash: "hash", ExpiresAt: time.Now().Add(time.Minute)}
+	if err := mr.CreateMFAChallenge(&challenge); err != nil {
+		t.Fatalf("Failed to create a test MFAChallenge: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if ok, err := mr.RecordMFAChallengeAttempt(challenge.ID, 2); err != nil || !ok {
+			t.Errorf("RecordMFAChallengeAttempt %d = %v, %v, expected true", i, ok, err)
+		}
+	}
+	if ok, err := mr.RecordMFAChallengeAttempt(challenge.ID, 2); err != nil || ok {
+		t.Errorf("RecordMFAChallengeAttempt = %v, %v out of attempts, expected false", ok, err)
+	}
+
+	if ok, err := mr.UseMFAChallenge(challenge.ID, time.Now()); err != nil || !ok {
+		t.Errorf("UseMFAChallenge = %v, %v, expected true", ok, err)
+	}
+	if ok, err := mr.UseMFAChallenge(challenge.ID, time.Now()); err != nil || ok {
+		t.Errorf("UseMFAChallenge = %v, %v on a used challenge, expected false", ok, err)
+	}
+}
+
+func TestSessionRepository_UseSocialLoginCode(t *testing.T) {
+	db, err := test_utils.SetupTestDB()
+	if err != nil {
+		t.Fatalf("Failed to set up test database: %v", err)
+	}
+	defer db.Migrator().DropTable(&models.SocialLoginCode{}) // Drop the table after testing
+
+	sr := NewSessionRepository()
+	sr.db = db
+
+	code := models.SocialLoginCode{UserID: 1, CodeHash: "hash", ExpiresAt: time.Now().Add(time.Minute)}
+	if err := sr.CreateSocialLoginCode(&code); err != nil {
+		t.Fatalf("Failed to create a test SocialLoginCode: %v", err)
+	}
+	if stored, err := sr.GetSocialLoginCodeByHash("hash"); err != nil || stored.ID != code.ID {
+		t.Errorf("GetSocialLoginCodeByHash = %+v, %v", stored, err)
+	}
+
+	if ok, err := sr.UseSocialLoginCode(code.ID, time.Now()); err != nil || !ok {
+		t.Errorf("UseSocialLoginCode = %v, %v, expected true", ok, err)
+	}
+	if ok, err := sr.UseSocialLoginCode(code.ID, time.Now()); err != nil || ok {
+		t.Errorf("UseSocialLoginCode = %v, %v on a used code, expected false", ok, err)
+	}
+}
+
+func TestPasskeyRepository_CreateAndDeletePasskey(t *testing.T) {
+	db, err := test_utils.SetupTestDB()
+	if err != nil {
+		t.Fatalf("Failed to set up test database: %v", err)
+	}
+	defer db.Migrator().DropTable(&models.Passkey{}, &models.AuthProvider{}) // Drop the tables after testing
+
+	pr := NewPasskeyRepository()
+	pr.db = db
+
+	passkey := models.Passkey{UserID: 1, CredentialID: "credential", PublicKey: []byte{1}, Name: "Laptop"}
+	if err := pr.CreatePasskey(&passkey); err != nil {
+		t.Fatalf("CreatePasskey returned an error: %v", err)
+	}
+
+	// Every passkey is an AuthProvider too
+	var authProvider models.AuthProvider
+	if err := db.First(&authProvider, passkey.AuthProviderID).Error; err != nil {
+		t.Fatalf("Expected an AuthProvider for the passkey: %v", err)
+	}
+	if authProvider.ProviderName != models.PasskeyProvider || authProvider.ProviderKey != "credential" || authProvider.UserID != 1 {
+		t.Errorf("Unexpected AuthProvider %+v", authProvider)
+	}
+
+	if err := pr.DeletePasskey(2, passkey.ID); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("DeletePasskey of another user's passkey = %v, expected gorm.ErrRecordNotFound", err)
+	}
+	if err := pr.DeletePasskey(1, passkey.ID); err != nil {
+		t.Fatalf("DeletePasskey returned an error: %v", err)
+	}
+	if _, err := pr.GetPasskeyByCredentialID("credential"); err == nil {
+		t.Error("Expected the passkey to be deleted")
+	}
+	if err := db.First(&models.AuthProvider{}, passkey.AuthProviderID).Error; err == nil {
+		t.Error("Expected the passkey's AuthProvider to be deleted")
+	}
+}
+
+func TestPasskeyRepository_UsePasskeyChallenge(t *testing.T) {
+	db, err := test_utils.SetupTestDB()
+	if err != nil {
+		t.Fatalf("Failed to set up test database: %v", err)
+	}
+	defer db.Migrator().DropTable(&models.PasskeyChallenge{}) // Drop the table after testing
+
+	pr := NewPasskeyRepository()
+	pr.db = db
+
+	now := time.Now()
+	pr.CreatePasskeyChallenge(&models.PasskeyChallenge{Challenge: "login", Purpose: models.PasskeyLogin, ExpiresAt: now.Add(time.Minute)})
+	pr.CreatePasskeyChallenge(&models.PasskeyChallenge{Challenge: "expired", Purpose: models.PasskeyLogin, ExpiresAt: now.Add(-time.Minute)})
+
+	if _, err := pr.UsePasskeyChallenge("login", models.PasskeyRegistration, now); err == nil {
+		t.Error("Expected an error for a challenge with another purpose")
+	}
+	if challenge, err := pr.UsePasskeyChallenge("login", models.PasskeyLogin, now); err != nil || challenge.UsedAt == nil {
+		t.Errorf("UsePasskeyChallenge = %+v, %v, expected a used challenge", challenge, err)
+	}
+	if _, err := pr.UsePasskeyChallenge("login", models.PasskeyLogin, now); err == nil {
+		t.Error("Expected an error for a used challenge")
+	}
+	if _, err := pr.UsePasskeyChallenge("expired", models.PasskeyLogin, now); err == nil {
+		t.Error("Expected an error for an expired challenge")
+	}
+}
+
+func TestPasskeyRepository_PurgePasskeyChallenges(t *testing.T) {
+	db, err := test_utils.SetupTestDB()
+	if err != nil {
+		t.Fatalf("Failed to set up test database: %v", err)
+	}
+	defer db.Migrator().DropTable(&models.PasskeyChallenge{}) // Drop the table after testing
+
+	pr := NewPasskeyRepository()
+	pr.db = db
+
+	now := time.Now()
+	pr.CreatePasskeyChallenge(&models.PasskeyChallenge{Challenge: "used", Purpose: models.PasskeyLogin, ExpiresAt: now.Add(time.Minute)})
+	pr.CreatePasskeyChallenge(&models.PasskeyChallenge{Challenge: "expired", Purpose: models.PasskeyLogin, ExpiresAt: now.Add(-time.Minute)})
+	pr.CreatePasskeyChallenge(&models.PasskeyChallenge{Challenge: "live", Purpose: models.PasskeyLogin, ExpiresAt: now.Add(time.Minute)})
+	if _, err := pr.UsePasskeyChallenge("used", models.PasskeyLogin, now); err != nil {
+		t.Fatalf("UsePasskeyChallenge returned an error: %v", err)
+	}
+
+	deleted, err := pr.PurgePasskeyChallenges(now)
+	if err != nil || deleted != 2 {
+		t.Errorf("PurgePasskeyChallenges = %d, %v, expected the expired and used challenges", deleted, err)
+	}
+	if _, err := pr.UsePasskeyChallenge("live", models.PasskeyLogin, now); err != nil {
+		t.Errorf("Expected the live challenge to be kept, got %v", err)
+	}
+}
+
+func TestThrottleRepository_RecordFailure(t *testing.T) {
+	db, err := test_utils.SetupTestDB()
+	if err != nil {
+		t.Fatalf("Failed to set up test database: %v", err)
+	}
+	defer db.Migrator().DropTable(&models.AuthThrottle{}) // Drop the table after testing
+
+	tr := NewThrottleRepository()
+	tr.db = db
+
+	start := time.Now()
+	for i := 1; i <= 3; i++ {
+		at := start.Add(time.Duration(i) * time.Second)
+		throttle, err := tr.RecordFailure("login:account:test@example.com", at, at.Add(-time.Hour))
+		if err != nil || throttle.Failures != i {
+			t.Errorf("RecordFailure %d = %d failures, %v", i, throttle.Failures, err)
+		}
+	}
+
+	// failures older than the window are forgotten
+	later := start.Add(2 * time.Hour)
+	throttle, err := tr.RecordFailure("login:account:test@example.com", later, later.Add(-time.Hour))
+	if err != nil || throttle.Failures != 1 {
+		t.Errorf("RecordFailure after the window = %d failures, %v, expected 1", throttle.Failures, err)
+	}
+
+	if err := tr.LockThrottle("login:account:test@example.com", later.Add(time.Minute)); err != nil {
+		t.Fatalf("LockThrottle: %v", err)
+	}
+	throttles, err := tr.GetThrottles([]string{"login:account:test@example.com", "login:ip:203.0.113.7"})
+	if err != nil || len(throttles) != 1 || throttles[0].LockedUntil == nil {
+		t.Errorf("GetThrottles = %+v, %v, expected the locked account", throttles, err)
+	}
+
+	if err := tr.ResetThrottle("login:account:test@example.com"); err != nil {
+		t.Fatalf("ResetThrottle: %v", err)
+	}
+	throttle, err = tr.RecordFailure("login:account:test@example.com", later, later.Add(-time.Hour))
+	if err != nil || throttle.Failures != 1 || throttle.LockedUntil != nil {
+		t.Errorf("RecordFailure after a reset = %+v, %v, expected 1 failure", throttle, err)
+	}
+}
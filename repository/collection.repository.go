@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"github.com/anirudhgray/mood-harbour-backend/infra/database"
+	"github.com/anirudhgray/mood-harbour-backend/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CollectionRepository struct {
+	db *gorm.DB
+}
+
+func NewCollectionRepository() *CollectionRepository {
+	return &CollectionRepository{database.DB}
+}
+
+// CollectionRepositoryInterface is the interface for the CollectionRepository.
+type CollectionRepositoryInterface interface {
+	CreateBookmark(bookmark *models.Bookmark) error
+	DeleteBookmark(userID, resourceID uint) error
+	GetBookmarksByUserID(userID uint) ([]models.Bookmark, error)
+	CreateCollection(collection *models.Collection) error
+	GetCollectionByID(collectionID uint) (models.Collection, error)
+	GetCollectionsByUserID(userID uint) ([]models.Collection, error)
+	GetPublicCollections() ([]models.Collection, error)
+	GetFollowedCollections(userID uint) ([]models.Collection, error)
+	UpdateCollection(collection *models.Collection) error
+	DeleteCollection(collectionID uint) error
+	GetCollectionItems(collectionID uint) ([]models.CollectionItem, error)
+	AddCollectionItem(item *models.CollectionItem) error
+	RemoveCollectionItem(collectionID, resourceID uint) error
+	ReorderCollectionItems(collectionID uint, resourceIDs []uint) error
+	FollowCollection(follow *models.CollectionFollow) error
+	UnfollowCollection(collectionID, userID uint) error
+	CountCollectionFollowers(collectionID uint) (int64, error)
+}
+
+// CreateBookmark bookmarks a resource for a user. Bookmarking an already bookmarked resource does nothing.
+func (cr *CollectionRepository) CreateBookmark(bookmark *models.Bookmark) error {
+	return cr.db.Clauses(clause.OnConflict{DoNothing: true}).Create(bookmark).Error
+}
+
+// DeleteBookmark removes a user's bookmark on a resource.
+func (cr *CollectionRepository) DeleteBookmark(userID, resourceID uint) error {
+	return cr.db.Where("user_id = ? AND resource_id = ?", userID, resourceID).Unscoped().Delete(&models.Bookmark{}).Error
+}
+
+// GetBookmarksByUserID gets all the bookmarks for a specific user, most recent first.
+func (cr *CollectionRepository) GetBookmarksByUserID(userID uint) ([]models.Bookmark, error) {
+	var bookmarks []models.Bookmark
+	err := cr.db.Where("user_id = ?", userID).Order("created_at desc").Find(&bookmarks).Error
+	return bookmarks, err
+}
+
+// CreateCollection creates a new collection in the database.
+func (cr *CollectionRepository) CreateCollection(collection *models.Collection) error {
+	return cr.db.Create(collection).Error
+}
+
+// GetCollectionByID gets a collection by its ID.
+func (cr *CollectionRepository) GetCollectionByID(collectionID uint) (models.Collection, error) {
+	var collection models.Collection
+	err := cr.db.First(&collection, collectionID).Error
+	return collection, err
+}
+
+// GetCollectionsByUserID gets all the collections created by a specific user.
+func (cr *CollectionRepository) GetCollectionsByUserID(userID uint) ([]models.Collection, error) {
+	var collections []models.Collection
+	err := cr.db.Where("user_id = ?", userID).Order("created_at desc").Find(&collections).Error
+	return collections, err
+}
+
+// GetPublicCollections gets all the public collections.
+func (cr *CollectionRepository) GetPublicCollections() ([]models.Collection, error) {
+	var collections []models.Collection
+	err := cr.db.Where("public = ?", true).Order("created_at desc").Find(&collections).Error
+	return collections, err
+}
+
+// GetFollowedCollections gets all the public collections followed by a specific user.
+func (cr *CollectionRepository) GetFollowedCollections(userID uint) ([]models.Collection, error) {
+	var collections []models.Collection
+	err := cr.db.Joins("JOIN collection_follows ON collection_follows.collection_id = collections.id").
+		Where("collection_follows.user_id = ? AND collections.public = ?", userID, true).
+		Order("collection_follows.created_at desc").
+		Find(&collections).Error
+	return collections, err
+}
+
+// UpdateCollection updates a collection in the database.
+func (cr *CollectionRepository) UpdateCollection(collection *models.Collection) error {
+	return cr.db.Save(collection).Error
+}
+
+// DeleteCollection deletes a collection along with its items and followers.
+func (cr *CollectionRepository) DeleteCollection(collectionID uint) error {
+	return cr.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("collection_id = ?", collectionID).Unscoped().Delete(&models.CollectionItem{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("collection_id = ?", collectionID).Unscoped().Delete(&models.CollectionFollow{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Collection{}, collectionID).Error
+	})
+}
+
+// GetCollectionItems gets all the items in a collection, in order.
+func (cr *CollectionRepository) GetCollectionItems(collectionID uint) ([]models.CollectionItem, error) {
+	var items []models.CollectionItem
+	err := cr.db.Where("collection_id = ?", collectionID).Order("position asc").Find(&items).Error
+	return items, err
+}
+
+// AddCollectionItem adds a resource to a collection.
+func (cr *CollectionRepository) AddCollectionItem(item *models.CollectionItem) error {
+	return cr.db.Create(item).Error
+}
+
+// RemoveCollectionItem removes a resource from a collection.
+func (cr *CollectionRepository) RemoveCollectionItem(collectionID, resourceID uint) error {
+	return cr.db.Where("collection_id = ? AND resource_id = ?", collectionID, resourceID).Unscoped().Delete(&models.CollectionItem{}).Error
+}
+
+// ReorderCollectionItems sets the position of each resource in a collection to its index in resourceIDs.
+func (cr *CollectionRepository) ReorderCollectionItems(collectionID uint, resourceIDs []uint) error {
+	return cr.db.Transaction(func(tx *gorm.DB) error {
+		for position, resourceID := range resourceIDs {
+			err := tx.Model(&models.CollectionItem{}).
+				Where("collection_id = ? AND resource_id = ?", collectionID, resourceID).
+				Update("position", position).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// FollowCollection makes a user follow a collection. Following an already followed collection does nothing.
+func (cr *CollectionRepository) FollowCollection(follow *models.CollectionFollow) error {
+	return cr.db.Clauses(clause.OnConflict{DoNothing: true}).Create(follow).Error
+}
+
+// UnfollowCollection makes a user stop following a collection.
+func (cr *CollectionRepository) UnfollowCollection(collectionID, userID uint) error {
+	return cr.db.Where("collection_id = ? AND user_id = ?", collectionID, userID).Unscoped().Delete(&models.CollectionFollow{}).Error
+}
+
+// CountCollectionFollowers gets the number of users following a collection.
+func (cr *CollectionRepository) CountCollectionFollowers(collectionID uint) (int64, error) {
+	var count int64
+	err := cr.db.Model(&models.CollectionFollow{}).Where("collection_id = ?", collectionID).Count(&count).Error
+	return count, err
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/anirudhgray/mood-harbour-backend/models"
+	"github.com/anirudhgray/mood-harbour-backend/utils/test_utils"
+)
+
+func TestCollectionRepository_Bookmarks(t *testing.T) {
+	db, err := test_utils.SetupTestDB()
+	if err != nil {
+		t.Fatalf("Failed to set up test database: %v", err)
+	}
+	defer db.Migrator().DropTable(&models.Bookmark{})
+
+	cr := NewCollectionRepository()
+	cr.db = db
+
+	bookmarks := []models.Bookmark{
+		{UserID: 1, ResourceID: 1},
+		{UserID: 1, ResourceID: 2},
+		{UserID: 2, ResourceID: 1},
+	}
+
+	for _, bookmark := range bookmarks {
+		if err := cr.CreateBookmark(&bookmark); err != nil {
+			t.Fatalf("Failed to create test bookmark: %v", err)
+		}
+	}
+
+	// bookmarking the same resource twice is a no-op
+	if err := cr.CreateBookmark(&models.Bookmark{UserID: 1, ResourceID: 1}); err != nil {
+		t.Errorf("CreateBookmark returned an error for a duplicate bookmark: %v", err)
+	}
+
+	if err := cr.DeleteBookmark(1, 2); err != nil {
+		t.Errorf("DeleteBookmark returned an error: %v", err)
+	}
+
+	userBookmarks, err := cr.GetBookmarksByUserID(1)
+	if err != nil {
+		t.Fatalf("GetBookmarksByUserID returned an error: %v", err)
+	}
+	if len(userBookmarks) != 1 || userBookmarks[0].ResourceID != 1 {
+		t.Errorf("Expected only resource 1 to be bookmarked, got: %v", userBookmarks)
+	}
+
+	// a removed bookmark can be added again
+	if err := cr.CreateBookmark(&models.Bookmark{UserID: 1, ResourceID: 2}); err != nil {
+		t.Errorf("CreateBookmark returned an error after removing the bookmark: %v", err)
+	}
+}
+
+func TestCollectionRepository_ReorderCollectionItems(t *testing.T) {
+	db, err := test_utils.SetupTestDB()
+	if err != nil {
+		t.Fatalf("Failed to set up test database: %v", err)
+	}
+	defer db.Migrator().DropTable(&models.Collection{}, &models.CollectionItem{})
+
+	cr := NewCollectionRepository()
+	cr.db = db
+
+	collection := models.Collection{UserID: 1, Name: "Panic toolkit"}
+	if err := cr.CreateCollection(&collection); err != nil {
+		t.Fatalf("Failed to create test collection: %v", err)
+	}
+
+	for i, resourceID := range []uint{10, 20, 30} {
+		item := models.CollectionItem{CollectionID: collection.ID, ResourceID: resourceID, Position: i}
+		if err := cr.AddCollectionItem(&item); err != nil {
+			t.Fatalf("Failed to create test collection item: %v", err)
+		}
+	}
+
+	if err := cr.ReorderCollectionItems(collection.ID, []uint{30, 10, 20}); err != nil {
+		t.Fatalf("ReorderCollectionItems returned an error: %v", err)
+	}
+
+	items, err := cr.GetCollectionItems(collection.ID)
+	if err != nil {
+		t.Fatalf("GetCollectionItems returned an error: %v", err)
+	}
+
+	expectedOrder := []uint{30, 10, 20}
+	if len(items) != len(expectedOrder) {
+		t.Fatalf("Expected length: %d, got: %d", len(expectedOrder), len(items))
+	}
+	for i, resourceID := range expectedOrder {
+		if items[i].ResourceID != resourceID {
+			t.Errorf("Expected resource %d at position %d, got: %d", resourceID, i, items[i].ResourceID)
+		}
+	}
+}
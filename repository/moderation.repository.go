@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"github.com/anirudhgray/mood-harbour-backend/infra/database"
+	"github.com/anirudhgray/mood-harbour-backend/models"
+	"gorm.io/gorm"
+)
+
+type ModerationRepository struct {
+	db *gorm.DB
+}
+
+func NewModerationRepository() *ModerationRepository {
+	return &ModerationRepository{database.DB}
+}
+
+// ModerationRepositoryInterface is the interface for the ModerationRepository.
+type ModerationRepositoryInterface interface {
+	CreateReport(report *models.Report) error
+	GetOpenReports() ([]models.Report, error)
+	HasOpenReport(targetType models.ReportTargetType, targetID, reportedBy uint) (bool, error)
+	ResolveReports(targetType models.ReportTargetType, targetID, resolvedBy uint, action models.ModerationAction) error
+}
+
+// CreateReport creates a new report in the database.
+func (mr *ModerationRepository) CreateReport(report *models.Report) error {
+	return mr.db.Create(report).Error
+}
+
+// GetOpenReports gets all the reports which have not been resolved yet, oldest first.
+func (mr *ModerationRepository) GetOpenReports() ([]models.Report, error) {
+	var reports []models.Report
+	err := mr.db.Where("status = ?", models.ReportOpen).Order("created_at asc").Find(&reports).Error
+	return reports, err
+}
+
+// HasOpenReport checks if a user already has an unresolved report against a piece of content.
+func (mr *ModerationRepository) HasOpenReport(targetType models.ReportTargetType, targetID, reportedBy uint) (bool, error) {
+	var count int64
+	err := mr.db.Model(&models.Report{}).
+		Where("target_type = ? AND target_id = ? AND reported_by = ? AND status = ?", targetType, targetID, reportedBy, models.ReportOpen).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// ResolveReports marks all open reports against a piece of content as resolved with the given moderation action.
+func (mr *ModerationRepository) ResolveReports(targetType models.ReportTargetType, targetID, resolvedBy uint, action models.ModerationAction) error {
+	return mr.db.Model(&models.Report{}).
+		Where("target_type = ? AND target_id = ? AND status = ?", targetType, targetID, models.ReportOpen).
+		Updates(map[string]interface{}{"status": models.ReportResolved, "resolved_by": resolvedBy, "resolution": action}).Error
+}
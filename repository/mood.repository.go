@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"time"
+
 	"github.com/anirudhgray/mood-harbour-backend/infra/database"
 	"github.com/anirudhgray/mood-harbour-backend/models"
 	"gorm.io/gorm"
@@ -31,6 +33,7 @@ type MoodRepositoryInterface interface {
 	UpdateMoodEntry(mood *models.Mood) error
 	GetAttributes(userID uint) ([]models.Attribute, error)
 	GetMoodsByUserIDAndOrderedByCreatedAt(userID uint) ([]models.Mood, error)
+	GetLatestMoodSince(userID uint, since time.Time) (models.Mood, error)
 }
 
 // CreateMoodEntry creates a new mood entry in the database.
@@ -125,3 +128,11 @@ func (mr *MoodRepository) GetMoodsByUserIDAndOrderedByCreatedAt(userID uint) ([]
 	err := mr.db.Where("user_id = ?", userID).Order("created_at desc").Find(&moods).Error
 	return moods, err
 }
+
+// GetLatestMoodSince gets the user's most recent mood entry created after since.
+// Returns an empty mood (ID 0) if there is none.
+func (mr *MoodRepository) GetLatestMoodSince(userID uint, since time.Time) (models.Mood, error) {
+	var mood models.Mood
+	err := mr.db.Where("user_id = ? AND created_at > ?", userID, since).Order("created_at desc").Limit(1).Find(&mood).Error
+	return mood, err
+}
@@ -0,0 +1,382 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/anirudhgray/mood-harbour-backend/infra/database"
+	"github.com/anirudhgray/mood-harbour-backend/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RecommendationRepository struct {
+	db *gorm.DB
+}
+
+func NewRecommendationRepository() *RecommendationRepository {
+	return &RecommendationRepository{database.DB}
+}
+
+// RecommendationRepositoryInterface is the interface for the RecommendationRepository.
+type RecommendationRepositoryInterface interface {
+	GetPublishedReviews() ([]models.Review, error)
+	GetBookmarks() ([]models.Bookmark, error)
+	GetPublishedResources() ([]models.Resource, error)
+	GetUserIDsWithStatus() ([]uint, error)
+	SaveUserRecommendations(userID uint, similarities []models.UserSimilarity, recommendations []models.UserRecommendation, startedAt time.Time) error
+	GetUserRecommendations(userID uint, offset, limit int) ([]models.UserRecommendation, int64, error)
+	GetRecommendationStatus(userID uint) (models.RecommendationStatus, error)
+	MarkUsersStale(userIDs []uint, at time.Time) error
+	GetStaleUserIDs(limit int) ([]uint, error)
+	GetUserIDsWhoReviewedResource(resourceID uint) ([]uint, error)
+	GetUserSimilarities(userID uint) ([]models.UserSimilarity, error)
+	GetMoodReviews(resourceIDs, userIDs []uint) ([]models.Review, error)
+	GetReviewsByUserIDs(userIDs []uint) ([]models.Review, error)
+	GetResourceIDsWithMoodTag(resourceIDs []uint, mood models.MoodType) ([]uint, error)
+	GetDismissals() ([]models.RecommendationDismissal, error)
+	GetDismissalsByUserID(userID uint) ([]models.RecommendationDismissal, error)
+	CreateDismissal(dismissal *models.RecommendationDismissal) error
+	DeleteDismissal(userID, resourceID uint) error
+	DeleteUserRecommendation(userID, resourceID uint) error
+	GetCuratedResourceIDs() ([]uint, error)
+	CreateCuratedResource(curated *models.CuratedResource) (bool, error)
+	DeleteCuratedResource(resourceID uint) error
+	SaveFactorizationModel(model *models.FactorizationModel, userFactors []models.UserFactor, resourceFactors []models.ResourceFactor) error
+	GetFactorizationModels() ([]models.FactorizationModel, error)
+	GetActiveFactorizationModel() (models.FactorizationModel, error)
+	GetFactors(modelID uint) ([]models.UserFactor, []models.ResourceFactor, error)
+	ActivateFactorizationModel(modelID uint) error
+	PruneFactorizationModels(keep int) error
+}
+
+// GetPublishedReviews gets every published review, oldest first.
+func (rr *RecommendationRepository) GetPublishedReviews() ([]models.Review, error) {
+	var reviews []models.Review
+	err := rr.db.Select("id", "created_at", "resource_id", "user_id", "rating", "status").
+		Where("status = ?", models.Published).
+		Order("id asc").
+		Find(&reviews).Error
+	return reviews, err
+}
+
+// GetBookmarks gets every bookmark.
+func (rr *RecommendationRepository) GetBookmarks() ([]models.Bookmark, error) {
+	var bookmarks []models.Bookmark
+	err := rr.db.Select("id", "created_at", "user_id", "resource_id").Find(&bookmarks).Error
+	return bookmarks, err
+}
+
+// GetPublishedResources gets the ID, title and content of all published resources.
+func (rr *RecommendationRepository) GetPublishedResources() ([]models.Resource, error) {
+	var resources []models.Resource
+	err := rr.db.Select("id", "title", "content").Where("status = ?", models.Published).Order("id asc").Find(&resources).Error
+	return resources, err
+}
+
+// GetUserIDsWithStatus gets the IDs of all users whose recommendations have been computed or requested before.
+func (rr *RecommendationRepository) GetUserIDsWithStatus() ([]uint, error) {
+	var userIDs []uint
+	err := rr.db.Model(&models.RecommendationStatus{}).Pluck("user_id", &userIDs).Error
+	return userIDs, err
+}
+
+// SaveUserRecommendations replaces a user's precomputed similarities and recommendations.
+// The user stays stale if they were marked stale again after startedAt, i.e. while the recommendations were being computed.
+func (rr *RecommendationRepository) SaveUserRecommendations(userID uint, similarities []models.UserSimilarity, recommendations []models.UserRecommendation, startedAt time.Time) error {
+	return rr.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&models.UserSimilarity{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", userID).Delete(&models.UserRecommendation{}).Error; err != nil {
+			return err
+		}
+		if len(similarities) > 0 {
+			if err := tx.CreateInBatches(similarities, 500).Error; err != nil {
+				return err
+			}
+		}
+		if len(recommendations) > 0 {
+			if err := tx.CreateInBatches(recommendations, 500).Error; err != nil {
+				return err
+			}
+		}
+
+		now := time.Now()
+		status := models.RecommendationStatus{UserID: userID, ComputedAt: &now}
+		if err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"computed_at"}),
+		}).Create(&status).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&models.RecommendationStatus{}).
+			Where("user_id = ? AND (stale_since IS NULL OR stale_since < ?)", userID, startedAt).
+			Updates(map[string]interface{}{"stale": false, "stale_since": nil}).Error
+	})
+}
+
+// GetUserRecommendations gets a page of a user's precomputed recommendations, best first, along with the total number of recommendations.
+func (rr *RecommendationRepository) GetUserRecommendations(userID uint, offset, limit int) ([]models.UserRecommendation, int64, error) {
+	var total int64
+	if err := rr.db.Model(&models.UserRecommendation{}).Where("user_id = ?", userID).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var recommendations []models.UserRecommendation
+	err := rr.db.Where("user_id = ?", userID).Order("rank asc").Offset(offset).Limit(limit).Find(&recommendations).Error
+	return recommendations, total, err
+}
+
+// GetRecommendationStatus gets a user's recommendation status. Returns an empty status (nil ComputedAt) if recommendations were never computed for the user.
+func (rr *RecommendationRepository) GetRecommendationStatus(userID uint) (models.RecommendationStatus, error) {
+	var status models.RecommendationStatus
+	err := rr.db.Where("user_id = ?", userID).Limit(1).Find(&status).Error
+	return status, err
+}
+
+// MarkUsersStale flags users whose recommendations need to be recomputed.
+func (rr *RecommendationRepository) MarkUsersStale(userIDs []uint, at time.Time) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	statuses := make([]models.RecommendationStatus, 0, len(userIDs))
+	for _, userID := range userIDs {
+		statuses = append(statuses, models.RecommendationStatus{UserID: userID, Stale: true, StaleSince: &at})
+	}
+
+	return rr.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"stale", "stale_since"}),
+	}).Create(&statuses).Error
+}
+
+// GetStaleUserIDs gets up to limit users whose recommendations are stale, the longest waiting first.
+func (rr *RecommendationRepository) GetStaleUserIDs(limit int) ([]uint, error) {
+	var userIDs []uint
+	err := rr.db.Model(&models.RecommendationStatus{}).
+		Where("stale = ?", true).
+		Order("stale_since asc").
+		Limit(limit).
+		Pluck("user_id", &userIDs).Error
+	return userIDs, err
+}
+
+// GetUserIDsWhoReviewedResource gets the IDs of users with a published review, a bookmark or a dismissal of the resource.
+func (rr *RecommendationRepository) GetUserIDsWhoReviewedResource(resourceID uint) ([]uint, error) {
+	var reviewers []uint
+	if err := rr.db.Model(&models.Review{}).Where("resource_id = ? AND status = ?", resourceID, models.Published).Distinct().Pluck("user_id", &reviewers).Error; err != nil {
+		return nil, err
+	}
+
+	var bookmarkers []uint
+	if err := rr.db.Model(&models.Bookmark{}).Where("resource_id = ?", resourceID).Pluck("user_id", &bookmarkers).Error; err != nil {
+		return nil, err
+	}
+
+	var dismissers []uint
+	if err := rr.db.Model(&models.RecommendationDismissal{}).Where("resource_id = ?", resourceID).Pluck("user_id", &dismissers).Error; err != nil {
+		return nil, err
+	}
+
+	return append(append(reviewers, bookmarkers...), dismissers...), nil
+}
+
+// GetUserSimilarities gets a user's precomputed similarities with other users.
+func (rr *RecommendationRepository) GetUserSimilarities(userID uint) ([]models.UserSimilarity, error) {
+	var similarities []models.UserSimilarity
+	err := rr.db.Where("user_id = ?", userID).Find(&similarities).Error
+	return similarities, err
+}
+
+// GetMoodReviews gets the published reviews of the given resources by the given users which were written in a known mood.
+func (rr *RecommendationRepository) GetMoodReviews(resourceIDs, userIDs []uint) ([]models.Review, error) {
+	var reviews []models.Review
+	if len(resourceIDs) == 0 || len(userIDs) == 0 {
+		return reviews, nil
+	}
+
+	err := rr.db.Select("id", "resource_id", "user_id", "rating", "reviewer_mood").
+		Where("resource_id IN ? AND user_id IN ? AND status = ? AND reviewer_mood <> 0", resourceIDs, userIDs, models.Published).
+		Find(&reviews).Error
+	return reviews, err
+}
+
+// GetReviewsByUserIDs gets all the published reviews of published resources by the given users, oldest first.
+func (rr *RecommendationRepository) GetReviewsByUserIDs(userIDs []uint) ([]models.Review, error) {
+	var reviews []models.Review
+	if len(userIDs) == 0 {
+		return reviews, nil
+	}
+
+	err := rr.db.Select("reviews.id", "reviews.resource_id", "reviews.user_id", "reviews.rating").
+		Joins("JOIN resources ON resources.id = reviews.resource_id").
+		Where("reviews.user_id IN ? AND reviews.status = ? AND resources.status = ? AND resources.deleted_at IS NULL", userIDs, models.Published, models.Published).
+		Order("reviews.id asc").
+		Find(&reviews).Error
+	return reviews, err
+}
+
+// GetResourceIDsWithMoodTag gets which of the given resources are tagged for the mood.
+func (rr *RecommendationRepository) GetResourceIDsWithMoodTag(resourceIDs []uint, mood models.MoodType) ([]uint, error) {
+	var tagged []uint
+	if len(resourceIDs) == 0 {
+		return tagged, nil
+	}
+
+	err := rr.db.Model(&models.ResourceMoodTag{}).Where("resource_id IN ? AND mood = ?", resourceIDs, mood).Pluck("resource_id", &tagged).Error
+	return tagged, err
+}
+
+// GetDismissals gets every dismissed recommendation.
+func (rr *RecommendationRepository) GetDismissals() ([]models.RecommendationDismissal, error) {
+	var dismissals []models.RecommendationDismissal
+	err := rr.db.Select("id", "created_at", "user_id", "resource_id").Order("id asc").Find(&dismissals).Error
+	return dismissals, err
+}
+
+// GetDismissalsByUserID gets the recommendations a user has dismissed, most recent first.
+func (rr *RecommendationRepository) GetDismissalsByUserID(userID uint) ([]models.RecommendationDismissal, error) {
+	var dismissals []models.RecommendationDismissal
+	err := rr.db.Where("user_id = ?", userID).Order("created_at desc").Find(&dismissals).Error
+	return dismissals, err
+}
+
+// CreateDismissal records a dismissed recommendation. Dismissing the same resource again updates the reason.
+func (rr *RecommendationRepository) CreateDismissal(dismissal *models.RecommendationDismissal) error {
+	return rr.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "resource_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"reason", "updated_at"}),
+	}).Create(dismissal).Error
+}
+
+// DeleteDismissal undoes a user's dismissal of a resource.
+func (rr *RecommendationRepository) DeleteDismissal(userID, resourceID uint) error {
+	return rr.db.Where("user_id = ? AND resource_id = ?", userID, resourceID).Unscoped().Delete(&models.RecommendationDismissal{}).Error
+}
+
+// DeleteUserRecommendation removes a single precomputed recommendation, leaving the ranks of the others as they are.
+func (rr *RecommendationRepository) DeleteUserRecommendation(userID, resourceID uint) error {
+	return rr.db.Where("user_id = ? AND resource_id = ?", userID, resourceID).Delete(&models.UserRecommendation{}).Error
+}
+
+// GetCuratedResourceIDs gets the IDs of the curated resources, most recently curated first.
+func (rr *RecommendationRepository) GetCuratedResourceIDs() ([]uint, error) {
+	var resourceIDs []uint
+	err := rr.db.Model(&models.CuratedResource{}).Order("created_at desc").Order("id desc").Pluck("resource_id", &resourceIDs).Error
+	return resourceIDs, err
+}
+
+// CreateCuratedResource adds a resource to the curated resources, returning false if it was already curated.
+func (rr *RecommendationRepository) CreateCuratedResource(curated *models.CuratedResource) (bool, error) {
+	result := rr.db.Clauses(clause.OnConflict{DoNothing: true}).Create(curated)
+	return result.RowsAffected == 1, result.Error
+}
+
+// DeleteCuratedResource removes a resource from the curated resources.
+func (rr *RecommendationRepository) DeleteCuratedResource(resourceID uint) error {
+	return rr.db.Where("resource_id = ?", resourceID).Unscoped().Delete(&models.CuratedResource{}).Error
+}
+
+// SaveFactorizationModel stores a newly trained version of the matrix factorization model with its factors, and activates it.
+func (rr *RecommendationRepository) SaveFactorizationModel(model *models.FactorizationModel, userFactors []models.UserFactor, resourceFactors []models.ResourceFactor) error {
+	return rr.db.Transaction(func(tx *gorm.DB) error {
+		model.Active = false
+		if err := tx.Create(model).Error; err != nil {
+			return err
+		}
+
+		for i := range userFactors {
+			userFactors[i].ModelID = model.ID
+		}
+		if len(userFactors) > 0 {
+			if err := tx.CreateInBatches(userFactors, 500).Error; err != nil {
+				return err
+			}
+		}
+		for i := range resourceFactors {
+			resourceFactors[i].ModelID = model.ID
+		}
+		if len(resourceFactors) > 0 {
+			if err := tx.CreateInBatches(resourceFactors, 500).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := activateFactorizationModel(tx, model.ID); err != nil {
+			return err
+		}
+		model.Active = true
+		return nil
+	})
+}
+
+// GetFactorizationModels gets every stored version of the matrix factorization model, newest first.
+func (rr *RecommendationRepository) GetFactorizationModels() ([]models.FactorizationModel, error) {
+	var factorizationModels []models.FactorizationModel
+	err := rr.db.Order("id desc").Find(&factorizationModels).Error
+	return factorizationModels, err
+}
+
+// GetActiveFactorizationModel gets the active version of the matrix factorization model. Returns an empty model (ID 0) if none was trained yet.
+func (rr *RecommendationRepository) GetActiveFactorizationModel() (models.FactorizationModel, error) {
+	var model models.FactorizationModel
+	err := rr.db.Where("active = ?", true).Limit(1).Find(&model).Error
+	return model, err
+}
+
+// GetFactors gets the user and resource factors of a version of the matrix factorization model.
+func (rr *RecommendationRepository) GetFactors(modelID uint) ([]models.UserFactor, []models.ResourceFactor, error) {
+	var userFactors []models.UserFactor
+	if err := rr.db.Where("model_id = ?", modelID).Find(&userFactors).Error; err != nil {
+		return nil, nil, err
+	}
+
+	var resourceFactors []models.ResourceFactor
+	if err := rr.db.Where("model_id = ?", modelID).Find(&resourceFactors).Error; err != nil {
+		return nil, nil, err
+	}
+	return userFactors, resourceFactors, nil
+}
+
+// ActivateFactorizationModel makes a version of the matrix factorization model the active one, e.g. to roll back to an older version.
+func (rr *RecommendationRepository) ActivateFactorizationModel(modelID uint) error {
+	return rr.db.Transaction(func(tx *gorm.DB) error {
+		var model models.FactorizationModel
+		if err := tx.First(&model, modelID).Error; err != nil {
+			return err
+		}
+		return activateFactorizationModel(tx, modelID)
+	})
+}
+
+func activateFactorizationModel(tx *gorm.DB, modelID uint) error {
+	if err := tx.Model(&models.FactorizationModel{}).Where("active = ? AND id <> ?", true, modelID).Update("active", false).Error; err != nil {
+		return err
+	}
+	return tx.Model(&models.FactorizationModel{}).Where("id = ?", modelID).Update("active", true).Error
+}
+
+// PruneFactorizationModels deletes all but the newest keep versions of the matrix factorization model, along with their factors.
+// The active version is never deleted, even after rolling back to an older one.
+func (rr *RecommendationRepository) PruneFactorizationModels(keep int) error {
+	var modelIDs []uint
+	err := rr.db.Model(&models.FactorizationModel{}).
+		Where("active = ?", false).
+		Where("id NOT IN (?)", rr.db.Model(&models.FactorizationModel{}).Select("id").Order("id desc").Limit(keep)).
+		Pluck("id", &modelIDs).Error
+	if err != nil || len(modelIDs) == 0 {
+		return err
+	}
+
+	return rr.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("model_id IN ?", modelIDs).Delete(&models.UserFactor{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("model_id IN ?", modelIDs).Delete(&models.ResourceFactor{}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Where("id IN ?", modelIDs).Delete(&models.FactorizationModel{}).Error
+	})
+}
@@ -0,0 +1,246 @@
+package repository
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/anirudhgray/mood-harbour-backend/models"
+	"github.com/anirudhgray/mood-harbour-backend/utils/test_utils"
+)
+
+func TestRecommendationRepository_SaveUserRecommendations(t *testing.T) {
+	db, err := test_utils.SetupTestDB()
+	if err != nil {
+		t.Fatalf("Failed to set up test database: %v", err)
+	}
+	defer db.Migrator().DropTable(&models.UserSimilarity{}, &models.UserRecommendation{}, &models.RecommendationStatus{})
+
+	rr := NewRecommendationRepository()
+	rr.db = db
+
+	userID := uint(1)
+	now := time.Now()
+
+	if err := rr.MarkUsersStale([]uint{userID}, now.Add(-time.Minute)); err != nil {
+		t.Fatalf("MarkUsersStale returned an error: %v", err)
+	}
+
+	recommendations := []models.UserRecommendation{
+		{UserID: userID, ResourceID: 30, Probability: 0.9, Rank: 1},
+		{UserID: userID, ResourceID: 10, Probability: 0.5, Rank: 2, Evidence: models.RecommendationEvidence{Algorithm: "content", MatchingTerms: []string{"sleep", "rest"}}},
+		{UserID: userID, ResourceID: 20, Probability: 0.1, Rank: 3},
+	}
+	similarities := []models.UserSimilarity{{UserID: userID, OtherUserID: 2, Similarity: 0.5}}
+
+	if err := rr.SaveUserRecommendations(userID, similarities, recommendations, now); err != nil {
+		t.Fatalf("SaveUserRecommendations returned an error: %v", err)
+	}
+
+	status, err := rr.GetRecommendationStatus(userID)
+	if err != nil {
+		t.Fatalf("GetRecommendationStatus returned an error: %v", err)
+	}
+	if status.ComputedAt == nil || status.Stale {
+		t.Errorf("Expected fresh recommendations after saving, got: %+v", status)
+	}
+
+	page, total, err := rr.GetUserRecommendations(userID, 1, 2)
+	if err != nil {
+		t.Fatalf("GetUserRecommendations returned an error: %v", err)
+	}
+	if total != 3 || len(page) != 2 || page[0].ResourceID != 10 || page[1].ResourceID != 20 {
+		t.Fatalf("GetUserRecommendations returned the wrong page: %v (total %d)", page, total)
+	}
+	if !reflect.DeepEqual(page[0].Evidence, recommendations[1].Evidence) {
+		t.Errorf("Expected evidence %+v to be stored, got: %+v", recommendations[1].Evidence, page[0].Evidence)
+	}
+
+	// a user marked stale while their recommendations were being computed stays stale
+	if err := rr.MarkUsersStale([]uint{userID}, now.Add(time.Second)); err != nil {
+		t.Fatalf("MarkUsersStale returned an error: %v", err)
+	}
+	if err := rr.SaveUserRecommendations(userID, nil, recommendations[:1], now); err != nil {
+		t.Fatalf("SaveUserRecommendations returned an error: %v", err)
+	}
+
+	staleUserIDs, err := rr.GetStaleUserIDs(10)
+	if err != nil {
+		t.Fatalf("GetStaleUserIDs returned an error: %v", err)
+	}
+	if len(staleUserIDs) != 1 || staleUserIDs[0] != userID {
+		t.Errorf("Expected user %d to still be stale, got: %v", userID, staleUserIDs)
+	}
+
+	_, total, err = rr.GetUserRecommendations(userID, 0, 10)
+	if err != nil {
+		t.Fatalf("GetUserRecommendations returned an error: %v", err)
+	}
+	if total != 1 {
+		t.Errorf("Expected old recommendations to be replaced, got total: %d", total)
+	}
+}
+
+func TestRecommendationRepository_GetPublishedResources(t *testing.T) {
+	db, err := test_utils.SetupTestDB()
+	if err != nil {
+		t.Fatalf("Failed to set up test database: %v", err)
+	}
+	defer db.Migrator().DropTable(&models.Resource{})
+
+	rr := NewRecommendationRepository()
+	rr.db = db
+
+	resources := []models.Resource{
+		{Title: "Published", Content: "Some content", Status: models.Published},
+		{Title: "Hidden", Content: "Some content", Status: models.Hidden},
+	}
+	db.Create(&resources)
+
+	published, err := rr.GetPublishedResources()
+	if err != nil {
+		t.Fatalf("GetPublishedResources returned an error: %v", err)
+	}
+	if len(published) != 1 || published[0].ID != resources[0].ID || published[0].Title != "Published" || published[0].Content != "Some content" {
+		t.Errorf("GetPublishedResources returned the wrong resources: %+v", published)
+	}
+}
+
+func TestRecommendationRepository_Dismissals(t *testing.T) {
+	db, err := test_utils.SetupTestDB()
+	if err != nil {
+		t.Fatalf("Failed to set up test database: %v", err)
+	}
+	defer db.Migrator().DropTable(&models.RecommendationDismissal{})
+
+	rr := NewRecommendationRepository()
+	rr.db = db
+
+	userID, resourceID := uint(1), uint(10)
+
+	if err := rr.CreateDismissal(&models.RecommendationDismissal{UserID: userID, ResourceID: resourceID, Reason: models.NotInterested}); err != nil {
+		t.Fatalf("CreateDismissal returned an error: %v", err)
+	}
+	// dismissing again only changes the reason
+	if err := rr.CreateDismissal(&models.RecommendationDismissal{UserID: userID, ResourceID: resourceID, Reason: models.AlreadySeen}); err != nil {
+		t.Fatalf("CreateDismissal returned an error: %v", err)
+	}
+
+	dismissals, err := rr.GetDismissalsByUserID(userID)
+	if err != nil {
+		t.Fatalf("GetDismissalsByUserID returned an error: %v", err)
+	}
+	if len(dismissals) != 1 || dismissals[0].Reason != models.AlreadySeen {
+		t.Errorf("Expected a single dismissal that was already seen, got: %+v", dismissals)
+	}
+
+	userIDs, err := rr.GetUserIDsWhoReviewedResource(resourceID)
+	if err != nil {
+		t.Fatalf("GetUserIDsWhoReviewedResource returned an error: %v", err)
+	}
+	if len(userIDs) != 1 || userIDs[0] != userID {
+		t.Errorf("Expected the user who dismissed the resource, got: %v", userIDs)
+	}
+
+	if err := rr.DeleteDismissal(userID, resourceID); err != nil {
+		t.Fatalf("DeleteDismissal returned an error: %v", err)
+	}
+	dismissals, _ = rr.GetDismissals()
+	if len(dismissals) != 0 {
+		t.Errorf("Expected the dismissal to be undone, got: %+v", dismissals)
+	}
+}
+
+func TestRecommendationRepository_CuratedResources(t *testing.T) {
+	db, err := test_utils.SetupTestDB()
+	if err != nil {
+		t.Fatalf("Failed to set up test database: %v", err)
+	}
+	defer db.Migrator().DropTable(&models.CuratedResource{})
+
+	rr := NewRecommendationRepository()
+	rr.db = db
+
+	created, err := rr.CreateCuratedResource(&models.CuratedResource{ResourceID: 10, CuratedBy: 1})
+	if err != nil || !created {
+		t.Fatalf("Expected the resource to be curated, got %v, %v", created, err)
+	}
+	// curating it again is reported rather than adding a second entry
+	created, err = rr.CreateCuratedResource(&models.CuratedResource{ResourceID: 10, CuratedBy: 2})
+	if err != nil || created {
+		t.Errorf("Expected the resource to be already curated, got %v, %v", created, err)
+	}
+
+	resourceIDs, err := rr.GetCuratedResourceIDs()
+	if err != nil {
+		t.Fatalf("GetCuratedResourceIDs returned an error: %v", err)
+	}
+	if !reflect.DeepEqual(resourceIDs, []uint{10}) {
+		t.Errorf("Expected curated resources [10], got: %v", resourceIDs)
+	}
+}
+
+func TestRecommendationRepository_FactorizationModels(t *testing.T) {
+	db, err := test_utils.SetupTestDB()
+	if err != nil {
+		t.Fatalf("Failed to set up test database: %v", err)
+	}
+	defer db.Migrator().DropTable(&models.FactorizationModel{}, &models.UserFactor{}, &models.ResourceFactor{})
+
+	rr := NewRecommendationRepository()
+	rr.db = db
+
+	if active, err := rr.GetActiveFactorizationModel(); err != nil || active.ID != 0 {
+		t.Fatalf("Expected no active model before training, got: %+v, %v", active, err)
+	}
+
+	var modelIDs []uint
+	for i := 1; i <= 3; i++ {
+		model := models.FactorizationModel{Hyperparameters: models.FactorizationHyperparameters{Factors: 2, Epochs: i}, Users: 1, Resources: 1}
+		userFactors := []models.UserFactor{{UserID: 1, Bias: 0.1, Vector: []float64{float64(i), 0.5}}}
+		resourceFactors := []models.ResourceFactor{{ResourceID: 7, Bias: -0.2, Vector: []float64{0.25, float64(-i)}}}
+		if err := rr.SaveFactorizationModel(&model, userFactors, resourceFactors); err != nil {
+			t.Fatalf("SaveFactorizationModel returned an error: %v", err)
+		}
+		if !model.Active {
+			t.Errorf("Expected the saved model to be active")
+		}
+		modelIDs = append(modelIDs, model.ID)
+	}
+
+	active, _ := rr.GetActiveFactorizationModel()
+	if active.ID != modelIDs[2] || active.Hyperparameters.Epochs != 3 {
+		t.Errorf("Expected the newest model to be active, got: %+v", active)
+	}
+
+	userFactors, resourceFactors, err := rr.GetFactors(modelIDs[1])
+	if err != nil {
+		t.Fatalf("GetFactors returned an error: %v", err)
+	}
+	if len(userFactors) != 1 || !reflect.DeepEqual(userFactors[0].Vector, []float64{2, 0.5}) || len(resourceFactors) != 1 || resourceFactors[0].Bias != -0.2 {
+		t.Errorf("Unexpected factors: %+v, %+v", userFactors, resourceFactors)
+	}
+
+	// roll back to the first version
+	if err := rr.ActivateFactorizationModel(modelIDs[0]); err != nil {
+		t.Fatalf("ActivateFactorizationModel returned an error: %v", err)
+	}
+	if active, _ := rr.GetActiveFactorizationModel(); active.ID != modelIDs[0] {
+		t.Errorf("Expected the first model to be active after rolling back, got: %+v", active)
+	}
+	if err := rr.ActivateFactorizationModel(modelIDs[2] + 1); err == nil {
+		t.Errorf("Expected an error activating a model which doesn't exist")
+	}
+
+	// the newest and the active model are kept
+	if err := rr.PruneFactorizationModels(1); err != nil {
+		t.Fatalf("PruneFactorizationModels returned an error: %v", err)
+	}
+	factorizationModels, _ := rr.GetFactorizationModels()
+	if len(factorizationModels) != 2 || factorizationModels[0].ID != modelIDs[2] || factorizationModels[1].ID != modelIDs[0] {
+		t.Errorf("Expected models %d and %d to be kept, got: %+v", modelIDs[2], modelIDs[0], factorizationModels)
+	}
+	if userFactors, resourceFactors, _ := rr.GetFactors(modelIDs[1]); len(userFactors) != 0 || len(resourceFactors) != 0 {
+		t.Errorf("Expected the pruned model's factors to be deleted, got: %+v, %+v", userFactors, resourceFactors)
+	}
+}
@@ -1,9 +1,13 @@
 package repository
 
 import (
+	"time"
+
 	"github.com/anirudhgray/mood-harbour-backend/infra/database"
 	"github.com/anirudhgray/mood-harbour-backend/models"
+	"github.com/anirudhgray/mood-harbour-backend/utils/ranking"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type ResourceRepository struct {
@@ -18,17 +22,30 @@ func NewResourceRepository() *ResourceRepository {
 type ResourceRepositoryInterface interface {
 	CreateResource(resource *models.Resource) error
 	GetResourceByID(resourceID uint) (models.Resource, error)
+	GetResourcesByIDs(resourceIDs []uint) ([]models.Resource, error)
 	GetResources() ([]models.Resource, error)
 	GetResourcesByUserID(userID uint) ([]models.Resource, error)
 	GetAdminResources() ([]models.Resource, error)
 	DeleteResource(resourceID uint) error
 	UpdateResource(resource *models.Resource) (models.Resource, error)
 	AddReview(resourceID uint, review *models.Review) error
-	GetReviewsByResourceID(resourceID uint) ([]models.Review, error)
+	GetReviewPageByResourceID(resourceID uint, offset, limit int) ([]models.Review, int64, error)
 	DeleteReview(reviewID uint) error
 	UpdateReview(review *models.Review) error
 	GetReviewByID(reviewID uint) (models.Review, error)
 	GetReviewsByUserID(userID uint) ([]models.Review, error)
+	UpsertReviewVote(vote *models.ReviewVote) error
+	DeleteReviewVote(reviewID, userID uint) error
+	GetReviewsByResourceIDs(resourceIDs []uint) ([]models.Review, error)
+	GetReviewVoteCounts(reviewIDs []uint) (map[uint]models.ReviewVoteCount, error)
+	SetResourceStatus(resourceID uint, status models.ModerationStatus) error
+	SetReviewStatus(reviewID uint, status models.ModerationStatus) error
+	GetResourceByURL(url string) (models.Resource, error)
+	GetResourcesForLinkCheck(checkedBefore time.Time, limit int) ([]models.Resource, error)
+	UpdateLinkStatus(resourceID uint, status models.LinkStatus, failures int, checkedAt time.Time) error
+	GetResourceMoodTags(resourceID uint) ([]models.MoodType, error)
+	GetResourceMoodTagsByIDs(resourceIDs []uint) (map[uint][]models.MoodType, error)
+	SetResourceMoodTags(resourceID uint, moods []models.MoodType) error
 }
 
 // CreateResource creates a new resource in the database.
@@ -43,10 +60,20 @@ func (rr *ResourceRepository) GetResourceByID(resourceID uint) (models.Resource,
 	return resource, err
 }
 
-// GetResources gets all the resources in the database.
+// GetResourcesByIDs gets the published resources with the given IDs. Resources that don't exist or aren't published are left out.
+func (rr *ResourceRepository) GetResourcesByIDs(resourceIDs []uint) ([]models.Resource, error) {
+	var resources []models.Resource
+	if len(resourceIDs) == 0 {
+		return resources, nil
+	}
+	err := rr.db.Where("id IN ? AND status = ?", resourceIDs, models.Published).Find(&resources).Error
+	return resources, err
+}
+
+// GetResources gets all the published resources in the database.
 func (rr *ResourceRepository) GetResources() ([]models.Resource, error) {
 	var resources []models.Resource
-	err := rr.db.Find(&resources).Error
+	err := rr.db.Where("status = ?", models.Published).Find(&resources).Error
 	return resources, err
 }
 
@@ -57,10 +84,10 @@ func (rr *ResourceRepository) GetResourcesByUserID(userID uint) ([]models.Resour
 	return resources, err
 }
 
-// GetAdminResources gets all the resources for an admin.
+// GetAdminResources gets all the published resources for an admin.
 func (rr *ResourceRepository) GetAdminResources() ([]models.Resource, error) {
 	var resources []models.Resource
-	err := rr.db.Where("admin_post = ?", true).Find(&resources).Error
+	err := rr.db.Where("admin_post = ? AND status = ?", true, models.Published).Find(&resources).Error
 	return resources, err
 }
 
@@ -80,10 +107,30 @@ func (rr *ResourceRepository) AddReview(resourceID uint, review *models.Review)
 	return rr.db.Create(review).Error
 }
 
-// GetReviewsByResourceID gets all the reviews for a specific resource.
-func (rr *ResourceRepository) GetReviewsByResourceID(resourceID uint) ([]models.Review, error) {
+// GetReviewPageByResourceID gets a page of the published reviews for a specific resource, most helpful first, and how many
+// there are in all. Reviews with the same helpfulness score are ordered newest first.
+func (rr *ResourceRepository) GetReviewPageByResourceID(resourceID uint, offset, limit int) ([]models.Review, int64, error) {
+	published := func() *gorm.DB {
+		return rr.db.Model(&models.Review{}).Where("resource_id = ? AND status = ?", resourceID, models.Published)
+	}
+
+	var total int64
+	if err := published().Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var reviews []models.Review
+	err := published().Order("helpfulness_score DESC, created_at DESC").Offset(offset).Limit(limit).Find(&reviews).Error
+	return reviews, total, err
+}
+
+// GetReviewsByResourceIDs gets all the published reviews for the given resources.
+func (rr *ResourceRepository) GetReviewsByResourceIDs(resourceIDs []uint) ([]models.Review, error) {
 	var reviews []models.Review
-	err := rr.db.Where("resource_id = ?", resourceID).Find(&reviews).Error
+	if len(resourceIDs) == 0 {
+		return reviews, nil
+	}
+	err := rr.db.Where("resource_id IN ? AND status = ?", resourceIDs, models.Published).Find(&reviews).Error
 	return reviews, err
 }
 
@@ -94,7 +141,8 @@ func (rr *ResourceRepository) DeleteReview(reviewID uint) error {
 
 // UpdateReview updates a review in the database.
 func (rr *ResourceRepository) UpdateReview(review *models.Review) error {
-	return rr.db.Save(review).Error
+	// the helpfulness score is only changed along with the votes, so a stale copy of the review can't overwrite it
+	return rr.db.Omit("helpfulness_score").Save(review).Error
 }
 
 // GetReviewByID gets a review by its ID.
@@ -110,3 +158,159 @@ func (rr *ResourceRepository) GetReviewsByUserID(userID uint) ([]models.Review,
 	err := rr.db.Where("user_id = ?", userID).Find(&reviews).Error
 	return reviews, err
 }
+
+// UpsertReviewVote records a user's helpfulness vote on a review, replacing any vote they had already cast on it.
+func (rr *ResourceRepository) UpsertReviewVote(vote *models.ReviewVote) error {
+	return rr.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockReview(tx, vote.ReviewID); err != nil {
+			return err
+		}
+		err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "review_id"}, {Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"helpful", "updated_at"}),
+		}).Create(vote).Error
+		if err != nil {
+			return err
+		}
+		return UpdateHelpfulnessScore(tx, vote.ReviewID)
+	})
+}
+
+// DeleteReviewVote removes a user's helpfulness vote on a review.
+func (rr *ResourceRepository) DeleteReviewVote(reviewID, userID uint) error {
+	return rr.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockReview(tx, reviewID); err != nil {
+			return err
+		}
+		if err := tx.Where("review_id = ? AND user_id = ?", reviewID, userID).Unscoped().Delete(&models.ReviewVote{}).Error; err != nil {
+			return err
+		}
+		return UpdateHelpfulnessScore(tx, reviewID)
+	})
+}
+
+// lockReview locks a review's row until the transaction ends, by updating it without changing it. Votes on the review
+// are changed one at a time, so each helpfulness score is computed from every vote committed before it.
+func lockReview(tx *gorm.DB, reviewID uint) error {
+	return tx.Model(&models.Review{}).Where("id = ?", reviewID).UpdateColumn("helpfulness_score", gorm.Expr("helpfulness_score")).Error
+}
+
+// UpdateHelpfulnessScore recomputes the Wilson lower bound of a review's helpfulness votes and stores it on the review.
+func UpdateHelpfulnessScore(db *gorm.DB, reviewID uint) error {
+	var counts models.ReviewVoteCount
+	err := db.Model(&models.ReviewVote{}).
+		Select("COALESCE(SUM(CASE WHEN helpful THEN 1 ELSE 0 END), 0) AS helpful, COALESCE(SUM(CASE WHEN helpful THEN 0 ELSE 1 END), 0) AS unhelpful").
+		Where("review_id = ?", reviewID).
+		Scan(&counts).Error
+	if err != nil {
+		return err
+	}
+
+	score := ranking.WilsonLowerBound(counts.Helpful, counts.Helpful+counts.Unhelpful)
+	return db.Model(&models.Review{}).Where("id = ?", reviewID).UpdateColumn("helpfulness_score", score).Error
+}
+
+// GetReviewVoteCounts gets the helpful and unhelpful vote counts for the given reviews, keyed by review ID.
+// Reviews without any votes are not present in the returned map.
+func (rr *ResourceRepository) GetReviewVoteCounts(reviewIDs []uint) (map[uint]models.ReviewVoteCount, error) {
+	counts := make(map[uint]models.ReviewVoteCount)
+	if len(reviewIDs) == 0 {
+		return counts, nil
+	}
+
+	var rows []models.ReviewVoteCount
+	err := rr.db.Model(&models.ReviewVote{}).
+		Select("review_id, SUM(CASE WHEN helpful THEN 1 ELSE 0 END) AS helpful, SUM(CASE WHEN helpful THEN 0 ELSE 1 END) AS unhelpful").
+		Where("review_id IN ?", reviewIDs).
+		Group("review_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		counts[row.ReviewID] = row
+	}
+	return counts, nil
+}
+
+// SetResourceStatus updates the moderation status of a resource.
+func (rr *ResourceRepository) SetResourceStatus(resourceID uint, status models.ModerationStatus) error {
+	return rr.db.Model(&models.Resource{}).Where("id = ?", resourceID).Update("status", status).Error
+}
+
+// SetReviewStatus updates the moderation status of a review.
+func (rr *ResourceRepository) SetReviewStatus(reviewID uint, status models.ModerationStatus) error {
+	return rr.db.Model(&models.Review{}).Where("id = ?", reviewID).Update("status", status).Error
+}
+
+// GetResourceByURL gets a resource by its normalized URL, whatever its moderation status.
+// Returns an empty resource (ID 0) if no resource has that URL.
+func (rr *ResourceRepository) GetResourceByURL(url string) (models.Resource, error) {
+	var resource models.Resource
+	err := rr.db.Where("url = ?", url).Limit(1).Find(&resource).Error
+	return resource, err
+}
+
+// GetResourcesForLinkCheck gets up to limit external resources whose link hasn't been checked since checkedBefore, least recently checked first.
+func (rr *ResourceRepository) GetResourcesForLinkCheck(checkedBefore time.Time, limit int) ([]models.Resource, error) {
+	var resources []models.Resource
+	err := rr.db.Where("external = ? AND url <> ? AND (link_checked_at IS NULL OR link_checked_at < ?)", true, "", checkedBefore).
+		Order("link_checked_at IS NOT NULL, link_checked_at ASC").
+		Limit(limit).
+		Find(&resources).Error
+	return resources, err
+}
+
+// UpdateLinkStatus records the result of a link check.
+func (rr *ResourceRepository) UpdateLinkStatus(resourceID uint, status models.LinkStatus, failures int, checkedAt time.Time) error {
+	return rr.db.Model(&models.Resource{}).Where("id = ?", resourceID).Updates(map[string]interface{}{
+		"link_status":     status,
+		"link_failures":   failures,
+		"link_checked_at": checkedAt,
+	}).Error
+}
+
+// GetResourceMoodTags gets the moods a resource is tagged for, in ascending order.
+func (rr *ResourceRepository) GetResourceMoodTags(resourceID uint) ([]models.MoodType, error) {
+	moods := []models.MoodType{}
+	err := rr.db.Model(&models.ResourceMoodTag{}).Where("resource_id = ?", resourceID).Order("mood asc").Pluck("mood", &moods).Error
+	return moods, err
+}
+
+// GetResourceMoodTagsByIDs gets the moods the given resources are tagged for, in ascending order, keyed by resource ID.
+// Resources without any tags are not present in the returned map.
+func (rr *ResourceRepository) GetResourceMoodTagsByIDs(resourceIDs []uint) (map[uint][]models.MoodType, error) {
+	moods := make(map[uint][]models.MoodType)
+	if len(resourceIDs) == 0 {
+		return moods, nil
+	}
+
+	var tags []models.ResourceMoodTag
+	if err := rr.db.Where("resource_id IN ?", resourceIDs).Order("mood asc").Find(&tags).Error; err != nil {
+		return nil, err
+	}
+
+	for _, tag := range tags {
+		moods[tag.ResourceID] = append(moods[tag.ResourceID], tag.Mood)
+	}
+	return moods, nil
+}
+
+// SetResourceMoodTags replaces the moods a resource is tagged for.
+func (rr *ResourceRepository) SetResourceMoodTags(resourceID uint, moods []models.MoodType) error {
+	return rr.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("resource_id = ?", resourceID).Delete(&models.ResourceMoodTag{}).Error; err != nil {
+			return err
+		}
+		if len(moods) == 0 {
+			return nil
+		}
+
+		tags := make([]models.ResourceMoodTag, 0, len(moods))
+		for _, mood := range moods {
+			tags = append(tags, models.ResourceMoodTag{ResourceID: resourceID, Mood: mood})
+		}
+		return tx.Create(&tags).Error
+	})
+}
@@ -0,0 +1,231 @@
+package repository
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/anirudhgray/mood-harbour-backend/models"
+	"github.com/anirudhgray/mood-harbour-backend/utils/test_utils"
+	"gorm.io/gorm"
+)
+
+func TestResourceRepository_ReviewVotes(t *testing.T) {
+	db, err := test_utils.SetupTestDB()
+	if err != nil {
+		t.Fatalf("Failed to set up test database: %v", err)
+	}
+	defer db.Migrator().DropTable(&models.ReviewVote{})
+
+	// Create the ResourceRepository with the test database
+	rr := NewResourceRepository()
+	rr.db = db
+
+	votes := []models.ReviewVote{
+		{ReviewID: 1, UserID: 1, Helpful: true},
+		{ReviewID: 1, UserID: 2, Helpful: true},
+		{ReviewID: 1, UserID: 3, Helpful: false},
+		{ReviewID: 2, UserID: 1, Helpful: false},
+	}
+
+	for _, vote := range votes {
+		if err := rr.UpsertReviewVote(&vote); err != nil {
+			t.Fatalf("Failed to create test review vote: %v", err)
+		}
+	}
+
+	// Voting again on the same review replaces the earlier vote instead of adding another one
+	if err := rr.UpsertReviewVote(&models.ReviewVote{ReviewID: 2, UserID: 1, Helpful: true}); err != nil {
+		t.Errorf("UpsertReviewVote returned an error: %v", err)
+	}
+
+	if err := rr.DeleteReviewVote(1, 3); err != nil {
+		t.Errorf("DeleteReviewVote returned an error: %v", err)
+	}
+
+	counts, err := rr.GetReviewVoteCounts([]uint{1, 2, 3})
+	if err != nil {
+		t.Fatalf("GetReviewVoteCounts returned an error: %v", err)
+	}
+
+	testCases := []struct {
+		reviewID          uint
+		expectedHelpful   int
+		expectedUnhelpful int
+	}{
+		{1, 2, 0},
+		{2, 1, 0},
+		{3, 0, 0},
+	}
+
+	for _, tc := range testCases {
+		if counts[tc.reviewID].Helpful != tc.expectedHelpful || counts[tc.reviewID].Unhelpful != tc.expectedUnhelpful {
+			t.Errorf("Review %d: expected %d/%d helpful/unhelpful, got: %d/%d", tc.reviewID, tc.expectedHelpful, tc.expectedUnhelpful, counts[tc.reviewID].Helpful, counts[tc.reviewID].Unhelpful)
+		}
+	}
+}
+
+func TestResourceRepository_GetReviewPageByResourceID(t *testing.T) {
+	db, err := test_utils.SetupTestDB()
+	if err != nil {
+		t.Fatalf("Failed to set up test database: %v", err)
+	}
+	defer db.Migrator().DropTable(&models.Review{}, &models.ReviewVote{})
+
+	rr := NewResourceRepository()
+	rr.db = db
+
+	now := time.Now()
+	reviews := []models.Review{
+		{Model: gorm.Model{CreatedAt: now.Add(-4 * time.Hour)}, ResourceID: 101, UserID: 1, Content: "One helpful vote", Rating: 4},
+		{Model: gorm.Model{CreatedAt: now.Add(-3 * time.Hour)}, ResourceID: 101, UserID: 2, Content: "Mostly helpful", Rating: 4},
+		{Model: gorm.Model{CreatedAt: now.Add(-2 * time.Hour)}, ResourceID: 101, UserID: 3, Content: "Mostly unhelpful", Rating: 4},
+		{Model: gorm.Model{CreatedAt: now.Add(-1 * time.Hour)}, ResourceID: 101, UserID: 4, Content: "No votes", Rating: 4},
+		{Model: gorm.Model{CreatedAt: now}, ResourceID: 101, UserID: 5, Content: "Pending", Rating: 4, Status: models.PendingModeration},
+		{Model: gorm.Model{CreatedAt: now}, ResourceID: 102, UserID: 1, Content: "Another resource", Rating: 4},
+	}
+	for i := range reviews {
+		if err := rr.AddReview(reviews[i].ResourceID, &reviews[i]); err != nil {
+			t.Fatalf("Failed to create test review: %v", err)
+		}
+	}
+
+	vote := func(review models.Review, helpful, unhelpful int) {
+		for userID := 1; userID <= helpful+unhelpful; userID++ {
+			if err := rr.UpsertReviewVote(&models.ReviewVote{ReviewID: review.ID, UserID: uint(userID), Helpful: userID <= helpful}); err != nil {
+				t.Fatalf("Failed to create test review vote: %v", err)
+			}
+		}
+	}
+	vote(reviews[0], 1, 0)
+	vote(reviews[1], 9, 1)
+	vote(reviews[2], 2, 8)
+	vote(reviews[4], 5, 0)
+
+	page, total, err := rr.GetReviewPageByResourceID(101, 0, 3)
+	if err != nil {
+		t.Fatalf("GetReviewPageByResourceID returned an error: %v", err)
+	}
+	// the review without votes scores 0, below even a mostly unhelpful one, and pending reviews aren't counted
+	expectedOrder := []uint{reviews[1].ID, reviews[0].ID, reviews[2].ID}
+	if total != 4 || len(page) != len(expectedOrder) {
+		t.Fatalf("Expected %d of 4 reviews, got %d of %d", len(expectedOrder), len(page), total)
+	}
+	for i, id := range expectedOrder {
+		if page[i].ID != id {
+			t.Errorf("Expected review %d at position %d, got: %d", id, i, page[i].ID)
+		}
+	}
+
+	secondPage, _, err := rr.GetReviewPageByResourceID(101, 3, 3)
+	if err != nil {
+		t.Fatalf("GetReviewPageByResourceID returned an error: %v", err)
+	}
+	if len(secondPage) != 1 || secondPage[0].ID != reviews[3].ID {
+		t.Errorf("Expected the second page to contain only review %d, got: %v", reviews[3].ID, secondPage)
+	}
+
+	// removing the only vote drops the review back to the same score as one without votes, where newer reviews come first
+	if err := rr.DeleteReviewVote(reviews[0].ID, 1); err != nil {
+		t.Fatalf("DeleteReviewVote returned an error: %v", err)
+	}
+	page, _, err = rr.GetReviewPageByResourceID(101, 0, 4)
+	if err != nil {
+		t.Fatalf("GetReviewPageByResourceID returned an error: %v", err)
+	}
+	if page[2].ID != reviews[3].ID || page[3].ID != reviews[0].ID {
+		t.Errorf("Expected reviews %d and %d last, got: %d and %d", reviews[3].ID, reviews[0].ID, page[2].ID, page[3].ID)
+	}
+}
+
+func TestResourceRepository_GetResourcesForLinkCheck(t *testing.T) {
+	db, err := test_utils.SetupTestDB()
+	if err != nil {
+		t.Fatalf("Failed to set up test database: %v", err)
+	}
+	defer db.Migrator().DropTable(&models.Resource{})
+
+	rr := NewResourceRepository()
+	rr.db = db
+
+	now := time.Now()
+	recent := now.Add(-time.Hour)
+	old := now.Add(-48 * time.Hour)
+
+	resources := []models.Resource{
+		{Title: "Recently checked", URL: "https://example.com/1", External: true, LinkCheckedAt: &recent},
+		{Title: "Checked long ago", URL: "https://example.com/2", External: true, LinkCheckedAt: &old},
+		{Title: "Never checked", URL: "https://example.com/3", External: true},
+		{Title: "Internal", URL: "", External: false},
+	}
+	for i := range resources {
+		if err := rr.CreateResource(&resources[i]); err != nil {
+			t.Fatalf("Failed to create test resource: %v", err)
+		}
+	}
+
+	toCheck, err := rr.GetResourcesForLinkCheck(now.Add(-24*time.Hour), 10)
+	if err != nil {
+		t.Fatalf("GetResourcesForLinkCheck returned an error: %v", err)
+	}
+
+	// never checked links come first, recently checked and internal resources are skipped
+	if len(toCheck) != 2 || toCheck[0].ID != resources[2].ID || toCheck[1].ID != resources[1].ID {
+		t.Errorf("GetResourcesForLinkCheck returned unexpected resources: %v", toCheck)
+	}
+
+	if err := rr.UpdateLinkStatus(resources[1].ID, models.LinkDead, 3, now); err != nil {
+		t.Errorf("UpdateLinkStatus returned an error: %v", err)
+	}
+
+	found, err := rr.GetResourceByURL("https://example.com/2")
+	if err != nil {
+		t.Fatalf("GetResourceByURL returned an error: %v", err)
+	}
+	if found.ID != resources[1].ID || found.LinkStatus != models.LinkDead || found.LinkFailures != 3 {
+		t.Errorf("UpdateLinkStatus did not update the resource: %+v", found)
+	}
+
+	missing, err := rr.GetResourceByURL("https://example.com/missing")
+	if err != nil || missing.ID != 0 {
+		t.Errorf("Expected an empty resource for an unknown URL, got: %+v, %v", missing, err)
+	}
+}
+
+func TestResourceRepository_SetResourceMoodTags(t *testing.T) {
+	db, err := test_utils.SetupTestDB()
+	if err != nil {
+		t.Fatalf("Failed to set up test database: %v", err)
+	}
+	defer db.Migrator().DropTable(&models.ResourceMoodTag{})
+
+	rr := NewResourceRepository()
+	rr.db = db
+
+	if err := rr.SetResourceMoodTags(1, []models.MoodType{models.Sad, models.Angry}); err != nil {
+		t.Fatalf("SetResourceMoodTags returned an error: %v", err)
+	}
+	// replacing the tags must not trip over the unique index
+	if err := rr.SetResourceMoodTags(1, []models.MoodType{models.Happy, models.Sad}); err != nil {
+		t.Fatalf("SetResourceMoodTags returned an error: %v", err)
+	}
+
+	moods, err := rr.GetResourceMoodTags(1)
+	if err != nil {
+		t.Fatalf("GetResourceMoodTags returned an error: %v", err)
+	}
+	if len(moods) != 2 || moods[0] != models.Sad || moods[1] != models.Happy {
+		t.Errorf("Expected moods [Sad Happy], got: %v", moods)
+	}
+
+	if err := rr.SetResourceMoodTags(2, []models.MoodType{models.Angry}); err != nil {
+		t.Fatalf("SetResourceMoodTags returned an error: %v", err)
+	}
+	byResource, err := rr.GetResourceMoodTagsByIDs([]uint{1, 2, 3})
+	if err != nil {
+		t.Fatalf("GetResourceMoodTagsByIDs returned an error: %v", err)
+	}
+	if !reflect.DeepEqual(byResource, map[uint][]models.MoodType{1: {models.Sad, models.Happy}, 2: {models.Angry}}) {
+		t.Errorf("Unexpected moods by resource: %v", byResource)
+	}
+}
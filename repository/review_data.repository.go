@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/anirudhgray/mood-harbour-backend/infra/database"
+	"github.com/anirudhgray/mood-harbour-backend/models"
+	"github.com/anirudhgray/mood-harbour-backend/utils/preference"
+	"gorm.io/gorm"
+)
+
+// ReviewDataRepository reads users' preferences straight from the database, weighing published reviews, bookmarks and
+// dismissed recommendations with its preference model. Only published resources are taken into account.
+type ReviewDataRepository struct {
+	db    *gorm.DB
+	model preference.Model
+}
+
+func NewReviewDataRepository(model preference.Model) *ReviewDataRepository {
+	return &ReviewDataRepository{database.DB, model}
+}
+
+// ReviewDataRepositoryInterface is the data recommenders learn from. It is implemented by ReviewDataRepository
+// and by the in-memory recommender.Snapshot used by the background recommendation jobs.
+//
+// Preferences are weights between -1 (strong dislike) and 1 (strong like), see preference.Model. Every resource a user has
+// interacted with has a weight, even if it's 0, so recommenders know not to recommend it again.
+type ReviewDataRepositoryInterface interface {
+	GetUserPreferences(userID uint) (map[uint]float64, error)         // resource -> weight
+	GetResourcePreferences(resourceID uint) (map[uint]float64, error) // user -> weight
+}
+
+// GetUserPreferences gets the weight of every resource the user has interacted with.
+func (rr *ReviewDataRepository) GetUserPreferences(userID uint) (map[uint]float64, error) {
+	var reviews []models.Review
+	err := rr.db.
+		Joins("JOIN resources ON resources.id = reviews.resource_id").
+		Where("reviews.user_id = ? AND reviews.status = ? AND resources.status = ? AND resources.deleted_at IS NULL", userID, models.Published, models.Published).
+		Order("reviews.id asc").
+		Find(&reviews).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var bookmarks []models.Bookmark
+	err = rr.db.
+		Joins("JOIN resources ON resources.id = bookmarks.resource_id").
+		Where("bookmarks.user_id = ? AND resources.status = ? AND resources.deleted_at IS NULL", userID, models.Published).
+		Find(&bookmarks).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var dismissals []models.RecommendationDismissal
+	err = rr.db.
+		Joins("JOIN resources ON resources.id = recommendation_dismissals.resource_id").
+		Where("recommendation_dismissals.user_id = ? AND resources.status = ? AND resources.deleted_at IS NULL", userID, models.Published).
+		Order("recommendation_dismissals.id asc").
+		Find(&dismissals).Error
+	if err != nil {
+		return nil, err
+	}
+
+	weights := rr.model.Weights(reviews, bookmarks, dismissals, rr.model.UserMeans(reviews), time.Now())
+
+	preferences := make(map[uint]float64, len(weights))
+	for pair, weight := range weights {
+		preferences[pair.ResourceID] = weight
+	}
+	return preferences, nil
+}
+
+// GetResourcePreferences gets the weight of the resource for every user who has interacted with it.
+func (rr *ReviewDataRepository) GetResourcePreferences(resourceID uint) (map[uint]float64, error) {
+	var resource models.Resource
+	if err := rr.db.Where("id = ? AND status = ?", resourceID, models.Published).Limit(1).Find(&resource).Error; err != nil {
+		return nil, err
+	}
+	if resource.ID == 0 {
+		return map[uint]float64{}, nil
+	}
+
+	var reviews []models.Review
+	if err := rr.db.Where("resource_id = ? AND status = ?", resourceID, models.Published).Order("id asc").Find(&reviews).Error; err != nil {
+		return nil, err
+	}
+
+	var bookmarks []models.Bookmark
+	if err := rr.db.Where("resource_id = ?", resourceID).Find(&bookmarks).Error; err != nil {
+		return nil, err
+	}
+
+	var dismissals []models.RecommendationDismissal
+	if err := rr.db.Where("resource_id = ?", resourceID).Order("id asc").Find(&dismissals).Error; err != nil {
+		return nil, err
+	}
+
+	// normalization needs each reviewer's mean over all their reviews
+	userMeans := map[uint]float64{}
+	if rr.model.Normalize && len(reviews) > 0 {
+		reviewerIDs := make([]uint, 0, len(reviews))
+		for _, review := range reviews {
+			reviewerIDs = append(reviewerIDs, review.UserID)
+		}
+
+		var reviewerReviews []models.Review
+		err := rr.db.
+			Joins("JOIN resources ON resources.id = reviews.resource_id").
+			Where("reviews.user_id IN ? AND reviews.status = ? AND resources.status = ? AND resources.deleted_at IS NULL", reviewerIDs, models.Published, models.Published).
+			Order("reviews.id asc").
+			Find(&reviewerReviews).Error
+		if err != nil {
+			return nil, err
+		}
+		userMeans = rr.model.UserMeans(reviewerReviews)
+	}
+
+	weights := rr.model.Weights(reviews, bookmarks, dismissals, userMeans, time.Now())
+
+	preferences := make(map[uint]float64, len(weights))
+	for pair, weight := range weights {
+		preferences[pair.UserID] = weight
+	}
+	return preferences, nil
+}
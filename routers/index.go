@@ -2,12 +2,20 @@ package routers
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/anirudhgray/mood-harbour-backend/controllers"
+	"github.com/anirudhgray/mood-harbour-backend/infra/logger"
+	"github.com/anirudhgray/mood-harbour-backend/models"
 	"github.com/anirudhgray/mood-harbour-backend/repository"
 	"github.com/anirudhgray/mood-harbour-backend/routers/middleware"
 	"github.com/anirudhgray/mood-harbour-backend/services"
+	"github.com/anirudhgray/mood-harbour-backend/utils/linkpreview"
+	"github.com/anirudhgray/mood-harbour-backend/utils/moderation"
+	"github.com/anirudhgray/mood-harbour-backend/utils/password"
+	"github.com/anirudhgray/mood-harbour-backend/utils/webauthn"
 	"github.com/gin-gonic/gin"
+	"github.com/spf13/viper"
 )
 
 // RegisterRoutes add all routing list here automatically get main router
@@ -18,34 +26,58 @@ func RegisterRoutes(route *gin.Engine) {
 	route.GET("/health", func(ctx *gin.Context) { ctx.JSON(http.StatusOK, gin.H{"live": "ok"}) })
 
 	authProviderRepo := repository.NewAuthProviderRepository()
-	forgotPasswordRepo := repository.NewForgotPasswordRepository()
-	deletionConfirmationRepo := repository.NewDeletionConfirmationRepository()
-	verificationRepo := repository.NewVerificationEntryRepository()
+	oneTimeTokenRepo := repository.NewOneTimeTokenRepository()
 	passwordAuthRepo := repository.NewPasswordAuthRepository()
 	userRepo := repository.NewUserRepository()
 	moodRepo := repository.NewMoodRepository()
 	resourceRepo := repository.NewResourceRepository()
+	moderationRepo := repository.NewModerationRepository()
+	collectionRepo := repository.NewCollectionRepository()
+	recommendationRepo := repository.NewRecommendationRepository()
+	sessionRepo := repository.NewSessionRepository()
+	refreshTokenRepo := repository.NewRefreshTokenRepository()
+	mfaRepo := repository.NewMFARepository()
+	passkeyRepo := repository.NewPasskeyRepository()
+	throttleRepo := repository.NewThrottleRepository()
 
 	emailService := services.NewEmailService(userRepo)
 	moodService := services.NewMoodService(moodRepo)
-	resourceService := services.NewResourceService(resourceRepo, userRepo)
+	moderationService := services.NewModerationService(moderationRepo, resourceRepo, moderation.NewFilterFromConfig())
+	recommendationService := services.NewRecommendationService(recommendationRepo, resourceRepo, moodRepo, viper.GetInt("RECOMMENDATION_MAX_PER_USER"), viper.GetString("RECOMMENDER_ALGORITHM"))
+	resourceService := services.NewResourceService(resourceRepo, userRepo, moderationService, linkpreview.NewFetcherFromConfig(), recommendationService)
+	collectionService := services.NewCollectionService(collectionRepo, resourceRepo, recommendationService)
+	accountThrottle, ipThrottle := services.ThrottlePoliciesFromConfig()
+	passwordPolicy, err := password.NewPolicyFromConfig()
+	if err != nil {
+		logger.Fatalf("Invalid password policy: %v", err)
+	}
+	throttleService := services.NewThrottleService(throttleRepo, userRepo, emailService, accountThrottle, ipThrottle)
 
 	authService := services.NewAuthService(
 		authProviderRepo,
-		verificationRepo,
-		forgotPasswordRepo,
-		deletionConfirmationRepo,
+		oneTimeTokenRepo,
 		passwordAuthRepo,
 		userRepo,
 		emailService,
 		moodRepo,
+		sessionRepo,
+		refreshTokenRepo,
+		mfaRepo,
+		passkeyRepo,
+		webauthn.NewRelyingPartyFromConfig(),
+		throttleService,
+		passwordPolicy,
 	)
 
+	userAdminService := services.NewUserAdminService(userRepo, sessionRepo, oneTimeTokenRepo, authService)
+
 	v1 := route.Group("/v1")
 
 	auth := v1.Group("/auth") // Create an /auth/ group
 	{
 		userController := controllers.NewUserController(authService) // Create an instance of the UserController
+		// shared by the routes which check a second factor, on top of the account's throttle
+		mfaRateLimit := middleware.RateLimit(viper.GetInt("MFA_RATE_LIMIT"), time.Minute)
 
 		// Define the user registration route
 		auth.POST("/register", userController.RegisterUser)
@@ -53,9 +85,61 @@ func RegisterRoutes(route *gin.Engine) {
 		// Define the user login route
 		auth.POST("/login", userController.Login)
 
+		// Complete a login of a user with two-factor authentication with a TOTP code or recovery code
+		auth.POST("/login/mfa", userController.MFALogin)
+
+		// Exchange a refresh token for new tokens
+		auth.POST("/refresh", userController.Refresh)
+
+		// Log out of the current session
+		auth.POST("/logout", middleware.BaseAuthMiddleware(), userController.Logout)
+
+		// List the devices the user is logged in on
+		auth.GET("/sessions", middleware.BaseAuthMiddleware(), userController.GetSessions)
+
+		// Log out of every device
+		auth.DELETE("/sessions", middleware.BaseAuthMiddleware(), userController.RevokeAllSessions)
+
+		// Log out of one device
+		auth.DELETE("/sessions/:id", middleware.BaseAuthMiddleware(), userController.RevokeSession)
+
 		// Verify user account by providing otp
 		auth.POST("/verify", userController.VerifyEmail)
 
+		// Start logging in with a passkey, getting the options for the authenticator
+		// Each one stores a challenge, so they're limited per IP
+		auth.POST("/passkeys/login/begin", middleware.RateLimit(viper.GetInt("PASSKEY_LOGIN_RATE_LIMIT"), time.Minute), userController.BeginPasskeyLogin)
+
+		// Log in with the authenticator's response
+		auth.POST("/passkeys/login/finish", userController.FinishPasskeyLogin)
+
+		// List the user's passkeys
+		auth.GET("/passkeys", middleware.BaseAuthMiddleware(), userController.GetPasskeys)
+
+		// Start registering a passkey, getting the options for the authenticator
+		auth.POST("/passkeys/register/begin", middleware.BaseAuthMiddleware(), userController.BeginPasskeyRegistration)
+
+		// Register a passkey with the authenticator's response
+		auth.POST("/passkeys/register/finish", middleware.BaseAuthMiddleware(), userController.FinishPasskeyRegistration)
+
+		// Remove a passkey
+		auth.DELETE("/passkeys/:id", middleware.BaseAuthMiddleware(), userController.DeletePasskey)
+
+		// Whether two-factor authentication is enabled
+		auth.GET("/mfa", middleware.BaseAuthMiddleware(), userController.GetMFAStatus)
+
+		// Start enabling two-factor authentication, getting an otpauth URI for an authenticator app
+		auth.POST("/mfa/totp", middleware.BaseAuthMiddleware(), userController.EnrollTOTP)
+
+		// Enable two-factor authentication with a first code, getting recovery codes
+		auth.POST("/mfa/totp/confirm", middleware.BaseAuthMiddleware(), userController.ConfirmTOTP)
+
+		// Disable two-factor authentication with a code or recovery code
+		auth.POST("/mfa/totp/disable", mfaRateLimit, middleware.BaseAuthMiddleware(), userController.DisableTOTP)
+
+		// Replace the recovery codes, with a code or recovery code
+		auth.POST("/mfa/recovery-codes", mfaRateLimit, middleware.BaseAuthMiddleware(), userController.RegenerateRecoveryCodes)
+
 		// Request another verification email
 		auth.GET("/request-verification", userController.RequestVerificationAgain)
 
@@ -82,6 +166,9 @@ func RegisterRoutes(route *gin.Engine) {
 
 		// Google Callback
 		auth.GET("/google/callback", userController.GoogleCallback)
+
+		// Exchange the code from the social login redirect for a login
+		auth.POST("/social/exchange", userController.ExchangeSocialLoginCode)
 	}
 
 	mood := v1.Group("/mood", middleware.BaseAuthMiddleware())
@@ -110,12 +197,24 @@ func RegisterRoutes(route *gin.Engine) {
 		mood.GET("/attribute/get", moodController.GetGenericAttributes)
 	}
 
+	moderationController := controllers.NewModerationController(moderationService)
+	recommendationController := controllers.NewRecommendationController(recommendationService)
+
 	resource := v1.Group("/resource", middleware.BaseAuthMiddleware())
 	{
 		resourceController := controllers.NewResourceController(resourceService)
 
 		// get reccs
-		resource.GET("/get-reccs", controllers.GenerateRecommendations)
+		resource.GET("/get-reccs", recommendationController.GenerateRecommendations)
+
+		// Dismiss a recommended resource so it isn't recommended again
+		resource.POST("/reccs/dismiss/:id", recommendationController.DismissRecommendation)
+
+		// Undo the dismissal of a recommended resource
+		resource.DELETE("/reccs/dismiss/:id", recommendationController.UndoDismissal)
+
+		// Get all recommendations dismissed by the current user
+		resource.GET("/reccs/dismissed", recommendationController.GetDismissals)
 
 		// Create a new resource
 		resource.POST("/create", resourceController.CreateResourceEntry)
@@ -126,7 +225,148 @@ func RegisterRoutes(route *gin.Engine) {
 		// Get single resource
 		resource.GET("/get/:id", resourceController.GetResourceByID)
 
+		// Update a resource
+		resource.PUT("/update/:id", resourceController.UpdateResourceEntry)
+
+		// Set the moods a resource is suited for, used to boost mood-aware recommendations
+		resource.PUT("/mood-tags/:id", resourceController.SetResourceMoodTags)
+
 		// Add a review to a resource
 		resource.POST("/review/add/:id", resourceController.AddReview)
+
+		// Get reviews for a resource, sorted by helpfulness
+		resource.GET("/review/get/:id", resourceController.GetResourceReviews)
+
+		// Get reviews written by the current user, along with their helpfulness votes
+		resource.GET("/review/mine", resourceController.GetMyReviews)
+
+		// Mark a review as helpful or unhelpful
+		resource.POST("/review/vote/:id", resourceController.VoteOnReview)
+
+		// Remove a helpfulness vote from a review
+		resource.DELETE("/review/vote/:id", resourceController.RemoveReviewVote)
+
+		// Report a resource to the moderators
+		resource.POST("/report/:id", moderationController.ReportResource)
+
+		// Report a review to the moderators
+		resource.POST("/review/report/:id", moderationController.ReportReview)
+	}
+
+	bookmarks := v1.Group("/resource", middleware.BaseAuthMiddleware())
+	{
+		collectionController := controllers.NewCollectionController(collectionService)
+
+		// Bookmark a resource
+		bookmarks.POST("/bookmark/:id", collectionController.BookmarkResource)
+
+		// Remove a bookmark
+		bookmarks.DELETE("/bookmark/:id", collectionController.RemoveBookmark)
+
+		// Get all bookmarked resources
+		bookmarks.GET("/bookmarks", collectionController.GetBookmarks)
+
+		collections := bookmarks.Group("/collections")
+
+		// Create a new collection
+		collections.POST("/create", collectionController.CreateCollection)
+
+		// Get all collections created by the current user
+		collections.GET("/get", collectionController.GetUserCollections)
+
+		// Get a single collection
+		collections.GET("/get/:id", collectionController.GetCollection)
+
+		// Get all public collections
+		collections.GET("/public", collectionController.GetPublicCollections)
+
+		// Get all collections followed by the current user
+		collections.GET("/followed", collectionController.GetFollowedCollections)
+
+		// Update a collection's details
+		collections.PUT("/update/:id", collectionController.UpdateCollection)
+
+		// Delete a collection
+		collections.DELETE("/delete/:id", collectionController.DeleteCollection)
+
+		// Add a resource to a collection
+		collections.POST("/items/add/:id", collectionController.AddToCollection)
+
+		// Remove a resource from a collection
+		collections.DELETE("/items/remove/:id/:resource_id", collectionController.RemoveFromCollection)
+
+		// Reorder the resources in a collection
+		collections.PUT("/reorder/:id", collectionController.ReorderCollection)
+
+		// Follow a public collection
+		collections.POST("/follow/:id", collectionController.FollowCollection)
+
+		// Unfollow a collection
+		collections.DELETE("/follow/:id", collectionController.UnfollowCollection)
+	}
+
+	admin := v1.Group("/admin", middleware.BaseAuthMiddleware())
+	{
+		moderate := middleware.RequirePermission(models.ModerateResources, models.ModerateReviews)
+
+		// Get all flagged and pending content the user can moderate
+		admin.GET("/moderation", moderate, moderationController.GetModerationQueue)
+
+		// Publish a flagged or pending resource/review
+		admin.POST("/moderation/:type/:id/approve", moderate, moderationController.ApproveContent)
+
+		// Hide a flagged resource/review
+		admin.POST("/moderation/:type/:id/hide", moderate, moderationController.HideContent)
+
+		// Delete a flagged resource/review
+		admin.DELETE("/moderation/:type/:id", moderate, moderationController.DeleteContent)
+
+		curate := middleware.RequirePermission(models.CurateResources)
+
+		// Get the curated resources recommended to everyone
+		admin.GET("/curated", curate, recommendationController.GetCuratedResources)
+
+		// Add a resource to the curated resources
+		admin.POST("/curated/:id", curate, recommendationController.CurateResource)
+
+		// Remove a resource from the curated resources
+		admin.DELETE("/curated/:id", curate, recommendationController.UncurateResource)
+
+		manageRecommender := middleware.RequirePermission(models.ManageRecommender)
+
+		// Get the stored versions of the matrix factorization recommender model
+		admin.GET("/recommender/models", manageRecommender, recommendationController.GetFactorizationModels)
+
+		// Train a new version of the matrix factorization model and activate it
+		admin.POST("/recommender/models", manageRecommender, recommendationController.TrainFactorizationModel)
+
+		// Activate another version of the matrix factorization model, e.g. to roll back
+		admin.POST("/recommender/models/:id/activate", manageRecommender, recommendationController.ActivateFactorizationModel)
+
+		userAdminController := controllers.NewUserAdminController(userAdminService)
+
+		// Search users, a page at a time
+		admin.GET("/users", middleware.RequirePermission(models.ViewUsers), userAdminController.GetUsers)
+
+		// Get a single user
+		admin.GET("/users/:id", middleware.RequirePermission(models.ViewUsers), userAdminController.GetUser)
+
+		// Disable a user, logging them out everywhere
+		admin.POST("/users/:id/disable", middleware.RequirePermission(models.DisableUsers), userAdminController.DisableUser)
+
+		// Re-enable a disabled user
+		admin.POST("/users/:id/enable", middleware.RequirePermission(models.DisableUsers), userAdminController.EnableUser)
+
+		// Assign a user a role
+		admin.PUT("/users/:id/role", middleware.RequirePermission(models.AssignRoles), userAdminController.SetUserRole)
+
+		// List the roles and their permissions
+		admin.GET("/roles", middleware.RequirePermission(models.AssignRoles), userAdminController.GetRoles)
+
+		// Mark a user's email as verified
+		admin.POST("/users/:id/verify", middleware.RequirePermission(models.VerifyUsers), userAdminController.VerifyUser)
+
+		// Mail a user a password reset OTP
+		admin.POST("/users/:id/reset-password", middleware.RequirePermission(models.ResetUserPasswords), userAdminController.SendPasswordReset)
 	}
 }
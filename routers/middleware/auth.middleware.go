@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/anirudhgray/mood-harbour-backend/infra/logger"
 	"github.com/anirudhgray/mood-harbour-backend/models"
@@ -10,11 +11,15 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-// BaseAuthMiddleware checks if the user is authenticated
+// sessionTouchInterval is how often a session's last seen time is updated, rather than on every request.
+const sessionTouchInterval = time.Minute
+
+// BaseAuthMiddleware checks if the user is authenticated, with an access token of a session which hasn't been revoked,
+// and that their account isn't disabled.
 func BaseAuthMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 
-		userID, err := token.ValidateToken(c)
+		userID, jti, err := token.ValidateToken(c)
 
 		if err != nil {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "auth", "message": "Please login to continue."})
@@ -23,6 +28,19 @@ func BaseAuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
+		sessionRepo := repository.NewSessionRepository()
+		session, err := sessionRepo.GetSessionByJTI(jti)
+		if err != nil || session.UserID != userID || session.RevokedAt != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "auth", "message": "Your session has ended, please login again."})
+			c.Abort()
+			return
+		}
+		if now := time.Now(); now.Sub(session.LastSeenAt) > sessionTouchInterval {
+			if err := sessionRepo.TouchSession(session.ID, now); err != nil {
+				logger.Errorf("Updating Session Error: %v", err)
+			}
+		}
+
 		var user models.User
 
 		userRepo := repository.NewUserRepository()
@@ -34,24 +52,35 @@ func BaseAuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
+		if user.Disabled {
+			c.JSON(http.StatusForbidden, gin.H{"error": "account-disabled", "message": "This account has been disabled."})
+			c.Abort()
+			return
+		}
+
 		c.Set("user", &user)
+		c.Set("session", &session)
 
 		c.Next()
 	}
 }
 
-// AdminAuthMiddleware checks if the user is an admin
-func AdminAuthMiddleware() gin.HandlerFunc {
+// RequirePermission only lets through users whose role grants at least one of the permissions.
+// Handlers of routes needing a specific one of them, depending on the request, check it themselves.
+// It must come after BaseAuthMiddleware.
+func RequirePermission(permissions ...models.Permission) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		user, _ := c.Get("user")
 		userData := user.(*models.User)
 
-		if !userData.Admin {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "auth", "message": "You are not authorized to access this resource."})
-			c.Abort()
-			return
+		for _, permission := range permissions {
+			if userData.Can(permission) {
+				c.Next()
+				return
+			}
 		}
 
-		c.Next()
+		c.JSON(http.StatusForbidden, gin.H{"error": "auth", "message": "You are not authorized to access this resource."})
+		c.Abort()
 	}
 }
@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/anirudhgray/mood-harbour-backend/models"
+	"github.com/gin-gonic/gin"
+)
+
+func TestRequirePermission(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	testCases := []struct {
+		name        string
+		role        models.Role
+		permissions []models.Permission
+		status      int
+	}{
+		{"Users can't moderate", models.UserRole, []models.Permission{models.ModerateReviews}, http.StatusForbidden},
+		{"Moderators can moderate reviews", models.ModeratorRole, []models.Permission{models.ModerateReviews}, http.StatusOK},
+		{"Moderators can't disable users", models.ModeratorRole, []models.Permission{models.DisableUsers}, http.StatusForbidden},
+		{"Any of the permissions is enough", models.ClinicianRole, []models.Permission{models.ModerateResources, models.CurateResources}, http.StatusOK},
+		{"Admins can do everything", models.AdminRole, []models.Permission{models.AssignRoles}, http.StatusOK},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			router := gin.New()
+			router.GET("/", func(c *gin.Context) {
+				c.Set("user", &models.User{Role: tc.role})
+			}, RequirePermission(tc.permissions...), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+			if w.Code != tc.status {
+				t.Errorf("Expected status %d, got %d", tc.status, w.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimit lets each client IP make at most limit requests per window, and responds with 429 Too Many Requests to the rest.
+// Requests are counted in memory, in fixed windows, so each instance of the server keeps its own counts.
+func RateLimit(limit int, window time.Duration) gin.HandlerFunc {
+	var mu sync.Mutex
+	counts := map[string]int{}
+	windowEnd := time.Now().Add(window)
+
+	return func(c *gin.Context) {
+		mu.Lock()
+		now := time.Now()
+		if !now.Before(windowEnd) {
+			// dropping the old window's counts keeps the map from growing with every IP ever seen
+			counts = map[string]int{}
+			windowEnd = now.Add(window)
+		}
+		counts[c.ClientIP()]++
+		count, retryAfter := counts[c.ClientIP()], windowEnd.Sub(now)
+		mu.Unlock()
+
+		if count > limit {
+			seconds := int(math.Ceil(retryAfter.Seconds()))
+			c.Header("Retry-After", strconv.Itoa(seconds))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "too-many-attempts", "message": fmt.Sprintf("too many requests, please try again in %d seconds", seconds)})
+			return
+		}
+		c.Next()
+	}
+}
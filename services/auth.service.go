@@ -8,56 +8,105 @@ import (
 	"github.com/anirudhgray/mood-harbour-backend/models"
 	"github.com/anirudhgray/mood-harbour-backend/repository"
 	"github.com/anirudhgray/mood-harbour-backend/utils/auth"
+	"github.com/anirudhgray/mood-harbour-backend/utils/password"
+	"github.com/anirudhgray/mood-harbour-backend/utils/token"
+	"github.com/anirudhgray/mood-harbour-backend/utils/webauthn"
+	"gorm.io/gorm"
+)
+
+var (
+	errAccountDisabled = errors.New("this account has been disabled")
+	errInvalidOTP      = errors.New("invalid or expired code")
 )
 
 // AuthService handles the business logic for authentication
 type AuthService struct {
 	authProviderRepo repository.AuthProviderRepositoryInterface
-	verificationRepo repository.VerificationRepositoryInterface
-	forgotRepo       repository.ForgotPasswordRepositoryInterface
-	deletionRepo     repository.DeletionConfirmationRepositoryInterface
+	oneTimeTokenRepo repository.OneTimeTokenRepositoryInterface
 	passwordAuthRepo repository.PasswordAuthRepositoryInterface
 	userRepo         repository.UserRepositoryInterface
 	emailService     EmailServiceInterface
 	moodRepo         repository.MoodRepositoryInterface
+	sessionRepo      repository.SessionRepositoryInterface
+	refreshTokenRepo repository.RefreshTokenRepositoryInterface
+	mfaRepo          repository.MFARepositoryInterface
+	passkeyRepo      repository.PasskeyRepositoryInterface
+	relyingParty     webauthn.RelyingParty
+	throttleService  ThrottleServiceInterface
+	passwordPolicy   password.Policy
 }
 
 // NewAuthService returns a new AuthService
 func NewAuthService(
 	authProviderRepo repository.AuthProviderRepositoryInterface,
-	verificationRepo repository.VerificationRepositoryInterface,
-	forgotRepo repository.ForgotPasswordRepositoryInterface,
-	deletionRepo repository.DeletionConfirmationRepositoryInterface,
+	oneTimeTokenRepo repository.OneTimeTokenRepositoryInterface,
 	passwordAuthRepo repository.PasswordAuthRepositoryInterface,
 	userRepo repository.UserRepositoryInterface,
 	emailService EmailServiceInterface,
 	moodRepo repository.MoodRepositoryInterface,
+	sessionRepo repository.SessionRepositoryInterface,
+	refreshTokenRepo repository.RefreshTokenRepositoryInterface,
+	mfaRepo repository.MFARepositoryInterface,
+	passkeyRepo repository.PasskeyRepositoryInterface,
+	relyingParty webauthn.RelyingParty,
+	throttleService ThrottleServiceInterface,
+	passwordPolicy password.Policy,
 ) *AuthService {
 	return &AuthService{
 		authProviderRepo: authProviderRepo,
-		verificationRepo: verificationRepo,
-		forgotRepo:       forgotRepo,
-		deletionRepo:     deletionRepo,
+		oneTimeTokenRepo: oneTimeTokenRepo,
 		passwordAuthRepo: passwordAuthRepo,
 		userRepo:         userRepo,
 		emailService:     emailService,
 		moodRepo:         moodRepo,
+		sessionRepo:      sessionRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		mfaRepo:          mfaRepo,
+		passkeyRepo:      passkeyRepo,
+		relyingParty:     relyingParty,
+		throttleService:  throttleService,
+		passwordPolicy:   passwordPolicy,
 	}
 }
 
 type AuthServiceInterface interface {
 	RegisterUser(email, name, profileImage, password string) (models.User, error)
-	LoginUser(email, password string) (string, models.User, error)
+	LoginUser(email, password string, client models.ClientInfo) (models.LoginResult, error)
+	SocialLogin(user models.User, client models.ClientInfo) (models.LoginResult, error)
+	IssueSocialLoginCode(user models.User) (string, error)
+	ExchangeSocialLoginCode(code string, client models.ClientInfo) (models.LoginResult, error)
+	CompleteMFALogin(mfaToken, code string, client models.ClientInfo) (models.LoginResult, error)
+	RefreshTokens(refreshToken string, client models.ClientInfo) (models.AuthTokens, error)
+	GetSessions(userID, currentSessionID uint) ([]models.SessionView, error)
+	RevokeSession(userID, sessionID uint) error
+	RevokeAllSessions(userID uint) error
 	RequestVerificationAgain(email string) error
-	VerifyEmail(email, otp string) error
+	VerifyEmail(email, otp string, client models.ClientInfo) error
 	ForgotPasswordRequest(email string) error
-	SetNewPassword(email string, otp string, newPassword string) error
-	ResetPassword(user models.User, oldPassword string, newPassword string) error
+	SetNewPassword(email string, otp string, newPassword string, client models.ClientInfo) error
+	ResetPassword(user models.User, currentSessionID uint, oldPassword string, newPassword string) error
 	RequestDeletion(user models.User) error
-	DeleteAccount(email string, otp string) error
+	DeleteAccount(email string, otp string, client models.ClientInfo) error
+	GetMFAStatus(userID uint) (models.MFAStatus, error)
+	BeginTOTPEnrollment(user models.User) (models.TOTPEnrollment, error)
+	ConfirmTOTPEnrollment(user models.User, code string) ([]string, error)
+	DisableTOTP(user models.User, code string, client models.ClientInfo) error
+	RegenerateRecoveryCodes(user models.User, code string, client models.ClientInfo) ([]string, error)
+	BeginPasskeyRegistration(user models.User) (webauthn.CreationOptions, error)
+	FinishPasskeyRegistration(user models.User, name string, response webauthn.AttestationResponse) (models.PasskeyView, error)
+	BeginPasskeyLogin() (webauthn.RequestOptions, error)
+	FinishPasskeyLogin(response webauthn.AssertionResponse, client models.ClientInfo) (models.LoginResult, error)
+	GetPasskeys(userID uint) ([]models.PasskeyView, error)
+	DeletePasskey(user models.User, passkeyID uint) error
 }
 
+// RegisterUser creates a user who logs in with a password. Registering an email which already has one only mails its
+// owner, and returns an empty user without an error, so the client can't tell whether the account exists.
 func (as *AuthService) RegisterUser(email, name, profileImage, password string) (models.User, error) {
+	if err := as.passwordPolicy.Validate(password, email, name); err != nil {
+		return models.User{}, err
+	}
+
 	// Check if the user already exists
 	existingUser, _ := as.userRepo.GetUserByEmail(email)
 	existingPwdAuth, _ := as.passwordAuthRepo.GetPwdAuthItemByEmail(email)
@@ -65,12 +114,8 @@ func (as *AuthService) RegisterUser(email, name, profileImage, password string)
 	var emptyPwdAuth models.PasswordAuth
 	var emptyUser models.User
 	if existingPwdAuth != emptyPwdAuth {
-		// email.SendRegistrationMail("Account Alert", "Someone attempted to create an account using your email. If this was you, try applying for password reset in case you have lost access to your account.", existingUser.Email, existingUser.ID, existingUser.Name, false)
-		return models.User{}, errors.New("user with that email address already exists")
-	}
-
-	if !auth.CheckPasswordStrength(password) {
-		return models.User{}, errors.New("password not strong enough")
+		as.emailService.GenericSendMail("Account Alert", "Someone attempted to create an account using your email. If this was you, try resetting your password in case you have lost access to your account.", existingUser.Email, existingUser.Name)
+		return models.User{}, nil
 	}
 
 	user := models.User{Name: name, Email: email, ProfileImage: profileImage}
@@ -88,7 +133,9 @@ func (as *AuthService) RegisterUser(email, name, profileImage, password string)
 			logger.Errorf("Failed to create user: %v", err)
 			return models.User{}, err
 		}
-		as.emailService.SendRegistrationMail("Account Verification.", "Please visit the following link to verify your account: ", user.Email, user.Name, true)
+		if err := as.sendVerificationMail(user); err != nil {
+			logger.Errorf("Failed to send verification mail: %v", err)
+		}
 		logger.Infof("New User Object Created.")
 		u, _ := as.userRepo.GetUserByEmail(email)
 		pwdauth.UserID = u.ID
@@ -110,110 +157,308 @@ func (as *AuthService) RegisterUser(email, name, profileImage, password string)
 	// }
 }
 
-// LoginUser handles user login.
-func (as *AuthService) LoginUser(email, password string) (string, models.User, error) {
-	token, user, err := auth.LoginCheck(email, password)
+// LoginUser handles user login, starting a session on the client's device. Users with two-factor authentication get an
+// MFA challenge instead, see CompleteMFALogin. Failed logins are throttled per account and IP address.
+func (as *AuthService) LoginUser(email, password string, client models.ClientInfo) (models.LoginResult, error) {
+	if err := as.throttleService.Check(ThrottleLogin, email, client.IP); err != nil {
+		return models.LoginResult{}, err
+	}
+
+	user, err := auth.LoginCheck(email, password)
 
 	if err != nil {
-		return "", models.User{}, err
+		if errors.Is(err, auth.ErrInvalidCredentials) {
+			as.recordFailure(ThrottleLogin, email, client)
+		}
+		return models.LoginResult{}, err
 	}
 
 	if !user.Verified {
-		return "", models.User{}, errors.New("please verify your email before logging in")
+		return models.LoginResult{}, errors.New("please verify your email before logging in")
 	}
 
-	return token, user, nil
+	result, err := as.login(user, client)
+	if err != nil {
+		return models.LoginResult{}, err
+	}
+	// with two-factor authentication, the password alone doesn't make the login successful, see CompleteMFALogin
+	if !result.MFARequired {
+		as.resetThrottle(ThrottleLogin, email)
+	}
+	return result, nil
 }
 
-// RequestVerificationAgain handles resending verification email.
-func (as *AuthService) RequestVerificationAgain(email string) error {
-	user, err := as.userRepo.GetUserByEmail(email)
+// SocialLogin logs in a user already authenticated by a social login provider, the same way as LoginUser.
+func (as *AuthService) SocialLogin(user models.User, client models.ClientInfo) (models.LoginResult, error) {
+	return as.login(user, client)
+}
+
+// SocialLoginCodeLifespan is how long the frontend has to exchange the code it was redirected with after a social login.
+const SocialLoginCodeLifespan = time.Minute
+
+// IssueSocialLoginCode creates a code for a user authenticated by a social login provider, for the frontend to exchange
+// with ExchangeSocialLoginCode. Only the code goes in the redirect, not the tokens themselves.
+func (as *AuthService) IssueSocialLoginCode(user models.User) (string, error) {
+	code, codeHash, err := token.GenerateOpaqueToken()
 	if err != nil {
-		return err
+		return "", err
+	}
+	err = as.sessionRepo.CreateSocialLoginCode(&models.SocialLoginCode{UserID: user.ID, CodeHash: codeHash, ExpiresAt: time.Now().Add(SocialLoginCodeLifespan)})
+	if err != nil {
+		logger.Errorf("Failed to create social login code: %v", err)
+		return "", err
 	}
+	return code, nil
+}
 
-	if user.Verified {
-		return nil
+// ExchangeSocialLoginCode logs in the user a social login code was issued for, with SocialLogin. Each code can be exchanged once.
+func (as *AuthService) ExchangeSocialLoginCode(code string, client models.ClientInfo) (models.LoginResult, error) {
+	invalid := errors.New("invalid or expired login, please log in again")
+
+	stored, err := as.sessionRepo.GetSocialLoginCodeByHash(token.HashOpaqueToken(code))
+	if err != nil || stored.UsedAt != nil || time.Now().After(stored.ExpiresAt) {
+		return models.LoginResult{}, invalid
+	}
+	ok, err := as.sessionRepo.UseSocialLoginCode(stored.ID, time.Now())
+	if err != nil {
+		return models.LoginResult{}, err
+	}
+	if !ok {
+		return models.LoginResult{}, invalid
+	}
+
+	user, err := as.userRepo.GetUserByID(stored.UserID)
+	if err != nil {
+		return models.LoginResult{}, invalid
+	}
+	return as.SocialLogin(user, client)
+}
+
+// RefreshTokens exchanges a refresh token for a new access token and refresh token, extending its session. Each refresh token
+// can be used once. A used refresh token being presented again means it has leaked, so its session is revoked, logging out
+// both the user and whoever has the token.
+func (as *AuthService) RefreshTokens(refreshToken string, client models.ClientInfo) (models.AuthTokens, error) {
+	invalid := errors.New("invalid or expired refresh token")
+
+	stored, err := as.refreshTokenRepo.GetRefreshTokenByHash(token.HashOpaqueToken(refreshToken))
+	if err != nil {
+		return models.AuthTokens{}, invalid
+	}
+	session, err := as.sessionRepo.GetSessionByID(stored.SessionID)
+	if err != nil {
+		return models.AuthTokens{}, invalid
+	}
+	if session.RevokedAt != nil || time.Now().After(stored.ExpiresAt) {
+		return models.AuthTokens{}, invalid
 	}
 
-	_, err = as.verificationRepo.GetVerificationEntryByEmail(user.Email)
-	if err == nil {
-		err = as.verificationRepo.DeleteVerificationEntry(user.Email)
+	now := time.Now()
+	if stored.UsedAt == nil {
+		ok, err := as.refreshTokenRepo.MarkRefreshTokenUsed(stored.ID, now)
 		if err != nil {
-			return err
+			return models.AuthTokens{}, err
 		}
+		if ok {
+			// the user may have deleted their account since, or been disabled
+			user, err := as.userRepo.GetUserByID(stored.UserID)
+			if err != nil || user.Disabled {
+				return models.AuthTokens{}, invalid
+			}
+			if err := as.sessionRepo.ExtendSession(session.ID, client, now, now.Add(token.RefreshTokenLifespan())); err != nil {
+				return models.AuthTokens{}, err
+			}
+			return as.issueTokens(user, session)
+		}
+		// someone else used it in the meantime
+	}
+
+	logger.Warnf("Refresh token %d of user %d was reused, revoking session %d", stored.ID, stored.UserID, session.ID)
+	if err := as.sessionRepo.RevokeSession(session.UserID, session.ID, now); err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return models.AuthTokens{}, err
 	}
+	return models.AuthTokens{}, invalid
+}
 
-	// Send verification email
-	err = as.emailService.SendRegistrationMail("Account Verification.", "Please visit the following link to verify your account: ", user.Email, user.Name, true)
+// GetSessions gets the user's active sessions, marking the one making the request as current.
+func (as *AuthService) GetSessions(userID, currentSessionID uint) ([]models.SessionView, error) {
+	sessions, err := as.sessionRepo.GetActiveSessions(userID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
+	views := make([]models.SessionView, 0, len(sessions))
+	for _, session := range sessions {
+		views = append(views, models.SessionView{
+			ID:         session.ID,
+			UserAgent:  session.UserAgent,
+			IP:         session.IP,
+			CreatedAt:  session.CreatedAt,
+			LastSeenAt: session.LastSeenAt,
+			Current:    session.ID == currentSessionID,
+		})
+	}
+	return views, nil
+}
+
+// RevokeSession logs one of the user's sessions out. Its access tokens stop working straight away.
+func (as *AuthService) RevokeSession(userID, sessionID uint) error {
+	return as.sessionRepo.RevokeSession(userID, sessionID, time.Now())
 }
 
-func (as *AuthService) VerifyEmail(email string, otp string) error {
-	// Fetch the verification entry by email
-	verificationEntry, err := as.verificationRepo.GetVerificationEntryByEmail(email)
+// RevokeAllSessions logs the user out everywhere.
+func (as *AuthService) RevokeAllSessions(userID uint) error {
+	return as.sessionRepo.RevokeUserSessions(userID, 0, time.Now())
+}
+
+// login starts a session for a user who has proven their identity, or an MFA challenge if they have two-factor authentication.
+func (as *AuthService) login(user models.User, client models.ClientInfo) (models.LoginResult, error) {
+	if user.Disabled {
+		return models.LoginResult{}, errAccountDisabled
+	}
+
+	factor, err := as.mfaRepo.GetTOTPFactor(user.ID)
 	if err != nil {
-		return err
+		return models.LoginResult{}, err
+	}
+	if factor.ConfirmedAt != nil {
+		return as.createMFAChallenge(user)
 	}
 
-	if verificationEntry.OTP != otp {
-		return errors.New("invalid verification")
+	tokens, err := as.startSession(user, client)
+	if err != nil {
+		return models.LoginResult{}, err
 	}
+	return models.LoginResult{Tokens: tokens, User: user}, nil
+}
 
-	// Verify the email by updating the user's verification status
-	err = as.userRepo.VerifyUserEmail(email)
+// recordFailure counts a failed attempt at the action. Failing to count it doesn't fail the request, which failed anyway.
+func (as *AuthService) recordFailure(action, email string, client models.ClientInfo) {
+	if err := as.throttleService.RecordFailure(action, email, client.IP); err != nil {
+		logger.Errorf("Failed to record failed %s attempt: %v", action, err)
+	}
+}
+
+// resetThrottle forgets the account's failed attempts at the action after a successful one.
+func (as *AuthService) resetThrottle(action, email string) {
+	if err := as.throttleService.Reset(action, email); err != nil {
+		logger.Errorf("Failed to reset %s throttle: %v", action, err)
+	}
+}
+
+// startSession creates a session for the user on the client's device, and its first tokens. Disabled users can't log in.
+func (as *AuthService) startSession(user models.User, client models.ClientInfo) (models.AuthTokens, error) {
+	if user.Disabled {
+		return models.AuthTokens{}, errAccountDisabled
+	}
+
+	jti, err := token.GenerateJTI()
 	if err != nil {
-		return err
+		return models.AuthTokens{}, err
 	}
 
-	// Delete the verification entry
-	err = as.verificationRepo.DeleteVerificationEntry(email)
+	now := time.Now()
+	session := models.Session{
+		UserID:     user.ID,
+		JTI:        jti,
+		UserAgent:  client.UserAgent,
+		IP:         client.IP,
+		LastSeenAt: now,
+		ExpiresAt:  now.Add(token.RefreshTokenLifespan()),
+	}
+	if err := as.sessionRepo.CreateSession(&session); err != nil {
+		logger.Errorf("Failed to create session: %v", err)
+		return models.AuthTokens{}, err
+	}
+
+	return as.issueTokens(user, session)
+}
+
+// issueTokens creates an access token and a refresh token for the session.
+func (as *AuthService) issueTokens(user models.User, session models.Session) (models.AuthTokens, error) {
+	accessToken, err := token.GenerateToken(user, session.JTI)
 	if err != nil {
-		return err
+		return models.AuthTokens{}, err
 	}
 
-	return nil
+	refreshToken, tokenHash, err := token.GenerateOpaqueToken()
+	if err != nil {
+		return models.AuthTokens{}, err
+	}
+	err = as.refreshTokenRepo.CreateRefreshToken(&models.RefreshToken{
+		UserID:    user.ID,
+		SessionID: session.ID,
+		TokenHash: tokenHash,
+		ExpiresAt: time.Now().Add(token.RefreshTokenLifespan()),
+	})
+	if err != nil {
+		logger.Errorf("Failed to create refresh token: %v", err)
+		return models.AuthTokens{}, err
+	}
+
+	return models.AuthTokens{AccessToken: accessToken, RefreshToken: refreshToken, ExpiresAt: time.Now().Add(token.AccessTokenLifespan())}, nil
 }
 
-func (as *AuthService) ForgotPasswordRequest(email string) error {
-	// Fetch the user by email
+// RequestVerificationAgain handles resending verification email.
+// Unknown emails are ignored, so the response doesn't reveal which emails have accounts.
+func (as *AuthService) RequestVerificationAgain(email string) error {
 	user, err := as.userRepo.GetUserByEmail(email)
 	if err != nil {
 		return nil
 	}
 
-	// Check if a forgot password entry already exists for the user's email
-	err = as.forgotRepo.DeleteForgotPasswordByEmail(user.Email)
-	if err != nil {
+	if user.Verified {
 		return nil
 	}
 
-	// Send the forgot password email
-	err = as.emailService.SendForgotPasswordMail(user.Email, user.Name)
+	return as.sendVerificationMail(user)
+}
+
+// sendVerificationMail emails the user a new code to verify their email with.
+func (as *AuthService) sendVerificationMail(user models.User) error {
+	otp, _, err := as.issueOneTimeToken(user.Email, models.OneTimeTokenVerifyEmail)
 	if err != nil {
 		return err
 	}
+	return as.emailService.SendRegistrationMail("Account Verification.", "Please visit the following link to verify your account: ", user.Email, user.Name, otp)
+}
 
-	return nil
+// VerifyEmail verifies the email with the code sent to it. Wrong codes are throttled like failed logins.
+func (as *AuthService) VerifyEmail(email string, otp string, client models.ClientInfo) error {
+	if err := as.useOneTimeToken(email, models.OneTimeTokenVerifyEmail, otp, client); err != nil {
+		return err
+	}
+
+	// Verify the email by updating the user's verification status
+	return as.userRepo.VerifyUserEmail(email)
 }
 
-func (as *AuthService) SetNewPassword(email string, otp string, newPassword string) error {
-	// Fetch the forgot password entry by email
-	forgotPasswordEntry, err := as.forgotRepo.GetForgotPasswordByEmail(email)
+func (as *AuthService) ForgotPasswordRequest(email string) error {
+	// Fetch the user by email
+	user, err := as.userRepo.GetUserByEmail(email)
+	if err != nil {
+		return nil
+	}
+
+	// Any earlier code stops working
+	otp, lifespan, err := as.issueOneTimeToken(user.Email, models.OneTimeTokenResetPassword)
 	if err != nil {
 		return err
 	}
 
-	if forgotPasswordEntry.ValidTill.Before(time.Now()) {
-		return errors.New("password OTP has expired, please request forgot password again")
+	// Send the forgot password email
+	return as.emailService.SendForgotPasswordMail(user.Email, user.Name, otp, lifespan)
+}
+
+// SetNewPassword sets a new password with the code sent by ForgotPasswordRequest. Wrong codes are throttled like failed logins.
+func (as *AuthService) SetNewPassword(email string, otp string, newPassword string, client models.ClientInfo) error {
+	// checked before using up the code, so users can try another password with it. Not against the user's name, whether
+	// an account exists for the email isn't revealed before the code is checked.
+	if err := as.passwordPolicy.Validate(newPassword, email, ""); err != nil {
+		return err
 	}
 
-	if forgotPasswordEntry.OTP != otp {
-		return errors.New("invalid verification")
+	if err := as.useOneTimeToken(email, models.OneTimeTokenResetPassword, otp, client); err != nil {
+		return err
 	}
 
 	// Fetch the user by email
@@ -227,12 +472,11 @@ func (as *AuthService) SetNewPassword(email string, otp string, newPassword stri
 		return err
 	}
 
-	if !auth.CheckPasswordStrength(newPassword) {
-		return errors.New("password not strong enough")
-	}
-
 	pwdAuth.Password = newPassword
-	pwdAuth.HashPassword()
+	if err := pwdAuth.HashPassword(); err != nil {
+		logger.Errorf("Failed to hash password: %v", err)
+		return err
+	}
 
 	err = as.userRepo.SaveUser(user)
 	if err != nil {
@@ -244,18 +488,18 @@ func (as *AuthService) SetNewPassword(email string, otp string, newPassword stri
 		return err
 	}
 
-	as.emailService.GenericSendMail("Password Reset", "Password for your account was reset recently.", user.Email, user.Name)
-
-	// Delete the forgot password entry
-	err = as.forgotRepo.DeleteForgotPasswordByEmail(email)
+	// whoever knew the old password may still be logged in
+	err = as.sessionRepo.RevokeUserSessions(user.ID, 0, time.Now())
 	if err != nil {
 		return err
 	}
 
+	as.emailService.GenericSendMail("Password Reset", "Password for your account was reset recently.", user.Email, user.Name)
+
 	return nil
 }
 
-func (as *AuthService) ResetPassword(user models.User, oldPassword string, newPassword string) error {
+func (as *AuthService) ResetPassword(user models.User, currentSessionID uint, oldPassword string, newPassword string) error {
 	// Fetch the password auth item by email
 	currentPwdAuth, err := as.passwordAuthRepo.GetPwdAuthItemByEmail(user.Email)
 	if err != nil {
@@ -267,12 +511,15 @@ func (as *AuthService) ResetPassword(user models.User, oldPassword string, newPa
 		return errors.New("incorrect current password")
 	}
 
-	if !auth.CheckPasswordStrength(newPassword) {
-		return errors.New("password not strong enough")
+	if err := as.passwordPolicy.Validate(newPassword, user.Email, user.Name); err != nil {
+		return err
 	}
 
 	currentPwdAuth.Password = newPassword
-	currentPwdAuth.HashPassword()
+	if err := currentPwdAuth.HashPassword(); err != nil {
+		logger.Errorf("Failed to hash password: %v", err)
+		return err
+	}
 
 	err = as.userRepo.SaveUser(user)
 	if err != nil {
@@ -284,42 +531,34 @@ func (as *AuthService) ResetPassword(user models.User, oldPassword string, newPa
 		return err
 	}
 
+	// log out every other device, whoever knew the old password may still be logged in
+	err = as.sessionRepo.RevokeUserSessions(user.ID, currentSessionID, time.Now())
+	if err != nil {
+		return err
+	}
+
 	as.emailService.GenericSendMail("Password Reset Successfully", "Your password was changed. Secure your account if this was not you.", user.Email, user.Name)
 
 	return nil
 }
 
 func (as *AuthService) RequestDeletion(user models.User) error {
-	// Check if a deletion confirmation record already exists for the user's email, and remove it
-	err := as.deletionRepo.DeleteDeletionConfirmationByEmail(user.Email)
+	// Any earlier code stops working
+	otp, lifespan, err := as.issueOneTimeToken(user.Email, models.OneTimeTokenDeleteAccount)
 	if err != nil {
 		return err
 	}
 
 	// Send deletion email
-	err = as.emailService.SendDeletionMail(user.Email, user.Name)
-	if err != nil {
-		return err
-	}
-
-	return nil
+	return as.emailService.SendDeletionMail(user.Email, user.Name, otp, lifespan)
 }
 
-func (as *AuthService) DeleteAccount(email string, otp string) error {
-	// Fetch the deletion confirmation entry by email
-	deletionEntry, err := as.deletionRepo.GetDeletionConfirmationByEmail(email)
-	if err != nil {
+// DeleteAccount deletes the account with the code sent by RequestDeletion. Wrong codes are throttled like failed logins.
+func (as *AuthService) DeleteAccount(email string, otp string, client models.ClientInfo) error {
+	if err := as.useOneTimeToken(email, models.OneTimeTokenDeleteAccount, otp, client); err != nil {
 		return err
 	}
 
-	if deletionEntry.ValidTill.Before(time.Now()) {
-		return errors.New("password OTP has expired, please request account deletion again")
-	}
-
-	if deletionEntry.OTP != otp {
-		return errors.New("invalid verification")
-	}
-
 	// Fetch the user by email
 	user, err := as.userRepo.GetUserByEmail(email)
 	if err != nil {
@@ -341,13 +580,23 @@ func (as *AuthService) DeleteAccount(email string, otp string) error {
 		return err
 	}
 
-	as.emailService.GenericSendMail("Account Deleted", "Your account on  Mood App has been deleted.", user.Email, user.Name)
+	err = as.passkeyRepo.DeleteUserPasskeys(user.ID)
+	if err != nil {
+		return err
+	}
+
+	err = as.sessionRepo.RevokeUserSessions(user.ID, 0, time.Now())
+	if err != nil {
+		return err
+	}
 
-	// Delete the deletion request entry
-	err = as.deletionRepo.DeleteDeletionConfirmationByEmail(email)
+	// codes sent to the account, like a password reset, mustn't work for a new account with the same email
+	err = as.oneTimeTokenRepo.DeleteUserOneTimeTokens(user.Email)
 	if err != nil {
 		return err
 	}
 
+	as.emailService.GenericSendMail("Account Deleted", "Your account on  Mood App has been deleted.", user.Email, user.Name)
+
 	return nil
 }
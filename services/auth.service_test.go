@@ -2,10 +2,17 @@ package services
 
 import (
 	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/anirudhgray/mood-harbour-backend/mocks"
 	"github.com/anirudhgray/mood-harbour-backend/models"
+	"github.com/anirudhgray/mood-harbour-backend/utils/password"
+	"github.com/anirudhgray/mood-harbour-backend/utils/token"
+	"github.com/gin-gonic/gin"
 	"github.com/golang/mock/gomock"
 )
 
@@ -14,20 +21,30 @@ func TestAuthService_RequestVerificationAgain(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockUserRepo := mocks.NewMockUserRepository(ctrl)
-	mockVerificationRepo := mocks.NewMockVerificationEntryRepository(ctrl)
+	mockOneTimeTokenRepo := mocks.NewMockOneTimeTokenRepository(ctrl)
 	mockEmailService := mocks.NewMockEmailService(ctrl)
 
 	testEmail := "test@example.com"
 	testName := "Test User"
 	testUser := models.User{Email: testEmail, Verified: false, Name: testName, ProfileImage: "test"}
 
+	var stored models.OneTimeToken
 	mockUserRepo.EXPECT().GetUserByEmail(testEmail).Return(testUser, nil)
-	mockVerificationRepo.EXPECT().GetVerificationEntryByEmail(testEmail).Return(nil, errors.New("not found"))
-	mockEmailService.EXPECT().SendRegistrationMail("Account Verification.", "Please visit the following link to verify your account: ", testEmail, testName, true).Return(nil)
+	mockOneTimeTokenRepo.EXPECT().ReplaceOneTimeToken(gomock.Any()).DoAndReturn(func(oneTimeToken *models.OneTimeToken) error {
+		stored = *oneTimeToken
+		return nil
+	})
+	mockEmailService.EXPECT().SendRegistrationMail("Account Verification.", "Please visit the following link to verify your account: ", testEmail, testName, gomock.Any()).DoAndReturn(func(_, _, _, _, otp string) error {
+		// only the hash of the emailed code is stored
+		if stored.TokenHash == otp || stored.TokenHash != token.HashOTP(otp, testEmail, models.OneTimeTokenVerifyEmail) {
+			t.Errorf("Stored token %+v doesn't match the emailed code %s", stored, otp)
+		}
+		return nil
+	})
 
 	as := &AuthService{
 		userRepo:         mockUserRepo,
-		verificationRepo: mockVerificationRepo,
+		oneTimeTokenRepo: mockOneTimeTokenRepo,
 		emailService:     mockEmailService,
 	}
 
@@ -36,50 +53,87 @@ func TestAuthService_RequestVerificationAgain(t *testing.T) {
 	if err != nil {
 		t.Errorf("Unexpected error: %v", err)
 	}
+	if stored.Purpose != models.OneTimeTokenVerifyEmail || !stored.ExpiresAt.After(time.Now()) {
+		t.Errorf("Unexpected token %+v", stored)
+	}
 }
 
 func TestAuthService_VerifyEmail(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	mockVerificationRepo := mocks.NewMockVerificationEntryRepository(ctrl)
+	mockOneTimeTokenRepo := mocks.NewMockOneTimeTokenRepository(ctrl)
 	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	mockThrottleService := mocks.NewMockThrottleServiceInterface(ctrl)
 
 	testEmail := "test@example.com"
 	testOTP := "123456"
-	testVerificationEntry := models.VerificationEntry{Email: testEmail, OTP: testOTP}
+	client := models.ClientInfo{IP: "203.0.113.7"}
+	stored := models.OneTimeToken{Email: testEmail, Purpose: models.OneTimeTokenVerifyEmail, TokenHash: token.HashOTP(testOTP, testEmail, models.OneTimeTokenVerifyEmail), ExpiresAt: time.Now().Add(time.Hour)}
+	stored.ID = 4
+	usedAt := time.Now()
+	used := stored
+	used.UsedAt = &usedAt
+	expired := stored
+	expired.ExpiresAt = time.Now().Add(-time.Minute)
 
 	as := &AuthService{
-		verificationRepo: mockVerificationRepo,
+		oneTimeTokenRepo: mockOneTimeTokenRepo,
 		userRepo:         mockUserRepo,
+		throttleService:  mockThrottleService,
 	}
 
 	testCases := []struct {
 		name        string
 		otp         string
+		stored      models.OneTimeToken
+		attempt     bool // whether the code is compared, and if so whether the token had attempts left
 		expectError bool
 	}{
 		{
 			name:        "Correct OTP",
 			otp:         testOTP,
+			stored:      stored,
+			attempt:     true,
 			expectError: false,
 		},
 		{
 			name:        "Incorrect OTP",
 			otp:         "123458",
+			stored:      stored,
+			attempt:     true,
+			expectError: true,
+		},
+		{
+			name:        "Used OTP",
+			otp:         testOTP,
+			stored:      used,
+			expectError: true,
+		},
+		{
+			name:        "Expired OTP",
+			otp:         testOTP,
+			stored:      expired,
 			expectError: true,
 		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			mockVerificationRepo.EXPECT().GetVerificationEntryByEmail(testEmail).Return(&testVerificationEntry, nil)
+			mockThrottleService.EXPECT().Check(ThrottleVerify, testEmail, client.IP).Return(nil)
+			mockOneTimeTokenRepo.EXPECT().GetOneTimeToken(testEmail, models.OneTimeTokenVerifyEmail).Return(tc.stored, nil)
+			if tc.attempt {
+				mockOneTimeTokenRepo.EXPECT().RecordOneTimeTokenAttempt(stored.ID, gomock.Any()).Return(true, nil)
+			}
 			if !tc.expectError {
+				mockOneTimeTokenRepo.EXPECT().UseOneTimeToken(stored.ID, gomock.Any()).Return(true, nil)
+				mockThrottleService.EXPECT().Reset(ThrottleVerify, testEmail).Return(nil)
 				mockUserRepo.EXPECT().VerifyUserEmail(testEmail).Return(nil)
-				mockVerificationRepo.EXPECT().DeleteVerificationEntry(testEmail).Return(nil)
+			} else {
+				mockThrottleService.EXPECT().RecordFailure(ThrottleVerify, testEmail, client.IP).Return(nil)
 			}
 
-			err := as.VerifyEmail(testEmail, tc.otp)
+			err := as.VerifyEmail(testEmail, tc.otp, client)
 
 			if tc.expectError && err == nil {
 				t.Errorf("Expected error, got nil")
@@ -88,4 +142,344 @@ func TestAuthService_VerifyEmail(t *testing.T) {
 			}
 		})
 	}
+
+	t.Run("Out of attempts", func(t *testing.T) {
+		mockThrottleService.EXPECT().Check(ThrottleVerify, testEmail, client.IP).Return(nil)
+		mockOneTimeTokenRepo.EXPECT().GetOneTimeToken(testEmail, models.OneTimeTokenVerifyEmail).Return(stored, nil)
+		mockOneTimeTokenRepo.EXPECT().RecordOneTimeTokenAttempt(stored.ID, gomock.Any()).Return(false, nil)
+		mockThrottleService.EXPECT().RecordFailure(ThrottleVerify, testEmail, client.IP).Return(nil)
+
+		// even the right code doesn't work anymore
+		if err := as.VerifyEmail(testEmail, testOTP, client); err == nil {
+			t.Error("Expected an error for a token out of attempts")
+		}
+	})
+}
+
+func TestAuthService_VerifyEmail_Throttled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockThrottleService := mocks.NewMockThrottleServiceInterface(ctrl)
+	as := &AuthService{throttleService: mockThrottleService}
+
+	// the code isn't even looked at while locked out, so guessing it right doesn't help
+	mockThrottleService.EXPECT().Check(ThrottleVerify, "test@example.com", "203.0.113.7").Return(&ThrottledError{RetryAfter: time.Minute})
+	err := as.VerifyEmail("test@example.com", "123456", models.ClientInfo{IP: "203.0.113.7"})
+
+	var throttledErr *ThrottledError
+	if !errors.As(err, &throttledErr) || throttledErr.RetrySeconds() != 60 {
+		t.Errorf("Expected a ThrottledError, got %v", err)
+	}
+}
+
+func TestAuthService_RefreshTokens(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRefreshTokenRepo := mocks.NewMockRefreshTokenRepository(ctrl)
+	mockSessionRepo := mocks.NewMockSessionRepository(ctrl)
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+
+	as := &AuthService{
+		refreshTokenRepo: mockRefreshTokenRepo,
+		sessionRepo:      mockSessionRepo,
+		userRepo:         mockUserRepo,
+	}
+
+	refreshToken := "refresh-token"
+	tokenHash := token.HashOpaqueToken(refreshToken)
+	user := models.User{Email: "test@example.com", Verified: true}
+	user.ID = 7
+	past, future := time.Now().Add(-time.Hour), time.Now().Add(time.Hour)
+	client := models.ClientInfo{UserAgent: "test-agent", IP: "127.0.0.1"}
+
+	session := models.Session{UserID: user.ID, JTI: "jti", ExpiresAt: future}
+	session.ID = 3
+	revokedSession := session
+	revokedSession.RevokedAt = &past
+
+	t.Run("Rotates an unused token", func(t *testing.T) {
+		stored := models.RefreshToken{UserID: user.ID, SessionID: session.ID, TokenHash: tokenHash, ExpiresAt: future}
+		stored.ID = 1
+		mockRefreshTokenRepo.EXPECT().GetRefreshTokenByHash(tokenHash).Return(stored, nil)
+		mockSessionRepo.EXPECT().GetSessionByID(session.ID).Return(session, nil)
+		mockRefreshTokenRepo.EXPECT().MarkRefreshTokenUsed(uint(1), gomock.Any()).Return(true, nil)
+		mockUserRepo.EXPECT().GetUserByID(user.ID).Return(user, nil)
+		mockSessionRepo.EXPECT().ExtendSession(session.ID, client, gomock.Any(), gomock.Any()).Return(nil)
+
+		var created *models.RefreshToken
+		mockRefreshTokenRepo.EXPECT().CreateRefreshToken(gomock.Any()).DoAndReturn(func(rt *models.RefreshToken) error {
+			created = rt
+			return nil
+		})
+
+		tokens, err := as.RefreshTokens(refreshToken, client)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if tokens.AccessToken == "" || tokens.RefreshToken == "" || tokens.RefreshToken == refreshToken {
+			t.Errorf("Expected a new access token and refresh token, got %+v", tokens)
+		}
+		if created.SessionID != session.ID || created.UserID != user.ID || created.TokenHash != token.HashOpaqueToken(tokens.RefreshToken) {
+			t.Errorf("New refresh token wasn't stored in the same session: %+v", created)
+		}
+	})
+
+	t.Run("Revokes the session when a used token is replayed", func(t *testing.T) {
+		stored := models.RefreshToken{UserID: user.ID, SessionID: session.ID, TokenHash: tokenHash, ExpiresAt: future, UsedAt: &past}
+		stored.ID = 1
+		mockRefreshTokenRepo.EXPECT().GetRefreshTokenByHash(tokenHash).Return(stored, nil)
+		mockSessionRepo.EXPECT().GetSessionByID(session.ID).Return(session, nil)
+		mockSessionRepo.EXPECT().RevokeSession(user.ID, session.ID, gomock.Any()).Return(nil)
+
+		if _, err := as.RefreshTokens(refreshToken, client); err == nil {
+			t.Error("Expected an error for a reused refresh token")
+		}
+	})
+
+	t.Run("Revokes the session when a concurrent request used the token first", func(t *testing.T) {
+		stored := models.RefreshToken{UserID: user.ID, SessionID: session.ID, TokenHash: tokenHash, ExpiresAt: future}
+		stored.ID = 1
+		mockRefreshTokenRepo.EXPECT().GetRefreshTokenByHash(tokenHash).Return(stored, nil)
+		mockSessionRepo.EXPECT().GetSessionByID(session.ID).Return(session, nil)
+		mockRefreshTokenRepo.EXPECT().MarkRefreshTokenUsed(uint(1), gomock.Any()).Return(false, nil)
+		mockSessionRepo.EXPECT().RevokeSession(user.ID, session.ID, gomock.Any()).Return(nil)
+
+		if _, err := as.RefreshTokens(refreshToken, client); err == nil {
+			t.Error("Expected an error for a reused refresh token")
+		}
+	})
+
+	t.Run("Rejects expired tokens and tokens of revoked sessions", func(t *testing.T) {
+		expired := models.RefreshToken{UserID: user.ID, SessionID: session.ID, TokenHash: tokenHash, ExpiresAt: past}
+		mockRefreshTokenRepo.EXPECT().GetRefreshTokenByHash(tokenHash).Return(expired, nil)
+		mockSessionRepo.EXPECT().GetSessionByID(session.ID).Return(session, nil)
+		if _, err := as.RefreshTokens(refreshToken, client); err == nil {
+			t.Error("Expected an error for an expired refresh token")
+		}
+
+		valid := models.RefreshToken{UserID: user.ID, SessionID: session.ID, TokenHash: tokenHash, ExpiresAt: future}
+		mockRefreshTokenRepo.EXPECT().GetRefreshTokenByHash(tokenHash).Return(valid, nil)
+		mockSessionRepo.EXPECT().GetSessionByID(session.ID).Return(revokedSession, nil)
+		if _, err := as.RefreshTokens(refreshToken, client); err == nil {
+			t.Error("Expected an error for a refresh token of a revoked session")
+		}
+	})
+
+	t.Run("Rejects unknown tokens", func(t *testing.T) {
+		mockRefreshTokenRepo.EXPECT().GetRefreshTokenByHash(tokenHash).Return(models.RefreshToken{}, errors.New("record not found"))
+		if _, err := as.RefreshTokens(refreshToken, client); err == nil {
+			t.Error("Expected an error for an unknown refresh token")
+		}
+	})
+}
+
+func TestAuthService_SocialLogin(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRefreshTokenRepo := mocks.NewMockRefreshTokenRepository(ctrl)
+	mockSessionRepo := mocks.NewMockSessionRepository(ctrl)
+	mockMFARepo := mocks.NewMockMFARepository(ctrl)
+
+	as := &AuthService{
+		refreshTokenRepo: mockRefreshTokenRepo,
+		sessionRepo:      mockSessionRepo,
+		mfaRepo:          mockMFARepo,
+	}
+
+	user := models.User{Email: "test@example.com", Verified: true}
+	user.ID = 7
+	client := models.ClientInfo{UserAgent: "test-agent", IP: "127.0.0.1"}
+
+	var session *models.Session
+	mockMFARepo.EXPECT().GetTOTPFactor(user.ID).Return(models.TOTPFactor{}, nil)
+	mockSessionRepo.EXPECT().CreateSession(gomock.Any()).DoAndReturn(func(s *models.Session) error {
+		s.ID = 3
+		session = s
+		return nil
+	})
+	mockRefreshTokenRepo.EXPECT().CreateRefreshToken(gomock.Any()).Return(nil)
+
+	result, err := as.SocialLogin(user, client)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.MFARequired {
+		t.Error("Expected no MFA challenge for a user without two-factor authentication")
+	}
+	tokens := result.Tokens
+	if session.UserID != user.ID || session.JTI == "" || session.UserAgent != client.UserAgent || session.IP != client.IP {
+		t.Errorf("Session wasn't stored for the user's device: %+v", session)
+	}
+
+	// the access token belongs to the session
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	c.Request.Header.Set("Authorization", "Bearer "+tokens.AccessToken)
+	userID, jti, err := token.ValidateToken(c)
+	if err != nil || userID != user.ID || jti != session.JTI {
+		t.Errorf("ValidateToken = %d, %q, %v, expected %d, %q", userID, jti, err, user.ID, session.JTI)
+	}
+}
+
+func TestAuthService_ResetPassword_RevokesOtherSessions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPasswordAuthRepo := mocks.NewMockPasswordAuthRepository(ctrl)
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	mockSessionRepo := mocks.NewMockSessionRepository(ctrl)
+	mockEmailService := mocks.NewMockEmailService(ctrl)
+
+	as := &AuthService{
+		passwordAuthRepo: mockPasswordAuthRepo,
+		userRepo:         mockUserRepo,
+		sessionRepo:      mockSessionRepo,
+		emailService:     mockEmailService,
+	}
+
+	user := models.User{Email: "test@example.com", Name: "Test User"}
+	user.ID = 7
+	pwdAuth := models.PasswordAuth{Email: user.Email, Password: "OldPass1!"}
+	if err := pwdAuth.HashPassword(); err != nil {
+		t.Fatalf("Failed to hash password: %v", err)
+	}
+
+	mockPasswordAuthRepo.EXPECT().GetPwdAuthItemByEmail(user.Email).Return(pwdAuth, nil)
+	mockUserRepo.EXPECT().SaveUser(user).Return(nil)
+	// the new hash is salted, so match any
+	ctrl.RecordCall(mockPasswordAuthRepo, "UpdatePwdAuthItem", gomock.Any()).Return(nil)
+	mockSessionRepo.EXPECT().RevokeUserSessions(user.ID, uint(3), gomock.Any()).Return(nil)
+	mockEmailService.EXPECT().GenericSendMail("Password Reset Successfully", "Your password was changed. Secure your account if this was not you.", user.Email, user.Name).Return(nil)
+
+	if err := as.ResetPassword(user, 3, "OldPass1!", "NewPass1!"); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestAuthService_ExchangeSocialLoginCode(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSessionRepo := mocks.NewMockSessionRepository(ctrl)
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	mockMFARepo := mocks.NewMockMFARepository(ctrl)
+	mockRefreshTokenRepo := mocks.NewMockRefreshTokenRepository(ctrl)
+	as := &AuthService{sessionRepo: mockSessionRepo, userRepo: mockUserRepo, mfaRepo: mockMFARepo, refreshTokenRepo: mockRefreshTokenRepo}
+
+	user := models.User{Email: "test@example.com", Verified: true}
+	user.ID = 7
+
+	var stored models.SocialLoginCode
+	mockSessionRepo.EXPECT().CreateSocialLoginCode(gomock.Any()).DoAndReturn(func(code *models.SocialLoginCode) error {
+		stored = *code
+		stored.ID = 3
+		return nil
+	})
+	code, err := as.IssueSocialLoginCode(user)
+	if err != nil {
+		t.Fatalf("IssueSocialLoginCode returned an error: %v", err)
+	}
+	if stored.UserID != user.ID || stored.CodeHash != token.HashOpaqueToken(code) || time.Until(stored.ExpiresAt) > SocialLoginCodeLifespan {
+		t.Errorf("Code wasn't stored for the user: %+v", stored)
+	}
+
+	t.Run("Logs in once", func(t *testing.T) {
+		mockSessionRepo.EXPECT().GetSocialLoginCodeByHash(stored.CodeHash).Return(stored, nil)
+		mockSessionRepo.EXPECT().UseSocialLoginCode(stored.ID, gomock.Any()).Return(true, nil)
+		mockUserRepo.EXPECT().GetUserByID(user.ID).Return(user, nil)
+		mockMFARepo.EXPECT().GetTOTPFactor(user.ID).Return(models.TOTPFactor{}, nil)
+		mockSessionRepo.EXPECT().CreateSession(gomock.Any()).Return(nil)
+		mockRefreshTokenRepo.EXPECT().CreateRefreshToken(gomock.Any()).Return(nil)
+
+		result, err := as.ExchangeSocialLoginCode(code, models.ClientInfo{})
+		if err != nil || result.Tokens.AccessToken == "" {
+			t.Errorf("ExchangeSocialLoginCode = %+v, %v; expected tokens", result, err)
+		}
+	})
+
+	t.Run("Rejects a code exchanged concurrently", func(t *testing.T) {
+		mockSessionRepo.EXPECT().GetSocialLoginCodeByHash(stored.CodeHash).Return(stored, nil)
+		mockSessionRepo.EXPECT().UseSocialLoginCode(stored.ID, gomock.Any()).Return(false, nil)
+
+		if _, err := as.ExchangeSocialLoginCode(code, models.ClientInfo{}); err == nil {
+			t.Error("Expected an error for a used code")
+		}
+	})
+
+	t.Run("Rejects expired codes", func(t *testing.T) {
+		expired := stored
+		expired.ExpiresAt = time.Now().Add(-time.Second)
+		mockSessionRepo.EXPECT().GetSocialLoginCodeByHash(stored.CodeHash).Return(expired, nil)
+
+		if _, err := as.ExchangeSocialLoginCode(code, models.ClientInfo{}); err == nil {
+			t.Error("Expected an error for an expired code")
+		}
+	})
+}
+
+func TestAuthService_SocialLogin_Disabled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// no session is created for a disabled user
+	as := &AuthService{sessionRepo: mocks.NewMockSessionRepository(ctrl), mfaRepo: mocks.NewMockMFARepository(ctrl)}
+
+	user := models.User{Email: "test@example.com", Verified: true, Disabled: true}
+	if _, err := as.SocialLogin(user, models.ClientInfo{}); err == nil {
+		t.Error("Expected an error for a disabled user")
+	}
+}
+
+func TestAuthService_SetNewPassword_WeakPassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// no mocks are expected to be called, the code isn't used up by a password which would be rejected anyway
+	as := &AuthService{
+		oneTimeTokenRepo: mocks.NewMockOneTimeTokenRepository(ctrl),
+		throttleService:  mocks.NewMockThrottleServiceInterface(ctrl),
+		passwordPolicy:   password.Policy{MinLength: 8, RequireNumber: true, DisallowPersonalInfo: true},
+	}
+
+	var policyErr *password.PolicyError
+	err := as.SetNewPassword("test@example.com", "123456", "test-weak", models.ClientInfo{})
+	if !errors.As(err, &policyErr) {
+		t.Fatalf("Expected a PolicyError for a weak password, got %v", err)
+	}
+	codes := []string{}
+	for _, violation := range policyErr.Violations {
+		codes = append(codes, violation.Code)
+	}
+	if expected := []string{password.ViolationMissingNumber, password.ViolationContainsPersonalInfo}; !reflect.DeepEqual(codes, expected) {
+		t.Errorf("Violations %v; expected %v", codes, expected)
+	}
+}
+
+func TestAuthService_RegisterUser_ExistingEmail(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	mockPasswordAuthRepo := mocks.NewMockPasswordAuthRepository(ctrl)
+	mockEmailService := mocks.NewMockEmailService(ctrl)
+	as := &AuthService{
+		userRepo:         mockUserRepo,
+		passwordAuthRepo: mockPasswordAuthRepo,
+		emailService:     mockEmailService,
+		passwordPolicy:   password.Policy{MinLength: 8},
+	}
+
+	user := models.User{Email: "test@example.com", Name: "Test User"}
+	user.ID = 7
+	mockUserRepo.EXPECT().GetUserByEmail(user.Email).Return(user, nil)
+	mockPasswordAuthRepo.EXPECT().GetPwdAuthItemByEmail(user.Email).Return(models.PasswordAuth{Email: user.Email, UserID: user.ID}, nil)
+	// the owner is told instead of the client, and nothing is created
+	ctrl.RecordCall(mockEmailService, "GenericSendMail", "Account Alert", gomock.Any(), user.Email, user.Name).Return(nil)
+
+	if _, err := as.RegisterUser(user.Email, "Someone Else", "", "long enough password"); err != nil {
+		t.Errorf("Expected no error for an existing email, got %v", err)
+	}
 }
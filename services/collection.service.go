@@ -0,0 +1,361 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/anirudhgray/mood-harbour-backend/infra/logger"
+	"github.com/anirudhgray/mood-harbour-backend/models"
+	"github.com/anirudhgray/mood-harbour-backend/repository"
+)
+
+type CollectionService struct {
+	collectionRepo        repository.CollectionRepositoryInterface
+	resourceRepo          repository.ResourceRepositoryInterface
+	recommendationService RecommendationServiceInterface
+}
+
+func NewCollectionService(collectionRepo repository.CollectionRepositoryInterface, resourceRepo repository.ResourceRepositoryInterface, recommendationService RecommendationServiceInterface) *CollectionService {
+	return &CollectionService{collectionRepo, resourceRepo, recommendationService}
+}
+
+type CollectionServiceInterface interface {
+	BookmarkResource(userID, resourceID uint) error
+	RemoveBookmark(userID, resourceID uint) error
+	GetBookmarkedResources(userID uint) ([]models.Resource, error)
+	CreateCollection(userID uint, name, description string, public bool) (models.CollectionResponse, error)
+	GetUserCollections(userID uint) ([]models.CollectionResponse, error)
+	GetPublicCollections() ([]models.CollectionResponse, error)
+	GetFollowedCollections(userID uint) ([]models.CollectionResponse, error)
+	GetCollection(userID, collectionID uint) (models.CollectionResponse, error)
+	UpdateCollection(userID, collectionID uint, name, description string, public bool) (models.CollectionResponse, error)
+	DeleteCollection(userID, collectionID uint) error
+	AddToCollection(userID, collectionID, resourceID uint) error
+	RemoveFromCollection(userID, collectionID, resourceID uint) error
+	ReorderCollection(userID, collectionID uint, resourceIDs []uint) error
+	FollowCollection(userID, collectionID uint) error
+	UnfollowCollection(userID, collectionID uint) error
+}
+
+// BookmarkResource saves a published resource to the user's bookmarks.
+func (cs *CollectionService) BookmarkResource(userID, resourceID uint) error {
+	if err := cs.checkResourcePublished(resourceID); err != nil {
+		return err
+	}
+
+	bookmark := models.Bookmark{UserID: userID, ResourceID: resourceID}
+	if err := cs.collectionRepo.CreateBookmark(&bookmark); err != nil {
+		return err
+	}
+
+	cs.markRecommendationsStale(userID, resourceID)
+	return nil
+}
+
+// RemoveBookmark removes a resource from the user's bookmarks.
+func (cs *CollectionService) RemoveBookmark(userID, resourceID uint) error {
+	if err := cs.collectionRepo.DeleteBookmark(userID, resourceID); err != nil {
+		return err
+	}
+
+	cs.markRecommendationsStale(userID, resourceID)
+	return nil
+}
+
+// markRecommendationsStale queues recommendations for recomputation, since bookmarks count as implicit likes.
+func (cs *CollectionService) markRecommendationsStale(userID, resourceID uint) {
+	if err := cs.recommendationService.MarkInteractionStale(userID, resourceID); err != nil {
+		logger.Errorf("Failed to mark recommendations stale after bookmark change: %v", err)
+	}
+}
+
+// GetBookmarkedResources gets all the resources bookmarked by the user, most recently bookmarked first.
+func (cs *CollectionService) GetBookmarkedResources(userID uint) ([]models.Resource, error) {
+	bookmarks, err := cs.collectionRepo.GetBookmarksByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	resourceIDs := make([]uint, 0, len(bookmarks))
+	for _, bookmark := range bookmarks {
+		resourceIDs = append(resourceIDs, bookmark.ResourceID)
+	}
+
+	return cs.getOrderedResources(resourceIDs)
+}
+
+// CreateCollection creates a new, empty collection for the user.
+func (cs *CollectionService) CreateCollection(userID uint, name, description string, public bool) (models.CollectionResponse, error) {
+	if name == "" {
+		return models.CollectionResponse{}, errors.New("collection name cannot be empty")
+	}
+
+	collection := models.Collection{
+		UserID:      userID,
+		Name:        name,
+		Description: description,
+		Public:      public,
+	}
+
+	err := cs.collectionRepo.CreateCollection(&collection)
+	if err != nil {
+		return models.CollectionResponse{}, err
+	}
+
+	return cs.buildCollectionResponse(collection)
+}
+
+// GetUserCollections gets all the collections created by the user.
+func (cs *CollectionService) GetUserCollections(userID uint) ([]models.CollectionResponse, error) {
+	collections, err := cs.collectionRepo.GetCollectionsByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	return cs.buildCollectionResponses(collections)
+}
+
+// GetPublicCollections gets all the public collections.
+func (cs *CollectionService) GetPublicCollections() ([]models.CollectionResponse, error) {
+	collections, err := cs.collectionRepo.GetPublicCollections()
+	if err != nil {
+		return nil, err
+	}
+	return cs.buildCollectionResponses(collections)
+}
+
+// GetFollowedCollections gets all the public collections the user follows.
+func (cs *CollectionService) GetFollowedCollections(userID uint) ([]models.CollectionResponse, error) {
+	collections, err := cs.collectionRepo.GetFollowedCollections(userID)
+	if err != nil {
+		return nil, err
+	}
+	return cs.buildCollectionResponses(collections)
+}
+
+// GetCollection gets a single collection. Private collections can only be viewed by their owner.
+func (cs *CollectionService) GetCollection(userID, collectionID uint) (models.CollectionResponse, error) {
+	collection, err := cs.collectionRepo.GetCollectionByID(collectionID)
+	if err != nil {
+		return models.CollectionResponse{}, err
+	}
+
+	if !collection.Public && collection.UserID != userID {
+		return models.CollectionResponse{}, errors.New("collection not found")
+	}
+
+	return cs.buildCollectionResponse(collection)
+}
+
+// UpdateCollection updates the name, description and visibility of a collection owned by the user.
+func (cs *CollectionService) UpdateCollection(userID, collectionID uint, name, description string, public bool) (models.CollectionResponse, error) {
+	if name == "" {
+		return models.CollectionResponse{}, errors.New("collection name cannot be empty")
+	}
+
+	collection, err := cs.getOwnedCollection(userID, collectionID)
+	if err != nil {
+		return models.CollectionResponse{}, err
+	}
+
+	collection.Name = name
+	collection.Description = description
+	collection.Public = public
+
+	err = cs.collectionRepo.UpdateCollection(&collection)
+	if err != nil {
+		return models.CollectionResponse{}, err
+	}
+
+	return cs.buildCollectionResponse(collection)
+}
+
+// DeleteCollection deletes a collection owned by the user.
+func (cs *CollectionService) DeleteCollection(userID, collectionID uint) error {
+	if _, err := cs.getOwnedCollection(userID, collectionID); err != nil {
+		return err
+	}
+
+	return cs.collectionRepo.DeleteCollection(collectionID)
+}
+
+// AddToCollection adds a published resource to the end of a collection owned by the user.
+func (cs *CollectionService) AddToCollection(userID, collectionID, resourceID uint) error {
+	if _, err := cs.getOwnedCollection(userID, collectionID); err != nil {
+		return err
+	}
+
+	if err := cs.checkResourcePublished(resourceID); err != nil {
+		return err
+	}
+
+	items, err := cs.collectionRepo.GetCollectionItems(collectionID)
+	if err != nil {
+		return err
+	}
+
+	position := 0
+	for _, item := range items {
+		if item.ResourceID == resourceID {
+			return errors.New("resource is already in this collection")
+		}
+		if item.Position >= position {
+			position = item.Position + 1
+		}
+	}
+
+	item := models.CollectionItem{
+		CollectionID: collectionID,
+		ResourceID:   resourceID,
+		Position:     position,
+	}
+
+	return cs.collectionRepo.AddCollectionItem(&item)
+}
+
+// RemoveFromCollection removes a resource from a collection owned by the user.
+func (cs *CollectionService) RemoveFromCollection(userID, collectionID, resourceID uint) error {
+	if _, err := cs.getOwnedCollection(userID, collectionID); err != nil {
+		return err
+	}
+
+	return cs.collectionRepo.RemoveCollectionItem(collectionID, resourceID)
+}
+
+// ReorderCollection reorders the resources in a collection owned by the user.
+// resourceIDs must contain every resource in the collection exactly once, in the new order.
+func (cs *CollectionService) ReorderCollection(userID, collectionID uint, resourceIDs []uint) error {
+	if _, err := cs.getOwnedCollection(userID, collectionID); err != nil {
+		return err
+	}
+
+	items, err := cs.collectionRepo.GetCollectionItems(collectionID)
+	if err != nil {
+		return err
+	}
+
+	if len(items) != len(resourceIDs) {
+		return errors.New("new order must include every resource in the collection exactly once")
+	}
+
+	inCollection := make(map[uint]bool)
+	for _, item := range items {
+		inCollection[item.ResourceID] = true
+	}
+	for _, resourceID := range resourceIDs {
+		if !inCollection[resourceID] {
+			return errors.New("new order must include every resource in the collection exactly once")
+		}
+		// guard against duplicates in the new order
+		delete(inCollection, resourceID)
+	}
+
+	return cs.collectionRepo.ReorderCollectionItems(collectionID, resourceIDs)
+}
+
+// FollowCollection makes the user follow another user's public collection.
+func (cs *CollectionService) FollowCollection(userID, collectionID uint) error {
+	collection, err := cs.collectionRepo.GetCollectionByID(collectionID)
+	if err != nil {
+		return err
+	}
+
+	if !collection.Public {
+		return errors.New("collection not found")
+	}
+	if collection.UserID == userID {
+		return errors.New("you cannot follow your own collection")
+	}
+
+	follow := models.CollectionFollow{CollectionID: collectionID, UserID: userID}
+	return cs.collectionRepo.FollowCollection(&follow)
+}
+
+// UnfollowCollection makes the user stop following a collection.
+func (cs *CollectionService) UnfollowCollection(userID, collectionID uint) error {
+	return cs.collectionRepo.UnfollowCollection(collectionID, userID)
+}
+
+func (cs *CollectionService) getOwnedCollection(userID, collectionID uint) (models.Collection, error) {
+	collection, err := cs.collectionRepo.GetCollectionByID(collectionID)
+	if err != nil {
+		return models.Collection{}, err
+	}
+
+	if collection.UserID != userID {
+		return models.Collection{}, errors.New("unauthorized")
+	}
+
+	return collection, nil
+}
+
+func (cs *CollectionService) checkResourcePublished(resourceID uint) error {
+	resource, err := cs.resourceRepo.GetResourceByID(resourceID)
+	if err != nil {
+		return err
+	}
+	if resource.Status != models.Published {
+		return errors.New("resource not found")
+	}
+	return nil
+}
+
+func (cs *CollectionService) buildCollectionResponses(collections []models.Collection) ([]models.CollectionResponse, error) {
+	collectionResponses := make([]models.CollectionResponse, 0, len(collections))
+	for _, collection := range collections {
+		collectionResponse, err := cs.buildCollectionResponse(collection)
+		if err != nil {
+			return nil, err
+		}
+		collectionResponses = append(collectionResponses, collectionResponse)
+	}
+	return collectionResponses, nil
+}
+
+func (cs *CollectionService) buildCollectionResponse(collection models.Collection) (models.CollectionResponse, error) {
+	items, err := cs.collectionRepo.GetCollectionItems(collection.ID)
+	if err != nil {
+		return models.CollectionResponse{}, err
+	}
+
+	resourceIDs := make([]uint, 0, len(items))
+	for _, item := range items {
+		resourceIDs = append(resourceIDs, item.ResourceID)
+	}
+
+	resources, err := cs.getOrderedResources(resourceIDs)
+	if err != nil {
+		return models.CollectionResponse{}, err
+	}
+
+	followers, err := cs.collectionRepo.CountCollectionFollowers(collection.ID)
+	if err != nil {
+		return models.CollectionResponse{}, err
+	}
+
+	return models.CollectionResponse{
+		ID:         collection.ID,
+		Collection: collection,
+		Resources:  resources,
+		Followers:  followers,
+	}, nil
+}
+
+// getOrderedResources gets the published resources with the given IDs, in the same order as the IDs.
+func (cs *CollectionService) getOrderedResources(resourceIDs []uint) ([]models.Resource, error) {
+	resources, err := cs.resourceRepo.GetResourcesByIDs(resourceIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	resourcesByID := make(map[uint]models.Resource)
+	for _, resource := range resources {
+		resourcesByID[resource.ID] = resource
+	}
+
+	orderedResources := make([]models.Resource, 0, len(resources))
+	for _, resourceID := range resourceIDs {
+		if resource, ok := resourcesByID[resourceID]; ok {
+			orderedResources = append(orderedResources, resource)
+		}
+	}
+	return orderedResources, nil
+}
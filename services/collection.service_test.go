@@ -0,0 +1,120 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/anirudhgray/mood-harbour-backend/mocks"
+	"github.com/anirudhgray/mood-harbour-backend/models"
+	"github.com/golang/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func TestCollectionService_ReorderCollection(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCollectionRepo := mocks.NewMockCollectionRepositoryInterface(ctrl)
+	cs := NewCollectionService(mockCollectionRepo, mocks.NewMockResourceRepositoryInterface(ctrl), mocks.NewMockRecommendationServiceInterface(ctrl))
+	ownerID := uint(1)
+	collectionID := uint(1)
+
+	collection := models.Collection{Model: gorm.Model{ID: collectionID}, UserID: ownerID, Name: "Panic toolkit"}
+	items := []models.CollectionItem{
+		{CollectionID: collectionID, ResourceID: 10, Position: 0},
+		{CollectionID: collectionID, ResourceID: 20, Position: 1},
+		{CollectionID: collectionID, ResourceID: 30, Position: 2},
+	}
+
+	mockCollectionRepo.EXPECT().GetCollectionByID(collectionID).Return(collection, nil).AnyTimes()
+	mockCollectionRepo.EXPECT().GetCollectionItems(collectionID).Return(items, nil).AnyTimes()
+	mockCollectionRepo.EXPECT().ReorderCollectionItems(collectionID, []uint{30, 10, 20}).Return(nil).Times(1)
+
+	testCases := []struct {
+		name        string
+		userID      uint
+		resourceIDs []uint
+		expectError bool
+	}{
+		{"valid order", ownerID, []uint{30, 10, 20}, false},
+		{"not the owner", 2, []uint{30, 10, 20}, true},
+		{"missing resource", ownerID, []uint{30, 10}, true},
+		{"unknown resource", ownerID, []uint{30, 10, 40}, true},
+		{"duplicate resource", ownerID, []uint{30, 10, 10}, true},
+	}
+
+	for _, tc := range testCases {
+		err := cs.ReorderCollection(tc.userID, collectionID, tc.resourceIDs)
+		if tc.expectError && err == nil {
+			t.Errorf("%s: expected an error, got nil", tc.name)
+		}
+		if !tc.expectError && err != nil {
+			t.Errorf("%s: unexpected error: %v", tc.name, err)
+		}
+	}
+}
+
+func TestCollectionService_GetCollection_PrivateCollection(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCollectionRepo := mocks.NewMockCollectionRepositoryInterface(ctrl)
+	mockResourceRepo := mocks.NewMockResourceRepositoryInterface(ctrl)
+	cs := NewCollectionService(mockCollectionRepo, mockResourceRepo, mocks.NewMockRecommendationServiceInterface(ctrl))
+	ownerID := uint(1)
+	collectionID := uint(1)
+
+	collection := models.Collection{Model: gorm.Model{ID: collectionID}, UserID: ownerID, Name: "Private", Public: false}
+	items := []models.CollectionItem{
+		{CollectionID: collectionID, ResourceID: 20, Position: 0},
+		{CollectionID: collectionID, ResourceID: 10, Position: 1},
+	}
+	resources := []models.Resource{
+		{Model: gorm.Model{ID: 10}, Title: "Breathing exercises"},
+		{Model: gorm.Model{ID: 20}, Title: "Grounding techniques"},
+	}
+
+	mockCollectionRepo.EXPECT().GetCollectionByID(collectionID).Return(collection, nil).Times(2)
+	mockCollectionRepo.EXPECT().GetCollectionItems(collectionID).Return(items, nil).Times(1)
+	mockCollectionRepo.EXPECT().CountCollectionFollowers(collectionID).Return(int64(0), nil).Times(1)
+	mockResourceRepo.EXPECT().GetResourcesByIDs([]uint{20, 10}).Return(resources, nil).Times(1)
+
+	// other users cannot see a private collection
+	if _, err := cs.GetCollection(2, collectionID); err == nil {
+		t.Errorf("Expected an error when fetching another user's private collection, got nil")
+	}
+
+	collectionResponse, err := cs.GetCollection(ownerID, collectionID)
+	if err != nil {
+		t.Fatalf("GetCollection returned an error: %v", err)
+	}
+
+	// resources are returned in collection order, not in the order the repository returned them
+	if len(collectionResponse.Resources) != 2 || collectionResponse.Resources[0].ID != 20 || collectionResponse.Resources[1].ID != 10 {
+		t.Errorf("GetCollection returned resources in the wrong order: %v", collectionResponse.Resources)
+	}
+}
+
+func TestCollectionService_FollowCollection(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCollectionRepo := mocks.NewMockCollectionRepositoryInterface(ctrl)
+	cs := NewCollectionService(mockCollectionRepo, mocks.NewMockResourceRepositoryInterface(ctrl), mocks.NewMockRecommendationServiceInterface(ctrl))
+
+	publicCollection := models.Collection{Model: gorm.Model{ID: 1}, UserID: 1, Public: true}
+	privateCollection := models.Collection{Model: gorm.Model{ID: 2}, UserID: 1, Public: false}
+
+	mockCollectionRepo.EXPECT().GetCollectionByID(uint(1)).Return(publicCollection, nil).AnyTimes()
+	mockCollectionRepo.EXPECT().GetCollectionByID(uint(2)).Return(privateCollection, nil).AnyTimes()
+	mockCollectionRepo.EXPECT().FollowCollection(&models.CollectionFollow{CollectionID: 1, UserID: 2}).Return(nil).Times(1)
+
+	if err := cs.FollowCollection(2, 1); err != nil {
+		t.Errorf("FollowCollection returned an error: %v", err)
+	}
+	if err := cs.FollowCollection(1, 1); err == nil {
+		t.Errorf("Expected an error when following your own collection, got nil")
+	}
+	if err := cs.FollowCollection(2, 2); err == nil {
+		t.Errorf("Expected an error when following a private collection, got nil")
+	}
+}
@@ -2,16 +2,13 @@ package services
 
 import (
 	"bytes"
-	"crypto/rand"
 	"encoding/json"
 	"fmt"
-	"math"
-	"math/big"
 	"net/http"
+	"net/url"
 	"time"
 
 	"github.com/anirudhgray/mood-harbour-backend/infra/logger"
-	"github.com/anirudhgray/mood-harbour-backend/models"
 	"github.com/anirudhgray/mood-harbour-backend/repository"
 	"github.com/spf13/viper"
 )
@@ -25,17 +22,6 @@ func NewEmailService(userRepo repository.UserRepositoryInterface) *EmailService
 	return &EmailService{}
 }
 
-func GenerateOTP(maxDigits uint32) string {
-	bi, err := rand.Int(
-		rand.Reader,
-		big.NewInt(int64(math.Pow(10, float64(maxDigits)))),
-	)
-	if err != nil {
-		panic(err)
-	}
-	return fmt.Sprintf("%0*d", maxDigits, bi)
-}
-
 type EmailAddress struct {
 	Email string `json:"email"`
 	Name  string `json:"name"`
@@ -51,32 +37,18 @@ type GenericEmail struct {
 
 type EmailServiceInterface interface {
 	GenericSendMail(subject string, content string, toEmail string, userName string) error
-	SendRegistrationMail(subject string, content string, toEmail string, userName string, newUser bool) error
-	SendForgotPasswordMail(toEmail string, userName string) error
-	SendDeletionMail(toEmail string, userName string) error
+	SendRegistrationMail(subject string, content string, toEmail string, userName string, otp string) error
+	SendForgotPasswordMail(toEmail string, userName string, otp string, validFor time.Duration) error
+	SendDeletionMail(toEmail string, userName string, otp string, validFor time.Duration) error
 }
 
-func (es *EmailService) SendRegistrationMail(subject string, content string, toEmail string, userName string, newUser bool) error {
-	otp := ""
-	if newUser {
-		otp = GenerateOTP(6)
-		content += "http://bookstore.anrdhmshr.tech/verify?email=" + toEmail + "&otp=" + otp
+// SendRegistrationMail sends a mail with a link to verify the email with the code, or just the content without a code.
+func (es *EmailService) SendRegistrationMail(subject string, content string, toEmail string, userName string, otp string) error {
+	if otp != "" {
+		content += "http://bookstore.anrdhmshr.tech/verify?email=" + url.QueryEscape(toEmail) + "&otp=" + otp
 	}
 
-	err := es.GenericSendMail(subject, content, toEmail, userName)
-	if err != nil {
-		return err
-	}
-
-	if newUser {
-		entry := models.VerificationEntry{
-			Email: toEmail,
-			OTP:   otp,
-		}
-		verifRepo := repository.NewVerificationEntryRepository()
-		verifRepo.CreateVerificationEntry(entry)
-	}
-	return nil
+	return es.GenericSendMail(subject, content, toEmail, userName)
 }
 
 // GenericSendMail sends a generic email.
@@ -128,48 +100,20 @@ func (es *EmailService) GenericSendMail(subject string, content string, toEmail
 	return nil
 }
 
-func (es *EmailService) SendForgotPasswordMail(toEmail string, userName string) error {
-	otp := ""
-	otp = GenerateOTP(6)
-	verificationURL := ""
-	verificationURL += "http://bookstore.anrdhmshr.tech/set-forgotten-password?email=" + toEmail + "&otp=" + otp
-	content := "A forgot password request was made for the email associated with your account. If this was not you, feel free to ignore this email. Otherwise, click on this link to post your new password: " + verificationURL + " . This link will be active for 3 minutes."
+// SendForgotPasswordMail sends a mail with a link to set a new password with the code.
+func (es *EmailService) SendForgotPasswordMail(toEmail string, userName string, otp string, validFor time.Duration) error {
+	verificationURL := "http://bookstore.anrdhmshr.tech/set-forgotten-password?email=" + url.QueryEscape(toEmail) + "&otp=" + otp
+	content := "A forgot password request was made for the email associated with your account. If this was not you, feel free to ignore this email. Otherwise, click on this link to post your new password: " + verificationURL + fmt.Sprintf(" . This link will be active for %d minutes.", int(validFor.Minutes()))
 	subject := "Forgot Password."
 
-	err := es.GenericSendMail(subject, content, toEmail, userName)
-	if err != nil {
-		return err
-	}
-
-	entry := models.ForgotPassword{
-		Email:     toEmail,
-		OTP:       otp,
-		ValidTill: time.Now().Add(3 * time.Minute),
-	}
-	forgotRepo := repository.NewForgotPasswordRepository()
-	forgotRepo.CreateForgotPassword(entry)
-	return nil
+	return es.GenericSendMail(subject, content, toEmail, userName)
 }
 
-func (es EmailService) SendDeletionMail(toEmail string, userName string) error {
-	otp := ""
-	otp = GenerateOTP(6)
-	confirmationURL := ""
-	confirmationURL += "http://bookstore.anrdhmshr.tech/delete-account?email=" + toEmail + "&otp=" + otp
-	content := "A request for the deletion of the bookstore account associated with your user has been made. If this was not you, please change your password. Otherwise, click on this link to confirm account deletion: " + confirmationURL + " . This link will be active for 3 minutes."
+// SendDeletionMail sends a mail with a link to confirm deleting the account with the code.
+func (es *EmailService) SendDeletionMail(toEmail string, userName string, otp string, validFor time.Duration) error {
+	confirmationURL := "http://bookstore.anrdhmshr.tech/delete-account?email=" + url.QueryEscape(toEmail) + "&otp=" + otp
+	content := "A request for the deletion of the bookstore account associated with your user has been made. If this was not you, please change your password. Otherwise, click on this link to confirm account deletion: " + confirmationURL + fmt.Sprintf(" . This link will be active for %d minutes.", int(validFor.Minutes()))
 	subject := "Request for account deletion."
 
-	err := es.GenericSendMail(subject, content, toEmail, userName)
-	if err != nil {
-		return err
-	}
-
-	entry := models.DeletionConfirmation{
-		Email:     toEmail,
-		OTP:       otp,
-		ValidTill: time.Now().Add(3 * time.Minute),
-	}
-	deletionRepo := repository.NewDeletionConfirmationRepository()
-	deletionRepo.CreateDeletionConfirmation(entry)
-	return nil
+	return es.GenericSendMail(subject, content, toEmail, userName)
 }
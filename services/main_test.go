@@ -0,0 +1,14 @@
+package services
+
+import (
+	"os"
+	"testing"
+
+	"github.com/anirudhgray/mood-harbour-backend/config"
+)
+
+func TestMain(m *testing.M) {
+	// set at startup outside of tests
+	config.SetDefaults()
+	os.Exit(m.Run())
+}
@@ -0,0 +1,281 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"github.com/anirudhgray/mood-harbour-backend/infra/logger"
+	"github.com/anirudhgray/mood-harbour-backend/models"
+	"github.com/anirudhgray/mood-harbour-backend/utils/token"
+	"github.com/anirudhgray/mood-harbour-backend/utils/totp"
+	"github.com/spf13/viper"
+)
+
+const (
+	// MFAChallengeLifespan is how long users have to enter their code after their password.
+	MFAChallengeLifespan = 5 * time.Minute
+	// MFAChallengeAttempts is how many codes can be tried per MFA challenge, after which the user has to log in again.
+	MFAChallengeAttempts = 5
+	// RecoveryCodeCount is how many recovery codes users get when they enable two-factor authentication.
+	RecoveryCodeCount = 10
+)
+
+var errInvalidCode = errors.New("invalid authentication code")
+
+// CompleteMFALogin completes a login with the token of its MFA challenge and a TOTP code or recovery code,
+// starting a session on the client's device. Wrong codes count as failed logins, throttled per account and IP address,
+// so asking for new challenges doesn't give more guesses.
+func (as *AuthService) CompleteMFALogin(mfaToken, code string, client models.ClientInfo) (models.LoginResult, error) {
+	invalid := errors.New("invalid or expired login, please log in again")
+
+	challenge, err := as.mfaRepo.GetMFAChallengeByHash(token.HashOpaqueToken(mfaToken))
+	if err != nil || challenge.UsedAt != nil || time.Now().After(challenge.ExpiresAt) {
+		return models.LoginResult{}, invalid
+	}
+	user, err := as.userRepo.GetUserByID(challenge.UserID)
+	if err != nil {
+		return models.LoginResult{}, invalid
+	}
+
+	if err := as.throttleService.Check(ThrottleLogin, user.Email, client.IP); err != nil {
+		return models.LoginResult{}, err
+	}
+	ok, err := as.mfaRepo.RecordMFAChallengeAttempt(challenge.ID, MFAChallengeAttempts)
+	if err != nil {
+		return models.LoginResult{}, err
+	}
+	if !ok {
+		return models.LoginResult{}, invalid
+	}
+
+	if err := as.verifySecondFactor(user, code, true); err != nil {
+		as.recordFailure(ThrottleLogin, user.Email, client)
+		if challenge.Attempts+1 >= MFAChallengeAttempts {
+			as.emailService.GenericSendMail("Failed Login Attempts", "Somebody entered your password correctly but failed to provide your two-factor authentication code. Change your password if this was not you.", user.Email, user.Name)
+		}
+		return models.LoginResult{}, err
+	}
+
+	ok, err = as.mfaRepo.UseMFAChallenge(challenge.ID, time.Now())
+	if err != nil {
+		return models.LoginResult{}, err
+	}
+	if !ok {
+		return models.LoginResult{}, invalid
+	}
+
+	tokens, err := as.startSession(user, client)
+	if err != nil {
+		return models.LoginResult{}, err
+	}
+	as.resetThrottle(ThrottleLogin, user.Email)
+	return models.LoginResult{Tokens: tokens, User: user}, nil
+}
+
+// GetMFAStatus gets whether the user has two-factor authentication, and how many recovery codes they have left.
+func (as *AuthService) GetMFAStatus(userID uint) (models.MFAStatus, error) {
+	factor, err := as.mfaRepo.GetTOTPFactor(userID)
+	if err != nil {
+		return models.MFAStatus{}, err
+	}
+	if factor.ConfirmedAt == nil {
+		return models.MFAStatus{}, nil
+	}
+
+	count, err := as.mfaRepo.CountRecoveryCodes(userID)
+	if err != nil {
+		return models.MFAStatus{}, err
+	}
+	return models.MFAStatus{TOTPEnabled: true, RecoveryCodesLeft: count}, nil
+}
+
+// BeginTOTPEnrollment creates a new TOTP secret for the user to add to their authenticator app. It doesn't protect their
+// logins until confirmed with ConfirmTOTPEnrollment. Beginning again replaces an unconfirmed secret.
+func (as *AuthService) BeginTOTPEnrollment(user models.User) (models.TOTPEnrollment, error) {
+	factor, err := as.mfaRepo.GetTOTPFactor(user.ID)
+	if err != nil {
+		return models.TOTPEnrollment{}, err
+	}
+	if factor.ConfirmedAt != nil {
+		return models.TOTPEnrollment{}, errors.New("two-factor authentication is already enabled")
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return models.TOTPEnrollment{}, err
+	}
+	factor.UserID = user.ID
+	factor.Secret = secret
+	factor.LastUsedStep = 0
+	if err := as.mfaRepo.SaveTOTPFactor(&factor); err != nil {
+		return models.TOTPEnrollment{}, err
+	}
+
+	return models.TOTPEnrollment{Secret: secret, OTPAuthURI: totp.URI(secret, totpIssuer(), user.Email)}, nil
+}
+
+// ConfirmTOTPEnrollment enables two-factor authentication once the user proves their authenticator app works with a first code.
+// Returns their recovery codes, which are only ever shown this once.
+func (as *AuthService) ConfirmTOTPEnrollment(user models.User, code string) ([]string, error) {
+	factor, err := as.mfaRepo.GetTOTPFactor(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if factor.ID == 0 {
+		return nil, errors.New("two-factor authentication enrollment hasn't been started")
+	}
+	if factor.ConfirmedAt != nil {
+		return nil, errors.New("two-factor authentication is already enabled")
+	}
+
+	if err := as.verifyTOTPCode(factor, code); err != nil {
+		return nil, err
+	}
+	if err := as.mfaRepo.ConfirmTOTPFactor(factor.ID, time.Now()); err != nil {
+		return nil, err
+	}
+
+	codes, err := as.replaceRecoveryCodes(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	as.emailService.GenericSendMail("Two-Factor Authentication Enabled", "Two-factor authentication was enabled on your account. Secure your account if this was not you.", user.Email, user.Name)
+	return codes, nil
+}
+
+// DisableTOTP turns off the user's two-factor authentication, which takes a current TOTP code or a recovery code.
+// An enrollment which was never confirmed is simply cancelled.
+func (as *AuthService) DisableTOTP(user models.User, code string, client models.ClientInfo) error {
+	factor, err := as.mfaRepo.GetTOTPFactor(user.ID)
+	if err != nil {
+		return err
+	}
+	if factor.ID == 0 {
+		return errors.New("two-factor authentication isn't enabled")
+	}
+
+	if factor.ConfirmedAt != nil {
+		if err := as.throttleService.Check(ThrottleMFA, user.Email, client.IP); err != nil {
+			return err
+		}
+		if err := as.verifySecondFactor(user, code, false); err != nil {
+			as.recordFailure(ThrottleMFA, user.Email, client)
+			return err
+		}
+		as.resetThrottle(ThrottleMFA, user.Email)
+	}
+
+	if err := as.mfaRepo.DeleteTOTPFactor(user.ID); err != nil {
+		return err
+	}
+
+	if factor.ConfirmedAt != nil {
+		as.emailService.GenericSendMail("Two-Factor Authentication Disabled", "Two-factor authentication was disabled on your account. Secure your account if this was not you.", user.Email, user.Name)
+	}
+	return nil
+}
+
+// RegenerateRecoveryCodes replaces the user's recovery codes with new ones, which takes a current TOTP code or a recovery code.
+func (as *AuthService) RegenerateRecoveryCodes(user models.User, code string, client models.ClientInfo) ([]string, error) {
+	if err := as.throttleService.Check(ThrottleMFA, user.Email, client.IP); err != nil {
+		return nil, err
+	}
+	if err := as.verifySecondFactor(user, code, false); err != nil {
+		as.recordFailure(ThrottleMFA, user.Email, client)
+		return nil, err
+	}
+	as.resetThrottle(ThrottleMFA, user.Email)
+
+	codes, err := as.replaceRecoveryCodes(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	as.emailService.GenericSendMail("Recovery Codes Regenerated", "New two-factor authentication recovery codes were generated for your account, and the old ones no longer work. Secure your account if this was not you.", user.Email, user.Name)
+	return codes, nil
+}
+
+// createMFAChallenge starts the second step of logging in the user.
+func (as *AuthService) createMFAChallenge(user models.User) (models.LoginResult, error) {
+	mfaToken, tokenHash, err := token.GenerateOpaqueToken()
+	if err != nil {
+		return models.LoginResult{}, err
+	}
+
+	expiresAt := time.Now().Add(MFAChallengeLifespan)
+	if err := as.mfaRepo.CreateMFAChallenge(&models.MFAChallenge{UserID: user.ID, TokenHash: tokenHash, ExpiresAt: expiresAt}); err != nil {
+		logger.Errorf("Failed to create MFA challenge: %v", err)
+		return models.LoginResult{}, err
+	}
+
+	return models.LoginResult{User: user, MFARequired: true, MFAToken: mfaToken, MFAExpires: expiresAt}, nil
+}
+
+// verifySecondFactor checks a TOTP code or recovery code of a user with two-factor authentication. Recovery codes are used up,
+// and the user is mailed when one is used to log in.
+func (as *AuthService) verifySecondFactor(user models.User, code string, login bool) error {
+	factor, err := as.mfaRepo.GetTOTPFactor(user.ID)
+	if err != nil {
+		return err
+	}
+	if factor.ConfirmedAt == nil {
+		return errors.New("two-factor authentication isn't enabled")
+	}
+
+	if err := as.verifyTOTPCode(factor, code); err != errInvalidCode {
+		return err
+	}
+
+	ok, err := as.mfaRepo.UseRecoveryCode(user.ID, totp.HashRecoveryCode(code), time.Now())
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errInvalidCode
+	}
+
+	if login {
+		as.emailService.GenericSendMail("Recovery Code Used", "A recovery code was used to log in to your account. Secure your account if this was not you.", user.Email, user.Name)
+	}
+	return nil
+}
+
+// verifyTOTPCode checks a TOTP code, rejecting codes which have already been used.
+func (as *AuthService) verifyTOTPCode(factor models.TOTPFactor, code string) error {
+	step, ok := totp.Validate(factor.Secret, code, time.Now())
+	if !ok {
+		return errInvalidCode
+	}
+
+	ok, err := as.mfaRepo.UseTOTPStep(factor.ID, step)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errInvalidCode
+	}
+	return nil
+}
+
+// replaceRecoveryCodes generates new recovery codes for the user, storing their hashes.
+func (as *AuthService) replaceRecoveryCodes(userID uint) ([]string, error) {
+	codes, err := totp.GenerateRecoveryCodes(RecoveryCodeCount)
+	if err != nil {
+		return nil, err
+	}
+
+	stored := make([]models.RecoveryCode, len(codes))
+	for i, code := range codes {
+		stored[i] = models.RecoveryCode{UserID: userID, CodeHash: totp.HashRecoveryCode(code)}
+	}
+	if err := as.mfaRepo.ReplaceRecoveryCodes(userID, stored); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// totpIssuer is the name authenticator apps show next to codes, TOTP_ISSUER.
+func totpIssuer() string {
+	return viper.GetString("TOTP_ISSUER")
+}
@@ -0,0 +1,334 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/anirudhgray/mood-harbour-backend/mocks"
+	"github.com/anirudhgray/mood-harbour-backend/models"
+	"github.com/anirudhgray/mood-harbour-backend/utils/token"
+	"github.com/anirudhgray/mood-harbour-backend/utils/totp"
+	"github.com/golang/mock/gomock"
+)
+
+const testTOTPSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func confirmedFactor(userID uint) models.TOTPFactor {
+	confirmedAt := time.Now().Add(-time.Hour)
+	factor := models.TOTPFactor{UserID: userID, Secret: testTOTPSecret, ConfirmedAt: &confirmedAt}
+	factor.ID = 5
+	return factor
+}
+
+func currentCode(t *testing.T) string {
+	code, err := totp.Code(testTOTPSecret, totp.Step(time.Now()))
+	if err != nil {
+		t.Fatalf("Failed to compute code: %v", err)
+	}
+	return code
+}
+
+func TestAuthService_SocialLogin_MFARequired(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockMFARepo := mocks.NewMockMFARepository(ctrl)
+	// no session is created until the second factor is provided
+	as := &AuthService{mfaRepo: mockMFARepo, sessionRepo: mocks.NewMockSessionRepository(ctrl)}
+
+	user := models.User{Email: "test@example.com", Verified: true}
+	user.ID = 7
+
+	var challenge *models.MFAChallenge
+	mockMFARepo.EXPECT().GetTOTPFactor(user.ID).Return(confirmedFactor(user.ID), nil)
+	mockMFARepo.EXPECT().CreateMFAChallenge(gomock.Any()).DoAndReturn(func(c *models.MFAChallenge) error {
+		challenge = c
+		return nil
+	})
+
+	result, err := as.SocialLogin(user, models.ClientInfo{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !result.MFARequired || result.MFAToken == "" || result.Tokens.AccessToken != "" {
+		t.Errorf("Expected an MFA challenge instead of tokens, got %+v", result)
+	}
+	if challenge.UserID != user.ID || challenge.TokenHash != token.HashOpaqueToken(result.MFAToken) {
+		t.Errorf("Challenge wasn't stored for the user: %+v", challenge)
+	}
+}
+
+func TestAuthService_CompleteMFALogin(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockMFARepo := mocks.NewMockMFARepository(ctrl)
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	mockSessionRepo := mocks.NewMockSessionRepository(ctrl)
+	mockRefreshTokenRepo := mocks.NewMockRefreshTokenRepository(ctrl)
+	mockEmailService := mocks.NewMockEmailService(ctrl)
+	mockThrottleService := mocks.NewMockThrottleServiceInterface(ctrl)
+
+	as := &AuthService{
+		mfaRepo:          mockMFARepo,
+		userRepo:         mockUserRepo,
+		sessionRepo:      mockSessionRepo,
+		refreshTokenRepo: mockRefreshTokenRepo,
+		emailService:     mockEmailService,
+		throttleService:  mockThrottleService,
+	}
+
+	user := models.User{Email: "test@example.com", Name: "Test User", Verified: true}
+	user.ID = 7
+	factor := confirmedFactor(user.ID)
+	mfaToken := "mfa-token"
+	tokenHash := token.HashOpaqueToken(mfaToken)
+	challenge := models.MFAChallenge{UserID: user.ID, TokenHash: tokenHash, ExpiresAt: time.Now().Add(time.Minute)}
+	challenge.ID = 9
+
+	expectSession := func() {
+		mockSessionRepo.EXPECT().CreateSession(gomock.Any()).Return(nil)
+		mockRefreshTokenRepo.EXPECT().CreateRefreshToken(gomock.Any()).Return(nil)
+	}
+
+	t.Run("Logs in with a TOTP code", func(t *testing.T) {
+		mockMFARepo.EXPECT().GetMFAChallengeByHash(tokenHash).Return(challenge, nil)
+		mockMFARepo.EXPECT().RecordMFAChallengeAttempt(challenge.ID, MFAChallengeAttempts).Return(true, nil)
+		mockUserRepo.EXPECT().GetUserByID(user.ID).Return(user, nil)
+		mockThrottleService.EXPECT().Check(ThrottleLogin, user.Email, "").Return(nil)
+		mockMFARepo.EXPECT().GetTOTPFactor(user.ID).Return(factor, nil)
+		mockMFARepo.EXPECT().UseTOTPStep(factor.ID, gomock.Any()).Return(true, nil)
+		mockMFARepo.EXPECT().UseMFAChallenge(challenge.ID, gomock.Any()).Return(true, nil)
+		expectSession()
+		mockThrottleService.EXPECT().Reset(ThrottleLogin, user.Email).Return(nil)
+
+		result, err := as.CompleteMFALogin(mfaToken, currentCode(t), models.ClientInfo{})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if result.Tokens.AccessToken == "" || result.Tokens.RefreshToken == "" || result.User.ID != user.ID {
+			t.Errorf("Expected tokens for the user, got %+v", result)
+		}
+	})
+
+	t.Run("Rejects a replayed TOTP code", func(t *testing.T) {
+		mockMFARepo.EXPECT().GetMFAChallengeByHash(tokenHash).Return(challenge, nil)
+		mockMFARepo.EXPECT().RecordMFAChallengeAttempt(challenge.ID, MFAChallengeAttempts).Return(true, nil)
+		mockUserRepo.EXPECT().GetUserByID(user.ID).Return(user, nil)
+		mockThrottleService.EXPECT().Check(ThrottleLogin, user.Email, "").Return(nil)
+		mockMFARepo.EXPECT().GetTOTPFactor(user.ID).Return(factor, nil)
+		mockMFARepo.EXPECT().UseTOTPStep(factor.ID, gomock.Any()).Return(false, nil)
+		code := currentCode(t)
+		mockMFARepo.EXPECT().UseRecoveryCode(user.ID, totp.HashRecoveryCode(code), gomock.Any()).Return(false, nil)
+		mockThrottleService.EXPECT().RecordFailure(ThrottleLogin, user.Email, "").Return(nil)
+
+		if _, err := as.CompleteMFALogin(mfaToken, code, models.ClientInfo{}); err == nil {
+			t.Error("Expected an error for a code which was already used")
+		}
+	})
+
+	t.Run("Logs in with a recovery code and mails the user", func(t *testing.T) {
+		mockMFARepo.EXPECT().GetMFAChallengeByHash(tokenHash).Return(challenge, nil)
+		mockMFARepo.EXPECT().RecordMFAChallengeAttempt(challenge.ID, MFAChallengeAttempts).Return(true, nil)
+		mockUserRepo.EXPECT().GetUserByID(user.ID).Return(user, nil)
+		mockThrottleService.EXPECT().Check(ThrottleLogin, user.Email, "").Return(nil)
+		mockMFARepo.EXPECT().GetTOTPFactor(user.ID).Return(factor, nil)
+		mockMFARepo.EXPECT().UseRecoveryCode(user.ID, totp.HashRecoveryCode("abcde-fghij"), gomock.Any()).Return(true, nil)
+		ctrl.RecordCall(mockEmailService, "GenericSendMail", "Recovery Code Used", gomock.Any(), user.Email, user.Name).Return(nil)
+		mockMFARepo.EXPECT().UseMFAChallenge(challenge.ID, gomock.Any()).Return(true, nil)
+		expectSession()
+		mockThrottleService.EXPECT().Reset(ThrottleLogin, user.Email).Return(nil)
+
+		if _, err := as.CompleteMFALogin(mfaToken, "ABCDE-FGHIJ", models.ClientInfo{}); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	})
+
+	t.Run("Mails the user when the last attempt fails", func(t *testing.T) {
+		last := challenge
+		last.Attempts = MFAChallengeAttempts - 1
+		mockMFARepo.EXPECT().GetMFAChallengeByHash(tokenHash).Return(last, nil)
+		mockMFARepo.EXPECT().RecordMFAChallengeAttempt(challenge.ID, MFAChallengeAttempts).Return(true, nil)
+		mockUserRepo.EXPECT().GetUserByID(user.ID).Return(user, nil)
+		mockThrottleService.EXPECT().Check(ThrottleLogin, user.Email, "").Return(nil)
+		mockMFARepo.EXPECT().GetTOTPFactor(user.ID).Return(factor, nil)
+		mockMFARepo.EXPECT().UseRecoveryCode(user.ID, totp.HashRecoveryCode("wrong"), gomock.Any()).Return(false, nil)
+		mockThrottleService.EXPECT().RecordFailure(ThrottleLogin, user.Email, "").Return(nil)
+		ctrl.RecordCall(mockEmailService, "GenericSendMail", "Failed Login Attempts", gomock.Any(), user.Email, user.Name).Return(nil)
+
+		if _, err := as.CompleteMFALogin(mfaToken, "wrong", models.ClientInfo{}); err == nil {
+			t.Error("Expected an error for a wrong code")
+		}
+	})
+
+	t.Run("Rejects challenges out of attempts", func(t *testing.T) {
+		mockMFARepo.EXPECT().GetMFAChallengeByHash(tokenHash).Return(challenge, nil)
+		mockUserRepo.EXPECT().GetUserByID(user.ID).Return(user, nil)
+		mockThrottleService.EXPECT().Check(ThrottleLogin, user.Email, "").Return(nil)
+		mockMFARepo.EXPECT().RecordMFAChallengeAttempt(challenge.ID, MFAChallengeAttempts).Return(false, nil)
+
+		if _, err := as.CompleteMFALogin(mfaToken, currentCode(t), models.ClientInfo{}); err == nil {
+			t.Error("Expected an error for a challenge out of attempts")
+		}
+	})
+
+	t.Run("Rejects throttled accounts without trying the code", func(t *testing.T) {
+		mockMFARepo.EXPECT().GetMFAChallengeByHash(tokenHash).Return(challenge, nil)
+		mockUserRepo.EXPECT().GetUserByID(user.ID).Return(user, nil)
+		mockThrottleService.EXPECT().Check(ThrottleLogin, user.Email, "").Return(&ThrottledError{RetryAfter: time.Minute})
+
+		var throttledErr *ThrottledError
+		if _, err := as.CompleteMFALogin(mfaToken, currentCode(t), models.ClientInfo{}); !errors.As(err, &throttledErr) {
+			t.Errorf("Expected a ThrottledError, got %v", err)
+		}
+	})
+
+	t.Run("Rejects expired challenges", func(t *testing.T) {
+		expired := challenge
+		expired.ExpiresAt = time.Now().Add(-time.Minute)
+		mockMFARepo.EXPECT().GetMFAChallengeByHash(tokenHash).Return(expired, nil)
+
+		if _, err := as.CompleteMFALogin(mfaToken, currentCode(t), models.ClientInfo{}); err == nil {
+			t.Error("Expected an error for an expired challenge")
+		}
+	})
+}
+
+func TestAuthService_ConfirmTOTPEnrollment(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockMFARepo := mocks.NewMockMFARepository(ctrl)
+	mockEmailService := mocks.NewMockEmailService(ctrl)
+	as := &AuthService{mfaRepo: mockMFARepo, emailService: mockEmailService}
+
+	user := models.User{Email: "test@example.com", Name: "Test User"}
+	user.ID = 7
+	factor := models.TOTPFactor{UserID: user.ID, Secret: testTOTPSecret}
+	factor.ID = 5
+
+	var stored []models.RecoveryCode
+	mockMFARepo.EXPECT().GetTOTPFactor(user.ID).Return(factor, nil)
+	mockMFARepo.EXPECT().UseTOTPStep(factor.ID, totp.Step(time.Now())).Return(true, nil)
+	mockMFARepo.EXPECT().ConfirmTOTPFactor(factor.ID, gomock.Any()).Return(nil)
+	mockMFARepo.EXPECT().ReplaceRecoveryCodes(user.ID, gomock.Any()).DoAndReturn(func(userID uint, codes []models.RecoveryCode) error {
+		stored = codes
+		return nil
+	})
+	ctrl.RecordCall(mockEmailService, "GenericSendMail", "Two-Factor Authentication Enabled", gomock.Any(), user.Email, user.Name).Return(nil)
+
+	codes, err := as.ConfirmTOTPEnrollment(user, currentCode(t))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(codes) != RecoveryCodeCount || len(stored) != RecoveryCodeCount {
+		t.Fatalf("Expected %d recovery codes, got %d, stored %d", RecoveryCodeCount, len(codes), len(stored))
+	}
+	for i, code := range codes {
+		if stored[i].CodeHash != totp.HashRecoveryCode(code) || stored[i].UserID != user.ID {
+			t.Errorf("Recovery code %d wasn't stored hashed: %+v", i, stored[i])
+		}
+	}
+}
+
+func TestAuthService_DisableTOTP(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockMFARepo := mocks.NewMockMFARepository(ctrl)
+	mockEmailService := mocks.NewMockEmailService(ctrl)
+	mockThrottleService := mocks.NewMockThrottleServiceInterface(ctrl)
+	as := &AuthService{mfaRepo: mockMFARepo, emailService: mockEmailService, throttleService: mockThrottleService}
+
+	user := models.User{Email: "test@example.com", Name: "Test User"}
+	user.ID = 7
+	factor := confirmedFactor(user.ID)
+	client := models.ClientInfo{IP: "203.0.113.7"}
+
+	t.Run("Requires a valid code", func(t *testing.T) {
+		mockMFARepo.EXPECT().GetTOTPFactor(user.ID).Return(factor, nil).Times(2)
+		mockThrottleService.EXPECT().Check(ThrottleMFA, user.Email, client.IP).Return(nil)
+		mockMFARepo.EXPECT().UseRecoveryCode(user.ID, totp.HashRecoveryCode("000000x"), gomock.Any()).Return(false, nil)
+		mockThrottleService.EXPECT().RecordFailure(ThrottleMFA, user.Email, client.IP).Return(nil)
+
+		if err := as.DisableTOTP(user, "000000x", client); err == nil {
+			t.Error("Expected an error for an invalid code")
+		}
+	})
+
+	t.Run("Rejects throttled accounts without trying the code", func(t *testing.T) {
+		mockMFARepo.EXPECT().GetTOTPFactor(user.ID).Return(factor, nil)
+		mockThrottleService.EXPECT().Check(ThrottleMFA, user.Email, client.IP).Return(&ThrottledError{RetryAfter: time.Minute})
+
+		var throttledErr *ThrottledError
+		if err := as.DisableTOTP(user, currentCode(t), client); !errors.As(err, &throttledErr) {
+			t.Errorf("Expected a ThrottledError, got %v", err)
+		}
+	})
+
+	t.Run("Disables with a current code", func(t *testing.T) {
+		mockMFARepo.EXPECT().GetTOTPFactor(user.ID).Return(factor, nil).Times(2)
+		mockThrottleService.EXPECT().Check(ThrottleMFA, user.Email, client.IP).Return(nil)
+		mockMFARepo.EXPECT().UseTOTPStep(factor.ID, gomock.Any()).Return(true, nil)
+		mockThrottleService.EXPECT().Reset(ThrottleMFA, user.Email).Return(nil)
+		mockMFARepo.EXPECT().DeleteTOTPFactor(user.ID).Return(nil)
+		ctrl.RecordCall(mockEmailService, "GenericSendMail", "Two-Factor Authentication Disabled", gomock.Any(), user.Email, user.Name).Return(nil)
+
+		if err := as.DisableTOTP(user, currentCode(t), client); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	})
+}
+
+func TestAuthService_RegenerateRecoveryCodes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockMFARepo := mocks.NewMockMFARepository(ctrl)
+	mockEmailService := mocks.NewMockEmailService(ctrl)
+	mockThrottleService := mocks.NewMockThrottleServiceInterface(ctrl)
+	as := &AuthService{mfaRepo: mockMFARepo, emailService: mockEmailService, throttleService: mockThrottleService}
+
+	user := models.User{Email: "test@example.com", Name: "Test User"}
+	user.ID = 7
+	factor := confirmedFactor(user.ID)
+	client := models.ClientInfo{IP: "203.0.113.7"}
+
+	t.Run("Counts invalid codes as failures", func(t *testing.T) {
+		mockThrottleService.EXPECT().Check(ThrottleMFA, user.Email, client.IP).Return(nil)
+		mockMFARepo.EXPECT().GetTOTPFactor(user.ID).Return(factor, nil)
+		mockMFARepo.EXPECT().UseRecoveryCode(user.ID, totp.HashRecoveryCode("000000x"), gomock.Any()).Return(false, nil)
+		mockThrottleService.EXPECT().RecordFailure(ThrottleMFA, user.Email, client.IP).Return(nil)
+
+		if _, err := as.RegenerateRecoveryCodes(user, "000000x", client); err == nil {
+			t.Error("Expected an error for an invalid code")
+		}
+	})
+
+	t.Run("Rejects throttled accounts without trying the code", func(t *testing.T) {
+		mockThrottleService.EXPECT().Check(ThrottleMFA, user.Email, client.IP).Return(&ThrottledError{RetryAfter: time.Minute})
+
+		var throttledErr *ThrottledError
+		if _, err := as.RegenerateRecoveryCodes(user, currentCode(t), client); !errors.As(err, &throttledErr) {
+			t.Errorf("Expected a ThrottledError, got %v", err)
+		}
+	})
+
+	t.Run("Replaces the codes given a current code", func(t *testing.T) {
+		mockThrottleService.EXPECT().Check(ThrottleMFA, user.Email, client.IP).Return(nil)
+		mockMFARepo.EXPECT().GetTOTPFactor(user.ID).Return(factor, nil)
+		mockMFARepo.EXPECT().UseTOTPStep(factor.ID, gomock.Any()).Return(true, nil)
+		mockThrottleService.EXPECT().Reset(ThrottleMFA, user.Email).Return(nil)
+		mockMFARepo.EXPECT().ReplaceRecoveryCodes(user.ID, gomock.Any()).Return(nil)
+		ctrl.RecordCall(mockEmailService, "GenericSendMail", "Recovery Codes Regenerated", gomock.Any(), user.Email, user.Name).Return(nil)
+
+		codes, err := as.RegenerateRecoveryCodes(user, currentCode(t), client)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(codes) != RecoveryCodeCount {
+			t.Errorf("Expected %d recovery codes, got %d", RecoveryCodeCount, len(codes))
+		}
+	})
+}
@@ -0,0 +1,181 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/anirudhgray/mood-harbour-backend/models"
+	"github.com/anirudhgray/mood-harbour-backend/repository"
+	"github.com/anirudhgray/mood-harbour-backend/utils/moderation"
+)
+
+type ModerationService struct {
+	moderationRepo repository.ModerationRepositoryInterface
+	resourceRepo   repository.ResourceRepositoryInterface
+	textFilter     moderation.TextFilter
+}
+
+func NewModerationService(moderationRepo repository.ModerationRepositoryInterface, resourceRepo repository.ResourceRepositoryInterface, textFilter moderation.TextFilter) *ModerationService {
+	return &ModerationService{moderationRepo, resourceRepo, textFilter}
+}
+
+type ModerationServiceInterface interface {
+	ReportContent(userID uint, targetType models.ReportTargetType, targetID uint, reason string) error
+	ScreenContent(targetType models.ReportTargetType, targetID uint, text string) error
+	QueueForPreModeration(resourceID uint) error
+	GetModerationQueue() ([]models.ModerationQueueItem, error)
+	ModerateContent(moderatorID uint, targetType models.ReportTargetType, targetID uint, action models.ModerationAction) error
+}
+
+// ReportContent flags a resource or review for moderation on behalf of a user. A user can only have one open report per item.
+func (ms *ModerationService) ReportContent(userID uint, targetType models.ReportTargetType, targetID uint, reason string) error {
+	if reason == "" {
+		return errors.New("please provide a reason for the report")
+	}
+
+	if err := ms.checkTargetExists(targetType, targetID); err != nil {
+		return err
+	}
+
+	alreadyReported, err := ms.moderationRepo.HasOpenReport(targetType, targetID, userID)
+	if err != nil {
+		return err
+	}
+	if alreadyReported {
+		return errors.New("you have already reported this content")
+	}
+
+	report := models.Report{
+		TargetType: targetType,
+		TargetID:   targetID,
+		ReportedBy: userID,
+		Reason:     reason,
+	}
+
+	return ms.moderationRepo.CreateReport(&report)
+}
+
+// ScreenContent runs newly created content through the text filter, and adds it to the moderation queue if it gets flagged.
+func (ms *ModerationService) ScreenContent(targetType models.ReportTargetType, targetID uint, text string) error {
+	flagged, reason := ms.textFilter.Check(text)
+	if !flagged {
+		return nil
+	}
+
+	report := models.Report{
+		TargetType:  targetType,
+		TargetID:    targetID,
+		Reason:      reason,
+		AutoFlagged: true,
+	}
+
+	return ms.moderationRepo.CreateReport(&report)
+}
+
+// QueueForPreModeration adds a pending resource to the moderation queue so that a moderator can publish it.
+func (ms *ModerationService) QueueForPreModeration(resourceID uint) error {
+	report := models.Report{
+		TargetType:  models.ResourceTarget,
+		TargetID:    resourceID,
+		Reason:      "Awaiting approval before being published.",
+		AutoFlagged: true,
+	}
+
+	return ms.moderationRepo.CreateReport(&report)
+}
+
+// GetModerationQueue gets all flagged content with open reports, grouped by the flagged item and oldest first.
+func (ms *ModerationService) GetModerationQueue() ([]models.ModerationQueueItem, error) {
+	reports, err := ms.moderationRepo.GetOpenReports()
+	if err != nil {
+		return nil, err
+	}
+
+	queue := make([]models.ModerationQueueItem, 0)
+	itemIndex := make(map[models.ReportTargetType]map[uint]int)
+
+	for _, report := range reports {
+		if itemIndex[report.TargetType] == nil {
+			itemIndex[report.TargetType] = make(map[uint]int)
+		}
+
+		if idx, ok := itemIndex[report.TargetType][report.TargetID]; ok {
+			queue[idx].Reports = append(queue[idx].Reports, report)
+			continue
+		}
+
+		item := models.ModerationQueueItem{
+			TargetType: report.TargetType,
+			TargetID:   report.TargetID,
+			Reports:    []models.Report{report},
+		}
+
+		switch report.TargetType {
+		case models.ResourceTarget:
+			resource, err := ms.resourceRepo.GetResourceByID(report.TargetID)
+			if err != nil {
+				// the resource has since been deleted, nothing left to moderate
+				continue
+			}
+			item.Resource = &resource
+		case models.ReviewTarget:
+			review, err := ms.resourceRepo.GetReviewByID(report.TargetID)
+			if err != nil {
+				continue
+			}
+			item.Review = &review
+		}
+
+		itemIndex[report.TargetType][report.TargetID] = len(queue)
+		queue = append(queue, item)
+	}
+
+	return queue, nil
+}
+
+// ModerateContent approves, hides or deletes a flagged resource or review, and resolves all open reports against it.
+func (ms *ModerationService) ModerateContent(moderatorID uint, targetType models.ReportTargetType, targetID uint, action models.ModerationAction) error {
+	if err := ms.checkTargetExists(targetType, targetID); err != nil {
+		return err
+	}
+
+	var err error
+	switch action {
+	case models.ApproveAction:
+		err = ms.setStatus(targetType, targetID, models.Published)
+	case models.HideAction:
+		err = ms.setStatus(targetType, targetID, models.Hidden)
+	case models.DeleteAction:
+		if targetType == models.ResourceTarget {
+			err = ms.resourceRepo.DeleteResource(targetID)
+		} else {
+			err = ms.resourceRepo.DeleteReview(targetID)
+		}
+	default:
+		return errors.New("invalid moderation action")
+	}
+	if err != nil {
+		return err
+	}
+
+	return ms.moderationRepo.ResolveReports(targetType, targetID, moderatorID, action)
+}
+
+func (ms *ModerationService) setStatus(targetType models.ReportTargetType, targetID uint, status models.ModerationStatus) error {
+	if targetType == models.ResourceTarget {
+		return ms.resourceRepo.SetResourceStatus(targetID, status)
+	}
+	return ms.resourceRepo.SetReviewStatus(targetID, status)
+}
+
+func (ms *ModerationService) checkTargetExists(targetType models.ReportTargetType, targetID uint) error {
+	var err error
+	switch targetType {
+	case models.ResourceTarget:
+		_, err = ms.resourceRepo.GetResourceByID(targetID)
+	case models.ReviewTarget:
+		_, err = ms.resourceRepo.GetReviewByID(targetID)
+	default:
+		return errors.New("invalid content type")
+	}
+	return err
+}
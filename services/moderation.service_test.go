@@ -0,0 +1,130 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/anirudhgray/mood-harbour-backend/mocks"
+	"github.com/anirudhgray/mood-harbour-backend/models"
+	"github.com/anirudhgray/mood-harbour-backend/utils/moderation"
+	"github.com/golang/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func TestModerationService_ReportContent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockModerationRepo := mocks.NewMockModerationRepositoryInterface(ctrl)
+	mockResourceRepo := mocks.NewMockResourceRepositoryInterface(ctrl)
+	ms := NewModerationService(mockModerationRepo, mockResourceRepo, moderation.NoopFilter{})
+	userID := uint(1)
+	reviewID := uint(2)
+
+	mockResourceRepo.EXPECT().GetReviewByID(reviewID).Return(models.Review{Model: gorm.Model{ID: reviewID}}, nil).Times(2)
+	mockModerationRepo.EXPECT().HasOpenReport(models.ReviewTarget, reviewID, userID).Return(false, nil)
+	mockModerationRepo.EXPECT().CreateReport(&models.Report{TargetType: models.ReviewTarget, TargetID: reviewID, ReportedBy: userID, Reason: "Spam"}).Return(nil)
+
+	if err := ms.ReportContent(userID, models.ReviewTarget, reviewID, "Spam"); err != nil {
+		t.Errorf("ReportContent returned an error: %v", err)
+	}
+
+	// reporting the same review again while the first report is still open is rejected
+	mockModerationRepo.EXPECT().HasOpenReport(models.ReviewTarget, reviewID, userID).Return(true, nil)
+	if err := ms.ReportContent(userID, models.ReviewTarget, reviewID, "Spam"); err == nil {
+		t.Errorf("Expected error for duplicate report, got nil")
+	}
+
+	if err := ms.ReportContent(userID, models.ReviewTarget, reviewID, ""); err == nil {
+		t.Errorf("Expected error for report without a reason, got nil")
+	}
+}
+
+func TestModerationService_ScreenContent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockModerationRepo := mocks.NewMockModerationRepositoryInterface(ctrl)
+	ms := NewModerationService(mockModerationRepo, mocks.NewMockResourceRepositoryInterface(ctrl), moderation.NewWordListFilter([]string{"scam"}))
+
+	mockModerationRepo.EXPECT().CreateReport(gomock.Any()).Do(func(report *models.Report) {
+		if !report.AutoFlagged || report.TargetType != models.ResourceTarget || report.TargetID != 1 {
+			t.Errorf("ScreenContent created an incorrect report: %+v", report)
+		}
+	}).Return(nil)
+
+	if err := ms.ScreenContent(models.ResourceTarget, 1, "This is a scam"); err != nil {
+		t.Errorf("ScreenContent returned an error: %v", err)
+	}
+	if err := ms.ScreenContent(models.ResourceTarget, 2, "A calming breathing exercise"); err != nil {
+		t.Errorf("ScreenContent returned an error: %v", err)
+	}
+}
+
+func TestModerationService_ModerateContent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockModerationRepo := mocks.NewMockModerationRepositoryInterface(ctrl)
+	mockResourceRepo := mocks.NewMockResourceRepositoryInterface(ctrl)
+	ms := NewModerationService(mockModerationRepo, mockResourceRepo, moderation.NoopFilter{})
+	moderatorID := uint(1)
+	resourceID := uint(3)
+
+	testCases := []struct {
+		action models.ModerationAction
+		expect func()
+	}{
+		{models.ApproveAction, func() {
+			mockResourceRepo.EXPECT().SetResourceStatus(resourceID, models.Published).Return(nil)
+		}},
+		{models.HideAction, func() {
+			mockResourceRepo.EXPECT().SetResourceStatus(resourceID, models.Hidden).Return(nil)
+		}},
+		{models.DeleteAction, func() {
+			mockResourceRepo.EXPECT().DeleteResource(resourceID).Return(nil)
+		}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(string(tc.action), func(t *testing.T) {
+			mockResourceRepo.EXPECT().GetResourceByID(resourceID).Return(models.Resource{Model: gorm.Model{ID: resourceID}}, nil)
+			tc.expect()
+			mockModerationRepo.EXPECT().ResolveReports(models.ResourceTarget, resourceID, moderatorID, tc.action).Return(nil)
+
+			if err := ms.ModerateContent(moderatorID, models.ResourceTarget, resourceID, tc.action); err != nil {
+				t.Errorf("ModerateContent returned an error: %v", err)
+			}
+		})
+	}
+}
+
+func TestModerationService_GetModerationQueue_GroupsReports(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockModerationRepo := mocks.NewMockModerationRepositoryInterface(ctrl)
+	mockResourceRepo := mocks.NewMockResourceRepositoryInterface(ctrl)
+	ms := NewModerationService(mockModerationRepo, mockResourceRepo, moderation.NoopFilter{})
+
+	mockModerationRepo.EXPECT().GetOpenReports().Return([]models.Report{
+		{TargetType: models.ResourceTarget, TargetID: 1, ReportedBy: 1},
+		{TargetType: models.ReviewTarget, TargetID: 1, ReportedBy: 1},
+		{TargetType: models.ResourceTarget, TargetID: 1, ReportedBy: 2},
+	}, nil)
+	mockResourceRepo.EXPECT().GetResourceByID(uint(1)).Return(models.Resource{Model: gorm.Model{ID: 1}}, nil)
+	mockResourceRepo.EXPECT().GetReviewByID(uint(1)).Return(models.Review{Model: gorm.Model{ID: 1}}, nil)
+
+	queue, err := ms.GetModerationQueue()
+	if err != nil {
+		t.Fatalf("GetModerationQueue returned an error: %v", err)
+	}
+	if len(queue) != 2 {
+		t.Fatalf("Expected 2 queue items, got: %d", len(queue))
+	}
+	if queue[0].Resource == nil || len(queue[0].Reports) != 2 {
+		t.Errorf("Expected both resource reports to be grouped together, got: %+v", queue[0])
+	}
+	if queue[1].Review == nil || len(queue[1].Reports) != 1 {
+		t.Errorf("Expected review to be a separate queue item, got: %+v", queue[1])
+	}
+}
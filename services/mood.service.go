@@ -3,6 +3,7 @@ package services
 import (
 	"github.com/anirudhgray/mood-harbour-backend/models"
 	"github.com/anirudhgray/mood-harbour-backend/repository"
+	"github.com/anirudhgray/mood-harbour-backend/utils/markdown"
 )
 
 type MoodService struct {
@@ -25,13 +26,19 @@ type MoodServiceInterface interface {
 
 // CreateMoodEntry creates a new mood entry in the database.
 func (ms *MoodService) CreateMoodEntry(moodType models.MoodType, notes string, userID uint, attributes []string) (models.MoodResponse, error) {
+	notesHTML, err := markdown.Render(notes)
+	if err != nil {
+		return models.MoodResponse{}, err
+	}
+
 	mood := models.Mood{
-		UserID: userID,
-		Mood:   moodType,
-		Notes:  notes,
+		UserID:    userID,
+		Mood:      moodType,
+		Notes:     notes,
+		NotesHTML: notesHTML,
 	}
 
-	err := ms.moodRepo.CreateMoodEntry(&mood)
+	err = ms.moodRepo.CreateMoodEntry(&mood)
 	if err != nil {
 		return models.MoodResponse{}, err
 	}
@@ -185,8 +192,14 @@ func (ms *MoodService) UpdateUserMoodEntry(moodID uint, moodType models.MoodType
 	if err != nil {
 		return err
 	}
+	notesHTML, err := markdown.Render(notes)
+	if err != nil {
+		return err
+	}
+
 	mood.Mood = moodType
 	mood.Notes = notes
+	mood.NotesHTML = notesHTML
 
 	err = ms.moodRepo.UpdateMoodEntry(&mood)
 	if err != nil {
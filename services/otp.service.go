@@ -0,0 +1,89 @@
+package services
+
+import (
+	"crypto/hmac"
+	"time"
+
+	"github.com/anirudhgray/mood-harbour-backend/infra/logger"
+	"github.com/anirudhgray/mood-harbour-backend/models"
+	"github.com/anirudhgray/mood-harbour-backend/utils/token"
+	"github.com/spf13/viper"
+)
+
+// OTPDigits is the length of the codes emailed to users.
+const OTPDigits = 6
+
+// OneTimeTokenLifespan is how long codes for the purpose are valid, OTP_VERIFY_EMAIL_TTL_MINUTES,
+// OTP_RESET_PASSWORD_TTL_MINUTES or OTP_DELETE_ACCOUNT_TTL_MINUTES.
+func OneTimeTokenLifespan(purpose string) time.Duration {
+	switch purpose {
+	case models.OneTimeTokenVerifyEmail:
+		return time.Duration(viper.GetInt("OTP_VERIFY_EMAIL_TTL_MINUTES")) * time.Minute
+	case models.OneTimeTokenResetPassword:
+		return time.Duration(viper.GetInt("OTP_RESET_PASSWORD_TTL_MINUTES")) * time.Minute
+	case models.OneTimeTokenDeleteAccount:
+		return time.Duration(viper.GetInt("OTP_DELETE_ACCOUNT_TTL_MINUTES")) * time.Minute
+	}
+	return 0
+}
+
+// oneTimeTokenAttempts is how many codes can be tried against a one-time token, OTP_MAX_ATTEMPTS, after which
+// the user has to request a new one.
+func oneTimeTokenAttempts() int {
+	return viper.GetInt("OTP_MAX_ATTEMPTS")
+}
+
+// issueOneTimeToken creates a code for the email to confirm the purpose with, replacing any earlier one.
+// Returns the code to email, and how long it's valid for.
+func (as *AuthService) issueOneTimeToken(email, purpose string) (string, time.Duration, error) {
+	otp, err := token.GenerateOTP(OTPDigits)
+	if err != nil {
+		return "", 0, err
+	}
+
+	lifespan := OneTimeTokenLifespan(purpose)
+	err = as.oneTimeTokenRepo.ReplaceOneTimeToken(&models.OneTimeToken{
+		Email:     email,
+		Purpose:   purpose,
+		TokenHash: token.HashOTP(otp, email, purpose),
+		ExpiresAt: time.Now().Add(lifespan),
+	})
+	if err != nil {
+		logger.Errorf("Failed to create %s one-time token: %v", purpose, err)
+		return "", 0, err
+	}
+	return otp, lifespan, nil
+}
+
+// useOneTimeToken checks the code sent to the email for the purpose, using it up. Wrong codes are throttled like failed
+// logins, per account and IP address, as well as counting towards the token's attempts.
+func (as *AuthService) useOneTimeToken(email, purpose, otp string, client models.ClientInfo) error {
+	if err := as.throttleService.Check(purpose, email, client.IP); err != nil {
+		return err
+	}
+
+	stored, err := as.oneTimeTokenRepo.GetOneTimeToken(email, purpose)
+	if err != nil || stored.UsedAt != nil || time.Now().After(stored.ExpiresAt) {
+		as.recordFailure(purpose, email, client)
+		return errInvalidOTP
+	}
+	ok, err := as.oneTimeTokenRepo.RecordOneTimeTokenAttempt(stored.ID, oneTimeTokenAttempts())
+	if err != nil {
+		return err
+	}
+	if !ok || !hmac.Equal([]byte(token.HashOTP(otp, email, purpose)), []byte(stored.TokenHash)) {
+		as.recordFailure(purpose, email, client)
+		return errInvalidOTP
+	}
+
+	ok, err = as.oneTimeTokenRepo.UseOneTimeToken(stored.ID, time.Now())
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errInvalidOTP
+	}
+
+	as.resetThrottle(purpose, email)
+	return nil
+}
@@ -0,0 +1,201 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/anirudhgray/mood-harbour-backend/infra/logger"
+	"github.com/anirudhgray/mood-harbour-backend/models"
+	"github.com/anirudhgray/mood-harbour-backend/utils/webauthn"
+)
+
+const (
+	// maxCredentialIDLength is the longest base64url encoded credential ID which fits the ProviderKey of an AuthProvider.
+	maxCredentialIDLength = 255
+	// maxPasskeyNameLength is the longest name users can give a passkey.
+	maxPasskeyNameLength = 64
+)
+
+// BeginPasskeyRegistration starts registering a passkey for the user, returning the options to pass to their authenticator.
+func (as *AuthService) BeginPasskeyRegistration(user models.User) (webauthn.CreationOptions, error) {
+	passkeys, err := as.passkeyRepo.GetUserPasskeys(user.ID)
+	if err != nil {
+		return webauthn.CreationOptions{}, err
+	}
+	// authenticators refuse to create a second passkey for the same account
+	exclude := make([][]byte, 0, len(passkeys))
+	for _, passkey := range passkeys {
+		if id, err := webauthn.Decode(passkey.CredentialID); err == nil {
+			exclude = append(exclude, id)
+		}
+	}
+
+	challenge, err := as.createPasskeyChallenge(user.ID, models.PasskeyRegistration)
+	if err != nil {
+		return webauthn.CreationOptions{}, err
+	}
+
+	webauthnUser := webauthn.User{ID: passkeyUserHandle(user.ID), Name: user.Email, DisplayName: user.Name}
+	return as.relyingParty.CreationOptions(challenge, webauthnUser, exclude), nil
+}
+
+// FinishPasskeyRegistration verifies and stores the passkey the user's authenticator created, under the given name.
+func (as *AuthService) FinishPasskeyRegistration(user models.User, name string, response webauthn.AttestationResponse) (models.PasskeyView, error) {
+	invalid := errors.New("invalid or expired passkey registration, please try again")
+
+	clientChallenge, err := webauthn.ClientChallenge(response.Response.ClientDataJSON)
+	if err != nil {
+		return models.PasskeyView{}, invalid
+	}
+	challenge, err := as.passkeyRepo.UsePasskeyChallenge(clientChallenge, models.PasskeyRegistration, time.Now())
+	if err != nil || challenge.UserID != user.ID {
+		return models.PasskeyView{}, invalid
+	}
+
+	credential, err := as.relyingParty.VerifyRegistration(challenge.Challenge, response)
+	if err != nil {
+		logger.Warnf("Passkey registration of user %d failed: %v", user.ID, err)
+		return models.PasskeyView{}, invalid
+	}
+	credentialID := webauthn.Encode(credential.ID)
+	if len(credentialID) > maxCredentialIDLength {
+		return models.PasskeyView{}, errors.New("this authenticator's credential IDs are too long")
+	}
+
+	if name == "" {
+		name = "Passkey"
+	}
+	if runes := []rune(name); len(runes) > maxPasskeyNameLength {
+		name = string(runes[:maxPasskeyNameLength])
+	}
+
+	passkey := models.Passkey{
+		UserID:       user.ID,
+		CredentialID: credentialID,
+		PublicKey:    credential.PublicKey,
+		SignCount:    credential.SignCount,
+		Name:         name,
+	}
+	if err := as.passkeyRepo.CreatePasskey(&passkey); err != nil {
+		logger.Errorf("Failed to create passkey: %v", err)
+		return models.PasskeyView{}, err
+	}
+
+	as.emailService.GenericSendMail("Passkey Added", "A passkey named \""+name+"\" was added to your account. Secure your account if this was not you.", user.Email, user.Name)
+	return passkeyView(passkey), nil
+}
+
+// BeginPasskeyLogin starts logging in with a passkey, returning the options to pass to the user's authenticator.
+func (as *AuthService) BeginPasskeyLogin() (webauthn.RequestOptions, error) {
+	challenge, err := as.createPasskeyChallenge(0, models.PasskeyLogin)
+	if err != nil {
+		return webauthn.RequestOptions{}, err
+	}
+	return as.relyingParty.RequestOptions(challenge), nil
+}
+
+// FinishPasskeyLogin verifies the authenticator's response and starts a session for the passkey's user on the client's device.
+// Authenticators verify their user with a PIN or biometrics for passkeys, so users with two-factor authentication aren't asked
+// for a TOTP code as well.
+func (as *AuthService) FinishPasskeyLogin(response webauthn.AssertionResponse, client models.ClientInfo) (models.LoginResult, error) {
+	invalid := errors.New("invalid or expired passkey login, please try again")
+
+	clientChallenge, err := webauthn.ClientChallenge(response.Response.ClientDataJSON)
+	if err != nil {
+		return models.LoginResult{}, invalid
+	}
+	challenge, err := as.passkeyRepo.UsePasskeyChallenge(clientChallenge, models.PasskeyLogin, time.Now())
+	if err != nil {
+		return models.LoginResult{}, invalid
+	}
+
+	credentialID, err := webauthn.Decode(response.ID)
+	if err != nil {
+		return models.LoginResult{}, invalid
+	}
+	passkey, err := as.passkeyRepo.GetPasskeyByCredentialID(webauthn.Encode(credentialID))
+	if err != nil {
+		return models.LoginResult{}, invalid
+	}
+	if response.Response.UserHandle != "" {
+		if userHandle, err := webauthn.Decode(response.Response.UserHandle); err != nil || !bytes.Equal(userHandle, passkeyUserHandle(passkey.UserID)) {
+			return models.LoginResult{}, invalid
+		}
+	}
+
+	credential := webauthn.Credential{ID: credentialID, PublicKey: passkey.PublicKey, SignCount: passkey.SignCount}
+	signCount, err := as.relyingParty.VerifyAssertion(challenge.Challenge, credential, response)
+	if err != nil {
+		logger.Warnf("Passkey login with passkey %d of user %d failed: %v", passkey.ID, passkey.UserID, err)
+		return models.LoginResult{}, invalid
+	}
+
+	user, err := as.userRepo.GetUserByID(passkey.UserID)
+	if err != nil {
+		return models.LoginResult{}, invalid
+	}
+	if err := as.passkeyRepo.UpdatePasskeyUsage(passkey.ID, signCount, time.Now()); err != nil {
+		return models.LoginResult{}, err
+	}
+
+	tokens, err := as.startSession(user, client)
+	if err != nil {
+		return models.LoginResult{}, err
+	}
+	return models.LoginResult{Tokens: tokens, User: user}, nil
+}
+
+// GetPasskeys gets the user's passkeys.
+func (as *AuthService) GetPasskeys(userID uint) ([]models.PasskeyView, error) {
+	passkeys, err := as.passkeyRepo.GetUserPasskeys(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	views := make([]models.PasskeyView, 0, len(passkeys))
+	for _, passkey := range passkeys {
+		views = append(views, passkeyView(passkey))
+	}
+	return views, nil
+}
+
+// DeletePasskey removes one of the user's passkeys, so it can't be used to log in anymore.
+func (as *AuthService) DeletePasskey(user models.User, passkeyID uint) error {
+	if err := as.passkeyRepo.DeletePasskey(user.ID, passkeyID); err != nil {
+		return err
+	}
+
+	as.emailService.GenericSendMail("Passkey Removed", "A passkey was removed from your account. Secure your account if this was not you.", user.Email, user.Name)
+	return nil
+}
+
+// createPasskeyChallenge starts a passkey ceremony, returning its challenge.
+func (as *AuthService) createPasskeyChallenge(userID uint, purpose string) (string, error) {
+	challenge, err := webauthn.NewChallenge()
+	if err != nil {
+		return "", err
+	}
+
+	err = as.passkeyRepo.CreatePasskeyChallenge(&models.PasskeyChallenge{
+		UserID:    userID,
+		Challenge: challenge,
+		Purpose:   purpose,
+		ExpiresAt: time.Now().Add(webauthn.Timeout),
+	})
+	if err != nil {
+		logger.Errorf("Failed to create passkey challenge: %v", err)
+		return "", err
+	}
+	return challenge, nil
+}
+
+// passkeyUserHandle is the opaque WebAuthn user handle of a user, which authenticators return when logging in.
+func passkeyUserHandle(userID uint) []byte {
+	return []byte(strconv.FormatUint(uint64(userID), 10))
+}
+
+func passkeyView(passkey models.Passkey) models.PasskeyView {
+	return models.PasskeyView{ID: passkey.ID, Name: passkey.Name, CreatedAt: passkey.CreatedAt, LastUsedAt: passkey.LastUsedAt}
+}
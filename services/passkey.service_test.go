@@ -0,0 +1,149 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/anirudhgray/mood-harbour-backend/mocks"
+	"github.com/anirudhgray/mood-harbour-backend/models"
+	"github.com/anirudhgray/mood-harbour-backend/utils/webauthn"
+	"github.com/anirudhgray/mood-harbour-backend/utils/webauthn/webauthntest"
+	"github.com/golang/mock/gomock"
+)
+
+var testRelyingParty = webauthn.RelyingParty{ID: "example.com", Name: "Mood Harbour", Origins: []string{"https://example.com"}}
+
+func TestAuthService_Passkeys(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPasskeyRepo := mocks.NewMockPasskeyRepository(ctrl)
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	mockSessionRepo := mocks.NewMockSessionRepository(ctrl)
+	mockRefreshTokenRepo := mocks.NewMockRefreshTokenRepository(ctrl)
+	mockEmailService := mocks.NewMockEmailService(ctrl)
+
+	as := &AuthService{
+		passkeyRepo:      mockPasskeyRepo,
+		userRepo:         mockUserRepo,
+		sessionRepo:      mockSessionRepo,
+		refreshTokenRepo: mockRefreshTokenRepo,
+		emailService:     mockEmailService,
+		relyingParty:     testRelyingParty,
+	}
+
+	user := models.User{Email: "test@example.com", Name: "Test User", Verified: true}
+	user.ID = 7
+	authenticator, err := webauthntest.NewAuthenticator(testRelyingParty.ID, "https://example.com")
+	if err != nil {
+		t.Fatalf("Failed to create authenticator: %v", err)
+	}
+
+	// challenges are looked up by their value, like the database would
+	challenges := make(map[string]models.PasskeyChallenge)
+	mockPasskeyRepo.EXPECT().CreatePasskeyChallenge(gomock.Any()).DoAndReturn(func(c *models.PasskeyChallenge) error {
+		challenges[c.Challenge] = *c
+		return nil
+	}).AnyTimes()
+	mockPasskeyRepo.EXPECT().UsePasskeyChallenge(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(challenge, purpose string, _ interface{}) (models.PasskeyChallenge, error) {
+		stored, ok := challenges[challenge]
+		if !ok || stored.Purpose != purpose {
+			return models.PasskeyChallenge{}, errors.New("record not found")
+		}
+		delete(challenges, challenge)
+		return stored, nil
+	}).AnyTimes()
+
+	var passkey models.Passkey
+	t.Run("Registers a passkey", func(t *testing.T) {
+		mockPasskeyRepo.EXPECT().GetUserPasskeys(user.ID).Return(nil, nil)
+		options, err := as.BeginPasskeyRegistration(user)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if options.RP.ID != testRelyingParty.ID || options.User.Name != user.Email {
+			t.Errorf("Unexpected options %+v", options)
+		}
+		userHandle, _ := webauthn.Decode(options.User.ID)
+
+		mockPasskeyRepo.EXPECT().CreatePasskey(gomock.Any()).DoAndReturn(func(p *models.Passkey) error {
+			p.ID = 3
+			passkey = *p
+			return nil
+		})
+		ctrl.RecordCall(mockEmailService, "GenericSendMail", "Passkey Added", gomock.Any(), user.Email, user.Name).Return(nil)
+
+		view, err := as.FinishPasskeyRegistration(user, "Laptop", authenticator.Register(options.Challenge, userHandle))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if view.ID != 3 || view.Name != "Laptop" {
+			t.Errorf("Unexpected passkey %+v", view)
+		}
+		if passkey.UserID != user.ID || passkey.CredentialID != webauthn.Encode(authenticator.CredentialID()) || passkey.SignCount != 1 {
+			t.Errorf("Passkey wasn't stored for the user: %+v", passkey)
+		}
+	})
+
+	t.Run("Rejects a registration for another user's challenge", func(t *testing.T) {
+		mockPasskeyRepo.EXPECT().GetUserPasskeys(user.ID).Return(nil, nil)
+		options, _ := as.BeginPasskeyRegistration(user)
+
+		other := models.User{Email: "other@example.com"}
+		other.ID = 8
+		otherAuthenticator, _ := webauthntest.NewAuthenticator(testRelyingParty.ID, "https://example.com")
+		if _, err := as.FinishPasskeyRegistration(other, "", otherAuthenticator.Register(options.Challenge, []byte("8"))); err == nil {
+			t.Error("Expected an error for another user's challenge")
+		}
+	})
+
+	t.Run("Logs in with the passkey", func(t *testing.T) {
+		options, err := as.BeginPasskeyLogin()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		mockPasskeyRepo.EXPECT().GetPasskeyByCredentialID(passkey.CredentialID).Return(passkey, nil)
+		mockUserRepo.EXPECT().GetUserByID(user.ID).Return(user, nil)
+		mockPasskeyRepo.EXPECT().UpdatePasskeyUsage(passkey.ID, uint32(2), gomock.Any()).Return(nil)
+		mockSessionRepo.EXPECT().CreateSession(gomock.Any()).Return(nil)
+		mockRefreshTokenRepo.EXPECT().CreateRefreshToken(gomock.Any()).Return(nil)
+
+		result, err := as.FinishPasskeyLogin(authenticator.Assert(options.Challenge), models.ClientInfo{})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if result.Tokens.AccessToken == "" || result.User.ID != user.ID {
+			t.Errorf("Expected tokens for the user, got %+v", result)
+		}
+	})
+
+	t.Run("Rejects a replayed login", func(t *testing.T) {
+		options, _ := as.BeginPasskeyLogin()
+		response := authenticator.Assert(options.Challenge)
+
+		mockPasskeyRepo.EXPECT().GetPasskeyByCredentialID(passkey.CredentialID).Return(passkey, nil)
+		mockUserRepo.EXPECT().GetUserByID(user.ID).Return(user, nil)
+		mockPasskeyRepo.EXPECT().UpdatePasskeyUsage(passkey.ID, gomock.Any(), gomock.Any()).Return(nil)
+		mockSessionRepo.EXPECT().CreateSession(gomock.Any()).Return(nil)
+		mockRefreshTokenRepo.EXPECT().CreateRefreshToken(gomock.Any()).Return(nil)
+		if _, err := as.FinishPasskeyLogin(response, models.ClientInfo{}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if _, err := as.FinishPasskeyLogin(response, models.ClientInfo{}); err == nil {
+			t.Error("Expected an error for a response to a used challenge")
+		}
+	})
+
+	t.Run("Rejects another user's handle", func(t *testing.T) {
+		options, _ := as.BeginPasskeyLogin()
+		response := authenticator.Assert(options.Challenge)
+		response.Response.UserHandle = webauthn.Encode([]byte("8"))
+
+		mockPasskeyRepo.EXPECT().GetPasskeyByCredentialID(passkey.CredentialID).Return(passkey, nil)
+		if _, err := as.FinishPasskeyLogin(response, models.ClientInfo{}); err == nil {
+			t.Error("Expected an error for another user's handle")
+		}
+	})
+}
@@ -0,0 +1,680 @@
+package services
+
+import (
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/anirudhgray/mood-harbour-backend/infra/logger"
+	"github.com/anirudhgray/mood-harbour-backend/models"
+	"github.com/anirudhgray/mood-harbour-backend/repository"
+	"github.com/anirudhgray/mood-harbour-backend/utils/preference"
+	"github.com/anirudhgray/mood-harbour-backend/utils/recommender"
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+)
+
+type RecommendationService struct {
+	recommendationRepo repository.RecommendationRepositoryInterface
+	resourceRepo       repository.ResourceRepositoryInterface
+	moodRepo           repository.MoodRepositoryInterface
+	maxPerUser         int
+	algorithm          string
+}
+
+// DefaultMaxRecommendationsPerUser is the number of recommendations stored per user when RECOMMENDATION_MAX_PER_USER isn't set.
+const DefaultMaxRecommendationsPerUser = 200
+
+// NewRecommendationService creates a RecommendationService which stores at most maxPerUser recommendations for each user,
+// computed with the named recommender algorithm (see recommender.New).
+func NewRecommendationService(recommendationRepo repository.RecommendationRepositoryInterface, resourceRepo repository.ResourceRepositoryInterface, moodRepo repository.MoodRepositoryInterface, maxPerUser int, algorithm string) *RecommendationService {
+	if maxPerUser <= 0 {
+		maxPerUser = DefaultMaxRecommendationsPerUser
+	}
+	return &RecommendationService{recommendationRepo, resourceRepo, moodRepo, maxPerUser, algorithm}
+}
+
+type RecommendationServiceInterface interface {
+	GetRecommendations(userID uint, page, perPage int, mood models.MoodType) (recommender.RecommendationPage, error)
+	CurrentMood(userID uint) (models.MoodType, error)
+	RecomputeAll() error
+	RecomputeStale(limit int) error
+	MarkInteractionStale(userID, resourceID uint) error
+	DismissRecommendation(userID, resourceID uint, reason models.DismissalReason) error
+	UndoDismissal(userID, resourceID uint) error
+	GetDismissals(userID uint) ([]models.DismissalResponse, error)
+	CurateResource(adminID, resourceID uint) error
+	UncurateResource(resourceID uint) error
+	GetCuratedResources() ([]models.Resource, error)
+	TrainFactorizationModel() (models.FactorizationModel, error)
+	QueueFactorizationTraining() error
+	GetFactorizationModels() ([]models.FactorizationModel, error)
+	ActivateFactorizationModel(modelID uint) error
+}
+
+// GetRecommendations reads a page of the user's precomputed recommendations.
+// Users whose recommendations were never computed are queued for the stale recommendations job.
+//
+// The recommendations are re-ranked for the given mood, or if mood is 0 for the user's current mood (see CurrentMood).
+// Without either they are returned in their precomputed order.
+func (rs *RecommendationService) GetRecommendations(userID uint, page, perPage int, mood models.MoodType) (recommender.RecommendationPage, error) {
+	if page < 1 {
+		page = 1
+	}
+
+	status, err := rs.recommendationRepo.GetRecommendationStatus(userID)
+	if err != nil {
+		return recommender.RecommendationPage{}, err
+	}
+
+	recommendationPage := recommender.RecommendationPage{
+		Recommendations: []recommender.Recommendation{},
+		Page:            page,
+		PerPage:         perPage,
+		ComputedAt:      status.ComputedAt,
+		Stale:           status.Stale,
+	}
+
+	if status.ComputedAt == nil {
+		if !status.Stale {
+			if err := rs.recommendationRepo.MarkUsersStale([]uint{userID}, time.Now()); err != nil {
+				return recommender.RecommendationPage{}, err
+			}
+			recommendationPage.Stale = true
+		}
+		return recommendationPage, nil
+	}
+
+	if mood == 0 {
+		mood, err = rs.CurrentMood(userID)
+		if err != nil {
+			return recommender.RecommendationPage{}, err
+		}
+	}
+
+	var userRecommendations []models.UserRecommendation
+	var total int64
+	if mood == 0 {
+		userRecommendations, total, err = rs.recommendationRepo.GetUserRecommendations(userID, (page-1)*perPage, perPage)
+	} else {
+		recommendationPage.Mood = mood
+		userRecommendations, total, err = rs.getMoodRecommendations(userID, mood, (page-1)*perPage, perPage)
+	}
+	if err != nil {
+		return recommender.RecommendationPage{}, err
+	}
+	recommendationPage.Total = total
+
+	resourceIDs := make([]uint, 0, len(userRecommendations))
+	for _, userRecommendation := range userRecommendations {
+		resourceIDs = append(resourceIDs, userRecommendation.ResourceID)
+	}
+
+	resources, err := rs.resourceRepo.GetResourcesByIDs(resourceIDs)
+	if err != nil {
+		return recommender.RecommendationPage{}, err
+	}
+
+	resourcesByID := make(map[uint]models.Resource)
+	for _, resource := range resources {
+		resourcesByID[resource.ID] = resource
+	}
+
+	// resources hidden since the recommendations were computed are left out
+	for _, userRecommendation := range userRecommendations {
+		if resource, ok := resourcesByID[userRecommendation.ResourceID]; ok {
+			recommendationPage.Recommendations = append(recommendationPage.Recommendations, recommender.Recommendation{
+				Resource:    resource,
+				Probability: userRecommendation.Probability,
+				Source:      recommender.SourceOf(userRecommendation.Evidence),
+				Reason:      recommender.Explain(userRecommendation.Evidence),
+				Evidence:    userRecommendation.Evidence,
+			})
+		}
+	}
+
+	return recommendationPage, nil
+}
+
+// CurrentMood gets the mood of the user's latest mood entry, if they logged one in the last RECOMMENDATION_MOOD_WINDOW_HOURS.
+// Returns 0 otherwise.
+func (rs *RecommendationService) CurrentMood(userID uint) (models.MoodType, error) {
+	window, _ := moodSettings()
+
+	mood, err := rs.moodRepo.GetLatestMoodSince(userID, time.Now().Add(-window))
+	if err != nil {
+		return 0, err
+	}
+	return mood.Mood, nil
+}
+
+// getMoodRecommendations re-ranks all of the user's precomputed recommendations for the mood and returns a page of them.
+// Each keeps its precomputed probability, only the order and evidence change. The mood order is by score, so it replaces the diversified order.
+func (rs *RecommendationService) getMoodRecommendations(userID uint, mood models.MoodType, offset, limit int) ([]models.UserRecommendation, int64, error) {
+	userRecommendations, total, err := rs.recommendationRepo.GetUserRecommendations(userID, 0, rs.maxPerUser)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	userSimilarities, err := rs.recommendationRepo.GetUserSimilarities(userID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	similarities := make(map[uint]float64, len(userSimilarities))
+	similarUserIDs := make([]uint, 0, len(userSimilarities))
+	for _, userSimilarity := range userSimilarities {
+		similarities[userSimilarity.OtherUserID] = userSimilarity.Similarity
+		similarUserIDs = append(similarUserIDs, userSimilarity.OtherUserID)
+	}
+
+	items := make([]recommender.ScoredItem, 0, len(userRecommendations))
+	byResourceID := make(map[uint]models.UserRecommendation, len(userRecommendations))
+	resourceIDs := make([]uint, 0, len(userRecommendations))
+	for _, userRecommendation := range userRecommendations {
+		items = append(items, recommender.ScoredItem{ResourceID: userRecommendation.ResourceID, Score: userRecommendation.Probability, Evidence: userRecommendation.Evidence})
+		byResourceID[userRecommendation.ResourceID] = userRecommendation
+		resourceIDs = append(resourceIDs, userRecommendation.ResourceID)
+	}
+
+	reviews, err := rs.recommendationRepo.GetMoodReviews(resourceIDs, similarUserIDs)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	taggedIDs, err := rs.recommendationRepo.GetResourceIDsWithMoodTag(resourceIDs, mood)
+	if err != nil {
+		return nil, 0, err
+	}
+	tagged := make(map[uint]bool, len(taggedIDs))
+	for _, resourceID := range taggedIDs {
+		tagged[resourceID] = true
+	}
+
+	model, err := preference.NewModelFromConfig()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// normalization needs each reviewer's mean over all their reviews, not just those of the recommended resources
+	var userMeans map[uint]float64
+	if model.Normalize {
+		reviewerReviews, err := rs.recommendationRepo.GetReviewsByUserIDs(similarUserIDs)
+		if err != nil {
+			return nil, 0, err
+		}
+		userMeans = model.UserMeans(reviewerReviews)
+	}
+
+	_, weights := moodSettings()
+	reranked := recommender.RerankForMood(items, recommender.MoodContext{
+		Mood:         mood,
+		Similarities: similarities,
+		Reviews:      reviews,
+		Tagged:       tagged,
+		Preferences:  model,
+		UserMeans:    userMeans,
+	}, weights)
+
+	page := []models.UserRecommendation{}
+	for i := offset; i < len(reranked) && i < offset+limit; i++ {
+		userRecommendation := byResourceID[reranked[i].ResourceID]
+		userRecommendation.Evidence = reranked[i].Evidence
+		page = append(page, userRecommendation)
+	}
+	return page, total, nil
+}
+
+// moodSettings reads how far back the user's current mood is looked for, and how much it counts.
+func moodSettings() (time.Duration, recommender.MoodWeights) {
+	window := time.Duration(viper.GetInt("RECOMMENDATION_MOOD_WINDOW_HOURS")) * time.Hour
+	return window, recommender.MoodWeights{
+		Context: viper.GetFloat64("RECOMMENDATION_MOOD_WEIGHT"),
+		Tag:     viper.GetFloat64("RECOMMENDATION_MOOD_TAG_BOOST"),
+	}
+}
+
+// fallbackSettings reads how personalised recommendations are filled up with popular and curated resources,
+// and how much diversity counts when they're ranked (see recommender.Diversify).
+func fallbackSettings() (recommender.FallbackMix, float64) {
+	return recommender.FallbackMix{
+		Popular: viper.GetFloat64("RECOMMENDATION_FALLBACK_POPULAR"),
+		Curated: viper.GetFloat64("RECOMMENDATION_FALLBACK_CURATED"),
+	}, viper.GetFloat64("RECOMMENDATION_DIVERSITY_LAMBDA")
+}
+
+// RecomputeAll recomputes the recommendations of every user who has reviewed or bookmarked something, or asked for recommendations before.
+func (rs *RecommendationService) RecomputeAll() error {
+	loadedAt := time.Now()
+	snapshot, sources, err := rs.loadSources()
+	if err != nil {
+		return err
+	}
+
+	userIDs := snapshot.UserIDs()
+	statusUserIDs, err := rs.recommendationRepo.GetUserIDsWithStatus()
+	if err != nil {
+		return err
+	}
+	userIDs = mergeUserIDs(userIDs, statusUserIDs)
+
+	return rs.recompute(sources, userIDs, loadedAt)
+}
+
+// RecomputeStale recomputes the recommendations of up to limit stale users.
+func (rs *RecommendationService) RecomputeStale(limit int) error {
+	userIDs, err := rs.recommendationRepo.GetStaleUserIDs(limit)
+	if err != nil {
+		return err
+	}
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	loadedAt := time.Now()
+	_, sources, err := rs.loadSources()
+	if err != nil {
+		return err
+	}
+
+	return rs.recompute(sources, userIDs, loadedAt)
+}
+
+// MarkInteractionStale marks recommendations as stale after a user reviews or bookmarks a resource.
+// That changes the user's own recommendations, and their similarity with everyone else who reviewed the same resource.
+func (rs *RecommendationService) MarkInteractionStale(userID, resourceID uint) error {
+	reviewers, err := rs.recommendationRepo.GetUserIDsWhoReviewedResource(resourceID)
+	if err != nil {
+		return err
+	}
+
+	return rs.recommendationRepo.MarkUsersStale(mergeUserIDs([]uint{userID}, reviewers), time.Now())
+}
+
+// DismissRecommendation stops the resource from being recommended to the user, and counts it as a soft dislike from now on.
+// It's removed from the user's precomputed recommendations straight away, the rest are recomputed by the stale recommendations job.
+func (rs *RecommendationService) DismissRecommendation(userID, resourceID uint, reason models.DismissalReason) error {
+	if _, err := rs.getPublishedResource(resourceID); err != nil {
+		return err
+	}
+
+	dismissal := models.RecommendationDismissal{UserID: userID, ResourceID: resourceID, Reason: reason}
+	if err := rs.recommendationRepo.CreateDismissal(&dismissal); err != nil {
+		return err
+	}
+	if err := rs.recommendationRepo.DeleteUserRecommendation(userID, resourceID); err != nil {
+		return err
+	}
+
+	return rs.MarkInteractionStale(userID, resourceID)
+}
+
+// getPublishedResource gets a resource which can be recommended, returning ErrResourceNotFound for one which doesn't exist
+// or isn't published.
+func (rs *RecommendationService) getPublishedResource(resourceID uint) (models.Resource, error) {
+	resource, err := rs.resourceRepo.GetResourceByID(resourceID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return models.Resource{}, ErrResourceNotFound
+	}
+	if err != nil {
+		return models.Resource{}, err
+	}
+	if resource.Status != models.Published {
+		return models.Resource{}, ErrResourceNotFound
+	}
+	return resource, nil
+}
+
+// UndoDismissal lets the resource be recommended to the user again, once their recommendations are recomputed.
+func (rs *RecommendationService) UndoDismissal(userID, resourceID uint) error {
+	// the other users who interacted with the resource have to be found while the dismissal still counts
+	if err := rs.MarkInteractionStale(userID, resourceID); err != nil {
+		return err
+	}
+	return rs.recommendationRepo.DeleteDismissal(userID, resourceID)
+}
+
+// GetDismissals gets the recommendations the user has dismissed, most recent first.
+// Dismissals of resources which have since been deleted or hidden are left out.
+func (rs *RecommendationService) GetDismissals(userID uint) ([]models.DismissalResponse, error) {
+	dismissals, err := rs.recommendationRepo.GetDismissalsByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	resourceIDs := make([]uint, 0, len(dismissals))
+	for _, dismissal := range dismissals {
+		resourceIDs = append(resourceIDs, dismissal.ResourceID)
+	}
+
+	resources, err := rs.resourceRepo.GetResourcesByIDs(resourceIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	resourcesByID := make(map[uint]models.Resource)
+	for _, resource := range resources {
+		resourcesByID[resource.ID] = resource
+	}
+
+	dismissalResponses := []models.DismissalResponse{}
+	for _, dismissal := range dismissals {
+		if resource, ok := resourcesByID[dismissal.ResourceID]; ok {
+			dismissalResponses = append(dismissalResponses, models.DismissalResponse{
+				Resource:    resource,
+				Reason:      dismissal.Reason,
+				DismissedAt: dismissal.UpdatedAt,
+			})
+		}
+	}
+	return dismissalResponses, nil
+}
+
+// ErrAlreadyCurated is returned when curating a resource which is already curated.
+var ErrAlreadyCurated = errors.New("resource is already curated")
+
+// CurateResource adds a published resource to the curated resources recommended to everyone.
+// Recommendations pick up the change when they are next fully recomputed.
+func (rs *RecommendationService) CurateResource(adminID, resourceID uint) error {
+	if _, err := rs.getPublishedResource(resourceID); err != nil {
+		return err
+	}
+
+	created, err := rs.recommendationRepo.CreateCuratedResource(&models.CuratedResource{ResourceID: resourceID, CuratedBy: adminID})
+	if err != nil {
+		return err
+	}
+	if !created {
+		return ErrAlreadyCurated
+	}
+	return nil
+}
+
+// UncurateResource removes a resource from the curated resources.
+func (rs *RecommendationService) UncurateResource(resourceID uint) error {
+	return rs.recommendationRepo.DeleteCuratedResource(resourceID)
+}
+
+// GetCuratedResources gets the curated resources which are still published, most recently curated first.
+func (rs *RecommendationService) GetCuratedResources() ([]models.Resource, error) {
+	resourceIDs, err := rs.recommendationRepo.GetCuratedResourceIDs()
+	if err != nil {
+		return nil, err
+	}
+
+	resources, err := rs.resourceRepo.GetResourcesByIDs(resourceIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	resourcesByID := make(map[uint]models.Resource)
+	for _, resource := range resources {
+		resourcesByID[resource.ID] = resource
+	}
+
+	curated := []models.Resource{}
+	for _, resourceID := range resourceIDs {
+		if resource, ok := resourcesByID[resourceID]; ok {
+			curated = append(curated, resource)
+		}
+	}
+	return curated, nil
+}
+
+// ErrTrainingInProgress is returned when a matrix factorization model is asked to be trained while one already is.
+var ErrTrainingInProgress = errors.New("a matrix factorization model is already being trained")
+
+// factorizationTraining is set while a model is being trained, by any RecommendationService, so they're trained one at a time.
+var factorizationTraining atomic.Bool
+
+// TrainFactorizationModel trains a new version of the matrix factorization model on everyone's preferences and activates it,
+// keeping the newest RECOMMENDATION_MF_KEEP_VERSIONS versions for rolling back.
+func (rs *RecommendationService) TrainFactorizationModel() (models.FactorizationModel, error) {
+	if !factorizationTraining.CompareAndSwap(false, true) {
+		return models.FactorizationModel{}, ErrTrainingInProgress
+	}
+	defer factorizationTraining.Store(false)
+	return rs.trainFactorizationModel()
+}
+
+// QueueFactorizationTraining starts training a new version of the matrix factorization model in the background,
+// as TrainFactorizationModel does. Training takes a while, so its errors are only logged.
+func (rs *RecommendationService) QueueFactorizationTraining() error {
+	if !factorizationTraining.CompareAndSwap(false, true) {
+		return ErrTrainingInProgress
+	}
+
+	go func() {
+		defer factorizationTraining.Store(false)
+		defer func() {
+			if r := recover(); r != nil {
+				logger.Errorf("Training a matrix factorization model panicked: %v", r)
+			}
+		}()
+
+		if _, err := rs.trainFactorizationModel(); err != nil {
+			logger.Errorf("Training a matrix factorization model failed: %v", err)
+		}
+	}()
+	return nil
+}
+
+func (rs *RecommendationService) trainFactorizationModel() (models.FactorizationModel, error) {
+	hyperparameters, err := recommender.NewHyperparametersFromConfig()
+	if err != nil {
+		return models.FactorizationModel{}, err
+	}
+
+	snapshot, _, err := rs.loadSources()
+	if err != nil {
+		return models.FactorizationModel{}, err
+	}
+
+	start := time.Now()
+	factors := recommender.TrainFactorization(snapshot, hyperparameters)
+	userFactors, resourceFactors := factors.Rows(0)
+
+	model := models.FactorizationModel{
+		Hyperparameters: hyperparameters,
+		Users:           len(userFactors),
+		Resources:       len(resourceFactors),
+		Interactions:    factors.Interactions,
+		RMSE:            factors.RMSE,
+		TrainedAt:       start,
+	}
+	if err := rs.recommendationRepo.SaveFactorizationModel(&model, userFactors, resourceFactors); err != nil {
+		return models.FactorizationModel{}, err
+	}
+	logger.Infof("Trained matrix factorization model %d on %d interactions in %s, RMSE %.4f", model.ID, model.Interactions, time.Since(start), model.RMSE)
+
+	if err := rs.recommendationRepo.PruneFactorizationModels(factorizationVersionsKept()); err != nil {
+		return model, err
+	}
+	return model, rs.markFactorizationUsersStale()
+}
+
+// GetFactorizationModels gets the stored versions of the matrix factorization model, newest first.
+func (rs *RecommendationService) GetFactorizationModels() ([]models.FactorizationModel, error) {
+	return rs.recommendationRepo.GetFactorizationModels()
+}
+
+// ActivateFactorizationModel switches to another stored version of the matrix factorization model, e.g. to roll back a bad one.
+func (rs *RecommendationService) ActivateFactorizationModel(modelID uint) error {
+	if err := rs.recommendationRepo.ActivateFactorizationModel(modelID); err != nil {
+		return err
+	}
+	return rs.markFactorizationUsersStale()
+}
+
+// markFactorizationUsersStale queues everyone's recommendations for the stale recommendations job after the active matrix
+// factorization model changes, if the configured algorithm uses it.
+func (rs *RecommendationService) markFactorizationUsersStale() error {
+	if !recommender.Uses(rs.algorithm, recommender.FactorizationAlgorithm) {
+		return nil
+	}
+
+	userIDs, err := rs.recommendationRepo.GetUserIDsWithStatus()
+	if err != nil {
+		return err
+	}
+	return rs.recommendationRepo.MarkUsersStale(userIDs, time.Now())
+}
+
+// factorizationVersionsKept reads how many versions of the matrix factorization model are kept.
+func factorizationVersionsKept() int {
+	return viper.GetInt("RECOMMENDATION_MF_KEEP_VERSIONS")
+}
+
+// loadSources loads everything recommendations are computed from into memory.
+func (rs *RecommendationService) loadSources() (*recommender.Snapshot, recommender.Sources, error) {
+	reviews, err := rs.recommendationRepo.GetPublishedReviews()
+	if err != nil {
+		return nil, recommender.Sources{}, err
+	}
+
+	bookmarks, err := rs.recommendationRepo.GetBookmarks()
+	if err != nil {
+		return nil, recommender.Sources{}, err
+	}
+
+	dismissals, err := rs.recommendationRepo.GetDismissals()
+	if err != nil {
+		return nil, recommender.Sources{}, err
+	}
+
+	resources, err := rs.recommendationRepo.GetPublishedResources()
+	if err != nil {
+		return nil, recommender.Sources{}, err
+	}
+
+	curatedIDs, err := rs.recommendationRepo.GetCuratedResourceIDs()
+	if err != nil {
+		return nil, recommender.Sources{}, err
+	}
+
+	model, err := preference.NewModelFromConfig()
+	if err != nil {
+		return nil, recommender.Sources{}, err
+	}
+
+	resourceIDs := make([]uint, 0, len(resources))
+	published := make(map[uint]bool, len(resources))
+	for _, resource := range resources {
+		resourceIDs = append(resourceIDs, resource.ID)
+		published[resource.ID] = true
+	}
+
+	// curated resources may have been hidden since
+	var curated []uint
+	for _, resourceID := range curatedIDs {
+		if published[resourceID] {
+			curated = append(curated, resourceID)
+		}
+	}
+
+	// the matrix factorization model is only loaded when it's used
+	var factors *recommender.FactorModel
+	if recommender.Uses(rs.algorithm, recommender.FactorizationAlgorithm) {
+		factors, err = rs.loadFactors()
+		if err != nil {
+			return nil, recommender.Sources{}, err
+		}
+	}
+
+	now := time.Now()
+	snapshot := recommender.NewSnapshot(reviews, bookmarks, dismissals, resourceIDs, model, now)
+	return snapshot, recommender.Sources{
+		Reviews:    snapshot,
+		Content:    recommender.NewContentIndex(resources),
+		Popularity: recommender.NewPopularityIndex(reviews, resourceIDs, now),
+		Curated:    curated,
+		Factors:    factors,
+	}, nil
+}
+
+// loadFactors loads the active matrix factorization model. Returns nil if none was trained yet.
+func (rs *RecommendationService) loadFactors() (*recommender.FactorModel, error) {
+	active, err := rs.recommendationRepo.GetActiveFactorizationModel()
+	if err != nil || active.ID == 0 {
+		return nil, err
+	}
+
+	userFactors, resourceFactors, err := rs.recommendationRepo.GetFactors(active.ID)
+	if err != nil {
+		return nil, err
+	}
+	return recommender.NewFactorModel(userFactors, resourceFactors), nil
+}
+
+// recompute computes and saves the recommendations of the users from sources loaded at loadedAt. They're saved as computed
+// at loadedAt, so users who interacted with resources since are left stale for the next run.
+func (rs *RecommendationService) recompute(sources recommender.Sources, userIDs []uint, loadedAt time.Time) error {
+	start := time.Now()
+
+	personalized, err := recommender.New(rs.algorithm, sources)
+	if err != nil {
+		return err
+	}
+	mix, lambda := fallbackSettings()
+	r := recommender.WithFallback(personalized, sources, mix)
+	similarUsersProvider, providesSimilarUsers := personalized.(recommender.SimilarUsersProvider)
+
+	for _, userID := range userIDs {
+		ranked, err := r.Recommend(userID, recommender.Options{Limit: rs.maxPerUser})
+		if err != nil {
+			return err
+		}
+		ranked = recommender.Diversify(ranked, sources.Content.Similarity, lambda)
+
+		// only some algorithms work out user-user similarities, which they keep from Recommend rather than working them out again
+		similarities := map[uint]float64{}
+		if providesSimilarUsers {
+			similarities, err = similarUsersProvider.SimilarUsers(userID)
+			if err != nil {
+				return err
+			}
+		}
+
+		userSimilarities := make([]models.UserSimilarity, 0, len(similarities))
+		for otherUserID, similarity := range similarities {
+			userSimilarities = append(userSimilarities, models.UserSimilarity{
+				UserID:      userID,
+				OtherUserID: otherUserID,
+				Similarity:  similarity,
+				ComputedAt:  loadedAt,
+			})
+		}
+
+		userRecommendations := make([]models.UserRecommendation, 0, len(ranked))
+		for i, scored := range ranked {
+			userRecommendations = append(userRecommendations, models.UserRecommendation{
+				UserID:      userID,
+				ResourceID:  scored.ResourceID,
+				Probability: scored.Score,
+				Rank:        i + 1,
+				Evidence:    scored.Evidence,
+				ComputedAt:  loadedAt,
+			})
+		}
+
+		if err := rs.recommendationRepo.SaveUserRecommendations(userID, userSimilarities, userRecommendations, loadedAt); err != nil {
+			return err
+		}
+	}
+
+	logger.Infof("Computed recommendations for %d users in %s", len(userIDs), time.Since(start))
+	return nil
+}
+
+// mergeUserIDs returns the union of both lists, keeping the order of first appearance.
+func mergeUserIDs(a, b []uint) []uint {
+	seen := make(map[uint]bool)
+	var merged []uint
+	for _, userID := range append(append([]uint{}, a...), b...) {
+		if !seen[userID] {
+			seen[userID] = true
+			merged = append(merged, userID)
+		}
+	}
+	return merged
+}
@@ -0,0 +1,346 @@
+package services
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/anirudhgray/mood-harbour-backend/mocks"
+	"github.com/anirudhgray/mood-harbour-backend/models"
+	"github.com/anirudhgray/mood-harbour-backend/utils/recommender"
+	"github.com/golang/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func TestRecommendationService_GetRecommendations_NotComputedYet(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRecommendationRepo := mocks.NewMockRecommendationRepositoryInterface(ctrl)
+	rs := NewRecommendationService(mockRecommendationRepo, mocks.NewMockResourceRepositoryInterface(ctrl), mocks.NewMockMoodRepositoryInterface(ctrl), 0, recommender.UserBasedAlgorithm)
+	userID := uint(1)
+
+	mockRecommendationRepo.EXPECT().GetRecommendationStatus(userID).Return(models.RecommendationStatus{}, nil)
+	// the user is queued for the stale recommendations job instead of computing anything during the request
+	mockRecommendationRepo.EXPECT().MarkUsersStale([]uint{userID}, gomock.Any()).Return(nil)
+
+	recommendationPage, err := rs.GetRecommendations(userID, 1, 20, 0)
+	if err != nil {
+		t.Fatalf("GetRecommendations returned an error: %v", err)
+	}
+	if recommendationPage.ComputedAt != nil || !recommendationPage.Stale || len(recommendationPage.Recommendations) != 0 {
+		t.Errorf("Expected an empty, stale page, got: %+v", recommendationPage)
+	}
+}
+
+func TestRecommendationService_GetRecommendations(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRecommendationRepo := mocks.NewMockRecommendationRepositoryInterface(ctrl)
+	mockResourceRepo := mocks.NewMockResourceRepositoryInterface(ctrl)
+	mockMoodRepo := mocks.NewMockMoodRepositoryInterface(ctrl)
+	rs := NewRecommendationService(mockRecommendationRepo, mockResourceRepo, mockMoodRepo, 0, recommender.UserBasedAlgorithm)
+	userID := uint(1)
+	computedAt := time.Now().Add(-time.Hour)
+
+	mockRecommendationRepo.EXPECT().GetRecommendationStatus(userID).Return(models.RecommendationStatus{UserID: userID, ComputedAt: &computedAt}, nil)
+	// no mood logged recently, so the precomputed order is kept
+	mockMoodRepo.EXPECT().GetLatestMoodSince(userID, gomock.Any()).Return(models.Mood{}, nil)
+	mockRecommendationRepo.EXPECT().GetUserRecommendations(userID, 2, 2).Return([]models.UserRecommendation{
+		{UserID: userID, ResourceID: 30, Probability: 0.5, Rank: 3, Evidence: models.RecommendationEvidence{Algorithm: recommender.UserBasedAlgorithm, SimilarUsersLiked: 2}},
+		{UserID: userID, ResourceID: 10, Probability: 0.4, Rank: 4},
+	}, int64(5), nil)
+	// resource 10 was hidden after the recommendations were computed
+	mockResourceRepo.EXPECT().GetResourcesByIDs([]uint{30, 10}).Return([]models.Resource{{Model: gorm.Model{ID: 30}}}, nil)
+
+	recommendationPage, err := rs.GetRecommendations(userID, 2, 2, 0)
+	if err != nil {
+		t.Fatalf("GetRecommendations returned an error: %v", err)
+	}
+	if recommendationPage.ComputedAt == nil || !recommendationPage.ComputedAt.Equal(computedAt) {
+		t.Errorf("Expected the freshness timestamp to be returned, got: %v", recommendationPage.ComputedAt)
+	}
+	if recommendationPage.Total != 5 || len(recommendationPage.Recommendations) != 1 || recommendationPage.Recommendations[0].Resource.ID != 30 {
+		t.Fatalf("GetRecommendations returned the wrong page: %+v", recommendationPage)
+	}
+	if reason := recommendationPage.Recommendations[0].Reason; reason != "Liked by 2 people with similar taste." {
+		t.Errorf("Unexpected reason: %q", reason)
+	}
+}
+
+func TestRecommendationService_GetRecommendations_Mood(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRecommendationRepo := mocks.NewMockRecommendationRepositoryInterface(ctrl)
+	mockResourceRepo := mocks.NewMockResourceRepositoryInterface(ctrl)
+	mockMoodRepo := mocks.NewMockMoodRepositoryInterface(ctrl)
+	rs := NewRecommendationService(mockRecommendationRepo, mockResourceRepo, mockMoodRepo, 0, recommender.UserBasedAlgorithm)
+	userID := uint(1)
+	computedAt := time.Now().Add(-time.Hour)
+
+	mockRecommendationRepo.EXPECT().GetRecommendationStatus(userID).Return(models.RecommendationStatus{UserID: userID, ComputedAt: &computedAt}, nil).Times(2)
+	mockRecommendationRepo.EXPECT().GetUserRecommendations(userID, 0, DefaultMaxRecommendationsPerUser).Return([]models.UserRecommendation{
+		{UserID: userID, ResourceID: 10, Probability: 0.6, Rank: 1},
+		{UserID: userID, ResourceID: 20, Probability: 0.5, Rank: 2},
+		{UserID: userID, ResourceID: 30, Probability: 0.45, Rank: 3},
+	}, int64(3), nil).Times(2)
+	mockRecommendationRepo.EXPECT().GetUserSimilarities(userID).Return([]models.UserSimilarity{
+		{UserID: userID, OtherUserID: 2, Similarity: 0.5},
+	}, nil).Times(2)
+	// user 2 loved resource 20 while sad, and disliked resource 10 while angry
+	mockRecommendationRepo.EXPECT().GetMoodReviews([]uint{10, 20, 30}, []uint{2}).Return([]models.Review{
+		{UserID: 2, ResourceID: 20, Rating: 5, ReviewerMood: models.Sad},
+		{UserID: 2, ResourceID: 10, Rating: 1, ReviewerMood: models.Angry},
+	}, nil).Times(2)
+	mockResourceRepo.EXPECT().GetResourcesByIDs(gomock.Any()).DoAndReturn(func(resourceIDs []uint) ([]models.Resource, error) {
+		var resources []models.Resource
+		for _, resourceID := range resourceIDs {
+			resources = append(resources, models.Resource{Model: gorm.Model{ID: resourceID}})
+		}
+		return resources, nil
+	}).Times(2)
+
+	testCases := []struct {
+		name          string
+		mood          models.MoodType
+		tagged        []uint
+		expectedOrder []uint
+		expectedFirst models.RecommendationEvidence
+	}{
+		// the latest logged mood is used, and angry is close enough to sad for the dislike to count half
+		{"Inferred sad mood", 0, nil, []uint{20, 30, 10}, models.RecommendationEvidence{Mood: models.Sad, SimilarMoodLikes: 1}},
+		// neither review counts for an excited mood, but resource 30 is tagged for it
+		{"Excited mood override", models.Excited, []uint{30}, []uint{30, 10, 20}, models.RecommendationEvidence{Mood: models.Excited, MoodTagged: true}},
+	}
+
+	mockMoodRepo.EXPECT().GetLatestMoodSince(userID, gomock.Any()).Return(models.Mood{UserID: userID, Mood: models.Sad}, nil)
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			expectedMood := tc.mood
+			if expectedMood == 0 {
+				expectedMood = models.Sad
+			}
+			mockRecommendationRepo.EXPECT().GetResourceIDsWithMoodTag([]uint{10, 20, 30}, expectedMood).Return(tc.tagged, nil)
+
+			recommendationPage, err := rs.GetRecommendations(userID, 1, 20, tc.mood)
+			if err != nil {
+				t.Fatalf("GetRecommendations returned an error: %v", err)
+			}
+			if recommendationPage.Mood != expectedMood {
+				t.Errorf("Expected mood %d, got: %d", expectedMood, recommendationPage.Mood)
+			}
+			if len(recommendationPage.Recommendations) != len(tc.expectedOrder) {
+				t.Fatalf("Expected %d recommendations, got: %d", len(tc.expectedOrder), len(recommendationPage.Recommendations))
+			}
+			for i, resourceID := range tc.expectedOrder {
+				if recommendationPage.Recommendations[i].Resource.ID != resourceID {
+					t.Errorf("Expected resource %d at position %d, got: %d", resourceID, i, recommendationPage.Recommendations[i].Resource.ID)
+				}
+			}
+			if evidence := recommendationPage.Recommendations[0].Evidence; !reflect.DeepEqual(evidence, tc.expectedFirst) {
+				t.Errorf("Expected evidence %+v, got: %+v", tc.expectedFirst, evidence)
+			}
+		})
+	}
+}
+
+func TestRecommendationService_RecomputeStale(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRecommendationRepo := mocks.NewMockRecommendationRepositoryInterface(ctrl)
+	rs := NewRecommendationService(mockRecommendationRepo, mocks.NewMockResourceRepositoryInterface(ctrl), mocks.NewMockMoodRepositoryInterface(ctrl), 0, recommender.UserBasedAlgorithm)
+
+	reviews := []models.Review{
+		{UserID: 1, ResourceID: 1, Rating: 5, Status: models.Published},
+		{UserID: 2, ResourceID: 1, Rating: 5, Status: models.Published},
+		{UserID: 2, ResourceID: 2, Rating: 4, Status: models.Published},
+		{UserID: 2, ResourceID: 3, Rating: 5, Status: models.Published},
+	}
+
+	mockRecommendationRepo.EXPECT().GetStaleUserIDs(10).Return([]uint{1}, nil)
+	var loadedAt time.Time
+	mockRecommendationRepo.EXPECT().GetPublishedReviews().DoAndReturn(func() ([]models.Review, error) {
+		loadedAt = time.Now()
+		return reviews, nil
+	})
+	mockRecommendationRepo.EXPECT().GetBookmarks().Return(nil, nil)
+	// user 1 isn't interested in resource 3
+	mockRecommendationRepo.EXPECT().GetDismissals().Return([]models.RecommendationDismissal{{UserID: 1, ResourceID: 3, Reason: models.NotInterested}}, nil)
+	mockRecommendationRepo.EXPECT().GetPublishedResources().Return([]models.Resource{{Model: gorm.Model{ID: 1}}, {Model: gorm.Model{ID: 2}}, {Model: gorm.Model{ID: 3}}, {Model: gorm.Model{ID: 4}}}, nil)
+	// resource 5 has been hidden since it was curated
+	mockRecommendationRepo.EXPECT().GetCuratedResourceIDs().Return([]uint{5, 4}, nil)
+	mockRecommendationRepo.EXPECT().SaveUserRecommendations(uint(1), gomock.Any(), gomock.Any(), gomock.Any()).
+		Do(func(userID uint, similarities []models.UserSimilarity, recommendations []models.UserRecommendation, startedAt time.Time) {
+			// interactions while the sources were being loaded must leave the user stale
+			if startedAt.After(loadedAt) {
+				t.Errorf("Expected the recommendations to be saved as of before the sources were loaded, got %v after %v", startedAt, loadedAt)
+			}
+			if len(similarities) != 1 || similarities[0].OtherUserID != 2 {
+				t.Errorf("Expected user 2 to be the only similar user, got: %v", similarities)
+			}
+			// popular resources are ones the user has already reviewed or dismissed, so only the curated one fills up the list
+			if len(recommendations) != 2 || recommendations[0].ResourceID != 2 || recommendations[0].Rank != 1 ||
+				recommendations[1].ResourceID != 4 || recommender.SourceOf(recommendations[1].Evidence) != recommender.SourceCurated {
+				t.Errorf("Expected resource 2 to be recommended, followed by curated resource 4, got: %v", recommendations)
+			}
+		}).Return(nil)
+
+	if err := rs.RecomputeStale(10); err != nil {
+		t.Errorf("RecomputeStale returned an error: %v", err)
+	}
+}
+
+func TestRecommendationService_TrainFactorizationModel(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRecommendationRepo := mocks.NewMockRecommendationRepositoryInterface(ctrl)
+	rs := NewRecommendationService(mockRecommendationRepo, mocks.NewMockResourceRepositoryInterface(ctrl), mocks.NewMockMoodRepositoryInterface(ctrl), 0, recommender.FactorizationAlgorithm+"+"+recommender.ContentBasedAlgorithm)
+
+	reviews := []models.Review{
+		{UserID: 1, ResourceID: 1, Rating: 5, Status: models.Published},
+		{UserID: 2, ResourceID: 1, Rating: 5, Status: models.Published},
+		{UserID: 2, ResourceID: 2, Rating: 1, Status: models.Published},
+	}
+
+	mockRecommendationRepo.EXPECT().GetPublishedReviews().Return(reviews, nil)
+	mockRecommendationRepo.EXPECT().GetBookmarks().Return([]models.Bookmark{{UserID: 1, ResourceID: 3}}, nil)
+	mockRecommendationRepo.EXPECT().GetDismissals().Return(nil, nil)
+	mockRecommendationRepo.EXPECT().GetPublishedResources().Return([]models.Resource{{Model: gorm.Model{ID: 1}}, {Model: gorm.Model{ID: 2}}, {Model: gorm.Model{ID: 3}}}, nil)
+	mockRecommendationRepo.EXPECT().GetCuratedResourceIDs().Return(nil, nil)
+	mockRecommendationRepo.EXPECT().GetActiveFactorizationModel().Return(models.FactorizationModel{}, nil)
+	mockRecommendationRepo.EXPECT().SaveFactorizationModel(gomock.Any(), gomock.Any(), gomock.Any()).
+		Do(func(model *models.FactorizationModel, userFactors []models.UserFactor, resourceFactors []models.ResourceFactor) {
+			if model.Users != 2 || model.Resources != 3 || model.Interactions != 4 || model.Hyperparameters != recommender.DefaultHyperparameters {
+				t.Errorf("Unexpected model: %+v", model)
+			}
+			if len(userFactors) != 2 || len(resourceFactors) != 3 || len(userFactors[0].Vector) != recommender.DefaultHyperparameters.Factors {
+				t.Errorf("Unexpected factors: %+v, %+v", userFactors, resourceFactors)
+			}
+			model.ID = 7
+		}).Return(nil)
+	mockRecommendationRepo.EXPECT().PruneFactorizationModels(3).Return(nil)
+	// the configured algorithm uses the model, so everyone's recommendations are recomputed
+	mockRecommendationRepo.EXPECT().GetUserIDsWithStatus().Return([]uint{1, 2, 3}, nil)
+	mockRecommendationRepo.EXPECT().MarkUsersStale([]uint{1, 2, 3}, gomock.Any()).Return(nil)
+
+	model, err := rs.TrainFactorizationModel()
+	if err != nil {
+		t.Fatalf("TrainFactorizationModel returned an error: %v", err)
+	}
+	if model.ID != 7 {
+		t.Errorf("Expected the saved model, got: %+v", model)
+	}
+
+	// models are trained one at a time, whether on schedule or queued by an admin
+	factorizationTraining.Store(true)
+	defer factorizationTraining.Store(false)
+	if _, err := rs.TrainFactorizationModel(); !errors.Is(err, ErrTrainingInProgress) {
+		t.Errorf("Expected ErrTrainingInProgress while training, got: %v", err)
+	}
+	if err := rs.QueueFactorizationTraining(); !errors.Is(err, ErrTrainingInProgress) {
+		t.Errorf("Expected ErrTrainingInProgress while training, got: %v", err)
+	}
+}
+
+func TestRecommendationService_ActivateFactorizationModel(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRecommendationRepo := mocks.NewMockRecommendationRepositoryInterface(ctrl)
+	rs := NewRecommendationService(mockRecommendationRepo, mocks.NewMockResourceRepositoryInterface(ctrl), mocks.NewMockMoodRepositoryInterface(ctrl), 0, recommender.UserBasedAlgorithm)
+
+	// user-based recommendations don't depend on the model, so nobody is marked stale
+	mockRecommendationRepo.EXPECT().ActivateFactorizationModel(uint(3)).Return(nil)
+
+	if err := rs.ActivateFactorizationModel(3); err != nil {
+		t.Errorf("ActivateFactorizationModel returned an error: %v", err)
+	}
+}
+
+func TestRecommendationService_DismissRecommendation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRecommendationRepo := mocks.NewMockRecommendationRepositoryInterface(ctrl)
+	mockResourceRepo := mocks.NewMockResourceRepositoryInterface(ctrl)
+	rs := NewRecommendationService(mockRecommendationRepo, mockResourceRepo, mocks.NewMockMoodRepositoryInterface(ctrl), 0, recommender.UserBasedAlgorithm)
+	userID, resourceID := uint(1), uint(10)
+
+	mockResourceRepo.EXPECT().GetResourceByID(resourceID).Return(models.Resource{Model: gorm.Model{ID: resourceID}, Status: models.Published}, nil)
+	mockRecommendationRepo.EXPECT().CreateDismissal(&models.RecommendationDismissal{UserID: userID, ResourceID: resourceID, Reason: models.AlreadySeen}).Return(nil)
+	mockRecommendationRepo.EXPECT().DeleteUserRecommendation(userID, resourceID).Return(nil)
+	mockRecommendationRepo.EXPECT().GetUserIDsWhoReviewedResource(resourceID).Return([]uint{2}, nil)
+	mockRecommendationRepo.EXPECT().MarkUsersStale([]uint{userID, 2}, gomock.Any()).Return(nil)
+
+	if err := rs.DismissRecommendation(userID, resourceID, models.AlreadySeen); err != nil {
+		t.Errorf("DismissRecommendation returned an error: %v", err)
+	}
+
+	// hidden and missing resources can't be dismissed
+	mockResourceRepo.EXPECT().GetResourceByID(uint(20)).Return(models.Resource{Model: gorm.Model{ID: 20}, Status: models.Hidden}, nil)
+	if err := rs.DismissRecommendation(userID, 20, models.NotInterested); !errors.Is(err, ErrResourceNotFound) {
+		t.Errorf("Expected ErrResourceNotFound when dismissing a hidden resource, got %v", err)
+	}
+	mockResourceRepo.EXPECT().GetResourceByID(uint(30)).Return(models.Resource{}, gorm.ErrRecordNotFound)
+	if err := rs.DismissRecommendation(userID, 30, models.NotInterested); !errors.Is(err, ErrResourceNotFound) {
+		t.Errorf("Expected ErrResourceNotFound when dismissing a missing resource, got %v", err)
+	}
+}
+
+func TestRecommendationService_CurateResource(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRecommendationRepo := mocks.NewMockRecommendationRepositoryInterface(ctrl)
+	mockResourceRepo := mocks.NewMockResourceRepositoryInterface(ctrl)
+	rs := NewRecommendationService(mockRecommendationRepo, mockResourceRepo, mocks.NewMockMoodRepositoryInterface(ctrl), 0, recommender.UserBasedAlgorithm)
+	adminID := uint(1)
+	published := models.Resource{Model: gorm.Model{ID: 10}, Status: models.Published}
+
+	mockResourceRepo.EXPECT().GetResourceByID(uint(10)).Return(published, nil).Times(2)
+	mockRecommendationRepo.EXPECT().CreateCuratedResource(&models.CuratedResource{ResourceID: 10, CuratedBy: adminID}).Return(true, nil)
+	if err := rs.CurateResource(adminID, 10); err != nil {
+		t.Errorf("CurateResource returned an error: %v", err)
+	}
+
+	mockRecommendationRepo.EXPECT().CreateCuratedResource(&models.CuratedResource{ResourceID: 10, CuratedBy: adminID}).Return(false, nil)
+	if err := rs.CurateResource(adminID, 10); !errors.Is(err, ErrAlreadyCurated) {
+		t.Errorf("Expected ErrAlreadyCurated when curating a resource twice, got %v", err)
+	}
+
+	mockResourceRepo.EXPECT().GetResourceByID(uint(20)).Return(models.Resource{}, gorm.ErrRecordNotFound)
+	if err := rs.CurateResource(adminID, 20); !errors.Is(err, ErrResourceNotFound) {
+		t.Errorf("Expected ErrResourceNotFound when curating a missing resource, got %v", err)
+	}
+}
+
+func TestRecommendationService_GetDismissals(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRecommendationRepo := mocks.NewMockRecommendationRepositoryInterface(ctrl)
+	mockResourceRepo := mocks.NewMockResourceRepositoryInterface(ctrl)
+	rs := NewRecommendationService(mockRecommendationRepo, mockResourceRepo, mocks.NewMockMoodRepositoryInterface(ctrl), 0, recommender.UserBasedAlgorithm)
+	userID := uint(1)
+
+	mockRecommendationRepo.EXPECT().GetDismissalsByUserID(userID).Return([]models.RecommendationDismissal{
+		{UserID: userID, ResourceID: 20, Reason: models.AlreadySeen},
+		{UserID: userID, ResourceID: 10, Reason: models.NotInterested},
+	}, nil)
+	// resource 10 has been hidden since
+	mockResourceRepo.EXPECT().GetResourcesByIDs([]uint{20, 10}).Return([]models.Resource{{Model: gorm.Model{ID: 20}}}, nil)
+
+	dismissals, err := rs.GetDismissals(userID)
+	if err != nil {
+		t.Fatalf("GetDismissals returned an error: %v", err)
+	}
+	if len(dismissals) != 1 || dismissals[0].Resource.ID != 20 || dismissals[0].Reason != models.AlreadySeen {
+		t.Errorf("GetDismissals returned the wrong dismissals: %+v", dismissals)
+	}
+}
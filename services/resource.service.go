@@ -2,18 +2,31 @@ package services
 
 import (
 	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
 
+	"github.com/anirudhgray/mood-harbour-backend/infra/logger"
 	"github.com/anirudhgray/mood-harbour-backend/models"
 	"github.com/anirudhgray/mood-harbour-backend/repository"
+	"github.com/anirudhgray/mood-harbour-backend/utils/linkpreview"
+	"github.com/anirudhgray/mood-harbour-backend/utils/markdown"
+	"github.com/anirudhgray/mood-harbour-backend/utils/ranking"
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
 )
 
 type ResourceService struct {
-	resourceRepo repository.ResourceRepositoryInterface
-	userRepo     repository.UserRepositoryInterface
+	resourceRepo          repository.ResourceRepositoryInterface
+	userRepo              repository.UserRepositoryInterface
+	moderationService     ModerationServiceInterface
+	linkFetcher           linkpreview.Fetcher
+	recommendationService RecommendationServiceInterface
 }
 
-func NewResourceService(resourceRepo repository.ResourceRepositoryInterface, userRepo repository.UserRepositoryInterface) *ResourceService {
-	return &ResourceService{resourceRepo, userRepo}
+func NewResourceService(resourceRepo repository.ResourceRepositoryInterface, userRepo repository.UserRepositoryInterface, moderationService ModerationServiceInterface, linkFetcher linkpreview.Fetcher, recommendationService RecommendationServiceInterface) *ResourceService {
+	return &ResourceService{resourceRepo, userRepo, moderationService, linkFetcher, recommendationService}
 }
 
 type ResourceServiceInterface interface {
@@ -24,34 +37,67 @@ type ResourceServiceInterface interface {
 	UpdateResource(resourceID uint, userID uint, title, content, url string, external, adminPost bool) (models.ResourceResponse, error)
 	GetAdminResources() ([]models.ResourceResponse, error)
 	AddReview(resourceID, userID uint, content string, rating models.Rating) error
+	GetResourceReviews(resourceID uint, page, perPage int) (models.ReviewPage, error)
+	GetUserReviews(userID uint) ([]models.ReviewResponse, error)
+	VoteOnReview(reviewID, userID uint, helpful bool) error
+	RemoveReviewVote(reviewID, userID uint) error
+	SetResourceMoodTags(resourceID, userID uint, moods []models.MoodType) (models.ResourceResponse, error)
 }
 
+// DefaultReviewsPerPage is the number of reviews returned alongside a resource, and the default page size for review listings.
+const DefaultReviewsPerPage = 10
+
 // CreateResourceEntry creates a new resource entry in the database.
+// If pre-moderation is enabled, resources created by users without the PublishResources permission stay pending until a moderator approves them.
 func (rs *ResourceService) CreateResourceEntry(userID uint, title, content, url string, external, adminPost bool) (models.ResourceResponse, error) {
-	resource := models.Resource{
-		CreatedBy: userID,
-		Title:     title,
-		Content:   content,
-		URL:       url,
-		External:  external,
-		AdminPost: adminPost,
+	user, err := rs.userRepo.GetUserByID(userID)
+	if err != nil {
+		return models.ResourceResponse{}, err
+	}
+
+	status := models.Published
+	if viper.GetBool("PRE_MODERATION") && !user.Can(models.PublishResources) {
+		status = models.PendingModeration
 	}
 
-	err := rs.resourceRepo.CreateResource(&resource)
+	url, err = rs.validateURL(0, url, external)
 	if err != nil {
 		return models.ResourceResponse{}, err
 	}
 
-	reviews, err := rs.resourceRepo.GetReviewsByResourceID(resource.ID)
+	contentHTML, err := markdown.Render(content)
 	if err != nil {
 		return models.ResourceResponse{}, err
 	}
 
-	return models.ResourceResponse{
-		ID:       resource.ID,
-		Resource: resource,
-		Reviews:  reviews,
-	}, nil
+	resource := models.Resource{
+		CreatedBy:   userID,
+		Title:       title,
+		Content:     content,
+		ContentHTML: contentHTML,
+		URL:         url,
+		External:    external,
+		AdminPost:   adminPost,
+		Status:      status,
+	}
+	rs.applyLinkPreview(&resource)
+
+	err = rs.resourceRepo.CreateResource(&resource)
+	if err != nil {
+		return models.ResourceResponse{}, err
+	}
+
+	if status == models.PendingModeration {
+		if err := rs.moderationService.QueueForPreModeration(resource.ID); err != nil {
+			return models.ResourceResponse{}, err
+		}
+	}
+
+	if err := rs.moderationService.ScreenContent(models.ResourceTarget, resource.ID, title+"\n"+content); err != nil {
+		logger.Errorf("Failed to screen resource %d: %v", resource.ID, err)
+	}
+
+	return rs.buildResourceResponse(resource)
 }
 
 // GetAllResources gets all the resources in the database.
@@ -61,45 +107,26 @@ func (rs *ResourceService) GetAllResources() ([]models.ResourceResponse, error)
 		return nil, err
 	}
 
-	var resourceResponses []models.ResourceResponse
-	for _, resource := range resources {
-		reviews, err := rs.resourceRepo.GetReviewsByResourceID(resource.ID)
-		if err != nil {
-			return nil, err
-		}
-
-		resourceResponses = append(resourceResponses, models.ResourceResponse{
-			ID:       resource.ID,
-			Resource: resource,
-			Reviews:  reviews,
-		})
-	}
-
-	return resourceResponses, nil
+	return rs.buildResourceResponses(resources)
 }
 
-// GetResourceByID gets a resource by its ID.
+// GetResourceByID gets a published resource by its ID.
 func (rs *ResourceService) GetResourceByID(resourceID uint) (models.ResourceResponse, error) {
 	resource, err := rs.resourceRepo.GetResourceByID(resourceID)
 	if err != nil {
 		return models.ResourceResponse{}, err
 	}
 
-	reviews, err := rs.resourceRepo.GetReviewsByResourceID(resource.ID)
-	if err != nil {
-		return models.ResourceResponse{}, err
+	if resource.Status != models.Published {
+		return models.ResourceResponse{}, ErrResourceNotFound
 	}
 
-	return models.ResourceResponse{
-		ID:       resource.ID,
-		Resource: resource,
-		Reviews:  reviews,
-	}, nil
+	return rs.buildResourceResponse(resource)
 }
 
 // DeleteResource deletes a resource by its ID.
 func (rs *ResourceService) DeleteResource(userID, resourceID uint) error {
-	// Check if the user is the owner of the resource or is an admin
+	// Check if the user is the owner of the resource or can manage all resources
 	resource, err := rs.resourceRepo.GetResourceByID(resourceID)
 	if err != nil {
 		return err
@@ -110,7 +137,7 @@ func (rs *ResourceService) DeleteResource(userID, resourceID uint) error {
 		return err
 	}
 
-	if resource.CreatedBy != user.ID && !user.Admin {
+	if resource.CreatedBy != user.ID && !user.Can(models.ManageResources) {
 		// return error unauthorized
 		return errors.New("unauthorized")
 	}
@@ -118,32 +145,56 @@ func (rs *ResourceService) DeleteResource(userID, resourceID uint) error {
 	return rs.resourceRepo.DeleteResource(resourceID)
 }
 
-// UpdateResource updates a resource in the database.
+// UpdateResource updates a resource in the database. Only the owner or a user who can manage resources can update it.
+// The link preview is fetched again if the URL changes, and the new content is screened like new resources are.
 func (rs *ResourceService) UpdateResource(resourceID uint, userID uint, title, content, url string, external, adminPost bool) (models.ResourceResponse, error) {
-	resource := models.Resource{
-		CreatedBy: userID,
-		Title:     title,
-		Content:   content,
-		URL:       url,
-		External:  external,
-		AdminPost: adminPost,
+	resource, err := rs.resourceRepo.GetResourceByID(resourceID)
+	if err != nil {
+		return models.ResourceResponse{}, err
 	}
 
-	updatedResource, err := rs.resourceRepo.UpdateResource(&resource)
+	user, err := rs.userRepo.GetUserByID(userID)
+	if err != nil {
+		return models.ResourceResponse{}, err
+	}
+
+	if resource.CreatedBy != user.ID && !user.Can(models.ManageResources) {
+		return models.ResourceResponse{}, errors.New("unauthorized")
+	}
+
+	url, err = rs.validateURL(resourceID, url, external)
 	if err != nil {
 		return models.ResourceResponse{}, err
 	}
 
-	reviews, err := rs.resourceRepo.GetReviewsByResourceID(resourceID)
+	contentHTML, err := markdown.Render(content)
 	if err != nil {
 		return models.ResourceResponse{}, err
 	}
 
-	return models.ResourceResponse{
-		ID:       updatedResource.ID,
-		Resource: updatedResource,
-		Reviews:  reviews,
-	}, nil
+	urlChanged := url != resource.URL || external != resource.External
+
+	resource.Title = title
+	resource.Content = content
+	resource.ContentHTML = contentHTML
+	resource.URL = url
+	resource.External = external
+	resource.AdminPost = adminPost
+	if urlChanged {
+		rs.applyLinkPreview(&resource)
+	}
+
+	updatedResource, err := rs.resourceRepo.UpdateResource(&resource)
+	if err != nil {
+		return models.ResourceResponse{}, err
+	}
+
+	// otherwise anything could be slipped past the filter by editing a resource after it's created
+	if err := rs.moderationService.ScreenContent(models.ResourceTarget, resource.ID, title+"\n"+content); err != nil {
+		logger.Errorf("Failed to screen resource %d: %v", resource.ID, err)
+	}
+
+	return rs.buildResourceResponse(updatedResource)
 }
 
 // GetAdminResources gets all the resources for an admin.
@@ -153,31 +204,368 @@ func (rs *ResourceService) GetAdminResources() ([]models.ResourceResponse, error
 		return nil, err
 	}
 
+	return rs.buildResourceResponses(resources)
+}
+
+// AddReview adds a review to a published resource, noting the reviewer's current mood for mood-aware recommendations.
+func (rs *ResourceService) AddReview(resourceID, userID uint, content string, rating models.Rating) error {
+	resource, err := rs.resourceRepo.GetResourceByID(resourceID)
+	if err != nil {
+		return err
+	}
+
+	if resource.Status != models.Published {
+		return ErrResourceNotFound
+	}
+
+	contentHTML, err := markdown.Render(content)
+	if err != nil {
+		return err
+	}
+
+	reviewerMood, err := rs.recommendationService.CurrentMood(userID)
+	if err != nil {
+		return err
+	}
+
+	review := models.Review{
+		ResourceID:   resourceID,
+		UserID:       userID,
+		Content:      content,
+		ContentHTML:  contentHTML,
+		Rating:       rating,
+		ReviewerMood: reviewerMood,
+	}
+
+	err = rs.resourceRepo.AddReview(resourceID, &review)
+	if err != nil {
+		return err
+	}
+
+	if err := rs.moderationService.ScreenContent(models.ReviewTarget, review.ID, content); err != nil {
+		logger.Errorf("Failed to screen review %d: %v", review.ID, err)
+	}
+
+	if err := rs.recommendationService.MarkInteractionStale(userID, resourceID); err != nil {
+		logger.Errorf("Failed to mark recommendations stale after review %d: %v", review.ID, err)
+	}
+
+	return nil
+}
+
+// ErrResourceNotFound is returned for resources which don't exist or aren't published.
+var ErrResourceNotFound = errors.New("resource not found")
+
+// GetResourceReviews gets a single page of reviews for a published resource, sorted by their Wilson-score helpfulness ranking.
+func (rs *ResourceService) GetResourceReviews(resourceID uint, page, perPage int) (models.ReviewPage, error) {
+	resource, err := rs.resourceRepo.GetResourceByID(resourceID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return models.ReviewPage{}, ErrResourceNotFound
+	}
+	if err != nil {
+		return models.ReviewPage{}, err
+	}
+	if resource.Status != models.Published {
+		return models.ReviewPage{}, ErrResourceNotFound
+	}
+
+	return rs.reviewPage(resourceID, page, perPage)
+}
+
+// reviewPage gets a single page of a resource's published reviews, most helpful first. The reviews are sorted and paged
+// by the database, on the helpfulness score stored with each review.
+func (rs *ResourceService) reviewPage(resourceID uint, page, perPage int) (models.ReviewPage, error) {
+	if page < 1 {
+		page = 1
+	}
+	if perPage < 1 {
+		perPage = DefaultReviewsPerPage
+	}
+
+	reviews, total, err := rs.resourceRepo.GetReviewPageByResourceID(resourceID, (page-1)*perPage, perPage)
+	if err != nil {
+		return models.ReviewPage{}, err
+	}
+
+	reviewIDs := make([]uint, 0, len(reviews))
+	for _, review := range reviews {
+		reviewIDs = append(reviewIDs, review.ID)
+	}
+
+	voteCounts, err := rs.resourceRepo.GetReviewVoteCounts(reviewIDs)
+	if err != nil {
+		return models.ReviewPage{}, err
+	}
+
+	return models.ReviewPage{
+		Reviews: reviewResponses(reviews, voteCounts),
+		Page:    page,
+		PerPage: perPage,
+		Total:   int(total),
+	}, nil
+}
+
+// pageReviews gets a single page of ranked reviews.
+func pageReviews(rankedReviews []models.ReviewResponse, page, perPage int) models.ReviewPage {
+	startIdx := (page - 1) * perPage
+	endIdx := startIdx + perPage
+	if startIdx > len(rankedReviews) {
+		startIdx = len(rankedReviews)
+	}
+	if endIdx > len(rankedReviews) {
+		endIdx = len(rankedReviews)
+	}
+
+	return models.ReviewPage{
+		Reviews: rankedReviews[startIdx:endIdx],
+		Page:    page,
+		PerPage: perPage,
+		Total:   len(rankedReviews),
+	}
+}
+
+// GetUserReviews gets all the reviews written by a user, along with how many people found each of them helpful.
+func (rs *ResourceService) GetUserReviews(userID uint) ([]models.ReviewResponse, error) {
+	reviews, err := rs.resourceRepo.GetReviewsByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return rs.rankReviews(reviews)
+}
+
+// ErrSelfVote is returned when a user votes on the helpfulness of their own review.
+var ErrSelfVote = errors.New("you cannot vote on your own review")
+
+// VoteOnReview marks a review as helpful or unhelpful for the given user. Voting again replaces the previous vote.
+func (rs *ResourceService) VoteOnReview(reviewID, userID uint, helpful bool) error {
+	review, err := rs.resourceRepo.GetReviewByID(reviewID)
+	if err != nil {
+		return err
+	}
+
+	if review.UserID == userID {
+		return ErrSelfVote
+	}
+
+	vote := models.ReviewVote{
+		ReviewID: reviewID,
+		UserID:   userID,
+		Helpful:  helpful,
+	}
+
+	return rs.resourceRepo.UpsertReviewVote(&vote)
+}
+
+// RemoveReviewVote removes the given user's helpfulness vote on a review.
+func (rs *ResourceService) RemoveReviewVote(reviewID, userID uint) error {
+	return rs.resourceRepo.DeleteReviewVote(reviewID, userID)
+}
+
+// SetResourceMoodTags replaces the moods a resource is suited for. Only the owner of the resource or a user who can manage resources can tag it.
+func (rs *ResourceService) SetResourceMoodTags(resourceID, userID uint, moods []models.MoodType) (models.ResourceResponse, error) {
+	resource, err := rs.resourceRepo.GetResourceByID(resourceID)
+	if err != nil {
+		return models.ResourceResponse{}, err
+	}
+
+	user, err := rs.userRepo.GetUserByID(userID)
+	if err != nil {
+		return models.ResourceResponse{}, err
+	}
+	if resource.CreatedBy != userID && !user.Can(models.ManageResources) {
+		return models.ResourceResponse{}, errors.New("you are not allowed to tag this resource")
+	}
+
+	seen := make(map[models.MoodType]bool)
+	uniqueMoods := make([]models.MoodType, 0, len(moods))
+	for _, mood := range moods {
+		if mood < models.Angry || mood > models.Excited {
+			return models.ResourceResponse{}, fmt.Errorf("invalid mood type %d", mood)
+		}
+		if !seen[mood] {
+			seen[mood] = true
+			uniqueMoods = append(uniqueMoods, mood)
+		}
+	}
+
+	if err := rs.resourceRepo.SetResourceMoodTags(resourceID, uniqueMoods); err != nil {
+		return models.ResourceResponse{}, err
+	}
+
+	return rs.buildResourceResponse(resource)
+}
+
+// validateURL normalizes a resource URL and makes sure no other resource already links to it.
+// Internal resources may leave the URL empty; external ones must have one.
+func (rs *ResourceService) validateURL(resourceID uint, rawURL string, external bool) (string, error) {
+	if strings.TrimSpace(rawURL) == "" {
+		if external {
+			return "", errors.New("external resources must have a URL")
+		}
+		return "", nil
+	}
+
+	url, err := linkpreview.NormalizeURL(rawURL)
+	if err != nil {
+		return "", err
+	}
+
+	existing, err := rs.resourceRepo.GetResourceByURL(url)
+	if err != nil {
+		return "", err
+	}
+	if existing.ID != 0 && existing.ID != resourceID {
+		return "", fmt.Errorf("a resource with this URL already exists (resource %d)", existing.ID)
+	}
+
+	return url, nil
+}
+
+// applyLinkPreview fetches the preview for an external resource's URL and stores it on the resource.
+// A failed fetch doesn't stop the resource from being saved; the link is counted as failing once and rechecked by the link checker job.
+func (rs *ResourceService) applyLinkPreview(resource *models.Resource) {
+	resource.PreviewTitle = ""
+	resource.PreviewDescription = ""
+	resource.PreviewImage = ""
+	resource.LinkStatus = models.LinkUnchecked
+	resource.LinkFailures = 0
+	resource.LinkCheckedAt = nil
+
+	if !resource.External || resource.URL == "" {
+		return
+	}
+
+	now := time.Now()
+	resource.LinkCheckedAt = &now
+
+	preview, err := rs.linkFetcher.Fetch(resource.URL)
+	if err != nil {
+		logger.Warnf("Failed to fetch link preview for %s: %v", resource.URL, err)
+		resource.LinkFailures = 1
+		return
+	}
+
+	resource.PreviewTitle = preview.Title
+	resource.PreviewDescription = preview.Description
+	resource.PreviewImage = preview.ImageURL
+	resource.LinkStatus = models.LinkAlive
+}
+
+// buildResourceResponse wraps a resource with its review count, its most helpful reviews and its mood tags.
+func (rs *ResourceService) buildResourceResponse(resource models.Resource) (models.ResourceResponse, error) {
+	reviewPage, err := rs.reviewPage(resource.ID, 1, DefaultReviewsPerPage)
+	if err != nil {
+		return models.ResourceResponse{}, err
+	}
+
+	moodTags, err := rs.resourceRepo.GetResourceMoodTags(resource.ID)
+	if err != nil {
+		return models.ResourceResponse{}, err
+	}
+
+	return models.ResourceResponse{
+		ID:          resource.ID,
+		Resource:    resource,
+		Reviews:     reviewPage.Reviews,
+		ReviewCount: reviewPage.Total,
+		MoodTags:    moodTags,
+	}, nil
+}
+
+// buildResourceResponses builds the responses of many resources, as buildResourceResponse does for one, with a fixed
+// number of queries rather than a few per resource.
+func (rs *ResourceService) buildResourceResponses(resources []models.Resource) ([]models.ResourceResponse, error) {
+	resourceIDs := make([]uint, 0, len(resources))
+	for _, resource := range resources {
+		resourceIDs = append(resourceIDs, resource.ID)
+	}
+
+	reviews, err := rs.resourceRepo.GetReviewsByResourceIDs(resourceIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	reviewIDs := make([]uint, 0, len(reviews))
+	reviewsByResource := make(map[uint][]models.Review)
+	for _, review := range reviews {
+		reviewIDs = append(reviewIDs, review.ID)
+		reviewsByResource[review.ResourceID] = append(reviewsByResource[review.ResourceID], review)
+	}
+
+	voteCounts, err := rs.resourceRepo.GetReviewVoteCounts(reviewIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	moodTags, err := rs.resourceRepo.GetResourceMoodTagsByIDs(resourceIDs)
+	if err != nil {
+		return nil, err
+	}
+
 	var resourceResponses []models.ResourceResponse
 	for _, resource := range resources {
-		reviews, err := rs.resourceRepo.GetReviewsByResourceID(resource.ID)
-		if err != nil {
-			return nil, err
+		reviewPage := pageReviews(sortReviews(reviewsByResource[resource.ID], voteCounts), 1, DefaultReviewsPerPage)
+
+		resourceMoodTags := moodTags[resource.ID]
+		if resourceMoodTags == nil {
+			resourceMoodTags = []models.MoodType{}
 		}
 
 		resourceResponses = append(resourceResponses, models.ResourceResponse{
-			ID:       resource.ID,
-			Resource: resource,
-			Reviews:  reviews,
+			ID:          resource.ID,
+			Resource:    resource,
+			Reviews:     reviewPage.Reviews,
+			ReviewCount: reviewPage.Total,
+			MoodTags:    resourceMoodTags,
 		})
 	}
 
 	return resourceResponses, nil
 }
 
-// AddReview adds a review to a resource.
-func (rs *ResourceService) AddReview(resourceID, userID uint, content string, rating models.Rating) error {
-	review := models.Review{
-		ResourceID: resourceID,
-		UserID:     userID,
-		Content:    content,
-		Rating:     rating,
+// rankReviews attaches vote counts to the given reviews and sorts them by helpfulness, see sortReviews.
+func (rs *ResourceService) rankReviews(reviews []models.Review) ([]models.ReviewResponse, error) {
+	reviewIDs := make([]uint, 0, len(reviews))
+	for _, review := range reviews {
+		reviewIDs = append(reviewIDs, review.ID)
 	}
 
-	return rs.resourceRepo.AddReview(resourceID, &review)
+	voteCounts, err := rs.resourceRepo.GetReviewVoteCounts(reviewIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return sortReviews(reviews, voteCounts), nil
+}
+
+// sortReviews attaches the vote counts to the reviews and sorts them by helpfulness, most helpful first.
+// Reviews with the same score are ordered newest first.
+func sortReviews(reviews []models.Review, voteCounts map[uint]models.ReviewVoteCount) []models.ReviewResponse {
+	rankedReviews := reviewResponses(reviews, voteCounts)
+
+	sort.SliceStable(rankedReviews, func(i, j int) bool {
+		if rankedReviews[i].Score != rankedReviews[j].Score {
+			return rankedReviews[i].Score > rankedReviews[j].Score
+		}
+		return rankedReviews[i].Review.CreatedAt.After(rankedReviews[j].Review.CreatedAt)
+	})
+
+	return rankedReviews
+}
+
+// reviewResponses attaches the vote counts, and the helpfulness scores computed from them, to the reviews in their order.
+func reviewResponses(reviews []models.Review, voteCounts map[uint]models.ReviewVoteCount) []models.ReviewResponse {
+	responses := make([]models.ReviewResponse, 0, len(reviews))
+	for _, review := range reviews {
+		counts := voteCounts[review.ID]
+		responses = append(responses, models.ReviewResponse{
+			ID:             review.ID,
+			Review:         review,
+			HelpfulCount:   counts.Helpful,
+			UnhelpfulCount: counts.Unhelpful,
+			Score:          ranking.WilsonLowerBound(counts.Helpful, counts.Helpful+counts.Unhelpful),
+		})
+	}
+	return responses
 }
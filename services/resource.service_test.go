@@ -0,0 +1,295 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/anirudhgray/mood-harbour-backend/mocks"
+	"github.com/anirudhgray/mood-harbour-backend/models"
+	"github.com/anirudhgray/mood-harbour-backend/utils/linkpreview"
+	"github.com/golang/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func TestResourceService_GetResourceReviews(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockResourceRepo := mocks.NewMockResourceRepositoryInterface(ctrl)
+	rs := NewResourceService(mockResourceRepo, mocks.NewMockUserRepository(ctrl), mocks.NewMockModerationServiceInterface(ctrl), linkpreview.NoopFetcher{}, mocks.NewMockRecommendationServiceInterface(ctrl))
+	resourceID := uint(1)
+
+	t.Run("Attaches vote counts to a page sorted by the database", func(t *testing.T) {
+		reviews := []models.Review{
+			{Model: gorm.Model{ID: 4}, ResourceID: resourceID},
+		}
+		mockResourceRepo.EXPECT().GetResourceByID(resourceID).Return(models.Resource{Model: gorm.Model{ID: resourceID}, Status: models.Published}, nil)
+		mockResourceRepo.EXPECT().GetReviewPageByResourceID(resourceID, 3, 3).Return(reviews, int64(4), nil)
+		mockResourceRepo.EXPECT().GetReviewVoteCounts([]uint{4}).Return(map[uint]models.ReviewVoteCount{
+			4: {ReviewID: 4, Helpful: 40, Unhelpful: 5},
+		}, nil)
+
+		reviewPage, err := rs.GetResourceReviews(resourceID, 2, 3)
+		if err != nil {
+			t.Fatalf("GetResourceReviews returned an error: %v", err)
+		}
+		if reviewPage.Total != 4 || reviewPage.Page != 2 || reviewPage.PerPage != 3 || len(reviewPage.Reviews) != 1 {
+			t.Fatalf("GetResourceReviews returned an unexpected page: %+v", reviewPage)
+		}
+		if review := reviewPage.Reviews[0]; review.ID != 4 || review.HelpfulCount != 40 || review.UnhelpfulCount != 5 || review.Score == 0 {
+			t.Errorf("GetResourceReviews returned incorrect vote counts: %+v", review)
+		}
+	})
+
+	t.Run("Missing resource", func(t *testing.T) {
+		mockResourceRepo.EXPECT().GetResourceByID(resourceID).Return(models.Resource{}, gorm.ErrRecordNotFound)
+
+		if _, err := rs.GetResourceReviews(resourceID, 1, 3); !errors.Is(err, ErrResourceNotFound) {
+			t.Errorf("Expected ErrResourceNotFound, got %v", err)
+		}
+	})
+
+	t.Run("Unpublished resource", func(t *testing.T) {
+		mockResourceRepo.EXPECT().GetResourceByID(resourceID).Return(models.Resource{Model: gorm.Model{ID: resourceID}, Status: models.Hidden}, nil)
+
+		if _, err := rs.GetResourceReviews(resourceID, 1, 3); !errors.Is(err, ErrResourceNotFound) {
+			t.Errorf("Expected ErrResourceNotFound, got %v", err)
+		}
+	})
+}
+
+func TestResourceService_GetAllResources(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockResourceRepo := mocks.NewMockResourceRepositoryInterface(ctrl)
+	rs := NewResourceService(mockResourceRepo, mocks.NewMockUserRepository(ctrl), mocks.NewMockModerationServiceInterface(ctrl), linkpreview.NoopFetcher{}, mocks.NewMockRecommendationServiceInterface(ctrl))
+
+	resources := []models.Resource{{Model: gorm.Model{ID: 1}}, {Model: gorm.Model{ID: 2}}, {Model: gorm.Model{ID: 3}}}
+	reviews := []models.Review{
+		{Model: gorm.Model{ID: 10}, ResourceID: 1},
+		{Model: gorm.Model{ID: 11}, ResourceID: 1},
+		{Model: gorm.Model{ID: 20}, ResourceID: 2},
+	}
+
+	// reviews, votes and tags are fetched for all the resources at once, however many there are
+	mockResourceRepo.EXPECT().GetResources().Return(resources, nil)
+	mockResourceRepo.EXPECT().GetReviewsByResourceIDs([]uint{1, 2, 3}).Return(reviews, nil)
+	mockResourceRepo.EXPECT().GetReviewVoteCounts([]uint{10, 11, 20}).Return(map[uint]models.ReviewVoteCount{
+		11: {ReviewID: 11, Helpful: 5},
+	}, nil)
+	mockResourceRepo.EXPECT().GetResourceMoodTagsByIDs([]uint{1, 2, 3}).Return(map[uint][]models.MoodType{2: {models.Sad}}, nil)
+
+	resourceResponses, err := rs.GetAllResources()
+	if err != nil {
+		t.Fatalf("GetAllResources returned an error: %v", err)
+	}
+	if len(resourceResponses) != 3 {
+		t.Fatalf("Expected 3 resources, got: %d", len(resourceResponses))
+	}
+
+	first := resourceResponses[0]
+	if first.ReviewCount != 2 || first.Reviews[0].ID != 11 || first.Reviews[0].HelpfulCount != 5 {
+		t.Errorf("Expected resource 1's reviews ranked by helpfulness, got: %+v", first.Reviews)
+	}
+	if len(resourceResponses[1].MoodTags) != 1 || resourceResponses[1].MoodTags[0] != models.Sad {
+		t.Errorf("Expected resource 2 to be tagged Sad, got: %v", resourceResponses[1].MoodTags)
+	}
+	if last := resourceResponses[2]; last.ReviewCount != 0 || len(last.Reviews) != 0 || last.MoodTags == nil {
+		t.Errorf("Expected resource 3 without reviews or tags, got: %+v", last)
+	}
+}
+
+func TestResourceService_VoteOnReview(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockResourceRepo := mocks.NewMockResourceRepositoryInterface(ctrl)
+	rs := NewResourceService(mockResourceRepo, mocks.NewMockUserRepository(ctrl), mocks.NewMockModerationServiceInterface(ctrl), linkpreview.NoopFetcher{}, mocks.NewMockRecommendationServiceInterface(ctrl))
+	review := models.Review{Model: gorm.Model{ID: 1}, UserID: 2}
+
+	testCases := []struct {
+		name        string
+		userID      uint
+		expectError bool
+	}{
+		{"Vote on another user's review", 3, false},
+		{"Vote on own review", 2, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockResourceRepo.EXPECT().GetReviewByID(review.ID).Return(review, nil)
+			if !tc.expectError {
+				mockResourceRepo.EXPECT().UpsertReviewVote(&models.ReviewVote{ReviewID: review.ID, UserID: tc.userID, Helpful: true}).Return(nil)
+			}
+
+			err := rs.VoteOnReview(review.ID, tc.userID, true)
+
+			if tc.expectError && !errors.Is(err, ErrSelfVote) {
+				t.Errorf("Expected ErrSelfVote, got %v", err)
+			} else if !tc.expectError && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+type stubFetcher struct {
+	preview linkpreview.Preview
+	err     error
+}
+
+func (sf stubFetcher) Fetch(rawURL string) (linkpreview.Preview, error) {
+	return sf.preview, sf.err
+}
+
+func TestResourceService_CreateResourceEntry_DuplicateURL(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockResourceRepo := mocks.NewMockResourceRepositoryInterface(ctrl)
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	rs := NewResourceService(mockResourceRepo, mockUserRepo, mocks.NewMockModerationServiceInterface(ctrl), linkpreview.NoopFetcher{}, mocks.NewMockRecommendationServiceInterface(ctrl))
+
+	mockUserRepo.EXPECT().GetUserByID(uint(1)).Return(models.User{Model: gorm.Model{ID: 1}}, nil).AnyTimes()
+	// the submitted URL is normalized before looking for duplicates
+	mockResourceRepo.EXPECT().GetResourceByURL("https://example.com/article").Return(models.Resource{Model: gorm.Model{ID: 7}}, nil)
+
+	_, err := rs.CreateResourceEntry(1, "Title", "Content", "HTTPS://Example.com/article/#top", true, false)
+	if err == nil {
+		t.Errorf("Expected an error for a duplicate URL, got nil")
+	}
+
+	_, err = rs.CreateResourceEntry(1, "Title", "Content", "not a url", true, false)
+	if err == nil {
+		t.Errorf("Expected an error for an invalid URL, got nil")
+	}
+
+	_, err = rs.CreateResourceEntry(1, "Title", "Content", "", true, false)
+	if err == nil {
+		t.Errorf("Expected an error for an external resource without a URL, got nil")
+	}
+}
+
+func TestResourceService_UpdateResource(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockResourceRepo := mocks.NewMockResourceRepositoryInterface(ctrl)
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	fetcher := stubFetcher{preview: linkpreview.Preview{Title: "New page", Description: "Fetched description"}}
+	mockModerationService := mocks.NewMockModerationServiceInterface(ctrl)
+	rs := NewResourceService(mockResourceRepo, mockUserRepo, mockModerationService, fetcher, mocks.NewMockRecommendationServiceInterface(ctrl))
+
+	resource := models.Resource{Model: gorm.Model{ID: 1}, CreatedBy: 1, Title: "Old", URL: "https://example.com/old", External: true, PreviewTitle: "Old page"}
+
+	mockResourceRepo.EXPECT().GetResourceByID(uint(1)).Return(resource, nil).Times(2)
+	mockUserRepo.EXPECT().GetUserByID(uint(1)).Return(models.User{Model: gorm.Model{ID: 1}}, nil)
+	mockUserRepo.EXPECT().GetUserByID(uint(2)).Return(models.User{Model: gorm.Model{ID: 2}}, nil)
+
+	// only the owner or an admin can update a resource
+	if _, err := rs.UpdateResource(1, 2, "New", "Content", "https://example.com/new", true, false); err == nil {
+		t.Errorf("Expected an error when updating another user's resource, got nil")
+	}
+
+	mockResourceRepo.EXPECT().GetResourceByURL("https://example.com/new").Return(models.Resource{}, nil)
+	mockResourceRepo.EXPECT().UpdateResource(gomock.Any()).DoAndReturn(func(r *models.Resource) (models.Resource, error) {
+		return *r, nil
+	})
+	mockResourceRepo.EXPECT().GetReviewPageByResourceID(uint(1), 0, DefaultReviewsPerPage).Return(nil, int64(0), nil)
+	mockResourceRepo.EXPECT().GetReviewVoteCounts([]uint{}).Return(map[uint]models.ReviewVoteCount{}, nil)
+	mockResourceRepo.EXPECT().GetResourceMoodTags(uint(1)).Return([]models.MoodType{}, nil)
+	// the new content is screened, as edits could slip anything past the filter otherwise
+	mockModerationService.EXPECT().ScreenContent(models.ResourceTarget, uint(1), "New\nContent").Return(nil)
+
+	resourceResponse, err := rs.UpdateResource(1, 1, "New", "Content", "https://example.com/new", true, false)
+	if err != nil {
+		t.Fatalf("UpdateResource returned an error: %v", err)
+	}
+
+	updated := resourceResponse.Resource
+	if updated.ID != 1 || updated.Title != "New" || updated.URL != "https://example.com/new" {
+		t.Errorf("UpdateResource did not update the existing resource: %+v", updated)
+	}
+	if updated.PreviewTitle != "New page" || updated.LinkStatus != models.LinkAlive || updated.LinkCheckedAt == nil {
+		t.Errorf("UpdateResource did not refresh the link preview: %+v", updated)
+	}
+}
+
+func TestResourceService_AddReview_RendersMarkdown(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockResourceRepo := mocks.NewMockResourceRepositoryInterface(ctrl)
+	mockModerationService := mocks.NewMockModerationServiceInterface(ctrl)
+	mockRecommendationService := mocks.NewMockRecommendationServiceInterface(ctrl)
+	rs := NewResourceService(mockResourceRepo, mocks.NewMockUserRepository(ctrl), mockModerationService, linkpreview.NoopFetcher{}, mockRecommendationService)
+
+	content := "**Really** helped <script>alert(1)</script>"
+
+	mockResourceRepo.EXPECT().GetResourceByID(uint(1)).Return(models.Resource{Model: gorm.Model{ID: 1}, Status: models.Published}, nil)
+	mockResourceRepo.EXPECT().AddReview(uint(1), gomock.Any()).Do(func(resourceID uint, review *models.Review) {
+		if review.Content != content {
+			t.Errorf("Expected the Markdown source to be stored unchanged, got: %q", review.Content)
+		}
+		expectedHTML := "<p><strong>Really</strong> helped alert(1)</p>\n"
+		if review.ContentHTML != expectedHTML {
+			t.Errorf("Expected rendered HTML %q, got: %q", expectedHTML, review.ContentHTML)
+		}
+		if review.ReviewerMood != models.Sad {
+			t.Errorf("Expected the reviewer's current mood to be stored, got: %d", review.ReviewerMood)
+		}
+	}).Return(nil)
+	mockRecommendationService.EXPECT().CurrentMood(uint(2)).Return(models.Sad, nil)
+	mockModerationService.EXPECT().ScreenContent(models.ReviewTarget, gomock.Any(), content).Return(nil)
+	// adding a review makes the reviewer's recommendations stale
+	mockRecommendationService.EXPECT().MarkInteractionStale(uint(2), uint(1)).Return(nil)
+
+	if err := rs.AddReview(1, 2, content, models.FiveStar); err != nil {
+		t.Errorf("AddReview returned an error: %v", err)
+	}
+
+	// hidden and pending resources can't be read, so they can't be reviewed either
+	for _, status := range []models.ModerationStatus{models.Hidden, models.PendingModeration} {
+		mockResourceRepo.EXPECT().GetResourceByID(uint(3)).Return(models.Resource{Model: gorm.Model{ID: 3}, Status: status}, nil)
+		if err := rs.AddReview(3, 2, content, models.FiveStar); err == nil {
+			t.Errorf("Expected an error reviewing a resource with status %v", status)
+		}
+	}
+}
+
+func TestResourceService_SetResourceMoodTags(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockResourceRepo := mocks.NewMockResourceRepositoryInterface(ctrl)
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	rs := NewResourceService(mockResourceRepo, mockUserRepo, mocks.NewMockModerationServiceInterface(ctrl), linkpreview.NoopFetcher{}, mocks.NewMockRecommendationServiceInterface(ctrl))
+
+	resource := models.Resource{Model: gorm.Model{ID: 1}, CreatedBy: 1}
+	mockResourceRepo.EXPECT().GetResourceByID(uint(1)).Return(resource, nil).AnyTimes()
+	mockUserRepo.EXPECT().GetUserByID(uint(1)).Return(models.User{Model: gorm.Model{ID: 1}}, nil).AnyTimes()
+	mockUserRepo.EXPECT().GetUserByID(uint(2)).Return(models.User{Model: gorm.Model{ID: 2}}, nil).AnyTimes()
+
+	if _, err := rs.SetResourceMoodTags(1, 2, []models.MoodType{models.Sad}); err == nil {
+		t.Errorf("Expected an error when tagging another user's resource, got nil")
+	}
+	if _, err := rs.SetResourceMoodTags(1, 1, []models.MoodType{models.Sad, 6}); err == nil {
+		t.Errorf("Expected an error for an invalid mood type, got nil")
+	}
+
+	// duplicate moods are dropped
+	mockResourceRepo.EXPECT().SetResourceMoodTags(uint(1), []models.MoodType{models.Sad, models.Angry}).Return(nil)
+	mockResourceRepo.EXPECT().GetReviewPageByResourceID(uint(1), 0, DefaultReviewsPerPage).Return(nil, int64(0), nil)
+	mockResourceRepo.EXPECT().GetReviewVoteCounts([]uint{}).Return(map[uint]models.ReviewVoteCount{}, nil)
+	mockResourceRepo.EXPECT().GetResourceMoodTags(uint(1)).Return([]models.MoodType{models.Angry, models.Sad}, nil)
+
+	resourceResponse, err := rs.SetResourceMoodTags(1, 1, []models.MoodType{models.Sad, models.Angry, models.Sad})
+	if err != nil {
+		t.Fatalf("SetResourceMoodTags returned an error: %v", err)
+	}
+	if len(resourceResponse.MoodTags) != 2 {
+		t.Errorf("Expected the mood tags in the response, got: %v", resourceResponse.MoodTags)
+	}
+}
@@ -0,0 +1,178 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/anirudhgray/mood-harbour-backend/infra/logger"
+	"github.com/anirudhgray/mood-harbour-backend/models"
+	"github.com/anirudhgray/mood-harbour-backend/repository"
+	"github.com/spf13/viper"
+)
+
+// Actions whose failed attempts are throttled. Each is counted separately, so codes being guessed for one don't lock the others.
+// One-time tokens are throttled by their purpose.
+const (
+	ThrottleLogin         = "login"
+	ThrottleMFA           = "mfa"
+	ThrottleVerify        = models.OneTimeTokenVerifyEmail
+	ThrottleResetPassword = models.OneTimeTokenResetPassword
+	ThrottleDeleteAccount = models.OneTimeTokenDeleteAccount
+)
+
+// ThrottlePolicy decides how long a subject has to wait after failing an action a number of times in a row.
+// After FreeAttempts failures the wait starts at Backoff and doubles with every further failure, up to Lockout.
+// Reaching LockoutAttempts locks the subject out for the whole Lockout. Failures are forgotten after Window without any.
+type ThrottlePolicy struct {
+	FreeAttempts    int
+	Backoff         time.Duration
+	LockoutAttempts int
+	Lockout         time.Duration
+	Window          time.Duration
+}
+
+// ThrottlePoliciesFromConfig reads the policy of accounts from THROTTLE_FREE_ATTEMPTS, THROTTLE_BACKOFF_SECONDS,
+// THROTTLE_LOCKOUT_ATTEMPTS, THROTTLE_LOCKOUT_MINUTES and THROTTLE_WINDOW_HOURS. IP addresses are shared by many users,
+// so they get THROTTLE_IP_MULTIPLIER times as many attempts.
+func ThrottlePoliciesFromConfig() (ThrottlePolicy, ThrottlePolicy) {
+	account := ThrottlePolicy{
+		FreeAttempts:    viper.GetInt("THROTTLE_FREE_ATTEMPTS"),
+		Backoff:         time.Duration(viper.GetInt("THROTTLE_BACKOFF_SECONDS")) * time.Second,
+		LockoutAttempts: viper.GetInt("THROTTLE_LOCKOUT_ATTEMPTS"),
+		Lockout:         time.Duration(viper.GetInt("THROTTLE_LOCKOUT_MINUTES")) * time.Minute,
+		Window:          time.Duration(viper.GetInt("THROTTLE_WINDOW_HOURS")) * time.Hour,
+	}
+	ip := account
+	ip.FreeAttempts *= viper.GetInt("THROTTLE_IP_MULTIPLIER")
+	ip.LockoutAttempts *= viper.GetInt("THROTTLE_IP_MULTIPLIER")
+	return account, ip
+}
+
+// Delay is how long to wait after the given number of failures in a row.
+func (p ThrottlePolicy) Delay(failures int) time.Duration {
+	if failures >= p.LockoutAttempts {
+		return p.Lockout
+	}
+	if failures <= p.FreeAttempts {
+		return 0
+	}
+	doublings := failures - p.FreeAttempts - 1
+	if doublings >= 32 || p.Backoff<<doublings > p.Lockout {
+		return p.Lockout
+	}
+	return p.Backoff << doublings
+}
+
+// ThrottledError is returned for attempts made before a subject's wait is over.
+type ThrottledError struct {
+	RetryAfter time.Duration
+}
+
+func (e *ThrottledError) Error() string {
+	return fmt.Sprintf("too many failed attempts, please try again in %d seconds", e.RetrySeconds())
+}
+
+// RetrySeconds is RetryAfter rounded up to whole seconds, as sent in the Retry-After header.
+func (e *ThrottledError) RetrySeconds() int {
+	return int(math.Ceil(e.RetryAfter.Seconds()))
+}
+
+// ThrottleService slows down guessing passwords and codes, by counting failed attempts per account and per IP address.
+type ThrottleService struct {
+	throttleRepo repository.ThrottleRepositoryInterface
+	userRepo     repository.UserRepositoryInterface
+	emailService EmailServiceInterface
+	account      ThrottlePolicy
+	ip           ThrottlePolicy
+}
+
+func NewThrottleService(throttleRepo repository.ThrottleRepositoryInterface, userRepo repository.UserRepositoryInterface, emailService EmailServiceInterface, account, ip ThrottlePolicy) *ThrottleService {
+	return &ThrottleService{throttleRepo, userRepo, emailService, account, ip}
+}
+
+type ThrottleServiceInterface interface {
+	Check(action, email, ip string) error
+	RecordFailure(action, email, ip string) error
+	Reset(action, email string) error
+}
+
+// Check returns a ThrottledError if the account or IP address have to wait before attempting the action again.
+func (ts *ThrottleService) Check(action, email, ip string) error {
+	subjects := []string{accountSubject(action, email)}
+	if ip != "" {
+		subjects = append(subjects, ipSubject(action, ip))
+	}
+	throttles, err := ts.throttleRepo.GetThrottles(subjects)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	var wait time.Duration
+	for _, throttle := range throttles {
+		if throttle.LockedUntil != nil && throttle.LockedUntil.Sub(now) > wait {
+			wait = throttle.LockedUntil.Sub(now)
+		}
+	}
+	if wait > 0 {
+		return &ThrottledError{RetryAfter: wait}
+	}
+	return nil
+}
+
+// RecordFailure counts a failed attempt at the action, making the account and IP address wait if they failed too often.
+// The account's owner is emailed when it gets locked out, whether or not the account exists isn't revealed to the client.
+func (ts *ThrottleService) RecordFailure(action, email, ip string) error {
+	failures, err := ts.recordFailure(accountSubject(action, email), ts.account)
+	if err != nil {
+		return err
+	}
+	if failures == ts.account.LockoutAttempts {
+		logger.Warnf("Locked out %s attempts of %s after %d failures", action, email, failures)
+		if user, err := ts.userRepo.GetUserByEmail(email); err == nil {
+			ts.emailService.GenericSendMail("Account Temporarily Locked", fmt.Sprintf("There were %d failed attempts to access your account, so it has been locked for %d minutes. Change your password if this was not you.", failures, int(ts.account.Lockout.Minutes())), user.Email, user.Name)
+		}
+	}
+
+	if ip != "" {
+		failures, err := ts.recordFailure(ipSubject(action, ip), ts.ip)
+		if err != nil {
+			return err
+		}
+		if failures == ts.ip.LockoutAttempts {
+			logger.Warnf("Locked out %s attempts from %s after %d failures", action, ip, failures)
+		}
+	}
+	return nil
+}
+
+// Reset forgets the account's failed attempts at the action, after a successful one. IP addresses aren't reset, otherwise
+// guessing could continue after every successful attempt at one's own account.
+func (ts *ThrottleService) Reset(action, email string) error {
+	return ts.throttleRepo.ResetThrottle(accountSubject(action, email))
+}
+
+// recordFailure counts a failure of the subject and locks it for the policy's delay, returning its failures in a row.
+func (ts *ThrottleService) recordFailure(subject string, policy ThrottlePolicy) (int, error) {
+	now := time.Now()
+	throttle, err := ts.throttleRepo.RecordFailure(subject, now, now.Add(-policy.Window))
+	if err != nil {
+		return 0, err
+	}
+	if delay := policy.Delay(throttle.Failures); delay > 0 {
+		if err := ts.throttleRepo.LockThrottle(subject, now.Add(delay)); err != nil {
+			return 0, err
+		}
+	}
+	return throttle.Failures, nil
+}
+
+func accountSubject(action, email string) string {
+	return action + ":account:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+func ipSubject(action, ip string) string {
+	return action + ":ip:" + ip
+}
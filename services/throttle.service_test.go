@@ -0,0 +1,113 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/anirudhgray/mood-harbour-backend/mocks"
+	"github.com/anirudhgray/mood-harbour-backend/models"
+	"github.com/golang/mock/gomock"
+)
+
+var testThrottlePolicy = ThrottlePolicy{FreeAttempts: 3, Backoff: time.Second, LockoutAttempts: 6, Lockout: 15 * time.Minute, Window: time.Hour}
+
+func TestThrottlePolicy_Delay(t *testing.T) {
+	tests := []struct {
+		failures int
+		delay    time.Duration
+	}{
+		{1, 0},
+		{3, 0},
+		{4, time.Second},
+		{5, 2 * time.Second},
+		{6, 15 * time.Minute},
+		{100, 15 * time.Minute},
+	}
+
+	for _, test := range tests {
+		if delay := testThrottlePolicy.Delay(test.failures); delay != test.delay {
+			t.Errorf("Delay(%d) = %v; expected %v", test.failures, delay, test.delay)
+		}
+	}
+
+	// the backoff never exceeds the lockout
+	policy := ThrottlePolicy{FreeAttempts: 0, Backoff: time.Minute, LockoutAttempts: 100, Lockout: 15 * time.Minute}
+	if delay := policy.Delay(50); delay != 15*time.Minute {
+		t.Errorf("Delay(50) = %v; expected the lockout", delay)
+	}
+}
+
+func TestThrottleService_Check(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockThrottleRepo := mocks.NewMockThrottleRepository(ctrl)
+	ts := NewThrottleService(mockThrottleRepo, nil, nil, testThrottlePolicy, testThrottlePolicy)
+	subjects := []string{"login:account:test@example.com", "login:ip:203.0.113.7"}
+
+	past := time.Now().Add(-time.Minute)
+	mockThrottleRepo.EXPECT().GetThrottles(subjects).Return([]models.AuthThrottle{{Subject: subjects[0], Failures: 4, LockedUntil: &past}}, nil)
+	if err := ts.Check(ThrottleLogin, " Test@Example.com", "203.0.113.7"); err != nil {
+		t.Errorf("Unexpected error after the wait: %v", err)
+	}
+
+	future := time.Now().Add(time.Minute)
+	mockThrottleRepo.EXPECT().GetThrottles(subjects).Return([]models.AuthThrottle{{Subject: subjects[1], Failures: 30, LockedUntil: &future}}, nil)
+	var throttledErr *ThrottledError
+	if err := ts.Check(ThrottleLogin, "test@example.com", "203.0.113.7"); !errors.As(err, &throttledErr) || throttledErr.RetrySeconds() != 60 {
+		t.Errorf("Expected a ThrottledError for a locked IP address, got %v", err)
+	}
+}
+
+func TestThrottleService_RecordFailure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockThrottleRepo := mocks.NewMockThrottleRepository(ctrl)
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	mockEmailService := mocks.NewMockEmailService(ctrl)
+	ipPolicy := testThrottlePolicy
+	ipPolicy.FreeAttempts, ipPolicy.LockoutAttempts = 12, 24
+	ts := NewThrottleService(mockThrottleRepo, mockUserRepo, mockEmailService, testThrottlePolicy, ipPolicy)
+
+	account := "login:account:test@example.com"
+	ip := "login:ip:203.0.113.7"
+	user := models.User{Email: "test@example.com", Name: "Test User"}
+
+	t.Run("Free attempts", func(t *testing.T) {
+		mockThrottleRepo.EXPECT().RecordFailure(account, gomock.Any(), gomock.Any()).Return(models.AuthThrottle{Failures: 2}, nil)
+		mockThrottleRepo.EXPECT().RecordFailure(ip, gomock.Any(), gomock.Any()).Return(models.AuthThrottle{Failures: 2}, nil)
+
+		if err := ts.RecordFailure(ThrottleLogin, "test@example.com", "203.0.113.7"); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	})
+
+	t.Run("Backs off", func(t *testing.T) {
+		mockThrottleRepo.EXPECT().RecordFailure(account, gomock.Any(), gomock.Any()).Return(models.AuthThrottle{Failures: 5}, nil)
+		mockThrottleRepo.EXPECT().LockThrottle(account, gomock.Any()).DoAndReturn(func(_ string, until time.Time) error {
+			if wait := time.Until(until); wait <= time.Second || wait > 2*time.Second {
+				t.Errorf("Locked for %v, expected 2s", wait)
+			}
+			return nil
+		})
+		mockThrottleRepo.EXPECT().RecordFailure(ip, gomock.Any(), gomock.Any()).Return(models.AuthThrottle{Failures: 5}, nil)
+
+		if err := ts.RecordFailure(ThrottleLogin, "test@example.com", "203.0.113.7"); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	})
+
+	t.Run("Locks out and emails the user", func(t *testing.T) {
+		mockThrottleRepo.EXPECT().RecordFailure(account, gomock.Any(), gomock.Any()).Return(models.AuthThrottle{Failures: 6}, nil)
+		mockThrottleRepo.EXPECT().LockThrottle(account, gomock.Any()).Return(nil)
+		mockUserRepo.EXPECT().GetUserByEmail("test@example.com").Return(user, nil)
+		ctrl.RecordCall(mockEmailService, "GenericSendMail", "Account Temporarily Locked", gomock.Any(), user.Email, user.Name).Return(nil)
+		mockThrottleRepo.EXPECT().RecordFailure(ip, gomock.Any(), gomock.Any()).Return(models.AuthThrottle{Failures: 6}, nil)
+
+		if err := ts.RecordFailure(ThrottleLogin, "test@example.com", "203.0.113.7"); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	})
+}
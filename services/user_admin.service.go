@@ -0,0 +1,138 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"github.com/anirudhgray/mood-harbour-backend/infra/logger"
+	"github.com/anirudhgray/mood-harbour-backend/models"
+	"github.com/anirudhgray/mood-harbour-backend/repository"
+)
+
+// DefaultUsersPerPage is the default page size of the admin user listing.
+const DefaultUsersPerPage = 20
+
+// UserAdminService lets admins manage users' accounts.
+type UserAdminService struct {
+	userRepo         repository.UserRepositoryInterface
+	sessionRepo      repository.SessionRepositoryInterface
+	oneTimeTokenRepo repository.OneTimeTokenRepositoryInterface
+	authService      AuthServiceInterface
+}
+
+func NewUserAdminService(userRepo repository.UserRepositoryInterface, sessionRepo repository.SessionRepositoryInterface, oneTimeTokenRepo repository.OneTimeTokenRepositoryInterface, authService AuthServiceInterface) *UserAdminService {
+	return &UserAdminService{userRepo, sessionRepo, oneTimeTokenRepo, authService}
+}
+
+type UserAdminServiceInterface interface {
+	GetUsers(filter models.UserFilter, page, perPage int) (models.UserPage, error)
+	GetUser(userID uint) (models.User, error)
+	SetDisabled(adminID, userID uint, disabled bool) (models.User, error)
+	SetRole(adminID, userID uint, role models.Role) (models.User, error)
+	VerifyUser(userID uint) (models.User, error)
+	SendPasswordReset(userID uint) error
+}
+
+// GetUsers gets a page of the users matching the filter, most recently registered first.
+func (us *UserAdminService) GetUsers(filter models.UserFilter, page, perPage int) (models.UserPage, error) {
+	if page < 1 {
+		page = 1
+	}
+	if perPage < 1 {
+		perPage = DefaultUsersPerPage
+	}
+
+	users, total, err := us.userRepo.SearchUsers(filter, (page-1)*perPage, perPage)
+	if err != nil {
+		return models.UserPage{}, err
+	}
+	if users == nil {
+		users = []models.User{}
+	}
+
+	return models.UserPage{Users: users, Page: page, PerPage: perPage, Total: total}, nil
+}
+
+// GetUser gets a single user.
+func (us *UserAdminService) GetUser(userID uint) (models.User, error) {
+	return us.userRepo.GetUserByID(userID)
+}
+
+// SetDisabled disables or re-enables a user. Disabling a user logs them out everywhere. Admins can't disable themselves.
+func (us *UserAdminService) SetDisabled(adminID, userID uint, disabled bool) (models.User, error) {
+	if adminID == userID {
+		return models.User{}, errors.New("you can't disable or enable your own account")
+	}
+
+	user, err := us.userRepo.GetUserByID(userID)
+	if err != nil {
+		return models.User{}, err
+	}
+
+	if err := us.userRepo.SetUserDisabled(userID, disabled); err != nil {
+		return models.User{}, err
+	}
+	user.Disabled = disabled
+
+	if disabled {
+		if err := us.sessionRepo.RevokeUserSessions(userID, 0, time.Now()); err != nil {
+			return models.User{}, err
+		}
+	}
+
+	logger.Infof("Admin %d set disabled = %v for user %d", adminID, disabled, userID)
+	return user, nil
+}
+
+// SetRole changes a user's role. Admins can't change their own role, so there's always an admin left.
+func (us *UserAdminService) SetRole(adminID, userID uint, role models.Role) (models.User, error) {
+	if !role.Valid() {
+		return models.User{}, errors.New("unknown role")
+	}
+	if adminID == userID {
+		return models.User{}, errors.New("you can't change your own role")
+	}
+
+	user, err := us.userRepo.GetUserByID(userID)
+	if err != nil {
+		return models.User{}, err
+	}
+
+	if err := us.userRepo.SetUserRole(userID, role); err != nil {
+		return models.User{}, err
+	}
+	user.Role = role
+
+	logger.Infof("Admin %d set role = %s for user %d", adminID, role, userID)
+	return user, nil
+}
+
+// VerifyUser marks a user's email as verified without them following the verification link.
+func (us *UserAdminService) VerifyUser(userID uint) (models.User, error) {
+	user, err := us.userRepo.GetUserByID(userID)
+	if err != nil {
+		return models.User{}, err
+	}
+
+	if err := us.userRepo.VerifyUserEmail(user.Email); err != nil {
+		return models.User{}, err
+	}
+	user.Verified = true
+
+	// the verification link isn't needed anymore
+	if err := us.oneTimeTokenRepo.DeleteOneTimeToken(user.Email, models.OneTimeTokenVerifyEmail); err != nil {
+		logger.Errorf("Failed to delete verification token: %v", err)
+	}
+
+	return user, nil
+}
+
+// SendPasswordReset sends a user the same forgot password mail they would get by requesting it themselves.
+func (us *UserAdminService) SendPasswordReset(userID uint) error {
+	user, err := us.userRepo.GetUserByID(userID)
+	if err != nil {
+		return err
+	}
+
+	return us.authService.ForgotPasswordRequest(user.Email)
+}
@@ -0,0 +1,142 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/anirudhgray/mood-harbour-backend/mocks"
+	"github.com/anirudhgray/mood-harbour-backend/models"
+	"github.com/golang/mock/gomock"
+)
+
+func TestUserAdminService_GetUsers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	us := &UserAdminService{userRepo: mockUserRepo}
+
+	disabled := true
+	filter := models.UserFilter{Query: "test", Disabled: &disabled}
+	mockUserRepo.EXPECT().SearchUsers(filter, 40, 20).Return([]models.User{{Email: "test@example.com"}}, int64(41), nil)
+
+	page, err := us.GetUsers(filter, 3, 0)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if page.Page != 3 || page.PerPage != DefaultUsersPerPage || page.Total != 41 || len(page.Users) != 1 {
+		t.Errorf("Unexpected page: %+v", page)
+	}
+}
+
+func TestUserAdminService_SetDisabled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	mockSessionRepo := mocks.NewMockSessionRepository(ctrl)
+	us := &UserAdminService{userRepo: mockUserRepo, sessionRepo: mockSessionRepo}
+
+	user := models.User{Email: "test@example.com"}
+	user.ID = 2
+
+	t.Run("Disabling logs the user out everywhere", func(t *testing.T) {
+		mockUserRepo.EXPECT().GetUserByID(uint(2)).Return(user, nil)
+		mockUserRepo.EXPECT().SetUserDisabled(uint(2), true).Return(nil)
+		mockSessionRepo.EXPECT().RevokeUserSessions(uint(2), uint(0), gomock.Any()).Return(nil)
+
+		updated, err := us.SetDisabled(1, 2, true)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !updated.Disabled {
+			t.Error("Expected the user to be disabled")
+		}
+	})
+
+	t.Run("Enabling", func(t *testing.T) {
+		mockUserRepo.EXPECT().GetUserByID(uint(2)).Return(user, nil)
+		mockUserRepo.EXPECT().SetUserDisabled(uint(2), false).Return(nil)
+
+		if _, err := us.SetDisabled(1, 2, false); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	})
+
+	t.Run("Admins can't disable themselves", func(t *testing.T) {
+		if _, err := us.SetDisabled(2, 2, true); err == nil {
+			t.Error("Expected an error")
+		}
+	})
+}
+
+func TestUserAdminService_SetRole(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	us := &UserAdminService{userRepo: mockUserRepo}
+
+	user := models.User{Email: "test@example.com"}
+	user.ID = 2
+
+	mockUserRepo.EXPECT().GetUserByID(uint(2)).Return(user, nil)
+	mockUserRepo.EXPECT().SetUserRole(uint(2), models.ModeratorRole).Return(nil)
+
+	updated, err := us.SetRole(1, 2, models.ModeratorRole)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if updated.Role != models.ModeratorRole {
+		t.Errorf("Expected the user to be a moderator, got %s", updated.Role)
+	}
+
+	if _, err := us.SetRole(1, 1, models.UserRole); err == nil {
+		t.Error("Expected an error for an admin demoting themselves")
+	}
+	if _, err := us.SetRole(1, 2, models.Role("superuser")); err == nil {
+		t.Error("Expected an error for an unknown role")
+	}
+}
+
+func TestUserAdminService_VerifyUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	mockOneTimeTokenRepo := mocks.NewMockOneTimeTokenRepository(ctrl)
+	us := &UserAdminService{userRepo: mockUserRepo, oneTimeTokenRepo: mockOneTimeTokenRepo}
+
+	user := models.User{Email: "test@example.com"}
+	user.ID = 2
+
+	mockUserRepo.EXPECT().GetUserByID(uint(2)).Return(user, nil)
+	mockUserRepo.EXPECT().VerifyUserEmail(user.Email).Return(nil)
+	mockOneTimeTokenRepo.EXPECT().DeleteOneTimeToken(user.Email, models.OneTimeTokenVerifyEmail).Return(nil)
+
+	updated, err := us.VerifyUser(2)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !updated.Verified {
+		t.Error("Expected the user to be verified")
+	}
+}
+
+func TestUserAdminService_SendPasswordReset(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	mockAuthService := mocks.NewMockAuthServiceInterface(ctrl)
+	us := &UserAdminService{userRepo: mockUserRepo, authService: mockAuthService}
+
+	user := models.User{Email: "test@example.com"}
+	user.ID = 2
+
+	mockUserRepo.EXPECT().GetUserByID(uint(2)).Return(user, nil)
+	mockAuthService.EXPECT().ForgotPasswordRequest(user.Email).Return(nil)
+
+	if err := us.SendPasswordReset(2); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
@@ -1,79 +1,65 @@
 package auth
 
 import (
-	"unicode"
+	"errors"
+	"sync"
 
+	"github.com/anirudhgray/mood-harbour-backend/infra/logger"
 	"github.com/anirudhgray/mood-harbour-backend/models"
 	"github.com/anirudhgray/mood-harbour-backend/repository"
-	"github.com/anirudhgray/mood-harbour-backend/utils/token"
-	"golang.org/x/crypto/bcrypt"
+	"github.com/anirudhgray/mood-harbour-backend/utils/password"
 )
 
-// CheckPasswordStrength checks for the following:
-//   - min length 7
-//   - uppercase
-//   - lowercase
-//   - number
-//   - special character
-func CheckPasswordStrength(s string) bool {
-	var (
-		hasMinLen  = false
-		hasUpper   = false
-		hasLower   = false
-		hasNumber  = false
-		hasSpecial = false
-	)
-	if len(s) >= 7 {
-		hasMinLen = true
+// VerifyPassword checks plaintext password against a hashed one, of any algorithm utils/password supports.
+func VerifyPassword(plaintext, hashedPassword string) error {
+	ok, _, err := password.Verify(plaintext, hashedPassword)
+	if err != nil {
+		return err
 	}
-	for _, char := range s {
-		switch {
-		case unicode.IsUpper(char):
-			hasUpper = true
-		case unicode.IsLower(char):
-			hasLower = true
-		case unicode.IsNumber(char):
-			hasNumber = true
-		case unicode.IsPunct(char) || unicode.IsSymbol(char):
-			hasSpecial = true
-		}
+	if !ok {
+		return errors.New("incorrect password")
 	}
-	return hasMinLen && hasUpper && hasLower && hasNumber && hasSpecial
+	return nil
 }
 
-// VerifyPassword checks plaintext password against a hashed one.
-func VerifyPassword(password, hashedPassword string) error {
-	return bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
-}
+// ErrInvalidCredentials is returned for every failed login, so it doesn't reveal which emails have accounts.
+var ErrInvalidCredentials = errors.New("invalid email or password")
 
-// LoginCheck checks validity of given email/password, and returns token in user exists and password is correct.
-func LoginCheck(email, password string) (t string, u models.User, e error) {
-	var err error
+var (
+	dummyHash     string
+	dummyHashOnce sync.Once
+)
 
+// LoginCheck checks validity of given email/password, and returns the user if they exist and the password is correct.
+// A password is hashed even for emails without an account, so the response time doesn't reveal them either.
+// Passwords hashed with an older algorithm or parameters are hashed again with the configured ones.
+func LoginCheck(email, plaintext string) (models.User, error) {
 	userRepo := repository.NewUserRepository()
 	pwdAuthRepo := repository.NewPasswordAuthRepository()
 
-	user, err := userRepo.GetUserByEmail(email)
-	if err != nil {
-		return "", user, err
-	}
-	pwdAuth, err := pwdAuthRepo.GetPwdAuthItemByEmail(email)
-	if err != nil {
-		return "", user, err
+	user, userErr := userRepo.GetUserByEmail(email)
+	pwdAuth, pwdAuthErr := pwdAuthRepo.GetPwdAuthItemByEmail(email)
+	if userErr != nil || pwdAuthErr != nil {
+		dummyHashOnce.Do(func() {
+			dummyHash, _ = password.Hash("mood-harbour")
+		})
+		password.Verify(plaintext, dummyHash)
+		return models.User{}, ErrInvalidCredentials
 	}
 
-	err = VerifyPassword(password, pwdAuth.Password)
-
-	if err != nil && err == bcrypt.ErrMismatchedHashAndPassword {
-		return "", user, err
+	ok, rehash, err := password.Verify(plaintext, pwdAuth.Password)
+	if err != nil || !ok {
+		return models.User{}, ErrInvalidCredentials
 	}
 
-	token, err := token.GenerateToken(user)
-
-	if err != nil {
-		return "", user, err
+	if rehash {
+		pwdAuth.Password = plaintext
+		if err := pwdAuth.HashPassword(); err != nil {
+			logger.Errorf("Failed to rehash password of user %d: %v", user.ID, err)
+		} else if err := pwdAuthRepo.UpdatePwdAuthItem(pwdAuth); err != nil {
+			logger.Errorf("Failed to store rehashed password of user %d: %v", user.ID, err)
+		}
 	}
 
-	return token, user, nil
-
+	return user, nil
 }
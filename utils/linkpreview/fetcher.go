@@ -0,0 +1,267 @@
+package linkpreview
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/viper"
+	"golang.org/x/net/html"
+)
+
+// Preview holds the metadata shown for a link: Open Graph tags where present, falling back to the plain HTML title and description.
+type Preview struct {
+	Title       string
+	Description string
+	ImageURL    string
+}
+
+// Fetcher fetches link previews. It also tells whether a link is still alive: any error means the link could not be reached.
+type Fetcher interface {
+	Fetch(rawURL string) (Preview, error)
+}
+
+// NoopFetcher never makes a request and always returns an empty preview. Used when link previews are disabled.
+type NoopFetcher struct{}
+
+// Fetch always returns an empty preview.
+func (NoopFetcher) Fetch(rawURL string) (Preview, error) {
+	return Preview{}, nil
+}
+
+// StatusError is returned when the server responds with a non-2xx status code.
+type StatusError struct {
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("link returned status %d", e.StatusCode)
+}
+
+// ErrPrivateAddress is returned when a link resolves to a loopback, private, shared, link-local or multicast address.
+var ErrPrivateAddress = errors.New("link points to a private network address")
+
+// sharedAddressSpace is 100.64.0.0/10, used for carrier-grade NAT, whose hosts can be internal to the network.
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// isPrivateAddress tells whether the IP address can't be reached from the public internet, or addresses many hosts.
+func isPrivateAddress(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || sharedAddressSpace.Contains(ip) || ip.IsLinkLocalUnicast() || ip.IsMulticast() || ip.IsUnspecified()
+}
+
+// HTTPFetcher fetches previews over HTTP, reading at most MaxBytes of the response body.
+type HTTPFetcher struct {
+	client   *http.Client
+	maxBytes int64
+}
+
+// NewHTTPFetcher creates an HTTPFetcher with the given request timeout and body size limit.
+// Unless allowPrivate is set, requests to private addresses are refused, so user submitted links can't be used to probe internal services.
+// The addresses are checked when connecting, so requests don't go through a proxy from the environment either, which would
+// connect to them instead.
+func NewHTTPFetcher(timeout time.Duration, maxBytes int64, allowPrivate bool) *HTTPFetcher {
+	dialer := &net.Dialer{Timeout: timeout}
+	if !allowPrivate {
+		dialer.Control = func(network, address string, c syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			ip := net.ParseIP(host)
+			if ip == nil || isPrivateAddress(ip) {
+				return ErrPrivateAddress
+			}
+			return nil
+		}
+	}
+
+	transport := &http.Transport{
+		DialContext:           dialer.DialContext,
+		TLSHandshakeTimeout:   timeout,
+		ResponseHeaderTimeout: timeout,
+	}
+	if allowPrivate {
+		transport.Proxy = http.ProxyFromEnvironment
+	}
+
+	client := &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= 5 {
+				return errors.New("too many redirects")
+			}
+			return nil
+		},
+	}
+
+	return &HTTPFetcher{client: client, maxBytes: maxBytes}
+}
+
+// Fetch requests the URL and extracts its preview. Non-HTML responses return an empty preview without an error.
+func (hf *HTTPFetcher) Fetch(rawURL string) (Preview, error) {
+	req, err := http.NewRequest(http.MethodGet, rawURL, nil)
+	if err != nil {
+		return Preview{}, err
+	}
+	req.Header.Set("User-Agent", "MoodHarbourLinkPreview/1.0")
+	req.Header.Set("Accept", "text/html,application/xhtml+xml;q=0.9,*/*;q=0.8")
+
+	resp, err := hf.client.Do(req)
+	if err != nil {
+		return Preview{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return Preview{}, &StatusError{StatusCode: resp.StatusCode}
+	}
+
+	if !strings.Contains(strings.ToLower(resp.Header.Get("Content-Type")), "html") {
+		return Preview{}, nil
+	}
+
+	preview, err := parsePreview(io.LimitReader(resp.Body, hf.maxBytes))
+	if err != nil {
+		return Preview{}, err
+	}
+
+	// relative image paths are resolved against the final URL, after redirects
+	if preview.ImageURL != "" {
+		if imageURL, err := resp.Request.URL.Parse(preview.ImageURL); err == nil && (imageURL.Scheme == "http" || imageURL.Scheme == "https") {
+			preview.ImageURL = imageURL.String()
+		} else {
+			preview.ImageURL = ""
+		}
+	}
+
+	return preview, nil
+}
+
+// parsePreview walks the HTML tokens up to the end of <head>, collecting Open Graph tags and the plain title and description.
+func parsePreview(r io.Reader) (Preview, error) {
+	var preview Preview
+	var htmlTitle, htmlDescription string
+
+	tokenizer := html.NewTokenizer(r)
+	inTitle := false
+
+	for {
+		tokenType := tokenizer.Next()
+		switch tokenType {
+		case html.ErrorToken:
+			// io.EOF, including hitting the size limit, just ends parsing with whatever was found
+			if err := tokenizer.Err(); err != nil && err != io.EOF {
+				return Preview{}, err
+			}
+			return fillPreview(preview, htmlTitle, htmlDescription), nil
+		case html.StartTagToken, html.SelfClosingTagToken:
+			token := tokenizer.Token()
+			switch token.Data {
+			case "title":
+				inTitle = tokenType == html.StartTagToken
+			case "meta":
+				key, content := metaKeyContent(token)
+				switch key {
+				case "og:title":
+					preview.Title = content
+				case "og:description":
+					preview.Description = content
+				case "og:image", "og:image:url":
+					if preview.ImageURL == "" {
+						preview.ImageURL = content
+					}
+				case "twitter:image":
+					if preview.ImageURL == "" {
+						preview.ImageURL = content
+					}
+				case "description":
+					htmlDescription = content
+				}
+			case "body":
+				return fillPreview(preview, htmlTitle, htmlDescription), nil
+			}
+		case html.EndTagToken:
+			token := tokenizer.Token()
+			if token.Data == "title" {
+				inTitle = false
+			}
+			if token.Data == "head" {
+				return fillPreview(preview, htmlTitle, htmlDescription), nil
+			}
+		case html.TextToken:
+			if inTitle && htmlTitle == "" {
+				htmlTitle = string(tokenizer.Text())
+			}
+		}
+	}
+}
+
+func metaKeyContent(token html.Token) (key, content string) {
+	for _, attr := range token.Attr {
+		switch strings.ToLower(attr.Key) {
+		case "property", "name":
+			if key == "" {
+				key = strings.ToLower(strings.TrimSpace(attr.Val))
+			}
+		case "content":
+			content = attr.Val
+		}
+	}
+	return key, content
+}
+
+func fillPreview(preview Preview, htmlTitle, htmlDescription string) Preview {
+	if preview.Title == "" {
+		preview.Title = htmlTitle
+	}
+	if preview.Description == "" {
+		preview.Description = htmlDescription
+	}
+	preview.Title = truncate(collapseSpace(preview.Title), 255)
+	preview.Description = truncate(collapseSpace(preview.Description), 1000)
+	preview.ImageURL = strings.TrimSpace(preview.ImageURL)
+	if len(preview.ImageURL) > 1000 {
+		preview.ImageURL = ""
+	}
+	return preview
+}
+
+func collapseSpace(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// truncate shortens text to at most max bytes without splitting a UTF-8 character.
+func truncate(text string, max int) string {
+	if len(text) <= max {
+		return text
+	}
+	for max > 0 && !isRuneStart(text[max]) {
+		max--
+	}
+	return text[:max]
+}
+
+func isRuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
+
+// NewFetcherFromConfig creates the link preview fetcher from LINK_PREVIEW_* config.
+// Falls back to a NoopFetcher if LINK_PREVIEW_ENABLED is false.
+func NewFetcherFromConfig() Fetcher {
+	viper.SetDefault("LINK_PREVIEW_ENABLED", true)
+	viper.SetDefault("LINK_PREVIEW_TIMEOUT_SECONDS", 5)
+	viper.SetDefault("LINK_PREVIEW_MAX_BYTES", 512*1024)
+
+	if !viper.GetBool("LINK_PREVIEW_ENABLED") {
+		return NoopFetcher{}
+	}
+
+	timeout := time.Duration(viper.GetInt("LINK_PREVIEW_TIMEOUT_SECONDS")) * time.Second
+	return NewHTTPFetcher(timeout, viper.GetInt64("LINK_PREVIEW_MAX_BYTES"), false)
+}
@@ -0,0 +1,72 @@
+package linkpreview
+
+import (
+	"errors"
+	"net"
+	"net/url"
+	"strings"
+)
+
+// MaxURLLength matches the size of the Resource.URL column.
+const MaxURLLength = 255
+
+// ErrInvalidURL is returned by NormalizeURL for anything that isn't an absolute http(s) URL.
+var ErrInvalidURL = errors.New("invalid URL: must be an absolute http or https URL")
+
+// trackingParams are query parameters stripped during normalization, so the same link shared from different places is detected as a duplicate.
+var trackingParams = []string{"utm_source", "utm_medium", "utm_campaign", "utm_term", "utm_content", "fbclid", "gclid"}
+
+// NormalizeURL validates a user submitted URL and returns it in a canonical form:
+// lowercase scheme and host, no default port, no fragment, no tracking parameters, sorted query and no trailing slash.
+func NormalizeURL(rawURL string) (string, error) {
+	rawURL = strings.TrimSpace(rawURL)
+	if rawURL == "" {
+		return "", ErrInvalidURL
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", ErrInvalidURL
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", ErrInvalidURL
+	}
+	if u.Hostname() == "" || u.User != nil {
+		return "", ErrInvalidURL
+	}
+
+	host := strings.ToLower(u.Hostname())
+	port := u.Port()
+	if (u.Scheme == "http" && port == "80") || (u.Scheme == "https" && port == "443") {
+		port = ""
+	}
+	switch {
+	case port != "":
+		u.Host = net.JoinHostPort(host, port)
+	case strings.Contains(host, ":"):
+		// IPv6 addresses keep their brackets
+		u.Host = "[" + host + "]"
+	default:
+		u.Host = host
+	}
+
+	u.Fragment = ""
+	u.RawFragment = ""
+	u.Path = strings.TrimRight(u.Path, "/")
+	u.RawPath = ""
+
+	query := u.Query()
+	for _, param := range trackingParams {
+		query.Del(param)
+	}
+	// url.Values.Encode sorts by key
+	u.RawQuery = query.Encode()
+
+	normalized := u.String()
+	if len(normalized) > MaxURLLength {
+		return "", errors.New("URL is too long")
+	}
+	return normalized, nil
+}
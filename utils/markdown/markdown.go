@@ -0,0 +1,25 @@
+package markdown
+
+import (
+	"bytes"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+)
+
+// renderer converts Markdown to HTML. Raw HTML in the source is left out by goldmark unless explicitly enabled, and the output is sanitized anyway.
+var renderer = goldmark.New(
+	goldmark.WithExtensions(
+		extension.Strikethrough,
+		extension.Linkify,
+	),
+)
+
+// Render converts user submitted Markdown to sanitized HTML that is safe to show as-is.
+func Render(source string) (string, error) {
+	var buf bytes.Buffer
+	if err := renderer.Convert([]byte(source), &buf); err != nil {
+		return "", err
+	}
+	return Sanitize(buf.String()), nil
+}
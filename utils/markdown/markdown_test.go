@@ -0,0 +1,63 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	testCases := []struct {
+		name     string
+		source   string
+		expected string
+	}{
+		{"emphasis", "Take a **deep** breath", "<p>Take a <strong>deep</strong> breath</p>\n"},
+		{"list", "1. Breathe in\n2. Breathe out", "<ol>\n<li>Breathe in</li>\n<li>Breathe out</li>\n</ol>\n"},
+		{"code block", "```go\nfmt.Println()\n```", "<pre><code class=\"language-go\">fmt.Println()\n</code></pre>\n"},
+		{"raw html is left out", "Hello <script>alert(1)</script>", "<p>Hello alert(1)</p>\n"},
+		{"external link", "[help](https://example.com/help)", `<p><a href="https://example.com/help" rel="nofollow noopener noreferrer" target="_blank">help</a></p>` + "\n"},
+		{"javascript link", "[click](javascript:alert(1))", "<p><a>click</a></p>\n"},
+		{"autolink", "see https://example.com", `<p>see <a href="https://example.com" rel="nofollow noopener noreferrer" target="_blank">https://example.com</a></p>` + "\n"},
+	}
+
+	for _, tc := range testCases {
+		rendered, err := Render(tc.source)
+		if err != nil {
+			t.Errorf("%s: Render returned an error: %v", tc.name, err)
+			continue
+		}
+		if rendered != tc.expected {
+			t.Errorf("%s: Render(%q) = %q; expected %q", tc.name, tc.source, rendered, tc.expected)
+		}
+	}
+}
+
+func TestSanitize(t *testing.T) {
+	testCases := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"plain text is escaped", "1 < 2 & 3 > 2", "1 &lt; 2 &amp; 3 &gt; 2"},
+		{"script removed with content", "<p>hi<script>alert(1)</script></p>", "<p>hi</p>"},
+		{"unknown tags unwrapped", "<div><span>kept</span></div>", "kept"},
+		{"event handlers removed", `<p onclick="alert(1)">text</p>`, "<p>text</p>"},
+		{"images removed", `<img src="x" onerror="alert(1)">after`, "after"},
+		{"data link removed", `<a href="data:text/html;base64,PHNjcmlwdD4=">x</a>`, "<a>x</a>"},
+		{"obfuscated javascript link removed", `<a href=" JaVaScRiPt:alert(1)">x</a>`, "<a>x</a>"},
+		{"mailto kept", `<a href="mailto:help@example.com">mail</a>`, `<a href="mailto:help@example.com">mail</a>`},
+		{"bad code class removed", `<code class="x onmouseover">c</code>`, "<code>c</code>"},
+		{"attribute values escaped", `<a href="https://example.com/?a=&quot;b" title="&lt;t&gt;">x</a>`, `<a href="https://example.com/?a=&#34;b" rel="nofollow noopener noreferrer" target="_blank" title="&lt;t&gt;">x</a>`},
+		{"comments removed", "a<!-- secret -->b", "ab"},
+	}
+
+	for _, tc := range testCases {
+		sanitized := Sanitize(tc.input)
+		if sanitized != tc.expected {
+			t.Errorf("%s: Sanitize(%q) = %q; expected %q", tc.name, tc.input, sanitized, tc.expected)
+		}
+		if strings.Contains(strings.ToLower(sanitized), "javascript:") {
+			t.Errorf("%s: Sanitize left a javascript: URL in %q", tc.name, sanitized)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package markdown
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// allowedTags lists the elements kept by Sanitize, mapped to the attributes allowed on each.
+// Any other element is unwrapped: the tag is dropped but its text is kept.
+var allowedTags = map[atom.Atom][]string{
+	atom.P:          nil,
+	atom.Br:         nil,
+	atom.Hr:         nil,
+	atom.H1:         nil,
+	atom.H2:         nil,
+	atom.H3:         nil,
+	atom.H4:         nil,
+	atom.H5:         nil,
+	atom.H6:         nil,
+	atom.Strong:     nil,
+	atom.Em:         nil,
+	atom.B:          nil,
+	atom.I:          nil,
+	atom.Del:        nil,
+	atom.S:          nil,
+	atom.Blockquote: nil,
+	atom.Ul:         nil,
+	atom.Ol:         {"start"},
+	atom.Li:         nil,
+	atom.Pre:        nil,
+	atom.Code:       {"class"},
+	atom.A:          {"href", "title"},
+}
+
+// droppedTags are removed together with everything inside them.
+var droppedTags = map[atom.Atom]bool{
+	atom.Script:   true,
+	atom.Style:    true,
+	atom.Iframe:   true,
+	atom.Object:   true,
+	atom.Embed:    true,
+	atom.Form:     true,
+	atom.Textarea: true,
+	atom.Select:   true,
+	atom.Noscript: true,
+	atom.Template: true,
+	atom.Svg:      true,
+	atom.Math:     true,
+}
+
+var voidTags = map[atom.Atom]bool{
+	atom.Br: true,
+	atom.Hr: true,
+}
+
+var (
+	codeClassPattern = regexp.MustCompile(`^language-[A-Za-z0-9_+-]+$`)
+	numberPattern    = regexp.MustCompile(`^[0-9]{1,6}$`)
+)
+
+// Sanitize strips everything from an HTML fragment except a small allowlist of formatting tags and attributes.
+// Links are only kept for http, https and mailto URLs, and external links are rewritten to open in a new tab without passing on the referrer.
+func Sanitize(fragment string) string {
+	body := &html.Node{Type: html.ElementNode, Data: "body", DataAtom: atom.Body}
+	nodes, err := html.ParseFragment(strings.NewReader(fragment), body)
+	if err != nil {
+		// the parser only fails on read errors, which can't happen with a strings.Reader
+		return html.EscapeString(fragment)
+	}
+
+	var sb strings.Builder
+	for _, node := range nodes {
+		writeNode(&sb, node)
+	}
+	return sb.String()
+}
+
+func writeNode(sb *strings.Builder, node *html.Node) {
+	switch node.Type {
+	case html.TextNode:
+		sb.WriteString(html.EscapeString(node.Data))
+	case html.ElementNode:
+		if droppedTags[node.DataAtom] {
+			return
+		}
+
+		allowedAttrs, allowed := allowedTags[node.DataAtom]
+		if !allowed {
+			writeChildren(sb, node)
+			return
+		}
+
+		sb.WriteString("<" + node.Data)
+		for _, attr := range sanitizeAttrs(node, allowedAttrs) {
+			sb.WriteString(" " + attr.Key + `="` + html.EscapeString(attr.Val) + `"`)
+		}
+		sb.WriteString(">")
+
+		if voidTags[node.DataAtom] {
+			return
+		}
+
+		writeChildren(sb, node)
+		sb.WriteString("</" + node.Data + ">")
+	case html.DocumentNode:
+		writeChildren(sb, node)
+	}
+	// comments and doctypes are dropped
+}
+
+func writeChildren(sb *strings.Builder, node *html.Node) {
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		writeNode(sb, child)
+	}
+}
+
+func sanitizeAttrs(node *html.Node, allowedAttrs []string) []html.Attribute {
+	var attrs []html.Attribute
+	for _, attr := range node.Attr {
+		if attr.Namespace != "" || !containsString(allowedAttrs, attr.Key) {
+			continue
+		}
+
+		switch attr.Key {
+		case "href":
+			href, external, ok := sanitizeLink(attr.Val)
+			if !ok {
+				continue
+			}
+			attr.Val = href
+			attrs = append(attrs, attr)
+			if external {
+				attrs = append(attrs,
+					html.Attribute{Key: "rel", Val: "nofollow noopener noreferrer"},
+					html.Attribute{Key: "target", Val: "_blank"},
+				)
+			}
+			continue
+		case "class":
+			if !codeClassPattern.MatchString(attr.Val) {
+				continue
+			}
+		case "start":
+			if !numberPattern.MatchString(attr.Val) {
+				continue
+			}
+		}
+
+		attrs = append(attrs, attr)
+	}
+	return attrs
+}
+
+// sanitizeLink returns the cleaned link and whether it points off-site. Anything other than http, https and mailto links is rejected.
+func sanitizeLink(rawURL string) (href string, external bool, ok bool) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return "", false, false
+	}
+
+	switch strings.ToLower(u.Scheme) {
+	case "http", "https":
+		if u.Host == "" {
+			return "", false, false
+		}
+		return u.String(), true, true
+	case "mailto":
+		return u.String(), false, true
+	default:
+		return "", false, false
+	}
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,101 @@
+package moderation
+
+import (
+	"bufio"
+	"os"
+	"strings"
+	"unicode"
+
+	"github.com/anirudhgray/mood-harbour-backend/infra/logger"
+	"github.com/spf13/viper"
+)
+
+// TextFilter checks user submitted text for content that should be looked at by a moderator.
+type TextFilter interface {
+	// Check returns true along with a human readable reason if the text should be flagged.
+	Check(text string) (flagged bool, reason string)
+}
+
+// NoopFilter never flags anything. Used when no word list is configured.
+type NoopFilter struct{}
+
+// Check always returns false.
+func (NoopFilter) Check(text string) (bool, string) {
+	return false, ""
+}
+
+// WordListFilter flags text containing any word or phrase from a local word list. Matching is case-insensitive and on whole words only.
+type WordListFilter struct {
+	terms []string
+}
+
+// NewWordListFilter creates a WordListFilter from the given words or phrases.
+func NewWordListFilter(words []string) *WordListFilter {
+	var terms []string
+	for _, word := range words {
+		term := normalise(word)
+		if term != "" {
+			terms = append(terms, term)
+		}
+	}
+	return &WordListFilter{terms: terms}
+}
+
+// LoadWordListFilter creates a WordListFilter from a file with one word or phrase per line. Blank lines and lines starting with # are ignored.
+func LoadWordListFilter(path string) (*WordListFilter, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var words []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		words = append(words, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return NewWordListFilter(words), nil
+}
+
+// Check flags the text if it contains any of the filter's words or phrases.
+func (wf *WordListFilter) Check(text string) (bool, string) {
+	padded := " " + normalise(text) + " "
+	for _, term := range wf.terms {
+		if strings.Contains(padded, " "+term+" ") {
+			return true, "Automatically flagged: contains blocked term \"" + term + "\"."
+		}
+	}
+	return false, ""
+}
+
+// normalise lowercases text and collapses everything that isn't a letter or number into single spaces.
+func normalise(text string) string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	return strings.Join(fields, " ")
+}
+
+// NewFilterFromConfig creates the text filter from the word list at MODERATION_WORDLIST_PATH.
+// Falls back to a NoopFilter if no word list is configured or it cannot be loaded.
+func NewFilterFromConfig() TextFilter {
+	path := viper.GetString("MODERATION_WORDLIST_PATH")
+	if path == "" {
+		return NoopFilter{}
+	}
+
+	filter, err := LoadWordListFilter(path)
+	if err != nil {
+		logger.Errorf("Failed to load moderation word list, content will not be auto-flagged: %v", err)
+		return NoopFilter{}
+	}
+	return filter
+}
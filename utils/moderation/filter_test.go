@@ -0,0 +1,51 @@
+package moderation
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWordListFilter_Check(t *testing.T) {
+	filter := NewWordListFilter([]string{"spam", "Buy Now"})
+
+	testCases := []struct {
+		text          string
+		expectFlagged bool
+	}{
+		{"A helpful article about breathing exercises.", false},
+		{"This is SPAM!", true},
+		{"click here, buy   now", true},
+		{"Spammy but not a whole word match", false},
+		{"", false},
+	}
+
+	for _, tc := range testCases {
+		flagged, reason := filter.Check(tc.text)
+		if flagged != tc.expectFlagged {
+			t.Errorf("Check(%q) returned %v; expected %v", tc.text, flagged, tc.expectFlagged)
+		}
+		if flagged && reason == "" {
+			t.Errorf("Check(%q) flagged text without a reason", tc.text)
+		}
+	}
+}
+
+func TestLoadWordListFilter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "wordlist.txt")
+	if err := os.WriteFile(path, []byte("# blocked terms\nscam\n\n  miracle cure  \n"), 0o600); err != nil {
+		t.Fatalf("Failed to write word list: %v", err)
+	}
+
+	filter, err := LoadWordListFilter(path)
+	if err != nil {
+		t.Fatalf("LoadWordListFilter returned an error: %v", err)
+	}
+
+	if flagged, _ := filter.Check("Try this Miracle Cure today"); !flagged {
+		t.Errorf("Expected phrase from word list to be flagged")
+	}
+	if flagged, _ := filter.Check("blocked terms"); flagged {
+		t.Errorf("Expected comment line in word list to be ignored")
+	}
+}
@@ -0,0 +1,198 @@
+// Package password hashes passwords for storage. Hashes are encoded with their algorithm and parameters, so hashes made
+// with older algorithms or weaker parameters keep verifying and can be replaced when their user next logs in.
+package password
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/viper"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// ErrUnknownHash is returned for encoded hashes of no supported algorithm.
+var ErrUnknownHash = errors.New("unknown password hash format")
+
+// Hasher hashes passwords with one algorithm and set of parameters.
+type Hasher interface {
+	// Hash hashes the password with a random salt, encoding the algorithm and parameters along with it.
+	Hash(password string) (string, error)
+	// Verify checks the password against an encoded hash made by this hasher's algorithm, whatever its parameters.
+	Verify(password, encoded string) (bool, error)
+	// Handles reports whether the encoded hash was made by this hasher's algorithm.
+	Handles(encoded string) bool
+	// Outdated reports whether the encoded hash of this hasher's algorithm was made with other parameters.
+	Outdated(encoded string) bool
+}
+
+// Argon2id hashes passwords with Argon2id, encoded in the PHC string format used by the reference implementation.
+type Argon2id struct {
+	Memory     uint32 // KiB
+	Time       uint32 // passes over the memory
+	Threads    uint8
+	SaltLength uint32
+	KeyLength  uint32
+}
+
+const argon2idPrefix = "$argon2id$"
+
+type argon2idHash struct {
+	memory, time uint32
+	threads      uint8
+	salt, key    []byte
+}
+
+func (a Argon2id) Hash(password string) (string, error) {
+	salt := make([]byte, a.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	key := argon2.IDKey([]byte(password), salt, a.Time, a.Memory, a.Threads, a.KeyLength)
+	return fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$%s$%s", argon2idPrefix, argon2.Version, a.Memory, a.Time, a.Threads,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+func (a Argon2id) Verify(password, encoded string) (bool, error) {
+	h, err := parseArgon2id(encoded)
+	if err != nil {
+		return false, err
+	}
+	key := argon2.IDKey([]byte(password), h.salt, h.time, h.memory, h.threads, uint32(len(h.key)))
+	return subtle.ConstantTimeCompare(key, h.key) == 1, nil
+}
+
+func (a Argon2id) Handles(encoded string) bool {
+	return strings.HasPrefix(encoded, argon2idPrefix)
+}
+
+func (a Argon2id) Outdated(encoded string) bool {
+	h, err := parseArgon2id(encoded)
+	if err != nil {
+		return true
+	}
+	return h.memory != a.Memory || h.time != a.Time || h.threads != a.Threads || uint32(len(h.salt)) != a.SaltLength || uint32(len(h.key)) != a.KeyLength
+}
+
+// parseArgon2id parses $argon2id$v=19$m=<memory>,t=<time>,p=<threads>$<salt>$<key>.
+func parseArgon2id(encoded string) (argon2idHash, error) {
+	var h argon2idHash
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return h, ErrUnknownHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return h, errors.New("unsupported argon2id version")
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &h.memory, &h.time, &h.threads); err != nil {
+		return h, errors.New("invalid argon2id parameters")
+	}
+	if h.time == 0 || h.threads == 0 {
+		return h, errors.New("invalid argon2id parameters")
+	}
+
+	var err error
+	if h.salt, err = base64.RawStdEncoding.DecodeString(parts[4]); err != nil {
+		return h, errors.New("invalid argon2id salt")
+	}
+	if h.key, err = base64.RawStdEncoding.DecodeString(parts[5]); err != nil || len(h.key) == 0 {
+		return h, errors.New("invalid argon2id key")
+	}
+	return h, nil
+}
+
+// BcryptMaxBytes is the length of the longest password bcrypt can hash.
+const BcryptMaxBytes = 72
+
+// Bcrypt hashes passwords with bcrypt, whose hashes encode their cost themselves. Passwords hashed before Argon2id
+// was the default are bcrypt hashes.
+type Bcrypt struct {
+	Cost int
+}
+
+func (b Bcrypt) Hash(password string) (string, error) {
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), b.Cost)
+	return string(hashed), err
+}
+
+func (b Bcrypt) Verify(password, encoded string) (bool, error) {
+	err := bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password))
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (b Bcrypt) Handles(encoded string) bool {
+	return strings.HasPrefix(encoded, "$2a$") || strings.HasPrefix(encoded, "$2b$") || strings.HasPrefix(encoded, "$2y$")
+}
+
+func (b Bcrypt) Outdated(encoded string) bool {
+	cost, err := bcrypt.Cost([]byte(encoded))
+	return err != nil || cost != b.Cost
+}
+
+// DefaultArgon2id has the minimum parameters OWASP recommends for Argon2id.
+var DefaultArgon2id = Argon2id{Memory: 19456, Time: 2, Threads: 1, SaltLength: 16, KeyLength: 32}
+
+// hasher is the hasher new passwords are hashed with, see SetHasher.
+var hasher Hasher = DefaultArgon2id
+
+// SetHasher sets the hasher Hash and Verify use. Called once at startup with the hasher from NewHasherFromConfig,
+// before any passwords are hashed.
+func SetHasher(h Hasher) {
+	hasher = h
+}
+
+// NewHasherFromConfig builds the hasher new passwords are hashed with from PASSWORD_HASHER, argon2id or bcrypt, and its
+// parameters: ARGON2_MEMORY_KIB, ARGON2_TIME and ARGON2_THREADS, or BCRYPT_COST.
+func NewHasherFromConfig() (Hasher, error) {
+	viper.SetDefault("PASSWORD_HASHER", "argon2id")
+	viper.SetDefault("ARGON2_MEMORY_KIB", DefaultArgon2id.Memory)
+	viper.SetDefault("ARGON2_TIME", DefaultArgon2id.Time)
+	viper.SetDefault("ARGON2_THREADS", DefaultArgon2id.Threads)
+	viper.SetDefault("BCRYPT_COST", bcrypt.DefaultCost)
+
+	switch viper.GetString("PASSWORD_HASHER") {
+	case "argon2id":
+		memory, time, threads := viper.GetUint32("ARGON2_MEMORY_KIB"), viper.GetUint32("ARGON2_TIME"), viper.GetUint("ARGON2_THREADS")
+		if memory < 8*uint32(threads) || time < 1 || threads < 1 || threads > 255 {
+			return nil, errors.New("invalid Argon2id parameters")
+		}
+		return Argon2id{Memory: memory, Time: time, Threads: uint8(threads), SaltLength: DefaultArgon2id.SaltLength, KeyLength: DefaultArgon2id.KeyLength}, nil
+	case "bcrypt":
+		cost := viper.GetInt("BCRYPT_COST")
+		if cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+			return nil, errors.New("invalid bcrypt cost")
+		}
+		return Bcrypt{Cost: cost}, nil
+	}
+	return nil, fmt.Errorf("unknown PASSWORD_HASHER %q", viper.GetString("PASSWORD_HASHER"))
+}
+
+// Hash hashes the password with the configured hasher.
+func Hash(password string) (string, error) {
+	return hasher.Hash(password)
+}
+
+// Verify checks the password against an encoded hash of any supported algorithm. If it matches, rehash reports whether
+// it should be hashed again because it wasn't made by the configured hasher with its current parameters.
+func Verify(password, encoded string) (ok bool, rehash bool, err error) {
+	for _, candidate := range []Hasher{hasher, Argon2id{}, Bcrypt{}} {
+		if !candidate.Handles(encoded) {
+			continue
+		}
+		ok, err := candidate.Verify(password, encoded)
+		if err != nil || !ok {
+			return false, false, err
+		}
+		return true, !hasher.Handles(encoded) || hasher.Outdated(encoded), nil
+	}
+	return false, false, ErrUnknownHash
+}
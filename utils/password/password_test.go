@@ -0,0 +1,142 @@
+package password
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// testArgon2id uses little memory to keep the tests fast.
+var testArgon2id = Argon2id{Memory: 64, Time: 1, Threads: 1, SaltLength: 16, KeyLength: 32}
+
+func TestArgon2id(t *testing.T) {
+	encoded, err := testArgon2id.Hash("correct horse")
+	if err != nil {
+		t.Fatalf("Hash returned an error: %v", err)
+	}
+	if !strings.HasPrefix(encoded, "$argon2id$v=19$m=64,t=1,p=1$") {
+		t.Errorf("Unexpected encoding %s", encoded)
+	}
+
+	if ok, err := testArgon2id.Verify("correct horse", encoded); err != nil || !ok {
+		t.Errorf("Verify = %v, %v for the right password", ok, err)
+	}
+	if ok, err := testArgon2id.Verify("wrong horse", encoded); err != nil || ok {
+		t.Errorf("Verify = %v, %v for the wrong password", ok, err)
+	}
+
+	// hashes are salted
+	if again, _ := testArgon2id.Hash("correct horse"); again == encoded {
+		t.Error("Expected a different hash for the same password")
+	}
+
+	if testArgon2id.Outdated(encoded) {
+		t.Error("Expected a hash with the same parameters to be up to date")
+	}
+	stronger := testArgon2id
+	stronger.Time = 2
+	if !stronger.Outdated(encoded) {
+		t.Error("Expected a hash with fewer passes to be outdated")
+	}
+	// the parameters are read from the hash, not the hasher
+	if ok, err := stronger.Verify("correct horse", encoded); err != nil || !ok {
+		t.Errorf("Verify = %v, %v with other parameters", ok, err)
+	}
+}
+
+func TestArgon2id_Malformed(t *testing.T) {
+	for _, encoded := range []string{
+		"$argon2id$v=19$m=64,t=1,p=1$c2FsdA",
+		"$argon2id$v=16$m=64,t=1,p=1$c2FsdA$a2V5",
+		"$argon2id$v=19$m=64,t=0,p=1$c2FsdA$a2V5",
+		"$argon2id$v=19$m=64,t=1,p=1$!!!$a2V5",
+		"$argon2i$v=19$m=64,t=1,p=1$c2FsdA$a2V5",
+	} {
+		if ok, err := testArgon2id.Verify("password", encoded); err == nil || ok {
+			t.Errorf("Verify(%s) = %v, %v, expected an error", encoded, ok, err)
+		}
+	}
+}
+
+func TestBcrypt(t *testing.T) {
+	hasher := Bcrypt{Cost: bcrypt.MinCost}
+	encoded, err := hasher.Hash("correct horse")
+	if err != nil {
+		t.Fatalf("Hash returned an error: %v", err)
+	}
+	if !hasher.Handles(encoded) || testArgon2id.Handles(encoded) {
+		t.Errorf("Expected only bcrypt to handle %s", encoded)
+	}
+
+	if ok, err := hasher.Verify("correct horse", encoded); err != nil || !ok {
+		t.Errorf("Verify = %v, %v for the right password", ok, err)
+	}
+	if ok, err := hasher.Verify("wrong horse", encoded); err != nil || ok {
+		t.Errorf("Verify = %v, %v for the wrong password", ok, err)
+	}
+	if hasher.Outdated(encoded) || !(Bcrypt{Cost: bcrypt.MinCost + 1}).Outdated(encoded) {
+		t.Error("Expected only a hash of another cost to be outdated")
+	}
+}
+
+func TestVerify(t *testing.T) {
+	SetHasher(testArgon2id)
+	defer SetHasher(DefaultArgon2id)
+
+	current, err := Hash("correct horse")
+	if err != nil {
+		t.Fatalf("Hash returned an error: %v", err)
+	}
+	legacy, _ := bcrypt.GenerateFromPassword([]byte("correct horse"), bcrypt.MinCost)
+	weaker, _ := Argon2id{Memory: 32, Time: 1, Threads: 1, SaltLength: 16, KeyLength: 32}.Hash("correct horse")
+
+	tests := []struct {
+		name     string
+		password string
+		encoded  string
+		ok       bool
+		rehash   bool
+	}{
+		{"Current hash", "correct horse", current, true, false},
+		{"Wrong password", "wrong horse", current, false, false},
+		{"Existing bcrypt hash", "correct horse", string(legacy), true, true},
+		{"Wrong password for a bcrypt hash", "wrong horse", string(legacy), false, false},
+		{"Outdated parameters", "correct horse", weaker, true, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ok, rehash, err := Verify(test.password, test.encoded)
+			if err != nil || ok != test.ok || rehash != test.rehash {
+				t.Errorf("Verify = %v, %v, %v; expected %v, %v", ok, rehash, err, test.ok, test.rehash)
+			}
+		})
+	}
+
+	if _, _, err := Verify("correct horse", "plaintext"); err != ErrUnknownHash {
+		t.Errorf("Expected ErrUnknownHash for an unknown format, got %v", err)
+	}
+}
+
+func TestNewHasherFromConfig(t *testing.T) {
+	defer viper.Reset()
+
+	viper.Set("PASSWORD_HASHER", "bcrypt")
+	viper.Set("BCRYPT_COST", 12)
+	if hasher, err := NewHasherFromConfig(); err != nil || hasher != (Bcrypt{Cost: 12}) {
+		t.Errorf("NewHasherFromConfig = %v, %v; expected bcrypt with cost 12", hasher, err)
+	}
+
+	viper.Set("PASSWORD_HASHER", "md5")
+	if _, err := NewHasherFromConfig(); err == nil {
+		t.Error("Expected an error for an unknown hasher")
+	}
+
+	viper.Set("PASSWORD_HASHER", "argon2id")
+	viper.Set("ARGON2_TIME", 0)
+	if _, err := NewHasherFromConfig(); err == nil {
+		t.Error("Expected an error for invalid Argon2id parameters")
+	}
+}
@@ -0,0 +1,351 @@
+package password
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/anirudhgray/mood-harbour-backend/infra/logger"
+	"github.com/spf13/viper"
+)
+
+// Codes of the ways a password can violate a Policy, for clients to show their own messages for.
+const (
+	ViolationTooShort             = "too_short"
+	ViolationTooLong              = "too_long"
+	ViolationMissingUppercase     = "missing_uppercase"
+	ViolationMissingLowercase     = "missing_lowercase"
+	ViolationMissingNumber        = "missing_number"
+	ViolationMissingSymbol        = "missing_symbol"
+	ViolationContainsPersonalInfo = "contains_personal_info"
+	ViolationTooWeak              = "too_weak"
+	ViolationBreached             = "breached"
+)
+
+// Violation is one way a password doesn't meet a Policy.
+type Violation struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// PolicyError is returned for passwords which don't meet the policy, with every way they don't.
+type PolicyError struct {
+	Violations []Violation
+}
+
+func (e *PolicyError) Error() string {
+	messages := make([]string, len(e.Violations))
+	for i, violation := range e.Violations {
+		messages[i] = violation.Message
+	}
+	return "password not strong enough: " + strings.Join(messages, "; ")
+}
+
+// Policy decides which passwords users can choose. Lengths are in characters, not bytes, except for MaxBytes.
+type Policy struct {
+	MinLength int
+	MaxLength int // 0 for no limit
+	// MaxBytes limits the length of passwords in bytes too, for hashers which can't hash longer ones like bcrypt. 0 for no limit.
+	MaxBytes      int
+	RequireUpper  bool
+	RequireLower  bool
+	RequireNumber bool
+	RequireSymbol bool
+	// DisallowPersonalInfo rejects passwords containing the user's email address, the part before the @ or a part of their name.
+	DisallowPersonalInfo bool
+	// MinEntropyBits is the least estimated entropy, see EstimateEntropy.
+	MinEntropyBits float64
+	// Breached rejects passwords known from data breaches or common password lists, if set.
+	Breached BreachedList
+}
+
+// Check returns every way the password of the user with the email and name doesn't meet the policy, nil if it does.
+func (p Policy) Check(password, email, name string) []Violation {
+	var violations []Violation
+	add := func(code, message string) {
+		violations = append(violations, Violation{Code: code, Message: message})
+	}
+
+	length := utf8.RuneCountInString(password)
+	if length < p.MinLength {
+		add(ViolationTooShort, fmt.Sprintf("must be at least %d characters long", p.MinLength))
+	}
+	if p.MaxLength > 0 && length > p.MaxLength {
+		add(ViolationTooLong, fmt.Sprintf("must be at most %d characters long", p.MaxLength))
+	} else if p.MaxBytes > 0 && len(password) > p.MaxBytes {
+		add(ViolationTooLong, fmt.Sprintf("must be at most %d bytes long, accented letters and other symbols take up more than one", p.MaxBytes))
+	}
+
+	var hasUpper, hasLower, hasNumber, hasSymbol bool
+	for _, char := range password {
+		switch {
+		case unicode.IsUpper(char):
+			hasUpper = true
+		case unicode.IsLower(char):
+			hasLower = true
+		case unicode.IsNumber(char):
+			hasNumber = true
+		case unicode.IsPunct(char) || unicode.IsSymbol(char):
+			hasSymbol = true
+		}
+	}
+	if p.RequireUpper && !hasUpper {
+		add(ViolationMissingUppercase, "must contain an uppercase letter")
+	}
+	if p.RequireLower && !hasLower {
+		add(ViolationMissingLowercase, "must contain a lowercase letter")
+	}
+	if p.RequireNumber && !hasNumber {
+		add(ViolationMissingNumber, "must contain a number")
+	}
+	if p.RequireSymbol && !hasSymbol {
+		add(ViolationMissingSymbol, "must contain a symbol")
+	}
+
+	if p.DisallowPersonalInfo && containsPersonalInfo(password, email, name) {
+		add(ViolationContainsPersonalInfo, "must not contain your email address or name")
+	}
+	// too short passwords are too weak anyway, no need to say so twice
+	if length >= p.MinLength && EstimateEntropy(password) < p.MinEntropyBits {
+		add(ViolationTooWeak, "is too easy to guess, try a longer password with fewer repeated or consecutive characters")
+	}
+
+	if p.Breached != nil {
+		breached, err := p.Breached.Contains(password)
+		if err != nil {
+			// not being able to look it up shouldn't stop users from setting passwords
+			logger.Errorf("Failed to look up password in the breached password list: %v", err)
+		} else if breached {
+			add(ViolationBreached, "has appeared in a data breach or is too common, choose another one")
+		}
+	}
+	return violations
+}
+
+// Validate returns a PolicyError if the password doesn't meet the policy.
+func (p Policy) Validate(password, email, name string) error {
+	if violations := p.Check(password, email, name); len(violations) > 0 {
+		return &PolicyError{Violations: violations}
+	}
+	return nil
+}
+
+// containsPersonalInfo reports whether the password contains the email address, the part of it before the @ or a part of
+// the name, ignoring case. Parts shorter than 3 characters are too likely to appear by chance.
+func containsPersonalInfo(password, email, name string) bool {
+	password = strings.ToLower(password)
+	email = strings.ToLower(strings.TrimSpace(email))
+	parts := strings.Fields(strings.ToLower(name))
+	if email != "" {
+		parts = append(parts, email)
+		if at := strings.LastIndex(email, "@"); at > 0 {
+			parts = append(parts, email[:at])
+		}
+	}
+	for _, part := range parts {
+		if utf8.RuneCountInString(part) >= 3 && strings.Contains(password, part) {
+			return true
+		}
+	}
+	return false
+}
+
+// EstimateEntropy estimates the bits of entropy of a password, as if each character was picked at random from the classes
+// of characters it uses: lowercase and uppercase letters, numbers, symbols and anything else. Characters repeating or
+// continuing a sequence from the one before, like "aaa", "abc" or "321", only count for 1 bit each.
+func EstimateEntropy(password string) float64 {
+	var lower, upper, number, symbol, other bool
+	for _, char := range password {
+		switch {
+		case char >= 'a' && char <= 'z':
+			lower = true
+		case char >= 'A' && char <= 'Z':
+			upper = true
+		case char >= '0' && char <= '9':
+			number = true
+		case char < utf8.RuneSelf && (unicode.IsPunct(char) || unicode.IsSymbol(char) || char == ' '):
+			symbol = true
+		default:
+			other = true
+		}
+	}
+
+	charset := 0
+	for _, class := range []struct {
+		used bool
+		size int
+	}{{lower, 26}, {upper, 26}, {number, 10}, {symbol, 33}, {other, 100}} {
+		if class.used {
+			charset += class.size
+		}
+	}
+	if charset == 0 {
+		return 0
+	}
+
+	bitsPerChar := math.Log2(float64(charset))
+	bits := 0.0
+	previous := rune(-10)
+	for _, char := range password {
+		if diff := char - previous; diff >= -1 && diff <= 1 {
+			bits++
+		} else {
+			bits += bitsPerChar
+		}
+		previous = char
+	}
+	return bits
+}
+
+// BreachedList looks up passwords known from data breaches or common password lists.
+type BreachedList interface {
+	Contains(password string) (bool, error)
+}
+
+// sha1Hex is the uppercase hex SHA-1 of the password, as the breached password lists are keyed by.
+func sha1Hex(password string) string {
+	sum := sha1.Sum([]byte(password))
+	return strings.ToUpper(hex.EncodeToString(sum[:]))
+}
+
+// HashList is a BreachedList held in memory, indexed by the first 5 characters of the hashes like a RangeDir.
+type HashList struct {
+	ranges map[string]map[string]struct{}
+}
+
+// LoadHashList loads a HashList from a file with one SHA-1 hash per line, optionally followed by :<count> as in the
+// Have I Been Pwned password files. Other lines are taken as plaintext passwords, so common password lists can be used
+// as they are. Blank lines and lines starting with # are ignored.
+func LoadHashList(path string) (*HashList, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	list := &HashList{ranges: map[string]map[string]struct{}{}}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		hash := strings.ToUpper(strings.SplitN(line, ":", 2)[0])
+		if !isSHA1Hex(hash) {
+			hash = sha1Hex(line)
+		}
+		if list.ranges[hash[:5]] == nil {
+			list.ranges[hash[:5]] = map[string]struct{}{}
+		}
+		list.ranges[hash[:5]][hash[5:]] = struct{}{}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (l *HashList) Contains(password string) (bool, error) {
+	hash := sha1Hex(password)
+	_, ok := l.ranges[hash[:5]][hash[5:]]
+	return ok, nil
+}
+
+func isSHA1Hex(s string) bool {
+	if len(s) != 40 {
+		return false
+	}
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
+
+// RangeDir is a BreachedList too big to hold in memory, split into a file per first 5 characters of the hashes, as
+// downloaded from the Have I Been Pwned range API. Each file, named by its prefix with an optional .txt extension, has
+// the remaining 35 characters of a hash per line, optionally followed by :<count>. Only the one file is read per lookup.
+type RangeDir struct {
+	Path string
+}
+
+func (d RangeDir) Contains(password string) (bool, error) {
+	hash := sha1Hex(password)
+	file, err := os.Open(filepath.Join(d.Path, hash[:5]))
+	if errors.Is(err, os.ErrNotExist) {
+		file, err = os.Open(filepath.Join(d.Path, hash[:5]+".txt"))
+	}
+	if errors.Is(err, os.ErrNotExist) {
+		// no breached password has this prefix
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		suffix := strings.SplitN(strings.TrimSpace(scanner.Text()), ":", 2)[0]
+		if strings.EqualFold(suffix, hash[5:]) {
+			return true, nil
+		}
+	}
+	return false, scanner.Err()
+}
+
+// LoadBreachedList loads a HashList from a file, or a RangeDir from a directory.
+func LoadBreachedList(path string) (BreachedList, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return RangeDir{Path: path}, nil
+	}
+	return LoadHashList(path)
+}
+
+// NewPolicyFromConfig reads the password policy from PASSWORD_MIN_LENGTH, PASSWORD_MAX_LENGTH, PASSWORD_REQUIRE_UPPERCASE,
+// PASSWORD_REQUIRE_LOWERCASE, PASSWORD_REQUIRE_NUMBER, PASSWORD_REQUIRE_SYMBOL, PASSWORD_DISALLOW_PERSONAL_INFO and
+// PASSWORD_MIN_ENTROPY_BITS. Passwords are checked against the breached password list at PASSWORD_BREACHED_LIST_PATH,
+// unless it is empty. Passwords are limited to the 72 bytes bcrypt can hash if PASSWORD_HASHER is bcrypt.
+func NewPolicyFromConfig() (Policy, error) {
+	viper.SetDefault("PASSWORD_MIN_LENGTH", 8)
+	viper.SetDefault("PASSWORD_MAX_LENGTH", 128)
+	viper.SetDefault("PASSWORD_REQUIRE_UPPERCASE", true)
+	viper.SetDefault("PASSWORD_REQUIRE_LOWERCASE", true)
+	viper.SetDefault("PASSWORD_REQUIRE_NUMBER", true)
+	viper.SetDefault("PASSWORD_REQUIRE_SYMBOL", true)
+	viper.SetDefault("PASSWORD_DISALLOW_PERSONAL_INFO", true)
+	viper.SetDefault("PASSWORD_MIN_ENTROPY_BITS", 28)
+
+	policy := Policy{
+		MinLength:            viper.GetInt("PASSWORD_MIN_LENGTH"),
+		MaxLength:            viper.GetInt("PASSWORD_MAX_LENGTH"),
+		RequireUpper:         viper.GetBool("PASSWORD_REQUIRE_UPPERCASE"),
+		RequireLower:         viper.GetBool("PASSWORD_REQUIRE_LOWERCASE"),
+		RequireNumber:        viper.GetBool("PASSWORD_REQUIRE_NUMBER"),
+		RequireSymbol:        viper.GetBool("PASSWORD_REQUIRE_SYMBOL"),
+		DisallowPersonalInfo: viper.GetBool("PASSWORD_DISALLOW_PERSONAL_INFO"),
+		MinEntropyBits:       viper.GetFloat64("PASSWORD_MIN_ENTROPY_BITS"),
+	}
+
+	if viper.GetString("PASSWORD_HASHER") == "bcrypt" {
+		policy.MaxBytes = BcryptMaxBytes
+	}
+
+	if path := viper.GetString("PASSWORD_BREACHED_LIST_PATH"); path != "" {
+		breached, err := LoadBreachedList(path)
+		if err != nil {
+			return Policy{}, fmt.Errorf("failed to load breached password list: %w", err)
+		}
+		policy.Breached = breached
+	}
+	return policy, nil
+}
@@ -0,0 +1,183 @@
+package password
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+var testPolicy = Policy{
+	MinLength:            8,
+	MaxLength:            64,
+	RequireUpper:         true,
+	RequireLower:         true,
+	RequireNumber:        true,
+	RequireSymbol:        true,
+	DisallowPersonalInfo: true,
+	MinEntropyBits:       28,
+}
+
+func violationCodes(violations []Violation) []string {
+	var codes []string
+	for _, violation := range violations {
+		codes = append(codes, violation.Code)
+	}
+	return codes
+}
+
+func TestPolicy_Check(t *testing.T) {
+	tests := []struct {
+		name     string
+		password string
+		codes    []string
+	}{
+		{"Strong password", "Abcdef1!", nil},
+		{"Too short", "Ab1!xyz", []string{ViolationTooShort}},
+		{"Too long", "Aa1!" + strings.Repeat("xq", 40), []string{ViolationTooLong}},
+		{"No number", "NoNumber@", []string{ViolationMissingNumber}},
+		{"No symbol", "NoSymbol123", []string{ViolationMissingSymbol}},
+		{"Lowercase only", "mellowfrog", []string{ViolationMissingUppercase, ViolationMissingNumber, ViolationMissingSymbol}},
+		{"Contains the name", "Harbour#2024", []string{ViolationContainsPersonalInfo}},
+		{"Contains the email's local part", "Xq7!jane.doe", []string{ViolationContainsPersonalInfo}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			codes := violationCodes(testPolicy.Check(test.password, "Jane.Doe@example.com", "Jane Harbour"))
+			if !reflect.DeepEqual(codes, test.codes) {
+				t.Errorf("Check(%s) = %v; expected %v", test.password, codes, test.codes)
+			}
+		})
+	}
+
+	// without character classes, long enough passwords can still be too predictable
+	lengthOnly := Policy{MinLength: 8, MinEntropyBits: 28}
+	for _, weak := range []string{"aaaaaaaaaaaa", "12345678", "abcdefghijklmnop"} {
+		if codes := violationCodes(lengthOnly.Check(weak, "", "")); !reflect.DeepEqual(codes, []string{ViolationTooWeak}) {
+			t.Errorf("Check(%s) = %v; expected too weak", weak, codes)
+		}
+	}
+	if violations := lengthOnly.Check("correct horse battery staple", "", ""); violations != nil {
+		t.Errorf("Unexpected violations %v for a passphrase", violations)
+	}
+
+	// parts of the name too short to mean anything are allowed
+	if violations := testPolicy.Check("Bo!Kx9$Lq", "", "Bo Li"); violations != nil {
+		t.Errorf("Unexpected violations %v for short name parts", violations)
+	}
+}
+
+func TestPolicy_Validate(t *testing.T) {
+	if err := testPolicy.Validate("Abcdef1!", "", ""); err != nil {
+		t.Errorf("Unexpected error for a strong password: %v", err)
+	}
+
+	var policyErr *PolicyError
+	err := testPolicy.Validate("short", "", "")
+	if !errors.As(err, &policyErr) || len(policyErr.Violations) != 4 {
+		t.Fatalf("Expected a PolicyError with 4 violations, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "must be at least 8 characters long") {
+		t.Errorf("Expected the error to list the violations, got %s", err)
+	}
+}
+
+func TestEstimateEntropy(t *testing.T) {
+	if bits := EstimateEntropy(""); bits != 0 {
+		t.Errorf("EstimateEntropy of an empty password = %v; expected 0", bits)
+	}
+	// 10 digits are about 3.3 bits each, but a run of them only counts for the first
+	if bits := EstimateEntropy("123456789"); bits < 11 || bits > 12 {
+		t.Errorf("EstimateEntropy(123456789) = %v; expected about 11.3", bits)
+	}
+	if EstimateEntropy("Tr0ub4dor&3") <= EstimateEntropy("Abcdefgh1!") {
+		t.Error("Expected a password without sequences to have more entropy than one of the same length with them")
+	}
+	if EstimateEntropy("correct horse battery staple") <= EstimateEntropy("Tr0ub4dor&3") {
+		t.Error("Expected a long passphrase to have more entropy than a short complex password")
+	}
+}
+
+func TestLoadBreachedList(t *testing.T) {
+	dir := t.TempDir()
+
+	// SHA-1 of "P@ssw0rd1" with a count, and a plaintext common password
+	file := filepath.Join(dir, "breached.txt")
+	content := "# breached passwords\n" + sha1Hex("P@ssw0rd1") + ":1234\n\nQwerty123!\n"
+	if err := os.WriteFile(file, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	ranges := filepath.Join(dir, "ranges")
+	hash := sha1Hex("P@ssw0rd1")
+	if err := os.Mkdir(ranges, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(ranges, hash[:5]+".txt"), []byte("0018A45C4D1DEF81644B54AB7F969B88D65:1\n"+strings.ToLower(hash[5:])+":1234\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, path := range []string{file, ranges} {
+		list, err := LoadBreachedList(path)
+		if err != nil {
+			t.Fatalf("LoadBreachedList(%s) returned an error: %v", path, err)
+		}
+		if ok, err := list.Contains("P@ssw0rd1"); err != nil || !ok {
+			t.Errorf("Contains(P@ssw0rd1) = %v, %v for %s; expected true", ok, err, path)
+		}
+		if ok, err := list.Contains("Xq7!vbn#Lm"); err != nil || ok {
+			t.Errorf("Contains(Xq7!vbn#Lm) = %v, %v for %s; expected false", ok, err, path)
+		}
+	}
+
+	list, _ := LoadBreachedList(file)
+	if ok, _ := list.Contains("Qwerty123!"); !ok {
+		t.Error("Expected plaintext lines to be looked up too")
+	}
+
+	policy := testPolicy
+	policy.Breached = list
+	if codes := violationCodes(policy.Check("P@ssw0rd1", "", "")); !reflect.DeepEqual(codes, []string{ViolationBreached}) {
+		t.Errorf("Check of a breached password = %v; expected breached", codes)
+	}
+
+	if _, err := LoadBreachedList(filepath.Join(dir, "missing.txt")); err == nil {
+		t.Error("Expected an error for a missing list")
+	}
+}
+
+func TestNewPolicyFromConfig(t *testing.T) {
+	defer viper.Reset()
+
+	viper.Set("PASSWORD_MIN_LENGTH", 12)
+	viper.Set("PASSWORD_REQUIRE_SYMBOL", false)
+
+	policy, err := NewPolicyFromConfig()
+	if err != nil {
+		t.Fatalf("NewPolicyFromConfig returned an error: %v", err)
+	}
+	if policy.MinLength != 12 || policy.MaxLength != 128 || policy.MaxBytes != 0 || policy.RequireSymbol || !policy.RequireUpper || policy.MinEntropyBits != 28 {
+		t.Errorf("Unexpected policy %+v", policy)
+	}
+
+	// bcrypt can't hash passwords longer than 72 bytes
+	viper.Set("PASSWORD_HASHER", "bcrypt")
+	if policy, _ = NewPolicyFromConfig(); policy.MaxBytes != BcryptMaxBytes {
+		t.Errorf("Expected passwords to be limited to %d bytes with bcrypt, got %d", BcryptMaxBytes, policy.MaxBytes)
+	}
+	long := "Aa1!" + strings.Repeat("éx", 30) // 94 bytes, but 64 characters
+	if codes := violationCodes(policy.Check(long, "", "")); !reflect.DeepEqual(codes, []string{ViolationTooLong}) {
+		t.Errorf("Check of a password over %d bytes = %v; expected too long", BcryptMaxBytes, codes)
+	}
+
+	// a list which cannot be loaded would leave passwords unchecked without anyone noticing
+	viper.Set("PASSWORD_BREACHED_LIST_PATH", filepath.Join(t.TempDir(), "missing.txt"))
+	if _, err := NewPolicyFromConfig(); err == nil {
+		t.Error("Expected an error for a missing breached password list")
+	}
+}
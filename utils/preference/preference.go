@@ -0,0 +1,170 @@
+// Package preference turns reviews, bookmarks and dismissed recommendations into the signed preference weights recommenders learn from.
+// A weight of 1 is a strong like, -1 a strong dislike and 0 a neutral interaction: the user has seen the resource, but it says nothing
+// about their taste.
+package preference
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/anirudhgray/mood-harbour-backend/models"
+	"github.com/spf13/viper"
+)
+
+// Model sets how interactions are weighted.
+//
+// A review weighs StarWeights[rating - 1]. With Normalize the user's mean review weight is subtracted first, so a 4 star review
+// from someone who gives everything 5 stars counts as a dislike. Review weights within NeutralBand of 0 are neutral.
+// Bookmarks without a review weigh BookmarkWeight, and dismissals without a review or bookmark DismissalWeight.
+// Every weight then decays exponentially with the age of the interaction, halving every HalfLife.
+type Model struct {
+	StarWeights     [5]float64    // Weight of a 1 to 5 star review, between -1 and 1
+	NeutralBand     float64       // Review weights between -NeutralBand and NeutralBand count as 0
+	Normalize       bool          // Centre each user's review weights on their mean
+	HalfLife        time.Duration // 0 for no decay
+	BookmarkWeight  float64
+	DismissalWeight float64
+}
+
+// DefaultModel weighs 3 star reviews as neutral and 1 and 2 star reviews as dislikes, without normalization or decay.
+var DefaultModel = Model{
+	StarWeights:     [5]float64{-1, -0.5, 0, 0.5, 1},
+	BookmarkWeight:  1,
+	DismissalWeight: -0.5,
+}
+
+// NewModelFromConfig reads the model from the RECOMMENDATION_* preference settings, falling back on DefaultModel for anything not set.
+func NewModelFromConfig() (Model, error) {
+	model := DefaultModel
+
+	if starWeights := viper.GetString("RECOMMENDATION_STAR_WEIGHTS"); starWeights != "" {
+		fields := strings.Split(starWeights, ",")
+		if len(fields) != len(model.StarWeights) {
+			return model, errors.New("RECOMMENDATION_STAR_WEIGHTS needs a weight for each of 1 to 5 stars")
+		}
+		for i, field := range fields {
+			weight, err := strconv.ParseFloat(strings.TrimSpace(field), 64)
+			if err != nil || weight < -1 || weight > 1 {
+				return model, fmt.Errorf("invalid weight %q for %d stars in RECOMMENDATION_STAR_WEIGHTS", field, i+1)
+			}
+			model.StarWeights[i] = weight
+		}
+	}
+
+	if viper.IsSet("RECOMMENDATION_NEUTRAL_BAND") {
+		model.NeutralBand = viper.GetFloat64("RECOMMENDATION_NEUTRAL_BAND")
+	}
+	if viper.IsSet("RECOMMENDATION_NORMALIZE_RATINGS") {
+		model.Normalize = viper.GetBool("RECOMMENDATION_NORMALIZE_RATINGS")
+	}
+	if viper.IsSet("RECOMMENDATION_HALF_LIFE_DAYS") {
+		model.HalfLife = time.Duration(viper.GetFloat64("RECOMMENDATION_HALF_LIFE_DAYS") * float64(24*time.Hour))
+	}
+	if viper.IsSet("RECOMMENDATION_BOOKMARK_WEIGHT") {
+		model.BookmarkWeight = viper.GetFloat64("RECOMMENDATION_BOOKMARK_WEIGHT")
+	}
+	if viper.IsSet("RECOMMENDATION_DISMISSAL_WEIGHT") {
+		model.DismissalWeight = viper.GetFloat64("RECOMMENDATION_DISMISSAL_WEIGHT")
+	}
+
+	if model.NeutralBand < 0 || model.HalfLife < 0 {
+		return model, errors.New("RECOMMENDATION_NEUTRAL_BAND and RECOMMENDATION_HALF_LIFE_DAYS can't be negative")
+	}
+	return model, nil
+}
+
+// Pair identifies a user's interaction with a resource.
+type Pair struct {
+	UserID     uint
+	ResourceID uint
+}
+
+// StarWeight is the weight of a review with the given rating, before normalization, the neutral band and decay.
+func (m Model) StarWeight(rating models.Rating) float64 {
+	if rating < models.OneStar || rating > models.FiveStar {
+		return 0
+	}
+	return m.StarWeights[rating-1]
+}
+
+// ReviewWeight is the weight of a review with the given rating by a user whose mean review weight is userMean, without decay.
+func (m Model) ReviewWeight(rating models.Rating, userMean float64) float64 {
+	weight := m.StarWeight(rating)
+	if m.Normalize {
+		weight = math.Max(-1, math.Min(1, weight-userMean))
+	}
+	if math.Abs(weight) <= m.NeutralBand {
+		return 0
+	}
+	return weight
+}
+
+// Decay is the factor a weight is multiplied by for an interaction made at the given time.
+func (m Model) Decay(at, now time.Time) float64 {
+	if m.HalfLife <= 0 || !now.After(at) {
+		return 1
+	}
+	return math.Pow(0.5, float64(now.Sub(at))/float64(m.HalfLife))
+}
+
+// UserMeans gets each user's mean review weight, counting only the oldest review of a resource.
+// Reviews should be published reviews of published resources, oldest first.
+func (m Model) UserMeans(reviews []models.Review) map[uint]float64 {
+	sums := make(map[uint]float64)
+	counts := make(map[uint]int)
+	reviewed := make(map[Pair]bool)
+
+	for _, review := range reviews {
+		key := Pair{review.UserID, review.ResourceID}
+		if reviewed[key] {
+			continue
+		}
+		reviewed[key] = true
+
+		sums[review.UserID] += m.StarWeight(review.Rating)
+		counts[review.UserID]++
+	}
+
+	means := make(map[uint]float64, len(sums))
+	for userID, sum := range sums {
+		means[userID] = sum / float64(counts[userID])
+	}
+	return means
+}
+
+// Weights weighs the given interactions as of now. Reviews should be published reviews of published resources, oldest first,
+// and only the oldest review of a resource counts. A review takes precedence over a bookmark, and a bookmark over a dismissal.
+// userMeans are the means from UserMeans, over all of each reviewer's reviews; they're only needed with Normalize.
+//
+// Every interaction gets a weight, neutral ones 0.
+func (m Model) Weights(reviews []models.Review, bookmarks []models.Bookmark, dismissals []models.RecommendationDismissal, userMeans map[uint]float64, now time.Time) map[Pair]float64 {
+	weights := make(map[Pair]float64)
+
+	for _, review := range reviews {
+		key := Pair{review.UserID, review.ResourceID}
+		if _, ok := weights[key]; ok {
+			continue
+		}
+		weights[key] = m.ReviewWeight(review.Rating, userMeans[review.UserID]) * m.Decay(review.CreatedAt, now)
+	}
+
+	for _, bookmark := range bookmarks {
+		key := Pair{bookmark.UserID, bookmark.ResourceID}
+		if _, ok := weights[key]; !ok {
+			weights[key] = m.BookmarkWeight * m.Decay(bookmark.CreatedAt, now)
+		}
+	}
+
+	for _, dismissal := range dismissals {
+		key := Pair{dismissal.UserID, dismissal.ResourceID}
+		if _, ok := weights[key]; !ok {
+			weights[key] = m.DismissalWeight * m.Decay(dismissal.CreatedAt, now)
+		}
+	}
+
+	return weights
+}
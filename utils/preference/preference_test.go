@@ -0,0 +1,153 @@
+package preference
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/anirudhgray/mood-harbour-backend/models"
+	"github.com/spf13/viper"
+	"gorm.io/gorm"
+)
+
+func TestModel_ReviewWeight(t *testing.T) {
+	banded := DefaultModel
+	banded.NeutralBand = 0.5
+
+	normalized := DefaultModel
+	normalized.Normalize = true
+
+	testCases := []struct {
+		name     string
+		model    Model
+		rating   models.Rating
+		userMean float64
+		expected float64
+	}{
+		{"Five stars", DefaultModel, models.FiveStar, 0, 1},
+		{"Three stars are neutral", DefaultModel, models.ThreeStar, 0, 0},
+		{"Two stars", DefaultModel, models.TwoStar, 0, -0.5},
+		{"Within the neutral band", banded, models.FourStar, 0, 0},
+		{"Outside the neutral band", banded, models.OneStar, 0, -1},
+		{"Four stars from a generous user", normalized, models.FourStar, 0.75, -0.25},
+		{"Normalized weights are clamped", normalized, models.FiveStar, -0.5, 1},
+		{"Invalid rating", DefaultModel, models.Rating(0), 0, 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := tc.model.ReviewWeight(tc.rating, tc.userMean)
+			if math.Abs(actual-tc.expected) > 1e-9 {
+				t.Errorf("Expected weight: %f, got: %f", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestModel_Decay(t *testing.T) {
+	now := time.Date(2023, time.June, 1, 0, 0, 0, 0, time.UTC)
+	model := DefaultModel
+	model.HalfLife = 30 * 24 * time.Hour
+
+	if decay := model.Decay(now.Add(-model.HalfLife), now); math.Abs(decay-0.5) > 1e-9 {
+		t.Errorf("Expected a weight to halve after one half-life, got: %f", decay)
+	}
+	if decay := model.Decay(now.Add(-2*model.HalfLife), now); math.Abs(decay-0.25) > 1e-9 {
+		t.Errorf("Expected a weight to quarter after two half-lives, got: %f", decay)
+	}
+	if decay := model.Decay(now.Add(time.Hour), now); decay != 1 {
+		t.Errorf("Expected no decay for interactions in the future, got: %f", decay)
+	}
+	if decay := DefaultModel.Decay(now.AddDate(-5, 0, 0), now); decay != 1 {
+		t.Errorf("Expected no decay without a half-life, got: %f", decay)
+	}
+}
+
+func TestModel_Weights(t *testing.T) {
+	now := time.Date(2023, time.June, 1, 0, 0, 0, 0, time.UTC)
+	at := func(daysAgo int) gorm.Model {
+		return gorm.Model{CreatedAt: now.AddDate(0, 0, -daysAgo)}
+	}
+
+	reviews := []models.Review{
+		{Model: at(10), UserID: 1, ResourceID: 1, Rating: models.TwoStar},
+		{Model: at(5), UserID: 1, ResourceID: 1, Rating: models.FiveStar}, // only the oldest review counts
+		{Model: at(0), UserID: 1, ResourceID: 2, Rating: models.ThreeStar},
+	}
+	bookmarks := []models.Bookmark{
+		{Model: at(0), UserID: 1, ResourceID: 2}, // the review wins
+		{Model: at(30), UserID: 1, ResourceID: 3},
+	}
+	dismissals := []models.RecommendationDismissal{
+		{Model: at(0), UserID: 1, ResourceID: 3}, // the bookmark wins
+		{Model: at(0), UserID: 1, ResourceID: 4},
+	}
+
+	model := DefaultModel
+	model.HalfLife = 30 * 24 * time.Hour
+
+	weights := model.Weights(reviews, bookmarks, dismissals, nil, now)
+
+	expected := map[Pair]float64{
+		{1, 1}: -0.5 * math.Pow(0.5, 10.0/30),
+		{1, 2}: 0, // neutral, but still there
+		{1, 3}: 0.5,
+		{1, 4}: -0.5,
+	}
+	if len(weights) != len(expected) {
+		t.Fatalf("Expected weights %v, got: %v", expected, weights)
+	}
+	for pair, weight := range expected {
+		actual, ok := weights[pair]
+		if !ok || math.Abs(actual-weight) > 1e-9 {
+			t.Errorf("Expected weight %f for %v, got: %f", weight, pair, actual)
+		}
+	}
+}
+
+func TestModel_UserMeans(t *testing.T) {
+	reviews := []models.Review{
+		{UserID: 1, ResourceID: 1, Rating: models.FiveStar},
+		{UserID: 1, ResourceID: 2, Rating: models.FourStar},
+		{UserID: 1, ResourceID: 2, Rating: models.OneStar}, // a later review of the same resource
+		{UserID: 2, ResourceID: 1, Rating: models.OneStar},
+	}
+
+	means := DefaultModel.UserMeans(reviews)
+	if math.Abs(means[1]-0.75) > 1e-9 || math.Abs(means[2]+1) > 1e-9 {
+		t.Errorf("Unexpected means: %v", means)
+	}
+}
+
+func TestNewModelFromConfig(t *testing.T) {
+	defer viper.Reset()
+
+	model, err := NewModelFromConfig()
+	if err != nil || model != DefaultModel {
+		t.Errorf("Expected the default model without any settings, got: %+v, %v", model, err)
+	}
+
+	viper.Set("RECOMMENDATION_STAR_WEIGHTS", "-1, -1, 0, 1, 1")
+	viper.Set("RECOMMENDATION_HALF_LIFE_DAYS", 90)
+	viper.Set("RECOMMENDATION_NORMALIZE_RATINGS", true)
+	model, err = NewModelFromConfig()
+	if err != nil {
+		t.Fatalf("NewModelFromConfig returned an error: %v", err)
+	}
+	if model.StarWeights != [5]float64{-1, -1, 0, 1, 1} || model.HalfLife != 90*24*time.Hour || !model.Normalize {
+		t.Errorf("Unexpected model: %+v", model)
+	}
+
+	for _, starWeights := range []string{"-1,0,1", "-1,-0.5,0,0.5,2", "-1,-0.5,zero,0.5,1"} {
+		viper.Set("RECOMMENDATION_STAR_WEIGHTS", starWeights)
+		if _, err := NewModelFromConfig(); err == nil {
+			t.Errorf("Expected an error for star weights %q", starWeights)
+		}
+	}
+
+	viper.Set("RECOMMENDATION_STAR_WEIGHTS", "")
+	viper.Set("RECOMMENDATION_NEUTRAL_BAND", -0.1)
+	if _, err := NewModelFromConfig(); err == nil {
+		t.Error("Expected an error for a negative neutral band")
+	}
+}
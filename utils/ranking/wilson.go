@@ -0,0 +1,22 @@
+package ranking
+
+import "math"
+
+// z-score for a 95% confidence interval.
+const wilsonZ = 1.96
+
+// WilsonLowerBound returns the lower bound of the Wilson score confidence interval for the proportion of positive votes.
+//
+// Unlike a plain positive/total ratio, this penalises items with very few votes, so a review with 40 out of 45 helpful votes
+// ranks above one with a single helpful vote.
+func WilsonLowerBound(positive, total int) float64 {
+	if total <= 0 {
+		return 0
+	}
+
+	n := float64(total)
+	p := float64(positive) / n
+	z2 := wilsonZ * wilsonZ
+
+	return (p + z2/(2*n) - wilsonZ*math.Sqrt((p*(1-p)+z2/(4*n))/n)) / (1 + z2/n)
+}
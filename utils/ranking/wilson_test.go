@@ -0,0 +1,37 @@
+package ranking
+
+import (
+	"math"
+	"testing"
+)
+
+func TestWilsonLowerBound(t *testing.T) {
+	testCases := []struct {
+		name     string
+		positive int
+		total    int
+		expected float64
+	}{
+		{"No votes", 0, 0, 0},
+		{"Single helpful vote", 1, 1, 0.2065},
+		{"All unhelpful", 0, 10, 0},
+		{"Mostly helpful", 40, 45, 0.7648},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := WilsonLowerBound(tc.positive, tc.total)
+			if math.Abs(actual-tc.expected) > 1e-3 {
+				t.Errorf("Expected score: %f, got: %f", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestWilsonLowerBound_MoreVotesRankHigher(t *testing.T) {
+	few := WilsonLowerBound(1, 1)
+	many := WilsonLowerBound(40, 45)
+	if many <= few {
+		t.Errorf("Expected 40/45 (%f) to rank above 1/1 (%f)", many, few)
+	}
+}
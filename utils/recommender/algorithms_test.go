@@ -0,0 +1,193 @@
+package recommender_test
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/anirudhgray/mood-harbour-backend/mocks"
+	"github.com/anirudhgray/mood-harbour-backend/models"
+	"github.com/anirudhgray/mood-harbour-backend/utils/recommender"
+	"github.com/golang/mock/gomock"
+)
+
+// the package name differs from the package under test since mocks imports recommender
+
+type interactions struct {
+	liked, disliked []uint
+}
+
+// expectReviewData sets up the mock to serve the given likes (weight 1) and dislikes (weight -1) per user, and their inverse per resource.
+func expectReviewData(mockReviewData *mocks.MockReviewDataRepositoryInterface, users map[uint]interactions) {
+	byUser := make(map[uint]map[uint]float64)
+	byResource := make(map[uint]map[uint]float64)
+	set := func(userID, resourceID uint, weight float64) {
+		if byUser[userID] == nil {
+			byUser[userID] = make(map[uint]float64)
+		}
+		byUser[userID][resourceID] = weight
+		if byResource[resourceID] == nil {
+			byResource[resourceID] = make(map[uint]float64)
+		}
+		byResource[resourceID][userID] = weight
+	}
+	for userID, i := range users {
+		for _, resourceID := range i.liked {
+			set(userID, resourceID, 1)
+		}
+		for _, resourceID := range i.disliked {
+			set(userID, resourceID, -1)
+		}
+	}
+
+	mockReviewData.EXPECT().GetUserPreferences(gomock.Any()).DoAndReturn(func(userID uint) (map[uint]float64, error) {
+		return byUser[userID], nil
+	}).AnyTimes()
+	mockReviewData.EXPECT().GetResourcePreferences(gomock.Any()).DoAndReturn(func(resourceID uint) (map[uint]float64, error) {
+		return byResource[resourceID], nil
+	}).AnyTimes()
+}
+
+func TestUserBased_Recommend(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockReviewData := mocks.NewMockReviewDataRepositoryInterface(ctrl)
+	expectReviewData(mockReviewData, map[uint]interactions{
+		1: {liked: []uint{1, 2}},
+		2: {liked: []uint{1, 2, 3}},                   // similarity 2/3
+		3: {liked: []uint{1, 4}, disliked: []uint{3}}, // similarity 1/4
+		4: {liked: []uint{5}},                         // no overlap with user 1
+	})
+
+	ub := recommender.NewUserBased(mockReviewData)
+
+	similarities, err := ub.SimilarUsers(1)
+	if err != nil {
+		t.Fatalf("SimilarUsers returned an error: %v", err)
+	}
+	if len(similarities) != 2 || similarities[2] != 0.667 || similarities[3] != 0.25 {
+		t.Errorf("Unexpected similarities: %v", similarities)
+	}
+
+	scored, err := ub.Recommend(1, recommender.Options{})
+	if err != nil {
+		t.Fatalf("Recommend returned an error: %v", err)
+	}
+
+	// resource 3: (0.667 - 0.25) / 2, resource 4: 0.25 / 1, resource 5 is unknown to similar users
+	expected := []recommender.ScoredItem{{ResourceID: 4, Score: 0.25}, {ResourceID: 3, Score: 0.2085}}
+	if !sameItems(scored, expected) {
+		t.Errorf("Expected %v, got: %v", expected, scored)
+	}
+
+	// resource 3 is liked by user 2 and disliked by user 3
+	for _, item := range scored {
+		if item.ResourceID == 3 {
+			expectedEvidence := models.RecommendationEvidence{
+				Algorithm:            recommender.UserBasedAlgorithm,
+				SimilarUsersLiked:    1,
+				SimilarUsersDisliked: 1,
+				TopSimilarities:      []float64{0.667},
+			}
+			if !reflect.DeepEqual(item.Evidence, expectedEvidence) {
+				t.Errorf("Expected evidence %+v, got: %+v", expectedEvidence, item.Evidence)
+			}
+		}
+	}
+
+	limited, _ := ub.Recommend(1, recommender.Options{Limit: 1})
+	if len(limited) != 1 || limited[0].ResourceID != 4 {
+		t.Errorf("Expected only resource 4 with a limit of 1, got: %v", limited)
+	}
+}
+
+func TestUserBased_SimilarUsers_ReusesRecommend(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// the similar users of user 1 are worked out once, by Recommend
+	mockReviewData := mocks.NewMockReviewDataRepositoryInterface(ctrl)
+	mockReviewData.EXPECT().GetUserPreferences(uint(1)).Return(map[uint]float64{1: 1}, nil)
+	mockReviewData.EXPECT().GetResourcePreferences(uint(1)).Return(map[uint]float64{1: 1, 2: 1}, nil)
+	mockReviewData.EXPECT().GetUserPreferences(uint(2)).Return(map[uint]float64{1: 1, 2: 1}, nil)
+
+	ub := recommender.NewUserBased(mockReviewData)
+	if _, err := ub.Recommend(1, recommender.Options{}); err != nil {
+		t.Fatalf("Recommend returned an error: %v", err)
+	}
+
+	similarities, err := ub.SimilarUsers(1)
+	if err != nil {
+		t.Fatalf("SimilarUsers returned an error: %v", err)
+	}
+	if len(similarities) != 1 || similarities[2] != 0.5 {
+		t.Errorf("Unexpected similarities: %v", similarities)
+	}
+}
+
+func TestItemBased_Recommend(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockReviewData := mocks.NewMockReviewDataRepositoryInterface(ctrl)
+	expectReviewData(mockReviewData, map[uint]interactions{
+		1: {liked: []uint{1}},
+		2: {liked: []uint{1, 2}, disliked: []uint{3}},
+		3: {liked: []uint{1, 2}, disliked: []uint{3}},
+		4: {liked: []uint{3}},
+	})
+
+	ib := recommender.NewItemBased(mockReviewData)
+
+	scored, err := ib.Recommend(1, recommender.Options{})
+	if err != nil {
+		t.Fatalf("Recommend returned an error: %v", err)
+	}
+
+	// resource 1 is liked by users 1, 2 and 3. Resource 2 is liked by users 2 and 3: cos = 2 / (sqrt(3) * sqrt(2)) > 0.
+	// Resource 3 is disliked by users 2 and 3 and liked by user 4: cos = -2 / (sqrt(3) * sqrt(3)) < 0.
+	// With a single rated resource each score is the sign of its similarity.
+	expected := []recommender.ScoredItem{{ResourceID: 2, Score: 1}, {ResourceID: 3, Score: -1}}
+	if !sameItems(scored, expected) {
+		t.Errorf("Expected %v, got: %v", expected, scored)
+	}
+}
+
+func TestNew(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockReviewData := mocks.NewMockReviewDataRepositoryInterface(ctrl)
+
+	testCases := []struct {
+		algorithm   string
+		expectError bool
+	}{
+		{"", false},
+		{recommender.UserBasedAlgorithm, false},
+		{recommender.ItemBasedAlgorithm, false},
+		{"random", true},
+	}
+
+	for _, tc := range testCases {
+		r, err := recommender.New(tc.algorithm, recommender.Sources{Reviews: mockReviewData})
+		if tc.expectError && err == nil {
+			t.Errorf("Expected an error for algorithm %q, got nil", tc.algorithm)
+		} else if !tc.expectError && (err != nil || r == nil) {
+			t.Errorf("Unexpected error for algorithm %q: %v", tc.algorithm, err)
+		}
+	}
+}
+
+func sameItems(a, b []recommender.ScoredItem) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].ResourceID != b[i].ResourceID || math.Abs(a[i].Score-b[i].Score) > 1e-9 {
+			return false
+		}
+	}
+	return true
+}
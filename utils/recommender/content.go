@@ -0,0 +1,214 @@
+package recommender
+
+import (
+	"math"
+	"strings"
+	"unicode"
+
+	"github.com/anirudhgray/mood-harbour-backend/models"
+	"github.com/anirudhgray/mood-harbour-backend/repository"
+)
+
+// titleWeight is how many times the words of a resource's title count, relative to the words of its content.
+const titleWeight = 2
+
+// stopWords are left out of the TF-IDF vectors.
+var stopWords = map[string]bool{
+	"a": true, "about": true, "after": true, "all": true, "also": true, "an": true, "and": true, "any": true, "are": true,
+	"as": true, "at": true, "be": true, "been": true, "but": true, "by": true, "can": true, "could": true, "do": true,
+	"does": true, "for": true, "from": true, "had": true, "has": true, "have": true, "he": true, "her": true, "his": true,
+	"how": true, "if": true, "in": true, "into": true, "is": true, "it": true, "its": true, "just": true, "more": true,
+	"most": true, "my": true, "no": true, "not": true, "of": true, "on": true, "or": true, "our": true, "out": true,
+	"she": true, "so": true, "some": true, "than": true, "that": true, "the": true, "their": true, "them": true,
+	"then": true, "there": true, "these": true, "they": true, "this": true, "to": true, "up": true, "was": true,
+	"we": true, "were": true, "what": true, "when": true, "which": true, "who": true, "will": true, "with": true,
+	"would": true, "you": true, "your": true, "http": true, "https": true, "www": true, "com": true,
+}
+
+// ContentIndex holds an L2-normalized TF-IDF vector for the title and content of each resource.
+//
+//	tf(t, d) = 1 + log(count of t in d)
+//	idf(t) = 1 + log((1 + N) / (1 + number of resources containing t))
+type ContentIndex struct {
+	vectors map[uint]map[int]float64 // resource -> term -> weight
+	terms   []string                 // term -> word
+}
+
+// NewContentIndex builds the index from the given resources, which should be every published resource.
+func NewContentIndex(resources []models.Resource) *ContentIndex {
+	terms := make(map[string]int)
+	counts := make(map[uint]map[int]int)
+	documentFrequency := make(map[int]int)
+
+	for _, resource := range resources {
+		resourceCounts := make(map[int]int)
+		for i := 0; i < titleWeight; i++ {
+			addTerms(terms, resourceCounts, resource.Title)
+		}
+		addTerms(terms, resourceCounts, resource.Content)
+
+		counts[resource.ID] = resourceCounts
+		for term := range resourceCounts {
+			documentFrequency[term]++
+		}
+	}
+
+	n := float64(len(resources))
+	index := &ContentIndex{vectors: make(map[uint]map[int]float64, len(counts)), terms: make([]string, len(terms))}
+	for word, term := range terms {
+		index.terms[term] = word
+	}
+	for resourceID, resourceCounts := range counts {
+		vector := make(map[int]float64, len(resourceCounts))
+		for term, count := range resourceCounts {
+			idf := 1 + math.Log((1+n)/(1+float64(documentFrequency[term])))
+			vector[term] = (1 + math.Log(float64(count))) * idf
+		}
+		index.vectors[resourceID] = normalize(vector)
+	}
+
+	return index
+}
+
+// Similarity is the cosine similarity of two resources' text, 0 if either isn't indexed.
+func (ci *ContentIndex) Similarity(a, b uint) float64 {
+	return dot(ci.vectors[a], ci.vectors[b])
+}
+
+func addTerms(terms map[string]int, counts map[int]int, text string) {
+	for _, token := range tokenize(text) {
+		term, ok := terms[token]
+		if !ok {
+			term = len(terms)
+			terms[token] = term
+		}
+		counts[term]++
+	}
+}
+
+// tokenize splits text into lower case words, dropping stop words, single characters and numbers.
+// Markdown syntax is just punctuation, so it never ends up in a word.
+func tokenize(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	tokens := make([]string, 0, len(words))
+	for _, word := range words {
+		if len([]rune(word)) < 2 || stopWords[word] || strings.IndexFunc(word, unicode.IsLetter) == -1 {
+			continue
+		}
+		tokens = append(tokens, word)
+	}
+	return tokens
+}
+
+func normalize(vector map[int]float64) map[int]float64 {
+	norm := 0.0
+	for _, weight := range vector {
+		norm += weight * weight
+	}
+	if norm == 0 {
+		return vector
+	}
+
+	norm = math.Sqrt(norm)
+	for term := range vector {
+		vector[term] /= norm
+	}
+	return vector
+}
+
+// ContentBased recommends resources whose text is similar to what the user liked.
+//
+// The user's profile is the sum of the TF-IDF vectors of the resources they've interacted with, weighted by their preference weights,
+// so e.g. with the default preference model 5 stars gives 1, 3 stars 0, 1 star -1, a bookmark 1 and a dismissed recommendation -0.5.
+// Every other indexed resource is scored by the cosine similarity of its vector and the profile, and those scoring above 0 are returned.
+// Unlike collaborative filtering this doesn't need anyone else to have reviewed a resource, so brand new resources can be recommended.
+type ContentBased struct {
+	data  repository.ReviewDataRepositoryInterface
+	index *ContentIndex
+}
+
+func NewContentBased(data repository.ReviewDataRepositoryInterface, index *ContentIndex) *ContentBased {
+	return &ContentBased{data, index}
+}
+
+// Recommend ranks resources by their cosine similarity with the user's profile.
+func (cb *ContentBased) Recommend(userID uint, opts Options) ([]ScoredItem, error) {
+	seen, err := cb.data.GetUserPreferences(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	profile := make(map[int]float64)
+	var liked []uint
+	for resourceID, weight := range seen {
+		for term, value := range cb.index.vectors[resourceID] {
+			profile[term] += weight * value
+		}
+		if weight > 0 {
+			liked = append(liked, resourceID)
+		}
+	}
+
+	profile = normalize(profile)
+
+	scores := make(map[uint]float64)
+	for resourceID, vector := range cb.index.vectors {
+		if _, ok := seen[resourceID]; ok {
+			continue
+		}
+		if score := dot(profile, vector); score > 0 {
+			scores[resourceID] = score
+		}
+	}
+
+	ranked := rankScores(scores, opts.Limit)
+	for i := range ranked {
+		ranked[i].Evidence = cb.evidence(ranked[i].ResourceID, profile, liked)
+	}
+	return ranked, nil
+}
+
+// evidence finds the words contributing the most to the resource's score, and the liked resources it's most similar to.
+func (cb *ContentBased) evidence(resourceID uint, profile map[int]float64, liked []uint) models.RecommendationEvidence {
+	vector := cb.index.vectors[resourceID]
+
+	contributions := make(map[uint]float64)
+	for term, value := range vector {
+		if contribution := value * profile[term]; contribution > 0 {
+			contributions[uint(term)] = contribution
+		}
+	}
+	var matchingTerms []string
+	for _, term := range topKeys(contributions, maxEvidence) {
+		matchingTerms = append(matchingTerms, cb.index.terms[term])
+	}
+
+	similarities := make(map[uint]float64)
+	for _, likedID := range liked {
+		if similarity := dot(vector, cb.index.vectors[likedID]); similarity > 0 {
+			similarities[likedID] = similarity
+		}
+	}
+
+	return models.RecommendationEvidence{
+		Algorithm:        ContentBasedAlgorithm,
+		MatchingTerms:    matchingTerms,
+		SimilarResources: topKeys(similarities, maxEvidence),
+	}
+}
+
+// dot product of two sparse vectors, which is their cosine similarity if both are normalized.
+func dot(a, b map[int]float64) float64 {
+	if len(b) < len(a) {
+		a, b = b, a
+	}
+
+	sum := 0.0
+	for term, value := range a {
+		sum += value * b[term]
+	}
+	return sum
+}
@@ -0,0 +1,82 @@
+package recommender
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/anirudhgray/mood-harbour-backend/models"
+	"github.com/anirudhgray/mood-harbour-backend/utils/preference"
+	"gorm.io/gorm"
+)
+
+func TestTokenize(t *testing.T) {
+	tokens := tokenize("**Box breathing** for the _4-7-8_ method: see https://example.com/Breathing!")
+
+	expected := []string{"box", "breathing", "method", "see", "example", "breathing"}
+	if !reflect.DeepEqual(tokens, expected) {
+		t.Errorf("Expected %v, got: %v", expected, tokens)
+	}
+}
+
+func TestContentBased_Recommend(t *testing.T) {
+	resources := []models.Resource{
+		{Model: gorm.Model{ID: 1}, Title: "Box breathing", Content: "A breathing exercise for panic attacks"},
+		{Model: gorm.Model{ID: 2}, Title: "Breathing through panic", Content: "Slow breathing when panic sets in"},
+		{Model: gorm.Model{ID: 3}, Title: "Sleep hygiene", Content: "Keep a regular sleep schedule and avoid screens"},
+		{Model: gorm.Model{ID: 4}, Title: "Better sleep", Content: "Screens before bed make sleep worse"},
+		{Model: gorm.Model{ID: 5}, Title: "Gratitude journal", Content: "Write three good things every evening"},
+		{Model: gorm.Model{ID: 6}, Title: "Panic attack first aid", Content: "Grounding and breathing steps"}, // nobody has reviewed it yet
+	}
+	reviews := []models.Review{
+		{UserID: 1, ResourceID: 1, Rating: 5, Status: models.Published},
+		{UserID: 1, ResourceID: 3, Rating: 1, Status: models.Published},
+		{UserID: 2, ResourceID: 5, Rating: 5, Status: models.Published},
+	}
+	bookmarks := []models.Bookmark{{UserID: 3, ResourceID: 3}}
+
+	snapshot := NewSnapshot(reviews, bookmarks, nil, []uint{1, 2, 3, 4, 5, 6}, preference.DefaultModel, time.Now())
+	cb := NewContentBased(snapshot, NewContentIndex(resources))
+
+	scored, err := cb.Recommend(1, Options{})
+	if err != nil {
+		t.Fatalf("Recommend returned an error: %v", err)
+	}
+	// the breathing and panic resources match, the sleep one is pushed away by the 1 star review and the journal shares no words
+	if len(scored) != 2 || scored[0].ResourceID != 2 || scored[1].ResourceID != 6 {
+		t.Errorf("Expected resources 2 and 6, got: %v", scored)
+	}
+
+	// a bookmark counts as a like
+	scored, _ = cb.Recommend(3, Options{})
+	if len(scored) == 0 || scored[0].ResourceID != 4 {
+		t.Errorf("Expected resource 4 first for a user who bookmarked a sleep resource, got: %v", scored)
+	}
+
+	// without any reviews there is no profile to compare with
+	scored, _ = cb.Recommend(4, Options{})
+	if len(scored) != 0 {
+		t.Errorf("Expected no recommendations for a user without reviews, got: %v", scored)
+	}
+}
+
+type stubRecommender []ScoredItem
+
+func (sr stubRecommender) Recommend(userID uint, opts Options) ([]ScoredItem, error) {
+	return rankScores(scoreMap(sr), opts.Limit), nil
+}
+
+func TestHybrid_Recommend(t *testing.T) {
+	primary := stubRecommender{{ResourceID: 1, Score: 0.5}, {ResourceID: 2, Score: -0.2}}
+	fallback := stubRecommender{{ResourceID: 2, Score: 0.9}, {ResourceID: 3, Score: 0.8}, {ResourceID: 4, Score: 0.1}}
+
+	scored, err := NewHybrid(primary, fallback).Recommend(1, Options{Limit: 3})
+	if err != nil {
+		t.Fatalf("Recommend returned an error: %v", err)
+	}
+
+	expected := []ScoredItem{{ResourceID: 1, Score: 0.5}, {ResourceID: 2, Score: -0.2}, {ResourceID: 3, Score: 0.8}}
+	if !reflect.DeepEqual(scored, expected) {
+		t.Errorf("Expected %v, got: %v", expected, scored)
+	}
+}
@@ -0,0 +1,46 @@
+package recommender
+
+// Diversify re-ranks items with maximal marginal relevance, so the top of the list isn't a run of near-identical resources.
+// Items are picked one at a time, each time taking the one maximising
+//
+//	lambda * rel(B) - (1 - lambda) * max sim(B, S)
+//
+// over the items S picked so far. rel is 1 for the first item of the original ranking down towards 0 for the last, since
+// scores from different recommenders can't be compared. A lambda of 1 keeps the original order.
+func Diversify(items []ScoredItem, similarity func(a, b uint) float64, lambda float64) []ScoredItem {
+	if lambda >= 1 || len(items) < 3 {
+		return items
+	}
+
+	n := len(items)
+	picked := make([]bool, n)
+	maxSimilarity := make([]float64, n)
+	diversified := make([]ScoredItem, 0, n)
+
+	for len(diversified) < n {
+		best, bestValue := -1, 0.0
+		for i := range items {
+			if picked[i] {
+				continue
+			}
+			relevance := 1 - float64(i)/float64(n)
+			value := lambda*relevance - (1-lambda)*maxSimilarity[i]
+			if best == -1 || value > bestValue {
+				best, bestValue = i, value
+			}
+		}
+
+		picked[best] = true
+		diversified = append(diversified, items[best])
+
+		for i := range items {
+			if !picked[i] {
+				if s := similarity(items[i].ResourceID, items[best].ResourceID); s > maxSimilarity[i] {
+					maxSimilarity[i] = s
+				}
+			}
+		}
+	}
+
+	return diversified
+}
@@ -0,0 +1,200 @@
+// Package evaluation measures how well the recommenders in utils/recommender predict what users go on to like,
+// on review data loaded from the database, a JSON fixture, or generated synthetically.
+package evaluation
+
+import (
+	"encoding/json"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/anirudhgray/mood-harbour-backend/models"
+	"github.com/anirudhgray/mood-harbour-backend/utils/preference"
+	"github.com/anirudhgray/mood-harbour-backend/utils/recommender"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// Dataset is the review data the recommenders learn from. It's also the format of JSON fixtures.
+type Dataset struct {
+	Resources []ResourceRecord `json:"resources"`
+	Reviews   []ReviewRecord   `json:"reviews"`
+	Bookmarks []BookmarkRecord `json:"bookmarks"`
+}
+
+type ResourceRecord struct {
+	ID      uint   `json:"id"`
+	Title   string `json:"title"`
+	Content string `json:"content"`
+}
+
+type ReviewRecord struct {
+	UserID     uint          `json:"user_id"`
+	ResourceID uint          `json:"resource_id"`
+	Rating     models.Rating `json:"rating"`
+	CreatedAt  time.Time     `json:"created_at"`
+}
+
+type BookmarkRecord struct {
+	UserID     uint `json:"user_id"`
+	ResourceID uint `json:"resource_id"`
+}
+
+// LoadJSON reads a dataset from a JSON fixture.
+func LoadJSON(path string) (Dataset, error) {
+	var dataset Dataset
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return dataset, err
+	}
+
+	err = json.Unmarshal(data, &dataset)
+	return dataset, err
+}
+
+// SaveJSON writes the dataset as a JSON fixture.
+func SaveJSON(dataset Dataset, path string) error {
+	data, err := json.MarshalIndent(dataset, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// OpenMemoryDB opens an in-memory SQLite database with the tables SaveDB and LoadDB use, so synthetic datasets go through
+// the same queries as production data.
+func OpenMemoryDB() (*gorm.DB, error) {
+	db, err := gorm.Open(sqlite.Open("file:evaluation?mode=memory&cache=shared"), &gorm.Config{})
+	if err != nil {
+		return nil, err
+	}
+	if err := db.AutoMigrate(&models.Resource{}, &models.Review{}, &models.Bookmark{}); err != nil {
+		return nil, err
+	}
+	return db, nil
+}
+
+// LoadDB reads the published resources, their published reviews and bookmarks from the database.
+func LoadDB(db *gorm.DB) (Dataset, error) {
+	var dataset Dataset
+
+	var resources []models.Resource
+	if err := db.Select("id", "title", "content").Where("status = ?", models.Published).Order("id asc").Find(&resources).Error; err != nil {
+		return dataset, err
+	}
+	published := make(map[uint]bool, len(resources))
+	for _, resource := range resources {
+		published[resource.ID] = true
+		dataset.Resources = append(dataset.Resources, ResourceRecord{ID: resource.ID, Title: resource.Title, Content: resource.Content})
+	}
+
+	var reviews []models.Review
+	if err := db.Where("status = ?", models.Published).Order("id asc").Find(&reviews).Error; err != nil {
+		return dataset, err
+	}
+	for _, review := range reviews {
+		if published[review.ResourceID] {
+			dataset.Reviews = append(dataset.Reviews, ReviewRecord{UserID: review.UserID, ResourceID: review.ResourceID, Rating: review.Rating, CreatedAt: review.CreatedAt})
+		}
+	}
+
+	var bookmarks []models.Bookmark
+	if err := db.Order("id asc").Find(&bookmarks).Error; err != nil {
+		return dataset, err
+	}
+	for _, bookmark := range bookmarks {
+		if published[bookmark.ResourceID] {
+			dataset.Bookmarks = append(dataset.Bookmarks, BookmarkRecord{UserID: bookmark.UserID, ResourceID: bookmark.ResourceID})
+		}
+	}
+
+	return dataset, nil
+}
+
+// SaveDB inserts the dataset into the database as published resources, reviews and bookmarks, keeping resource IDs.
+func SaveDB(db *gorm.DB, dataset Dataset) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		resources := make([]models.Resource, 0, len(dataset.Resources))
+		for _, resource := range dataset.Resources {
+			resources = append(resources, models.Resource{
+				Model:   gorm.Model{ID: resource.ID},
+				Title:   resource.Title,
+				Content: resource.Content,
+				Status:  models.Published,
+			})
+		}
+		if len(resources) > 0 {
+			if err := tx.CreateInBatches(resources, 500).Error; err != nil {
+				return err
+			}
+		}
+
+		reviews := make([]models.Review, 0, len(dataset.Reviews))
+		for _, review := range dataset.Reviews {
+			reviews = append(reviews, models.Review{
+				Model:      gorm.Model{CreatedAt: review.CreatedAt},
+				UserID:     review.UserID,
+				ResourceID: review.ResourceID,
+				Rating:     review.Rating,
+				Status:     models.Published,
+			})
+		}
+		if len(reviews) > 0 {
+			if err := tx.CreateInBatches(reviews, 500).Error; err != nil {
+				return err
+			}
+		}
+
+		bookmarks := make([]models.Bookmark, 0, len(dataset.Bookmarks))
+		for _, bookmark := range dataset.Bookmarks {
+			bookmarks = append(bookmarks, models.Bookmark{UserID: bookmark.UserID, ResourceID: bookmark.ResourceID})
+		}
+		if len(bookmarks) > 0 {
+			return tx.CreateInBatches(bookmarks, 500).Error
+		}
+		return nil
+	})
+}
+
+// sources builds what the recommenders need from the dataset.
+func (d Dataset) sources() recommender.Sources {
+	resources := make([]models.Resource, 0, len(d.Resources))
+	resourceIDs := make([]uint, 0, len(d.Resources))
+	for _, resource := range d.Resources {
+		resources = append(resources, models.Resource{Model: gorm.Model{ID: resource.ID}, Title: resource.Title, Content: resource.Content})
+		resourceIDs = append(resourceIDs, resource.ID)
+	}
+
+	// the snapshot counts the oldest review when a user reviewed a resource more than once
+	ordered := append([]ReviewRecord{}, d.Reviews...)
+	sort.SliceStable(ordered, func(i, j int) bool { return ordered[i].CreatedAt.Before(ordered[j].CreatedAt) })
+
+	reviews := make([]models.Review, 0, len(ordered))
+	for _, review := range ordered {
+		reviews = append(reviews, models.Review{
+			Model:      gorm.Model{CreatedAt: review.CreatedAt},
+			UserID:     review.UserID,
+			ResourceID: review.ResourceID,
+			Rating:     review.Rating,
+			Status:     models.Published,
+		})
+	}
+
+	// popularity is measured as of the newest review
+	var now time.Time
+	if len(ordered) > 0 {
+		now = ordered[len(ordered)-1].CreatedAt
+	}
+
+	bookmarks := make([]models.Bookmark, 0, len(d.Bookmarks))
+	for _, bookmark := range d.Bookmarks {
+		bookmarks = append(bookmarks, models.Bookmark{UserID: bookmark.UserID, ResourceID: bookmark.ResourceID})
+	}
+
+	return recommender.Sources{
+		Reviews:    recommender.NewSnapshot(reviews, bookmarks, nil, resourceIDs, preference.DefaultModel, now),
+		Content:    recommender.NewContentIndex(resources),
+		Popularity: recommender.NewPopularityIndex(reviews, resourceIDs, now),
+	}
+}
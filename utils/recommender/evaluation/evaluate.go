@@ -0,0 +1,81 @@
+package evaluation
+
+import (
+	"sort"
+
+	"github.com/anirudhgray/mood-harbour-backend/models"
+	"github.com/anirudhgray/mood-harbour-backend/utils/recommender"
+)
+
+// Report holds the metrics of one recommender algorithm, averaged over the test users.
+type Report struct {
+	Algorithm string
+	K         int
+	Users     int     // Test users with at least one liked held out resource
+	Precision float64 // Mean precision@k
+	Recall    float64 // Mean recall@k
+	NDCG      float64 // Mean NDCG@k
+	Coverage  float64 // Fraction of the catalog recommended to anyone
+	Diversity float64 // Mean intra-list diversity, by content similarity
+}
+
+// Evaluate trains the named algorithm (see recommender.New) on the split's training data and measures its top k
+// recommendations against what each test user went on to like. Matrix factorization is trained with the given hyperparameters.
+func Evaluate(algorithm string, split Split, k int, hyperparameters models.FactorizationHyperparameters) (Report, error) {
+	report := Report{Algorithm: algorithm, K: k}
+
+	sources := split.Train.sources()
+	if recommender.Uses(algorithm, recommender.FactorizationAlgorithm) {
+		sources.Factors = recommender.TrainFactorization(sources.Reviews.(*recommender.Snapshot), hyperparameters)
+	}
+
+	r, err := recommender.New(algorithm, sources)
+	if err != nil {
+		return report, err
+	}
+
+	// evaluate users in a fixed order so runs are reproducible
+	userIDs := make([]uint, 0, len(split.Test))
+	for userID := range split.Test {
+		userIDs = append(userIDs, userID)
+	}
+	sort.Slice(userIDs, func(i, j int) bool { return userIDs[i] < userIDs[j] })
+
+	var lists [][]uint
+	for _, userID := range userIDs {
+		relevant := make(map[uint]bool)
+		for _, resourceID := range split.Test[userID] {
+			relevant[resourceID] = true
+		}
+		if len(relevant) == 0 {
+			continue
+		}
+
+		scored, err := r.Recommend(userID, recommender.Options{Limit: k})
+		if err != nil {
+			return report, err
+		}
+		recommended := make([]uint, 0, len(scored))
+		for _, item := range scored {
+			recommended = append(recommended, item.ResourceID)
+		}
+		lists = append(lists, recommended)
+
+		report.Users++
+		report.Precision += PrecisionAtK(recommended, relevant, k)
+		report.Recall += RecallAtK(recommended, relevant, k)
+		report.NDCG += NDCGAtK(recommended, relevant, k)
+		report.Diversity += IntraListDiversity(recommended, sources.Content.Similarity)
+	}
+
+	if report.Users > 0 {
+		n := float64(report.Users)
+		report.Precision /= n
+		report.Recall /= n
+		report.NDCG /= n
+		report.Diversity /= n
+	}
+	report.Coverage = Coverage(lists, len(split.Train.Resources))
+
+	return report, nil
+}
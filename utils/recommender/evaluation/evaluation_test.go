@@ -0,0 +1,161 @@
+package evaluation
+
+import (
+	"math"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/anirudhgray/mood-harbour-backend/models"
+	"github.com/anirudhgray/mood-harbour-backend/utils/recommender"
+)
+
+func TestMetrics(t *testing.T) {
+	recommended := []uint{1, 2, 3, 4}
+	relevant := map[uint]bool{2: true, 4: true, 9: true}
+
+	testCases := []struct {
+		name     string
+		got      float64
+		expected float64
+	}{
+		{"Precision@4", PrecisionAtK(recommended, relevant, 4), 0.5},
+		{"Precision@2", PrecisionAtK(recommended, relevant, 2), 0.5},
+		{"Recall@4", RecallAtK(recommended, relevant, 4), 2.0 / 3},
+		// hits at positions 2 and 4, ideally at positions 1, 2 and 3
+		{"NDCG@4", NDCGAtK(recommended, relevant, 4), (1/math.Log2(3) + 1/math.Log2(5)) / (1 + 1/math.Log2(3) + 1/math.Log2(4))},
+		{"NDCG of a perfect ranking", NDCGAtK([]uint{2, 4, 9}, relevant, 3), 1},
+		{"Coverage", Coverage([][]uint{{1, 2}, {2, 3}}, 6), 0.5},
+		{"Diversity", IntraListDiversity([]uint{1, 2, 3}, func(a, b uint) float64 {
+			if a+b == 3 {
+				return 1 // 1 and 2 are identical
+			}
+			return 0
+		}), 2.0 / 3},
+		{"Diversity of a single item", IntraListDiversity([]uint{1}, nil), 0},
+	}
+
+	for _, tc := range testCases {
+		if math.Abs(tc.got-tc.expected) > 1e-9 {
+			t.Errorf("%s: expected %f, got: %f", tc.name, tc.expected, tc.got)
+		}
+	}
+}
+
+func TestSplits(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2023, time.January, d, 0, 0, 0, 0, time.UTC) }
+	dataset := Dataset{
+		Reviews: []ReviewRecord{
+			{UserID: 1, ResourceID: 1, Rating: 5, CreatedAt: day(1)},
+			{UserID: 1, ResourceID: 2, Rating: 4, CreatedAt: day(5)},
+			{UserID: 1, ResourceID: 3, Rating: 2, CreatedAt: day(6)},
+			{UserID: 2, ResourceID: 1, Rating: 5, CreatedAt: day(2)},
+			{UserID: 3, ResourceID: 2, Rating: 1, CreatedAt: day(3)},
+			{UserID: 3, ResourceID: 3, Rating: 5, CreatedAt: day(4)},
+		},
+		Bookmarks: []BookmarkRecord{{UserID: 1, ResourceID: 2}, {UserID: 2, ResourceID: 3}},
+	}
+
+	// user 1's newest liked review is held out, user 2 has a single review and user 3 a single liked one
+	loo := SplitLeaveOneOut(dataset)
+	expectedTest := map[uint][]uint{1: {2}, 3: {3}}
+	if !reflect.DeepEqual(loo.Test, expectedTest) {
+		t.Errorf("Leave one out: expected test set %v, got: %v", expectedTest, loo.Test)
+	}
+	if len(loo.Train.Reviews) != 4 {
+		t.Errorf("Leave one out: expected 4 training reviews, got: %v", loo.Train.Reviews)
+	}
+	// user 1's bookmark of the held out resource would give it away
+	if len(loo.Train.Bookmarks) != 1 || loo.Train.Bookmarks[0].UserID != 2 {
+		t.Errorf("Leave one out: expected only user 2's bookmark to be kept, got: %v", loo.Train.Bookmarks)
+	}
+
+	// the two newest reviews are held out, and only the liked one counts
+	byTime := SplitByTime(dataset, 0.34)
+	expectedTest = map[uint][]uint{1: {2}}
+	if !reflect.DeepEqual(byTime.Test, expectedTest) {
+		t.Errorf("Time split: expected test set %v, got: %v", expectedTest, byTime.Test)
+	}
+	if len(byTime.Train.Reviews) != 4 {
+		t.Errorf("Time split: expected 4 training reviews, got: %v", byTime.Train.Reviews)
+	}
+
+	if _, err := NewSplit("random", dataset, 0.2); err == nil {
+		t.Errorf("Expected an error for an unknown split, got nil")
+	}
+}
+
+func TestEvaluate_SyntheticDataset(t *testing.T) {
+	config := DefaultGeneratorConfig
+	config.Users = 60
+	config.Resources = 80
+
+	if !reflect.DeepEqual(Generate(config), Generate(config)) {
+		t.Fatalf("Generate is not reproducible")
+	}
+
+	db, err := OpenMemoryDB()
+	if err != nil {
+		t.Fatalf("Failed to set up test database: %v", err)
+	}
+	defer db.Migrator().DropTable(&models.Resource{}, &models.Review{}, &models.Bookmark{})
+
+	generated := Generate(config)
+	if err := SaveDB(db, generated); err != nil {
+		t.Fatalf("SaveDB returned an error: %v", err)
+	}
+	dataset, err := LoadDB(db)
+	if err != nil {
+		t.Fatalf("LoadDB returned an error: %v", err)
+	}
+	if len(dataset.Resources) != len(generated.Resources) || len(dataset.Reviews) != len(generated.Reviews) || len(dataset.Bookmarks) != len(generated.Bookmarks) {
+		t.Fatalf("LoadDB did not load back the generated dataset")
+	}
+
+	fixture := filepath.Join(t.TempDir(), "fixture.json")
+	if err := SaveJSON(dataset, fixture); err != nil {
+		t.Fatalf("SaveJSON returned an error: %v", err)
+	}
+	loaded, err := LoadJSON(fixture)
+	if err != nil {
+		t.Fatalf("LoadJSON returned an error: %v", err)
+	}
+	if len(loaded.Reviews) != len(dataset.Reviews) || !loaded.Reviews[0].CreatedAt.Equal(dataset.Reviews[0].CreatedAt) {
+		t.Errorf("LoadJSON did not load back the saved dataset")
+	}
+
+	split := SplitLeaveOneOut(dataset)
+	for _, algorithm := range []string{recommender.UserBasedAlgorithm, recommender.ItemBasedAlgorithm, recommender.ContentBasedAlgorithm} {
+		report, err := Evaluate(algorithm, split, 10, recommender.DefaultHyperparameters)
+		if err != nil {
+			t.Fatalf("Evaluate returned an error: %v", err)
+		}
+		if report.Users != len(split.Test) {
+			t.Errorf("%s: expected %d test users, got: %d", algorithm, len(split.Test), report.Users)
+		}
+		// users stick to their topics, so every algorithm should find some of the held out resources
+		if report.Recall == 0 || report.Coverage == 0 {
+			t.Errorf("%s: expected some held out resources to be recommended, got: %+v", algorithm, report)
+		}
+	}
+}
+
+// TestEvaluate_FactorizationAgainstJaccard compares matrix factorization with user-based collaborative filtering, which uses the
+// modified Jaccard coefficient. On the synthetic community the latent topics are exactly what factorization picks up on.
+func TestEvaluate_FactorizationAgainstJaccard(t *testing.T) {
+	split := SplitLeaveOneOut(Generate(DefaultGeneratorConfig))
+
+	jaccard, err := Evaluate(recommender.UserBasedAlgorithm, split, 10, recommender.DefaultHyperparameters)
+	if err != nil {
+		t.Fatalf("Evaluate returned an error: %v", err)
+	}
+	factorization, err := Evaluate(recommender.FactorizationAlgorithm, split, 10, recommender.DefaultHyperparameters)
+	if err != nil {
+		t.Fatalf("Evaluate returned an error: %v", err)
+	}
+
+	if factorization.NDCG <= jaccard.NDCG || factorization.Recall <= jaccard.Recall {
+		t.Errorf("Expected matrix factorization to beat Jaccard, got: %+v and %+v", factorization, jaccard)
+	}
+}
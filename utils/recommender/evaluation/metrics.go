@@ -0,0 +1,91 @@
+package evaluation
+
+import "math"
+
+// PrecisionAtK is the fraction of the top k recommendations which are relevant.
+func PrecisionAtK(recommended []uint, relevant map[uint]bool, k int) float64 {
+	if k <= 0 {
+		return 0
+	}
+	return float64(hits(recommended, relevant, k)) / float64(k)
+}
+
+// RecallAtK is the fraction of the relevant resources found in the top k recommendations.
+func RecallAtK(recommended []uint, relevant map[uint]bool, k int) float64 {
+	if len(relevant) == 0 {
+		return 0
+	}
+	return float64(hits(recommended, relevant, k)) / float64(len(relevant))
+}
+
+// NDCGAtK is the normalized discounted cumulative gain of the top k recommendations, with binary relevance:
+//
+//	DCG = sum over relevant positions i (starting at 1) of 1 / log2(i + 1)
+//
+// divided by the DCG of a perfect ranking.
+func NDCGAtK(recommended []uint, relevant map[uint]bool, k int) float64 {
+	dcg := 0.0
+	for i, resourceID := range top(recommended, k) {
+		if relevant[resourceID] {
+			dcg += 1 / math.Log2(float64(i+2))
+		}
+	}
+
+	ideal := 0.0
+	for i := 0; i < len(relevant) && i < k; i++ {
+		ideal += 1 / math.Log2(float64(i+2))
+	}
+	if ideal == 0 {
+		return 0
+	}
+	return dcg / ideal
+}
+
+// Coverage is the fraction of the catalog which was recommended to at least one user.
+func Coverage(recommendations [][]uint, catalogSize int) float64 {
+	if catalogSize == 0 {
+		return 0
+	}
+
+	recommended := make(map[uint]bool)
+	for _, list := range recommendations {
+		for _, resourceID := range list {
+			recommended[resourceID] = true
+		}
+	}
+	return float64(len(recommended)) / float64(catalogSize)
+}
+
+// IntraListDiversity is the average dissimilarity (1 - similarity) of every pair of recommendations in the list.
+// Lists of fewer than two recommendations have no diversity to speak of and give 0.
+func IntraListDiversity(recommended []uint, similarity func(a, b uint) float64) float64 {
+	pairs := 0
+	total := 0.0
+	for i := 0; i < len(recommended); i++ {
+		for j := i + 1; j < len(recommended); j++ {
+			total += 1 - similarity(recommended[i], recommended[j])
+			pairs++
+		}
+	}
+	if pairs == 0 {
+		return 0
+	}
+	return total / float64(pairs)
+}
+
+func hits(recommended []uint, relevant map[uint]bool, k int) int {
+	count := 0
+	for _, resourceID := range top(recommended, k) {
+		if relevant[resourceID] {
+			count++
+		}
+	}
+	return count
+}
+
+func top(recommended []uint, k int) []uint {
+	if len(recommended) > k {
+		return recommended[:k]
+	}
+	return recommended
+}
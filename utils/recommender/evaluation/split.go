@@ -0,0 +1,121 @@
+package evaluation
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Split is a dataset divided into what the recommenders learn from, and the resources each test user went on to like.
+type Split struct {
+	Train Dataset
+	Test  map[uint][]uint // user -> liked held out resources
+}
+
+// Split strategies accepted by NewSplit.
+const (
+	TimeSplit        = "time"
+	LeaveOneOutSplit = "loo"
+)
+
+// NewSplit splits the dataset with the named strategy. testFraction is only used by the time-based split.
+func NewSplit(strategy string, dataset Dataset, testFraction float64) (Split, error) {
+	switch strategy {
+	case TimeSplit:
+		return SplitByTime(dataset, testFraction), nil
+	case LeaveOneOutSplit:
+		return SplitLeaveOneOut(dataset), nil
+	default:
+		return Split{}, fmt.Errorf("unknown split %q", strategy)
+	}
+}
+
+// SplitByTime holds out the newest testFraction of all reviews. Test users are those with at least one liked (4 stars or more)
+// held out review. Bookmarks are part of the training data, except those of held out resources.
+func SplitByTime(dataset Dataset, testFraction float64) Split {
+	reviews := append([]ReviewRecord{}, dataset.Reviews...)
+	sort.SliceStable(reviews, func(i, j int) bool { return reviews[i].CreatedAt.Before(reviews[j].CreatedAt) })
+
+	cutoff := len(reviews) - int(float64(len(reviews))*testFraction)
+	if cutoff < 0 {
+		cutoff = 0
+	}
+
+	split := Split{
+		Train: Dataset{Resources: dataset.Resources, Reviews: reviews[:cutoff]},
+		Test:  make(map[uint][]uint),
+	}
+	for _, review := range reviews[cutoff:] {
+		if review.Rating >= 4 {
+			split.Test[review.UserID] = appendUnique(split.Test[review.UserID], review.ResourceID)
+		}
+	}
+	split.Train.Bookmarks = withoutHeldOut(dataset.Bookmarks, split.Test)
+	return split
+}
+
+// SplitLeaveOneOut holds out each user's newest liked (4 stars or more) review, for users with at least two reviews.
+func SplitLeaveOneOut(dataset Dataset) Split {
+	latestLiked := make(map[uint]int) // user -> index into dataset.Reviews
+	reviewCounts := make(map[uint]int)
+	for i, review := range dataset.Reviews {
+		reviewCounts[review.UserID]++
+		if review.Rating < 4 {
+			continue
+		}
+		if j, ok := latestLiked[review.UserID]; !ok || review.CreatedAt.After(dataset.Reviews[j].CreatedAt) {
+			latestLiked[review.UserID] = i
+		}
+	}
+
+	heldOut := make(map[int]bool)
+	split := Split{
+		Train: Dataset{Resources: dataset.Resources},
+		Test:  make(map[uint][]uint),
+	}
+	for userID, i := range latestLiked {
+		if reviewCounts[userID] >= 2 {
+			heldOut[i] = true
+			split.Test[userID] = []uint{dataset.Reviews[i].ResourceID}
+		}
+	}
+
+	for i, review := range dataset.Reviews {
+		if heldOut[i] {
+			continue
+		}
+		// other reviews of the held out resource by the same user would give it away
+		if resourceIDs, ok := split.Test[review.UserID]; ok && resourceIDs[0] == review.ResourceID {
+			continue
+		}
+		split.Train.Reviews = append(split.Train.Reviews, review)
+	}
+	split.Train.Bookmarks = withoutHeldOut(dataset.Bookmarks, split.Test)
+	return split
+}
+
+// withoutHeldOut drops the bookmarks of held out resources, which would give them away.
+func withoutHeldOut(bookmarks []BookmarkRecord, test map[uint][]uint) []BookmarkRecord {
+	var kept []BookmarkRecord
+	for _, bookmark := range bookmarks {
+		if !contains(test[bookmark.UserID], bookmark.ResourceID) {
+			kept = append(kept, bookmark)
+		}
+	}
+	return kept
+}
+
+func appendUnique(ids []uint, id uint) []uint {
+	if contains(ids, id) {
+		return ids
+	}
+	return append(ids, id)
+}
+
+func contains(ids []uint, id uint) bool {
+	for _, existing := range ids {
+		if existing == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,147 @@
+package evaluation
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/anirudhgray/mood-harbour-backend/models"
+)
+
+// GeneratorConfig configures Generate.
+type GeneratorConfig struct {
+	Users           int
+	Resources       int
+	ReviewsPerUser  int     // Average number of reviews per user
+	PreferredTopics int     // Number of topics each user is interested in
+	Noise           float64 // Chance of a review going against the user's interests
+	BookmarkRate    float64 // Chance of a user bookmarking a resource from their topics without reviewing it
+	Seed            int64
+}
+
+// DefaultGeneratorConfig is a small community with a clear structure for the recommenders to find.
+var DefaultGeneratorConfig = GeneratorConfig{
+	Users:           200,
+	Resources:       300,
+	ReviewsPerUser:  12,
+	PreferredTopics: 2,
+	Noise:           0.1,
+	BookmarkRate:    0.05,
+	Seed:            1,
+}
+
+// syntheticTopics are the words each topic's resources are written with.
+var syntheticTopics = [][]string{
+	{"breathing", "breath", "inhale", "exhale", "calm", "lungs", "slow"},
+	{"sleep", "insomnia", "bedtime", "rest", "dreams", "night", "routine"},
+	{"journaling", "journal", "writing", "thoughts", "reflection", "notebook", "prompts"},
+	{"exercise", "running", "walking", "workout", "movement", "stretching", "yoga"},
+	{"mindfulness", "meditation", "present", "awareness", "attention", "body", "scan"},
+	{"friends", "social", "loneliness", "connection", "family", "talking", "support"},
+	{"nutrition", "food", "eating", "diet", "water", "meals", "caffeine"},
+	{"gratitude", "thankful", "appreciation", "positive", "kindness", "joy", "small"},
+}
+
+var syntheticFillerWords = []string{"guide", "tips", "daily", "help", "practice", "simple", "week", "feel", "better", "start"}
+
+// Generate creates a synthetic dataset. Every resource belongs to a topic and every user is interested in a few topics:
+// users mostly review resources from their topics and rate them highly, and rate everything else poorly, with some noise.
+// Reviews are spread over 180 days so time-based splits work. The same config always generates the same dataset.
+func Generate(config GeneratorConfig) Dataset {
+	rng := rand.New(rand.NewSource(config.Seed))
+	start := time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	var dataset Dataset
+	resourcesByTopic := make([][]uint, len(syntheticTopics))
+
+	for i := 1; i <= config.Resources; i++ {
+		topic := (i - 1) % len(syntheticTopics)
+		words := syntheticTopics[topic]
+
+		title := fmt.Sprintf("%s %s %s", pick(rng, words), pick(rng, words), pick(rng, syntheticFillerWords))
+		content := make([]string, 0, 20)
+		for j := 0; j < 20; j++ {
+			if rng.Float64() < 0.7 {
+				content = append(content, pick(rng, words))
+			} else {
+				content = append(content, pick(rng, syntheticFillerWords))
+			}
+		}
+
+		dataset.Resources = append(dataset.Resources, ResourceRecord{ID: uint(i), Title: title, Content: strings.Join(content, " ")})
+		resourcesByTopic[topic] = append(resourcesByTopic[topic], uint(i))
+	}
+
+	for userID := uint(1); userID <= uint(config.Users); userID++ {
+		preferred := make(map[int]bool)
+		for len(preferred) < config.PreferredTopics && len(preferred) < len(syntheticTopics) {
+			preferred[rng.Intn(len(syntheticTopics))] = true
+		}
+
+		reviewCount := config.ReviewsPerUser/2 + rng.Intn(config.ReviewsPerUser+1)
+		reviewed := make(map[uint]bool)
+		for j := 0; j < reviewCount; j++ {
+			// mostly browse the preferred topics
+			topic := rng.Intn(len(syntheticTopics))
+			if rng.Float64() < 0.8 && len(preferred) > 0 {
+				topic = pickTopic(rng, preferred)
+			}
+			if len(resourcesByTopic[topic]) == 0 {
+				continue
+			}
+			resourceID := resourcesByTopic[topic][rng.Intn(len(resourcesByTopic[topic]))]
+			if reviewed[resourceID] {
+				continue
+			}
+			reviewed[resourceID] = true
+
+			likes := preferred[topic]
+			if rng.Float64() < config.Noise {
+				likes = !likes
+			}
+			rating := models.Rating(1 + rng.Intn(3))
+			if likes {
+				rating = models.Rating(4 + rng.Intn(2))
+			}
+
+			dataset.Reviews = append(dataset.Reviews, ReviewRecord{
+				UserID:     userID,
+				ResourceID: resourceID,
+				Rating:     rating,
+				CreatedAt:  start.Add(time.Duration(rng.Int63n(int64(180 * 24 * time.Hour)))),
+			})
+		}
+
+		for _, topic := range sortedTopics(preferred) {
+			for _, resourceID := range resourcesByTopic[topic] {
+				if !reviewed[resourceID] && rng.Float64() < config.BookmarkRate {
+					dataset.Bookmarks = append(dataset.Bookmarks, BookmarkRecord{UserID: userID, ResourceID: resourceID})
+				}
+			}
+		}
+	}
+
+	return dataset
+}
+
+func pick(rng *rand.Rand, words []string) string {
+	return words[rng.Intn(len(words))]
+}
+
+// pickTopic picks one of the topics at random.
+func pickTopic(rng *rand.Rand, topics map[int]bool) int {
+	ordered := sortedTopics(topics)
+	return ordered[rng.Intn(len(ordered))]
+}
+
+// sortedTopics lists the topics in ascending order, since ranging over the map would make the dataset differ between runs.
+func sortedTopics(topics map[int]bool) []int {
+	ordered := make([]int, 0, len(topics))
+	for topic := 0; topic < len(syntheticTopics); topic++ {
+		if topics[topic] {
+			ordered = append(ordered, topic)
+		}
+	}
+	return ordered
+}
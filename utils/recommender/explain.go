@@ -0,0 +1,105 @@
+package recommender
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/anirudhgray/mood-harbour-backend/models"
+)
+
+// maxEvidence is how many similarities, resources or words are kept as evidence for a recommendation.
+const maxEvidence = 3
+
+var moodNames = map[models.MoodType]string{
+	models.Angry:   "angry",
+	models.Sad:     "sad",
+	models.Neutral: "neutral",
+	models.Happy:   "happy",
+	models.Excited: "excited",
+}
+
+// Explain turns the evidence for a recommendation into a sentence for the user, e.g.
+//
+//	Liked by 4 people with similar taste. Tagged for when you're feeling sad.
+func Explain(evidence models.RecommendationEvidence) string {
+	var reasons []string
+
+	switch evidence.Algorithm {
+	case UserBasedAlgorithm:
+		if evidence.SimilarUsersLiked > 0 {
+			reasons = append(reasons, fmt.Sprintf("Liked by %s with similar taste.", countOf(evidence.SimilarUsersLiked, "person", "people")))
+		} else {
+			reasons = append(reasons, fmt.Sprintf("Reviewed by %s with similar taste.", countOf(evidence.SimilarUsersDisliked, "person", "people")))
+		}
+	case ItemBasedAlgorithm:
+		if len(evidence.SimilarResources) > 0 {
+			reasons = append(reasons, fmt.Sprintf("People who liked %s you liked also liked this.", countOf(len(evidence.SimilarResources), "a resource", "resources")))
+		} else {
+			reasons = append(reasons, "Reviewed by people who reviewed the same resources as you.")
+		}
+	case ContentBasedAlgorithm:
+		if len(evidence.MatchingTerms) > 0 {
+			reasons = append(reasons, fmt.Sprintf("About %s, like resources you liked.", strings.Join(evidence.MatchingTerms, ", ")))
+		} else {
+			reasons = append(reasons, "Similar to resources you liked.")
+		}
+	case PopularAlgorithm:
+		if evidence.RecentReviews > 0 {
+			reasons = append(reasons, fmt.Sprintf("Popular in the community, rated %.1f on average and reviewed by %s in the last two weeks.", evidence.AverageRating, countOf(evidence.RecentReviews, "person", "people")))
+		} else {
+			reasons = append(reasons, fmt.Sprintf("Popular in the community, rated %.1f on average.", evidence.AverageRating))
+		}
+	case CuratedAlgorithm:
+		reasons = append(reasons, "Picked by the Mood Harbour team.")
+	case FactorizationAlgorithm:
+		if len(evidence.SimilarResources) > 0 {
+			reasons = append(reasons, fmt.Sprintf("People who liked %s you liked tend to like this too.", countOf(len(evidence.SimilarResources), "a resource", "resources")))
+		} else {
+			reasons = append(reasons, "Matches what people with similar taste like.")
+		}
+	default:
+		reasons = append(reasons, "Recommended for you.")
+	}
+
+	if mood, ok := moodNames[evidence.Mood]; ok {
+		if evidence.MoodTagged {
+			reasons = append(reasons, fmt.Sprintf("Tagged for when you're feeling %s.", mood))
+		}
+		if evidence.SimilarMoodLikes > 0 {
+			reasons = append(reasons, fmt.Sprintf("Liked by %s with similar taste while feeling like you do now.", countOf(evidence.SimilarMoodLikes, "person", "people")))
+		}
+	}
+
+	return strings.Join(reasons, " ")
+}
+
+// countOf formats a count with the singular or plural noun, using "one" for a single noun without an article.
+func countOf(n int, singular, plural string) string {
+	if n == 1 {
+		if strings.HasPrefix(singular, "a ") {
+			return singular
+		}
+		return "one " + singular
+	}
+	return fmt.Sprintf("%d %s", n, plural)
+}
+
+// topValues returns the n largest values, largest first.
+func topValues(values []float64, n int) []float64 {
+	sorted := append([]float64{}, values...)
+	sort.Sort(sort.Reverse(sort.Float64Slice(sorted)))
+	if len(sorted) > n {
+		sorted = sorted[:n]
+	}
+	return sorted
+}
+
+// topKeys returns the keys of the n largest values, largest first, breaking ties by key.
+func topKeys(values map[uint]float64, n int) []uint {
+	var keys []uint
+	for _, item := range rankScores(values, n) {
+		keys = append(keys, item.ResourceID)
+	}
+	return keys
+}
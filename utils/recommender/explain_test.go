@@ -0,0 +1,32 @@
+package recommender
+
+import (
+	"testing"
+
+	"github.com/anirudhgray/mood-harbour-backend/models"
+)
+
+func TestExplain(t *testing.T) {
+	testCases := []struct {
+		name     string
+		evidence models.RecommendationEvidence
+		expected string
+	}{
+		{"Similar users", models.RecommendationEvidence{Algorithm: UserBasedAlgorithm, SimilarUsersLiked: 1}, "Liked by one person with similar taste."},
+		{"Similar resources", models.RecommendationEvidence{Algorithm: ItemBasedAlgorithm, SimilarResources: []uint{4, 2}}, "People who liked 2 resources you liked also liked this."},
+		{"Latent taste", models.RecommendationEvidence{Algorithm: FactorizationAlgorithm, SimilarResources: []uint{7}}, "People who liked a resource you liked tend to like this too."},
+		{"Matching words", models.RecommendationEvidence{Algorithm: ContentBasedAlgorithm, MatchingTerms: []string{"sleep", "rest"}}, "About sleep, rest, like resources you liked."},
+		{
+			"Mood",
+			models.RecommendationEvidence{Algorithm: UserBasedAlgorithm, SimilarUsersLiked: 3, Mood: models.Sad, MoodTagged: true, SimilarMoodLikes: 2},
+			"Liked by 3 people with similar taste. Tagged for when you're feeling sad. Liked by 2 people with similar taste while feeling like you do now.",
+		},
+		{"Computed before evidence was stored", models.RecommendationEvidence{}, "Recommended for you."},
+	}
+
+	for _, tc := range testCases {
+		if reason := Explain(tc.evidence); reason != tc.expected {
+			t.Errorf("%s: expected %q, got: %q", tc.name, tc.expected, reason)
+		}
+	}
+}
@@ -0,0 +1,307 @@
+package recommender
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"sort"
+
+	"github.com/anirudhgray/mood-harbour-backend/models"
+	"github.com/anirudhgray/mood-harbour-backend/repository"
+	"github.com/spf13/viper"
+)
+
+// DefaultHyperparameters train a small model quickly, which is plenty for a community of a few thousand users.
+var DefaultHyperparameters = models.FactorizationHyperparameters{
+	Factors:         16,
+	Epochs:          30,
+	LearningRate:    0.05,
+	Regularization:  0.02,
+	NegativeSamples: 4,
+	Seed:            1,
+}
+
+// NewHyperparametersFromConfig reads the RECOMMENDATION_MF_* settings, falling back on DefaultHyperparameters for anything not set.
+func NewHyperparametersFromConfig() (models.FactorizationHyperparameters, error) {
+	params := DefaultHyperparameters
+
+	if viper.IsSet("RECOMMENDATION_MF_FACTORS") {
+		params.Factors = viper.GetInt("RECOMMENDATION_MF_FACTORS")
+	}
+	if viper.IsSet("RECOMMENDATION_MF_EPOCHS") {
+		params.Epochs = viper.GetInt("RECOMMENDATION_MF_EPOCHS")
+	}
+	if viper.IsSet("RECOMMENDATION_MF_LEARNING_RATE") {
+		params.LearningRate = viper.GetFloat64("RECOMMENDATION_MF_LEARNING_RATE")
+	}
+	if viper.IsSet("RECOMMENDATION_MF_REGULARIZATION") {
+		params.Regularization = viper.GetFloat64("RECOMMENDATION_MF_REGULARIZATION")
+	}
+	if viper.IsSet("RECOMMENDATION_MF_NEGATIVE_SAMPLES") {
+		params.NegativeSamples = viper.GetInt("RECOMMENDATION_MF_NEGATIVE_SAMPLES")
+	}
+	if viper.IsSet("RECOMMENDATION_MF_SEED") {
+		params.Seed = viper.GetInt64("RECOMMENDATION_MF_SEED")
+	}
+
+	if params.Factors < 1 || params.Epochs < 1 || params.LearningRate <= 0 {
+		return params, errors.New("RECOMMENDATION_MF_FACTORS, RECOMMENDATION_MF_EPOCHS and RECOMMENDATION_MF_LEARNING_RATE must be positive")
+	}
+	if params.Regularization < 0 || params.NegativeSamples < 0 {
+		return params, errors.New("RECOMMENDATION_MF_REGULARIZATION and RECOMMENDATION_MF_NEGATIVE_SAMPLES can't be negative")
+	}
+	return params, nil
+}
+
+// Factor is a user's or resource's bias and latent vector.
+type Factor struct {
+	Bias   float64
+	Vector []float64
+}
+
+// FactorModel is a matrix factorization model of users' preferences. The predicted preference of user U for resource B is
+//
+//	r(U, B) = b(U) + b(B) + p(U) . q(B)
+//
+// where b are the biases and p and q the latent vectors of the user and resource.
+type FactorModel struct {
+	Users        map[uint]Factor
+	Resources    map[uint]Factor
+	Interactions int     // Number of interactions it was trained on
+	RMSE         float64 // Root mean squared error on those interactions
+}
+
+// NewFactorModel builds a model from its stored factors.
+func NewFactorModel(userFactors []models.UserFactor, resourceFactors []models.ResourceFactor) *FactorModel {
+	m := &FactorModel{
+		Users:     make(map[uint]Factor, len(userFactors)),
+		Resources: make(map[uint]Factor, len(resourceFactors)),
+	}
+	for _, f := range userFactors {
+		m.Users[f.UserID] = Factor{f.Bias, f.Vector}
+	}
+	for _, f := range resourceFactors {
+		m.Resources[f.ResourceID] = Factor{f.Bias, f.Vector}
+	}
+	return m
+}
+
+// Rows converts the model to factors to be stored under the given model ID, ordered by user and resource ID.
+func (m *FactorModel) Rows(modelID uint) ([]models.UserFactor, []models.ResourceFactor) {
+	userFactors := make([]models.UserFactor, 0, len(m.Users))
+	for userID, f := range m.Users {
+		userFactors = append(userFactors, models.UserFactor{ModelID: modelID, UserID: userID, Bias: f.Bias, Vector: f.Vector})
+	}
+	sort.Slice(userFactors, func(i, j int) bool { return userFactors[i].UserID < userFactors[j].UserID })
+
+	resourceFactors := make([]models.ResourceFactor, 0, len(m.Resources))
+	for resourceID, f := range m.Resources {
+		resourceFactors = append(resourceFactors, models.ResourceFactor{ModelID: modelID, ResourceID: resourceID, Bias: f.Bias, Vector: f.Vector})
+	}
+	sort.Slice(resourceFactors, func(i, j int) bool { return resourceFactors[i].ResourceID < resourceFactors[j].ResourceID })
+
+	return userFactors, resourceFactors
+}
+
+// Predict returns the predicted preference of the user for the resource, between -1 and 1.
+// It's false if either wasn't around when the model was trained.
+func (m *FactorModel) Predict(userID, resourceID uint) (float64, bool) {
+	user, ok := m.Users[userID]
+	if !ok {
+		return 0, false
+	}
+	resource, ok := m.Resources[resourceID]
+	if !ok {
+		return 0, false
+	}
+	return math.Max(-1, math.Min(1, user.Bias+resource.Bias+denseDot(user.Vector, resource.Vector))), true
+}
+
+// TrainFactorization fits a FactorModel to the preferences in the snapshot with stochastic gradient descent,
+// minimising the squared error of r(U, B) plus L2 regularization of the biases and vectors.
+//
+// Preferences are explicit: the weights of reviews, bookmarks and dismissals. Every epoch each interaction is joined by
+// NegativeSamples random resources the user hasn't interacted with, as neutral preferences, so the model learns to rank what
+// users interact with above what they don't rather than predicting every resource as liked. The same snapshot and
+// hyperparameters always train the same model.
+func TrainFactorization(snapshot *Snapshot, params models.FactorizationHyperparameters) *FactorModel {
+	rng := rand.New(rand.NewSource(params.Seed))
+
+	userIDs := snapshot.UserIDs()
+	resourceIDs := make([]uint, 0, len(snapshot.byResource))
+	for resourceID := range snapshot.byResource {
+		resourceIDs = append(resourceIDs, resourceID)
+	}
+	sortIDs(resourceIDs)
+
+	userIndex := make(map[uint]int, len(userIDs))
+	for i, userID := range userIDs {
+		userIndex[userID] = i
+	}
+	resourceIndex := make(map[uint]int, len(resourceIDs))
+	for i, resourceID := range resourceIDs {
+		resourceIndex[resourceID] = i
+	}
+
+	type sample struct {
+		user, resource int
+		weight         float64
+	}
+	var observed []sample
+	for _, userID := range userIDs {
+		preferences := snapshot.byUser[userID]
+		userResourceIDs := make([]uint, 0, len(preferences))
+		for resourceID := range preferences {
+			userResourceIDs = append(userResourceIDs, resourceID)
+		}
+		for _, resourceID := range sortIDs(userResourceIDs) {
+			observed = append(observed, sample{userIndex[userID], resourceIndex[resourceID], preferences[resourceID]})
+		}
+	}
+
+	initial := func(n int) [][]float64 {
+		vectors := make([][]float64, n)
+		for i := range vectors {
+			vectors[i] = make([]float64, params.Factors)
+			for f := range vectors[i] {
+				vectors[i][f] = rng.NormFloat64() * 0.1
+			}
+		}
+		return vectors
+	}
+	userBiases, userVectors := make([]float64, len(userIDs)), initial(len(userIDs))
+	resourceBiases, resourceVectors := make([]float64, len(resourceIDs)), initial(len(resourceIDs))
+
+	lr, reg := params.LearningRate, params.Regularization
+	for epoch := 0; epoch < params.Epochs; epoch++ {
+		samples := append([]sample{}, observed...)
+		for _, s := range observed {
+			preferences := snapshot.byUser[userIDs[s.user]]
+			for n := 0; n < params.NegativeSamples; n++ {
+				resource := rng.Intn(len(resourceIDs))
+				if _, ok := preferences[resourceIDs[resource]]; !ok {
+					samples = append(samples, sample{s.user, resource, 0})
+				}
+			}
+		}
+		rng.Shuffle(len(samples), func(i, j int) { samples[i], samples[j] = samples[j], samples[i] })
+
+		for _, s := range samples {
+			p, q := userVectors[s.user], resourceVectors[s.resource]
+			e := s.weight - (userBiases[s.user] + resourceBiases[s.resource] + denseDot(p, q))
+
+			userBiases[s.user] += lr * (e - reg*userBiases[s.user])
+			resourceBiases[s.resource] += lr * (e - reg*resourceBiases[s.resource])
+			for f := range p {
+				pf, qf := p[f], q[f]
+				p[f] += lr * (e*qf - reg*pf)
+				q[f] += lr * (e*pf - reg*qf)
+			}
+		}
+	}
+
+	m := &FactorModel{
+		Users:        make(map[uint]Factor, len(userIDs)),
+		Resources:    make(map[uint]Factor, len(resourceIDs)),
+		Interactions: len(observed),
+	}
+	for i, userID := range userIDs {
+		m.Users[userID] = Factor{userBiases[i], userVectors[i]}
+	}
+	for i, resourceID := range resourceIDs {
+		m.Resources[resourceID] = Factor{resourceBiases[i], resourceVectors[i]}
+	}
+
+	squaredError := 0.0
+	for _, s := range observed {
+		predicted, _ := m.Predict(userIDs[s.user], resourceIDs[s.resource])
+		squaredError += (s.weight - predicted) * (s.weight - predicted)
+	}
+	if len(observed) > 0 {
+		m.RMSE = math.Sqrt(squaredError / float64(len(observed)))
+	}
+
+	return m
+}
+
+// Factorized recommends resources by their predicted preference in a trained FactorModel.
+// Users and resources the model wasn't trained on get no recommendations, until it's retrained.
+type Factorized struct {
+	data  repository.ReviewDataRepositoryInterface
+	model *FactorModel
+}
+
+func NewFactorized(data repository.ReviewDataRepositoryInterface, model *FactorModel) *Factorized {
+	return &Factorized{data, model}
+}
+
+// Recommend ranks resources by r(U, B). The resources the user liked with the most similar latent vectors are kept as evidence.
+func (fz *Factorized) Recommend(userID uint, opts Options) ([]ScoredItem, error) {
+	if _, ok := fz.model.Users[userID]; !ok {
+		return nil, nil
+	}
+
+	// the user may have interacted with more resources since the model was trained
+	preferences, err := fz.data.GetUserPreferences(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	scores := make(map[uint]float64)
+	for resourceID := range fz.model.Resources {
+		if _, ok := preferences[resourceID]; ok {
+			continue
+		}
+		// a predicted dislike isn't a recommendation, however few better ones there are
+		if score, _ := fz.model.Predict(userID, resourceID); score > 0 {
+			scores[resourceID] = score
+		}
+	}
+
+	ranked := rankScores(scores, opts.Limit)
+	for i := range ranked {
+		vector := fz.model.Resources[ranked[i].ResourceID].Vector
+
+		similarLiked := make(map[uint]float64)
+		for resourceID, weight := range preferences {
+			liked, ok := fz.model.Resources[resourceID]
+			if !ok || weight <= 0 {
+				continue
+			}
+			if similarity := vectorSimilarity(vector, liked.Vector); similarity > 0 {
+				similarLiked[resourceID] = similarity
+			}
+		}
+
+		ranked[i].Evidence = models.RecommendationEvidence{
+			Algorithm:        FactorizationAlgorithm,
+			SimilarResources: topKeys(similarLiked, maxEvidence),
+		}
+	}
+	return ranked, nil
+}
+
+// denseDot is the dot product of two dense vectors.
+func denseDot(a, b []float64) float64 {
+	sum := 0.0
+	for i := range a {
+		if i < len(b) {
+			sum += a[i] * b[i]
+		}
+	}
+	return sum
+}
+
+// vectorSimilarity is the cosine similarity of two dense vectors.
+func vectorSimilarity(a, b []float64) float64 {
+	normA, normB := math.Sqrt(denseDot(a, a)), math.Sqrt(denseDot(b, b))
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return denseDot(a, b) / (normA * normB)
+}
+
+func sortIDs(ids []uint) []uint {
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
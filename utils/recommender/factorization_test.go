@@ -0,0 +1,84 @@
+package recommender
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/anirudhgray/mood-harbour-backend/models"
+	"github.com/anirudhgray/mood-harbour-backend/utils/preference"
+)
+
+func TestTrainFactorization(t *testing.T) {
+	// users 1 to 3 like breathing resources 1 to 3 and dislike sleep resource 4, users 4 to 6 the other way round.
+	// Users 1 and 4 haven't seen resources 3 and 5 yet
+	var reviews []models.Review
+	for userID := uint(1); userID <= 6; userID++ {
+		liked, disliked := []uint{1, 2, 3}, []uint{4}
+		if userID > 3 {
+			liked, disliked = []uint{4, 5, 6}, []uint{1}
+		}
+		for _, resourceID := range liked {
+			if (userID == 1 && resourceID == 3) || (userID == 4 && resourceID == 5) {
+				continue
+			}
+			reviews = append(reviews, models.Review{UserID: userID, ResourceID: resourceID, Rating: 5, Status: models.Published})
+		}
+		for _, resourceID := range disliked {
+			reviews = append(reviews, models.Review{UserID: userID, ResourceID: resourceID, Rating: 1, Status: models.Published})
+		}
+	}
+	snapshot := NewSnapshot(reviews, nil, nil, []uint{1, 2, 3, 4, 5, 6, 7}, preference.DefaultModel, time.Now())
+
+	params := DefaultHyperparameters
+	params.Epochs = 200
+	model := TrainFactorization(snapshot, params)
+
+	if len(model.Users) != 6 || len(model.Resources) != 6 || model.Interactions != len(reviews) {
+		t.Fatalf("Unexpected model size: %d users, %d resources, %d interactions", len(model.Users), len(model.Resources), model.Interactions)
+	}
+	if model.RMSE > 0.5 {
+		t.Errorf("Expected the model to fit the interactions, got RMSE: %f", model.RMSE)
+	}
+	if again := TrainFactorization(snapshot, params); !reflect.DeepEqual(again, model) {
+		t.Errorf("Expected training to be reproducible")
+	}
+
+	// a model loaded from its stored factors predicts the same
+	loaded := NewFactorModel(model.Rows(1))
+	for userID := uint(1); userID <= 6; userID++ {
+		for resourceID := uint(1); resourceID <= 6; resourceID++ {
+			expected, _ := model.Predict(userID, resourceID)
+			if predicted, _ := loaded.Predict(userID, resourceID); predicted != expected {
+				t.Errorf("Expected the loaded model to predict %f for user %d and resource %d, got: %f", expected, userID, resourceID, predicted)
+			}
+		}
+	}
+
+	scored, err := NewFactorized(snapshot, model).Recommend(1, Options{})
+	if err != nil {
+		t.Fatalf("Recommend returned an error: %v", err)
+	}
+	// resource 7 was never interacted with, so it isn't in the model, and user 1 is predicted to dislike sleep resources 5 and 6
+	if len(scored) != 1 || scored[0].ResourceID != 3 || scored[0].Score <= 0 {
+		t.Fatalf("Expected only resource 3 out of 3 unseen resources, got: %v", scored)
+	}
+	if scored[0].Evidence.Algorithm != FactorizationAlgorithm || len(scored[0].Evidence.SimilarResources) == 0 {
+		t.Errorf("Expected evidence of similar liked resources, got: %+v", scored[0].Evidence)
+	}
+
+	if scored, _ := NewFactorized(snapshot, model).Recommend(4, Options{Limit: 1}); len(scored) != 1 || scored[0].ResourceID != 5 {
+		t.Errorf("Expected resource 5 for user 4, got: %v", scored)
+	}
+
+	// users who joined after training get nothing until the model is retrained
+	if scored, _ := NewFactorized(snapshot, model).Recommend(99, Options{}); len(scored) != 0 {
+		t.Errorf("Expected no recommendations for an unknown user, got: %v", scored)
+	}
+}
+
+func TestUses(t *testing.T) {
+	if !Uses("mf+content", FactorizationAlgorithm) || Uses("user+content", FactorizationAlgorithm) || !Uses("", UserBasedAlgorithm) {
+		t.Errorf("Uses gave the wrong answer")
+	}
+}
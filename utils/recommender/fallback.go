@@ -0,0 +1,98 @@
+package recommender
+
+// FallbackMix sets the shares of popular and curated resources filling up personalised recommendations, see WithFallback.
+type FallbackMix struct {
+	Popular float64
+	Curated float64
+}
+
+// WithFallback fills up the slots the personalised recommender leaves empty with popular and curated resources, mixed as configured.
+// Users who have reviewed little, or only resources nobody else reviewed, get a full page of recommendations this way.
+func WithFallback(primary Recommender, sources Sources, mix FallbackMix) *Fallback {
+	return NewFallback(primary, []Recommender{
+		NewPopular(sources.Reviews, sources.popularity()),
+		NewCurated(sources.Reviews, sources.Curated),
+	}, []float64{mix.Popular, mix.Curated})
+}
+
+// Fallback fills the slots its primary recommender leaves empty with the recommendations of the fallback recommenders,
+// interleaved in proportion to their shares. E.g. with shares of 0.7 and 0.3 seven out of every ten fallback slots go to
+// the first fallback, and three to the second. A fallback which runs out leaves its slots to the others.
+type Fallback struct {
+	primary   Recommender
+	fallbacks []Recommender
+	shares    []float64
+}
+
+// NewFallback creates a Fallback. Fallbacks with a share of 0 or less are never used.
+func NewFallback(primary Recommender, fallbacks []Recommender, shares []float64) *Fallback {
+	f := &Fallback{primary: primary}
+	for i, fallback := range fallbacks {
+		if i < len(shares) && shares[i] > 0 {
+			f.fallbacks = append(f.fallbacks, fallback)
+			f.shares = append(f.shares, shares[i])
+		}
+	}
+	return f
+}
+
+// Recommend returns the primary recommendations, followed by the interleaved fallback recommendations.
+func (f *Fallback) Recommend(userID uint, opts Options) ([]ScoredItem, error) {
+	items, err := f.primary.Recommend(userID, opts)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Limit > 0 && len(items) >= opts.Limit {
+		return items, nil
+	}
+
+	seen := make(map[uint]bool, len(items))
+	for _, item := range items {
+		seen[item.ResourceID] = true
+	}
+
+	// ask each fallback for the full limit, some of them may already be recommended
+	queues := make([][]ScoredItem, len(f.fallbacks))
+	for i, fallback := range f.fallbacks {
+		if queues[i], err = fallback.Recommend(userID, opts); err != nil {
+			return nil, err
+		}
+	}
+
+	// smooth weighted round robin: every turn each fallback earns its share, and the richest one pays for a slot
+	credits := make([]float64, len(f.fallbacks))
+	for opts.Limit == 0 || len(items) < opts.Limit {
+		next, total := -1, 0.0
+		for i := range queues {
+			if len(queues[i]) == 0 {
+				continue
+			}
+			credits[i] += f.shares[i]
+			total += f.shares[i]
+			if next == -1 || credits[i] > credits[next] {
+				next = i
+			}
+		}
+		if next == -1 {
+			break
+		}
+		credits[next] -= total
+
+		item := queues[next][0]
+		queues[next] = queues[next][1:]
+		if !seen[item.ResourceID] {
+			seen[item.ResourceID] = true
+			items = append(items, item)
+		}
+	}
+
+	return items, nil
+}
+
+// SimilarUsers returns the similar users of the primary recommender, if it works them out.
+func (f *Fallback) SimilarUsers(userID uint) (map[uint]float64, error) {
+	if provider, ok := f.primary.(SimilarUsersProvider); ok {
+		return provider.SimilarUsers(userID)
+	}
+	return map[uint]float64{}, nil
+}
@@ -0,0 +1,118 @@
+package recommender
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/anirudhgray/mood-harbour-backend/models"
+	"github.com/anirudhgray/mood-harbour-backend/utils/preference"
+	"gorm.io/gorm"
+)
+
+func TestPopular_Recommend(t *testing.T) {
+	now := time.Date(2023, time.June, 1, 0, 0, 0, 0, time.UTC)
+	review := func(userID, resourceID uint, rating models.Rating, daysAgo int) models.Review {
+		return models.Review{
+			Model:      gorm.Model{CreatedAt: now.AddDate(0, 0, -daysAgo)},
+			UserID:     userID,
+			ResourceID: resourceID,
+			Rating:     rating,
+			Status:     models.Published,
+		}
+	}
+
+	reviews := []models.Review{
+		review(1, 1, 5, 100), // a single old 5 star review
+		review(2, 2, 4, 1),
+		review(3, 2, 4, 2),
+		review(4, 2, 5, 3),
+		review(5, 3, 1, 30), // poorly rated, and not recently enough to make up for it
+		review(6, 3, 2, 30),
+		review(1, 4, 5, 1), // unpublished resource
+	}
+	publishedIDs := []uint{1, 2, 3}
+
+	index := NewPopularityIndex(reviews, publishedIDs, now)
+
+	// mean rating of published reviews is 21 / 6 = 3.5
+	average := (popularityPriorWeight*3.5 + 13) / (popularityPriorWeight + 3)
+	expected := (1-popularityVelocityWeight)*(average-3)/2 + popularityVelocityWeight
+	if math.Abs(index.scores[2]-expected) > 1e-9 {
+		t.Errorf("Expected resource 2 to score %f, got: %f", expected, index.scores[2])
+	}
+	if _, ok := index.scores[4]; ok {
+		t.Errorf("Expected unpublished resource 4 to be left out, got: %v", index.scores)
+	}
+
+	// user 1 has already reviewed resource 1, and resource 3 has a negative score
+	scored, err := NewPopular(NewSnapshot(reviews, nil, nil, publishedIDs, preference.DefaultModel, now), index).Recommend(1, Options{})
+	if err != nil {
+		t.Fatalf("Recommend returned an error: %v", err)
+	}
+	if index.scores[3] >= 0 {
+		t.Errorf("Expected resource 3 to have a negative score, got: %f", index.scores[3])
+	}
+	if len(scored) != 1 || scored[0].ResourceID != 2 {
+		t.Fatalf("Expected only resource 2, got: %v", scored)
+	}
+	if scored[0].Evidence.Reviews != 3 || scored[0].Evidence.RecentReviews != 3 || scored[0].Evidence.AverageRating != 3.8 {
+		t.Errorf("Unexpected evidence: %+v", scored[0].Evidence)
+	}
+}
+
+func TestFallback_Recommend(t *testing.T) {
+	primary := stubRecommender{{ResourceID: 1, Score: 0.5}}
+	popular := stubRecommender{{ResourceID: 1, Score: 0.9}, {ResourceID: 2, Score: 0.8}, {ResourceID: 3, Score: 0.7}, {ResourceID: 4, Score: 0.6}}
+	curated := stubRecommender{{ResourceID: 10, Score: 1}, {ResourceID: 11, Score: 0.5}}
+
+	testCases := []struct {
+		name     string
+		shares   []float64
+		limit    int
+		expected []uint
+	}{
+		// resource 1 is already recommended, so the popular recommender's first slot is wasted
+		{"Two to one", []float64{2, 1}, 6, []uint{1, 10, 2, 3, 11, 4}},
+		{"Popular only", []float64{1, 0}, 0, []uint{1, 2, 3, 4}},
+		{"Curated runs out", []float64{1, 1}, 0, []uint{1, 10, 2, 11, 3, 4}},
+	}
+
+	for _, tc := range testCases {
+		scored, err := NewFallback(primary, []Recommender{popular, curated}, tc.shares).Recommend(1, Options{Limit: tc.limit})
+		if err != nil {
+			t.Fatalf("%s: Recommend returned an error: %v", tc.name, err)
+		}
+		var resourceIDs []uint
+		for _, item := range scored {
+			resourceIDs = append(resourceIDs, item.ResourceID)
+		}
+		if !reflect.DeepEqual(resourceIDs, tc.expected) {
+			t.Errorf("%s: expected %v, got: %v", tc.name, tc.expected, resourceIDs)
+		}
+	}
+}
+
+func TestDiversify(t *testing.T) {
+	items := []ScoredItem{{ResourceID: 1}, {ResourceID: 2}, {ResourceID: 3}, {ResourceID: 4}}
+	// 1, 2 and 3 are near-identical articles
+	similarity := func(a, b uint) float64 {
+		if a != 4 && b != 4 {
+			return 0.9
+		}
+		return 0
+	}
+
+	diversified := Diversify(items, similarity, 0.5)
+	expected := []uint{1, 4, 2, 3}
+	for i, resourceID := range expected {
+		if diversified[i].ResourceID != resourceID {
+			t.Fatalf("Expected %v, got: %v", expected, diversified)
+		}
+	}
+
+	if !reflect.DeepEqual(Diversify(items, similarity, 1), items) {
+		t.Errorf("Expected a lambda of 1 to keep the original order")
+	}
+}
@@ -0,0 +1,53 @@
+package recommender
+
+// Hybrid combines recommenders in order of preference. Everything the first recommender returns comes first, and the following
+// recommenders only fill up the remaining slots with resources not already recommended.
+// E.g. user-based collaborative filtering followed by content-based recommendations covers new resources nobody has reviewed yet,
+// and users whose resources have no other reviewers.
+type Hybrid struct {
+	recommenders []Recommender
+}
+
+func NewHybrid(recommenders ...Recommender) *Hybrid {
+	return &Hybrid{recommenders}
+}
+
+// Recommend merges the recommendations of each recommender in turn.
+func (h *Hybrid) Recommend(userID uint, opts Options) ([]ScoredItem, error) {
+	var merged []ScoredItem
+	seen := make(map[uint]bool)
+
+	for _, r := range h.recommenders {
+		if opts.Limit > 0 && len(merged) >= opts.Limit {
+			break
+		}
+
+		// ask for the full limit, some of them may already be recommended
+		scored, err := r.Recommend(userID, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, item := range scored {
+			if opts.Limit > 0 && len(merged) >= opts.Limit {
+				break
+			}
+			if !seen[item.ResourceID] {
+				seen[item.ResourceID] = true
+				merged = append(merged, item)
+			}
+		}
+	}
+
+	return merged, nil
+}
+
+// SimilarUsers returns the similar users of the first recommender which works them out, if any.
+func (h *Hybrid) SimilarUsers(userID uint) (map[uint]float64, error) {
+	for _, r := range h.recommenders {
+		if provider, ok := r.(SimilarUsersProvider); ok {
+			return provider.SimilarUsers(userID)
+		}
+	}
+	return map[uint]float64{}, nil
+}
@@ -0,0 +1,133 @@
+package recommender
+
+import (
+	"math"
+
+	"github.com/anirudhgray/mood-harbour-backend/models"
+	"github.com/anirudhgray/mood-harbour-backend/repository"
+)
+
+// ItemBased is item-based collaborative filtering.
+//
+// Each resource is a vector over users of their preference weights, and two resources are as similar as the cosine of their vectors.
+// Candidates are the resources anyone who interacted with one of the current user's resources has interacted with. Each candidate B is scored
+//
+//	P(U, B) = sum(sim(A, B) * w(U, A)) / sum(|sim(A, B)|)
+//
+// over the resources A the current user has a non-neutral preference w for. Produces a value between -1 and 1.
+type ItemBased struct {
+	data repository.ReviewDataRepositoryInterface
+}
+
+func NewItemBased(data repository.ReviewDataRepositoryInterface) *ItemBased {
+	return &ItemBased{data}
+}
+
+// Recommend ranks resources by P(U, B).
+func (ib *ItemBased) Recommend(userID uint, opts Options) ([]ScoredItem, error) {
+	seen, err := ib.data.GetUserPreferences(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	vectors := make(map[uint]map[uint]float64)
+	vector := func(resourceID uint) (map[uint]float64, error) {
+		if v, ok := vectors[resourceID]; ok {
+			return v, nil
+		}
+		v, err := ib.data.GetResourcePreferences(resourceID)
+		if err != nil {
+			return nil, err
+		}
+		vectors[resourceID] = v
+		return v, nil
+	}
+
+	// find candidates through the users who share a resource with the current user
+	candidates := make(map[uint]bool)
+	visitedUsers := map[uint]bool{userID: true}
+	for resourceID := range seen {
+		v, err := vector(resourceID)
+		if err != nil {
+			return nil, err
+		}
+		for otherUserID := range v {
+			if visitedUsers[otherUserID] {
+				continue
+			}
+			visitedUsers[otherUserID] = true
+
+			otherPreferences, err := ib.data.GetUserPreferences(otherUserID)
+			if err != nil {
+				return nil, err
+			}
+			for candidateID := range otherPreferences {
+				if _, ok := seen[candidateID]; !ok {
+					candidates[candidateID] = true
+				}
+			}
+		}
+	}
+
+	scores := make(map[uint]float64)
+	becauseOf := make(map[uint]map[uint]float64) // candidate -> liked resource -> similarity
+	for candidateID := range candidates {
+		candidateVector, err := vector(candidateID)
+		if err != nil {
+			return nil, err
+		}
+
+		numerator, denominator := 0.0, 0.0
+		similarLiked := make(map[uint]float64)
+		for resourceID, weight := range seen {
+			if weight == 0 {
+				continue
+			}
+			similarity := cosineSimilarity(vectors[resourceID], candidateVector)
+			numerator += similarity * weight
+			denominator += math.Abs(similarity)
+			if weight > 0 && similarity > 0 {
+				similarLiked[resourceID] = similarity
+			}
+		}
+
+		if denominator == 0 {
+			continue
+		}
+		scores[candidateID] = numerator / denominator
+		becauseOf[candidateID] = similarLiked
+	}
+
+	ranked := rankScores(scores, opts.Limit)
+	for i := range ranked {
+		ranked[i].Evidence = models.RecommendationEvidence{
+			Algorithm:        ItemBasedAlgorithm,
+			SimilarResources: topKeys(becauseOf[ranked[i].ResourceID], maxEvidence),
+		}
+	}
+	return ranked, nil
+}
+
+func cosineSimilarity(a, b map[uint]float64) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	if len(b) < len(a) {
+		a, b = b, a
+	}
+
+	dot := 0.0
+	for key, value := range a {
+		dot += value * b[key]
+	}
+
+	normA, normB := 0.0, 0.0
+	for _, value := range a {
+		normA += value * value
+	}
+	for _, value := range b {
+		normB += value * value
+	}
+
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
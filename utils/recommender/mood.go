@@ -0,0 +1,107 @@
+package recommender
+
+import (
+	"math"
+	"sort"
+
+	"github.com/anirudhgray/mood-harbour-backend/models"
+	"github.com/anirudhgray/mood-harbour-backend/utils/preference"
+)
+
+// MoodSimilarity of two moods. Mood types run from Angry to Excited, so neighbouring moods are half as similar as the same mood,
+// and anything further apart isn't similar at all.
+func MoodSimilarity(a, b models.MoodType) float64 {
+	switch a - b {
+	case 0:
+		return 1
+	case -1, 1:
+		return 0.5
+	default:
+		return 0
+	}
+}
+
+// MoodContext is what RerankForMood needs to know about the user and their current mood.
+type MoodContext struct {
+	Mood         models.MoodType
+	Similarities map[uint]float64 // The user's similar users and their similarity coefficients
+	Reviews      []models.Review  // Reviews by similar users with ReviewerMood set
+	Tagged       map[uint]bool    // Resources tagged for Mood
+	Preferences  preference.Model // Weighs the reviews
+	UserMeans    map[uint]float64 // The reviewers' means over all their reviews (see preference.Model.UserMeans), only needed with Normalize
+}
+
+// MoodWeights set how much the mood context counts relative to the recommendation's own score.
+type MoodWeights struct {
+	Context float64 // Weight of the mood-weighted score of similar users' reviews
+	Tag     float64 // Added to resources tagged for the mood
+}
+
+// RerankForMood re-scores recommendations for the user's current mood and sorts them best first:
+//
+//	score' = score + Context * M(U, B) + Tag * [B is tagged for the mood]
+//
+// M(U, B) is the modified Jaccard probability computed only from similar users' reviews, each weighted by how similar the reviewer's
+// mood at the time was to the user's current mood:
+//
+//	M(U, B) = sum(sim(U, V) * MoodSimilarity(mood, mood of V) * r(V, B)) / sum(|sim(U, V)| * MoodSimilarity(mood, mood of V) * |r(V, B)|)
+//
+// where r is the weight of V's rating in the preference model, e.g. 1 for 5 stars and -1 for 1 star, relative to V's mean if the
+// model normalizes ratings. Neutral reviews don't count,
+// and M is 0 when no similar user reviewed the resource in a similar mood.
+// The mood context is added to each item's evidence.
+func RerankForMood(items []ScoredItem, context MoodContext, weights MoodWeights) []ScoredItem {
+	type tally struct {
+		numerator, denominator float64
+		likes                  int
+	}
+	tallies := make(map[uint]*tally)
+
+	for _, review := range context.Reviews {
+		similarity, ok := context.Similarities[review.UserID]
+		if !ok {
+			continue
+		}
+		moodSimilarity := MoodSimilarity(context.Mood, review.ReviewerMood)
+		if moodSimilarity == 0 {
+			continue
+		}
+
+		r := context.Preferences.ReviewWeight(review.Rating, context.UserMeans[review.UserID])
+		if r == 0 {
+			continue
+		}
+
+		t := tallies[review.ResourceID]
+		if t == nil {
+			t = &tally{}
+			tallies[review.ResourceID] = t
+		}
+		t.numerator += similarity * moodSimilarity * r
+		if r > 0 && similarity > 0 {
+			t.likes++
+		}
+		t.denominator += math.Abs(similarity) * moodSimilarity * math.Abs(r)
+	}
+
+	reranked := make([]ScoredItem, 0, len(items))
+	for _, item := range items {
+		score := item.Score
+		evidence := item.Evidence
+		evidence.Mood = context.Mood
+		if t := tallies[item.ResourceID]; t != nil && t.denominator > 0 {
+			score += weights.Context * t.numerator / t.denominator
+			evidence.SimilarMoodLikes = t.likes
+		}
+		if context.Tagged[item.ResourceID] {
+			score += weights.Tag
+			evidence.MoodTagged = true
+		}
+		reranked = append(reranked, ScoredItem{ResourceID: item.ResourceID, Score: score, Evidence: evidence})
+	}
+
+	sort.SliceStable(reranked, func(i, j int) bool {
+		return reranked[i].Score > reranked[j].Score
+	})
+	return reranked
+}
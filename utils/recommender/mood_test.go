@@ -0,0 +1,37 @@
+package recommender
+
+import (
+	"testing"
+
+	"github.com/anirudhgray/mood-harbour-backend/models"
+	"github.com/anirudhgray/mood-harbour-backend/utils/preference"
+)
+
+func TestRerankForMood(t *testing.T) {
+	items := []ScoredItem{{ResourceID: 1, Score: 0.5}, {ResourceID: 2, Score: 0.4}}
+	context := MoodContext{
+		Mood:         models.Sad,
+		Similarities: map[uint]float64{2: 1},
+		Reviews: []models.Review{
+			{UserID: 2, ResourceID: 1, Rating: models.FourStar, ReviewerMood: models.Sad},
+			{UserID: 2, ResourceID: 2, Rating: models.FiveStar, ReviewerMood: models.Sad},
+		},
+		Tagged:      map[uint]bool{},
+		Preferences: preference.DefaultModel,
+	}
+	weights := MoodWeights{Context: 0.5}
+
+	// both resources were liked by a similar user in the same mood
+	reranked := RerankForMood(items, context, weights)
+	if reranked[0].ResourceID != 1 || reranked[0].Score != 1 || reranked[1].Score != 0.9 {
+		t.Errorf("Unexpected reranking: %+v", reranked)
+	}
+
+	// four stars are below the mean of a user who rates most things five stars
+	context.Preferences.Normalize = true
+	context.UserMeans = map[uint]float64{2: 0.75}
+	reranked = RerankForMood(items, context, weights)
+	if reranked[0].ResourceID != 2 || reranked[1].ResourceID != 1 || reranked[1].Score != 0 || reranked[1].Evidence.SimilarMoodLikes != 0 {
+		t.Errorf("Expected the normalized four star review to count against resource 1, got: %+v", reranked)
+	}
+}
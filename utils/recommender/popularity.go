@@ -0,0 +1,181 @@
+package recommender
+
+import (
+	"math"
+	"time"
+
+	"github.com/anirudhgray/mood-harbour-backend/models"
+	"github.com/anirudhgray/mood-harbour-backend/repository"
+)
+
+const (
+	// popularityPriorWeight is how many reviews of the average rating every resource starts with, so a single 5 star review
+	// doesn't make a resource the most popular one.
+	popularityPriorWeight = 5
+	// popularityRecentWindow is how far back reviews count towards a resource's review velocity.
+	popularityRecentWindow = 14 * 24 * time.Hour
+	// popularityVelocityWeight is how much review velocity counts relative to the average rating.
+	popularityVelocityWeight = 0.3
+)
+
+// PopularityIndex scores every reviewed resource by how well and how recently the community has rated it:
+//
+//	bayes(B) = (C * m + sum of ratings of B) / (C + n(B))
+//	velocity(B) = log(1 + recent(B)) / log(1 + max recent)
+//	score(B) = (1 - w) * (bayes(B) - 3) / 2 + w * velocity(B)
+//
+// where m is the mean of all ratings, C is popularityPriorWeight, n(B) is the number of reviews of B, recent(B) the number of them
+// written in the last popularityRecentWindow and w is popularityVelocityWeight. Scores are between -1 and 1.
+type PopularityIndex struct {
+	scores map[uint]float64
+	stats  map[uint]popularityStats
+}
+
+type popularityStats struct {
+	average float64 // Bayesian average rating
+	reviews int
+	recent  int
+}
+
+// NewPopularityIndex builds the index from published reviews, counting reviews written since now - popularityRecentWindow as recent.
+// Reviews of resources that aren't published are ignored.
+func NewPopularityIndex(reviews []models.Review, publishedResourceIDs []uint, now time.Time) *PopularityIndex {
+	published := make(map[uint]bool, len(publishedResourceIDs))
+	for _, resourceID := range publishedResourceIDs {
+		published[resourceID] = true
+	}
+
+	sums := make(map[uint]float64)
+	stats := make(map[uint]popularityStats)
+	total, count := 0.0, 0
+	for _, review := range reviews {
+		if !published[review.ResourceID] || review.Status != models.Published {
+			continue
+		}
+
+		s := stats[review.ResourceID]
+		s.reviews++
+		if now.Sub(review.CreatedAt) <= popularityRecentWindow {
+			s.recent++
+		}
+		stats[review.ResourceID] = s
+
+		sums[review.ResourceID] += float64(review.Rating)
+		total += float64(review.Rating)
+		count++
+	}
+
+	mean := 3.0
+	if count > 0 {
+		mean = total / float64(count)
+	}
+
+	maxRecent := 0
+	for _, s := range stats {
+		if s.recent > maxRecent {
+			maxRecent = s.recent
+		}
+	}
+
+	index := &PopularityIndex{scores: make(map[uint]float64, len(stats)), stats: stats}
+	for resourceID, s := range stats {
+		s.average = (popularityPriorWeight*mean + sums[resourceID]) / float64(popularityPriorWeight+s.reviews)
+		stats[resourceID] = s
+
+		velocity := 0.0
+		if maxRecent > 0 {
+			velocity = math.Log(1+float64(s.recent)) / math.Log(1+float64(maxRecent))
+		}
+		index.scores[resourceID] = (1-popularityVelocityWeight)*(s.average-3)/2 + popularityVelocityWeight*velocity
+	}
+
+	return index
+}
+
+// Popular recommends the most popular resources the user hasn't interacted with yet, the same for everyone.
+// It's a baseline, and fills up the recommendations of users the personalised recommenders know little about.
+type Popular struct {
+	data  repository.ReviewDataRepositoryInterface
+	index *PopularityIndex
+}
+
+func NewPopular(data repository.ReviewDataRepositoryInterface, index *PopularityIndex) *Popular {
+	return &Popular{data, index}
+}
+
+// Recommend ranks resources by their popularity score, leaving out those without a positive one.
+func (p *Popular) Recommend(userID uint, opts Options) ([]ScoredItem, error) {
+	seen, err := seenResources(p.data, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	scores := make(map[uint]float64)
+	for resourceID, score := range p.index.scores {
+		// resources the community rated poorly aren't worth recommending, however few resources there are
+		if !seen[resourceID] && score > 0 {
+			scores[resourceID] = score
+		}
+	}
+
+	ranked := rankScores(scores, opts.Limit)
+	for i := range ranked {
+		s := p.index.stats[ranked[i].ResourceID]
+		ranked[i].Evidence = models.RecommendationEvidence{
+			Algorithm:     PopularAlgorithm,
+			AverageRating: math.Round(s.average*10) / 10,
+			Reviews:       s.reviews,
+			RecentReviews: s.recent,
+		}
+	}
+	return ranked, nil
+}
+
+// Curated recommends the resources picked by admins which the user hasn't interacted with yet, in the order they were picked.
+type Curated struct {
+	data        repository.ReviewDataRepositoryInterface
+	resourceIDs []uint
+}
+
+// NewCurated creates a Curated recommender for the given curated resources, most important first.
+func NewCurated(data repository.ReviewDataRepositoryInterface, resourceIDs []uint) *Curated {
+	return &Curated{data, resourceIDs}
+}
+
+// Recommend returns the curated resources, scored from 1 for the first down towards 0.
+func (c *Curated) Recommend(userID uint, opts Options) ([]ScoredItem, error) {
+	seen, err := seenResources(c.data, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []ScoredItem
+	for i, resourceID := range c.resourceIDs {
+		if opts.Limit > 0 && len(items) >= opts.Limit {
+			break
+		}
+		if seen[resourceID] {
+			continue
+		}
+		items = append(items, ScoredItem{
+			ResourceID: resourceID,
+			Score:      1 - float64(i)/float64(len(c.resourceIDs)),
+			Evidence:   models.RecommendationEvidence{Algorithm: CuratedAlgorithm},
+		})
+	}
+	return items, nil
+}
+
+// seenResources gets the resources the user has interacted with, which are never recommended to them.
+func seenResources(data repository.ReviewDataRepositoryInterface, userID uint) (map[uint]bool, error) {
+	preferences, err := data.GetUserPreferences(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[uint]bool, len(preferences))
+	for resourceID := range preferences {
+		seen[resourceID] = true
+	}
+	return seen, nil
+}
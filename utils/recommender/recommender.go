@@ -6,341 +6,246 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
-	"github.com/anirudhgray/mood-harbour-backend/infra/database"
 	"github.com/anirudhgray/mood-harbour-backend/models"
+	"github.com/anirudhgray/mood-harbour-backend/repository"
 )
 
-// GetUserLikesDislikes retrieves the resource IDs that a user has liked and disliked based on their review ratings.
-// It queries the database for reviews associated with the given user ID and categorizes the resources into liked and disliked lists.
-func GetUserLikesDislikes(userID uint) (liked []uint, notLiked []uint, err error) {
-	var reviews []models.Review
-	result := database.DB.Where("user_id = ?", userID).Find(&reviews)
-	if result.Error != nil {
-		return nil, nil, result.Error
-	}
-
-	var likedResources []uint
-	var notLikedResources []uint
-
-	for _, review := range reviews {
-		if review.Rating >= 4 {
-			likedResources = append(likedResources, review.ResourceID)
-		} else {
-			notLikedResources = append(notLikedResources, review.ResourceID)
-		}
-	}
-
-	return likedResources, notLikedResources, nil
+// Recommender ranks the resources a user hasn't interacted with yet by how likely they are to like them.
+type Recommender interface {
+	// Recommend returns scored resources, best first. Scores are between -1 and 1.
+	Recommend(userID uint, opts Options) ([]ScoredItem, error)
 }
 
-// GetUsersWhoReviewedResource retrieves the user IDs of users who have reviewed a specific resource.
-// It queries the database for reviews associated with the given resource ID and returns the user IDs of users who have reviewed the resource.
-func GetUsersWhoReviewedResource(resourceID uint) (userslist []uint, err error) {
-	var reviews []models.Review
-	result := database.DB.Where("resource_id = ?", resourceID).Find(&reviews)
-	if result.Error != nil {
-		return nil, result.Error
-	}
-
-	var users []uint
-
-	for _, review := range reviews {
-		users = append(users, review.UserID)
-	}
-
-	return users, nil
+// SimilarUsersProvider is implemented by recommenders which work out user-user similarities along the way.
+// The similarities are stored with the precomputed recommendations. Asked right after recommending to the same user,
+// providers return the similarities Recommend used rather than working them out again.
+type SimilarUsersProvider interface {
+	SimilarUsers(userID uint) (map[uint]float64, error)
 }
 
-// GetUsersWithSimilarInteractions retrieves user IDs who have interacted with resources that are similar to the given user's liked and disliked resources.
-// It queries the database for users who have reviewed resources that match the liked and disliked resources of the given user.
-func GetUsersWithSimilarInteractions(likedResources []uint, dislikedResources []uint, userID uint) (userslist []uint, err error) {
-	var users []uint
-
-	for _, resourceID := range likedResources {
-		reviewedUsers, err := GetUsersWhoReviewedResource(resourceID)
-		if err != nil {
-			return nil, err
-		}
-		users = append(users, reviewedUsers...)
-	}
-
-	for _, resourceID := range dislikedResources {
-		reviewedUsers, err := GetUsersWhoReviewedResource(resourceID)
-		if err != nil {
-			return nil, err
-		}
-		users = append(users, reviewedUsers...)
-	}
-
-	// remove duplicates and remove the current user's ID
-	currentUserID := userID
-	uniqueUsers := make(map[uint]bool)
-	var resultUsers []uint
-
-	for _, userID := range users {
-		if userID != currentUserID && !uniqueUsers[userID] {
-			uniqueUsers[userID] = true
-			resultUsers = append(resultUsers, userID)
-		}
-	}
-
-	return resultUsers, nil
+// Options tweak a single call to Recommend.
+type Options struct {
+	Limit int // Maximum number of items returned, 0 for no limit
 }
 
-func formatFloat(num float64, prc int) string {
-	var (
-		zero, dot = "0", "."
-
-		str = fmt.Sprintf("%."+strconv.Itoa(prc)+"f", num)
-	)
-
-	return strings.TrimRight(strings.TrimRight(str, zero), dot)
+// ScoredItem is a recommended resource with its score, and the evidence the score is based on.
+type ScoredItem struct {
+	ResourceID uint
+	Score      float64
+	Evidence   models.RecommendationEvidence
 }
 
-// CalculateUserSimilarity of two users via modified Jaccard Coefficient.
-//
-//	S(U1, U2) = (|L1 intersec L2| + |D1 intersect D2| - |L1 intersect D2| - |L2 intersect D1|) / |L1 union L2 union D1 union D2|
-func CalculateUserSimilarity(currentUserLiked, currentUserDisliked, otherUserLiked, otherUserDisliked []uint) (similarityCoefficient float64) {
-	L1, L2, D1, D2 := currentUserLiked, otherUserLiked, currentUserDisliked, otherUserDisliked
-
-	L1IntersectL2Size := intersectionSize(currentUserLiked, otherUserLiked)
-	D1IntersectD2Size := intersectionSize(currentUserDisliked, otherUserDisliked)
-	L1IntersectD2Size := intersectionSize(currentUserLiked, otherUserDisliked)
-	L2IntersectD1Size := intersectionSize(otherUserLiked, currentUserDisliked)
-
-	numerator := float64(L1IntersectL2Size + D1IntersectD2Size - L1IntersectD2Size - L2IntersectD1Size)
-	denominator := float64(unionSize4(L1, L2, D1, D2))
-
-	if numerator == 0 {
-		return 0
-	}
-
-	similarity := numerator / denominator
+// Algorithm names accepted by New, set through RECOMMENDER_ALGORITHM.
+// Names can be joined with "+" to fall back on the next algorithms when the first returns too few results, see Hybrid.
+const (
+	UserBasedAlgorithm     = "user"
+	ItemBasedAlgorithm     = "item"
+	ContentBasedAlgorithm  = "content"
+	PopularAlgorithm       = "popular"
+	CuratedAlgorithm       = "curated"
+	FactorizationAlgorithm = "mf"
+)
 
-	res, _ := strconv.ParseFloat(formatFloat(similarity, 3), 64)
+// DefaultAlgorithm is user-based collaborative filtering, topped up with content-based recommendations.
+const DefaultAlgorithm = UserBasedAlgorithm + "+" + ContentBasedAlgorithm
 
-	return res
+// Sources is the data recommenders are built from. Content, Popularity, Curated and Factors are only needed by the
+// content-based, popular, curated and matrix factorization algorithms respectively.
+type Sources struct {
+	Reviews    repository.ReviewDataRepositoryInterface
+	Content    *ContentIndex
+	Popularity *PopularityIndex
+	Curated    []uint       // Curated resources, most important first
+	Factors    *FactorModel // The active matrix factorization model, see TrainFactorization
 }
 
-func intersectionSize(set1, set2 []uint) int {
-	count := 0
-	set2Map := make(map[uint]bool)
-	for _, item := range set2 {
-		set2Map[item] = true
+func (s Sources) content() *ContentIndex {
+	if s.Content == nil {
+		return NewContentIndex(nil)
 	}
-	for _, item := range set1 {
-		if set2Map[item] {
-			count++
-		}
-	}
-	return count
+	return s.Content
 }
 
-func unionSize4(arr1, arr2, arr3, arr4 []uint) int {
-	unionSet := make(map[uint]bool)
-
-	for _, item := range arr1 {
-		unionSet[item] = true
-	}
-	for _, item := range arr2 {
-		unionSet[item] = true
+func (s Sources) popularity() *PopularityIndex {
+	if s.Popularity == nil {
+		return NewPopularityIndex(nil, nil, time.Now())
 	}
-	for _, item := range arr3 {
-		unionSet[item] = true
-	}
-	for _, item := range arr4 {
-		unionSet[item] = true
-	}
-
-	return len(unionSet)
+	return s.Popularity
 }
 
-// CalculateSimilaritiesWithOtherUsers returns a mapping of similar users (returned by GetUsersWithSimilarInteractions) to their similarity coefficient with current user.
-func CalculateSimilaritiesWithOtherUsers(currentUserID uint, similarUsers []uint, currentUserLiked, currentUserDisliked []uint) (sims map[uint]float64, err error) {
-	userSimilarities := make(map[uint]float64)
-
-	for _, otherUserID := range similarUsers {
-		otherUserLiked, otherUserDisliked, err := GetUserLikesDislikes(otherUserID)
-		if err != nil {
-			return nil, err
-		}
-
-		similarity := CalculateUserSimilarity(currentUserLiked, currentUserDisliked, otherUserLiked, otherUserDisliked)
-		userSimilarities[otherUserID] = similarity
+func (s Sources) factors() *FactorModel {
+	if s.Factors == nil {
+		return NewFactorModel(nil, nil)
 	}
-
-	return userSimilarities, nil
+	return s.Factors
 }
 
-// GetUnreviewedResources fetches resources which the current user has not yet reviewed (will select recommendations from among these)
-func GetUnreviewedResources(currentUserID uint) ([]uint, error) {
-	userLikedResources, userDislikedResources, err := GetUserLikesDislikes(currentUserID)
-	if err != nil {
-		return nil, err
+// New creates the recommender with the given algorithm name on top of the given sources.
+func New(algorithm string, sources Sources) (Recommender, error) {
+	if algorithm == "" {
+		algorithm = DefaultAlgorithm
 	}
 
-	userReviewedResourceIDs := append(userLikedResources, userDislikedResources...)
-
-	var unreviewedResources []uint
-
-	var allResourceIDs []uint
-	result := database.DB.Model(&models.Resource{}).Pluck("id", &allResourceIDs)
-	if result.Error != nil {
-		return nil, result.Error
-	}
-
-	// Filter out the resources that the user has reviewed
-	for _, resourceID := range allResourceIDs {
-		if !contains(userReviewedResourceIDs, resourceID) {
-			unreviewedResources = append(unreviewedResources, resourceID)
+	names := strings.Split(algorithm, "+")
+	recommenders := make([]Recommender, 0, len(names))
+	for _, name := range names {
+		switch strings.TrimSpace(name) {
+		case UserBasedAlgorithm:
+			recommenders = append(recommenders, NewUserBased(sources.Reviews))
+		case ItemBasedAlgorithm:
+			recommenders = append(recommenders, NewItemBased(sources.Reviews))
+		case ContentBasedAlgorithm:
+			recommenders = append(recommenders, NewContentBased(sources.Reviews, sources.content()))
+		case PopularAlgorithm:
+			recommenders = append(recommenders, NewPopular(sources.Reviews, sources.popularity()))
+		case CuratedAlgorithm:
+			recommenders = append(recommenders, NewCurated(sources.Reviews, sources.Curated))
+		case FactorizationAlgorithm:
+			recommenders = append(recommenders, NewFactorized(sources.Reviews, sources.factors()))
+		default:
+			return nil, fmt.Errorf("unknown recommender algorithm %q", name)
 		}
 	}
 
-	return unreviewedResources, nil
+	if len(recommenders) == 1 {
+		return recommenders[0], nil
+	}
+	return NewHybrid(recommenders...), nil
 }
 
-func contains(arr []uint, item uint) bool {
-	for _, val := range arr {
-		if val == item {
+// Uses tells whether the algorithm name passed to New includes the named algorithm, e.g. whether "mf+content" uses "mf".
+func Uses(algorithm, name string) bool {
+	if algorithm == "" {
+		algorithm = DefaultAlgorithm
+	}
+	for _, n := range strings.Split(algorithm, "+") {
+		if strings.TrimSpace(n) == name {
 			return true
 		}
 	}
 	return false
 }
 
-// CalculateRecommendationProbabilities returns mapping on user's unreviewed resources to the "probability" of them liking it (i.e., order of recommendation).
-//
-// ZL = sum of similarity coefficients of other similar users who have liked a particular resource.
-//
-// ML = number of such users as above
-//
-// ZD = sum of similarity coefficients of other similar users who have disliked a particular resource.
-//
-// MD = number of such users as above
-//
-//	P(U, B) = (ZL - ZD) / (|ML| + |MD|)
-//
-// Produces a value between -1 and 1
-func CalculateRecommendationProbabilities(currentUserID uint, unreviewedResources []uint, similarUsers map[uint]float64) map[uint]float64 {
-	recommendationProbabilities := make(map[uint]float64)
-
-	for _, resourceID := range unreviewedResources {
-		ZL := 0.0
-		ZD := 0.0
-		ML := 0
-		MD := 0
-
-		likedByUsers, dislikedByUsers, err := GetLikersDislikersForResource(resourceID)
-		if err != nil {
-			continue
-		}
-
-		for _, userID := range likedByUsers {
-			if similarity, ok := similarUsers[userID]; ok {
-				ZL += similarity
-				ML++
-			}
-		}
-
-		for _, userID := range dislikedByUsers {
-			if similarity, ok := similarUsers[userID]; ok {
-				ZD += similarity
-				MD++
-			}
-		}
+// Recommendation is a recommended resource. Reason explains the recommendation in a sentence, from its Evidence.
+// Source is SourcePersonalized, SourcePopular or SourceCurated.
+type Recommendation struct {
+	Resource    models.Resource
+	Probability float64
+	Source      string
+	Reason      string
+	Evidence    models.RecommendationEvidence
+}
 
-		if ML+MD == 0 {
-			continue
-		}
+// Where a recommendation came from, see SourceOf.
+const (
+	SourcePersonalized = "personalized"
+	SourcePopular      = "popular"
+	SourceCurated      = "curated"
+)
 
-		probability := (ZL - ZD) / float64(ML+MD)
-		recommendationProbabilities[resourceID] = probability
+// SourceOf tells whether a recommendation is personalised, or one of the popular or curated resources everyone is recommended.
+func SourceOf(evidence models.RecommendationEvidence) string {
+	switch evidence.Algorithm {
+	case PopularAlgorithm:
+		return SourcePopular
+	case CuratedAlgorithm:
+		return SourceCurated
+	default:
+		return SourcePersonalized
 	}
-
-	return recommendationProbabilities
 }
 
-func GetLikersDislikersForResource(resourceID uint) ([]uint, []uint, error) {
-	var likedByUsers []uint
-	var dislikedByUsers []uint
+// RecommendationPage is a single page of a user's precomputed recommendations.
+// ComputedAt is nil if the user's recommendations haven't been computed yet. Mood is the mood they were re-ranked for, if any.
+type RecommendationPage struct {
+	Recommendations []Recommendation `json:"recommendations"`
+	Page            int              `json:"page"`
+	PerPage         int              `json:"per_page"`
+	Total           int64            `json:"total"`
+	ComputedAt      *time.Time       `json:"computed_at"`
+	Stale           bool             `json:"stale"`
+	Mood            models.MoodType  `json:"mood,omitempty"`
+}
 
-	usersWhoLiked, err := GetUsersWhoLikedResource(resourceID)
-	if err != nil {
-		return nil, nil, err
-	}
-	likedByUsers = append(likedByUsers, usersWhoLiked...)
+func formatFloat(num float64, prc int) string {
+	var (
+		zero, dot = "0", "."
 
-	// Fetch users who disliked the resource
-	usersWhoDisliked, err := GetUsersWhoDislikedResource(resourceID)
-	if err != nil {
-		return nil, nil, err
-	}
-	dislikedByUsers = append(dislikedByUsers, usersWhoDisliked...)
+		str = fmt.Sprintf("%."+strconv.Itoa(prc)+"f", num)
+	)
 
-	return likedByUsers, dislikedByUsers, nil
+	return strings.TrimRight(strings.TrimRight(str, zero), dot)
 }
 
-func GetUsersWhoLikedResource(resourceID uint) ([]uint, error) {
-	var likedByUsers []uint
+// CalculateUserSimilarity of two users via modified Jaccard Coefficient.
+//
+//	S(U1, U2) = (|L1 intersec L2| + |D1 intersect D2| - |L1 intersect D2| - |L2 intersect D1|) / |L1 union L2 union D1 union D2|
+//
+// It's CalculateWeightedUserSimilarity with every like weighing 1 and every dislike -1.
+func CalculateUserSimilarity(currentUserLiked, currentUserDisliked, otherUserLiked, otherUserDisliked []uint) (similarityCoefficient float64) {
+	return CalculateWeightedUserSimilarity(binaryPreferences(currentUserLiked, currentUserDisliked), binaryPreferences(otherUserLiked, otherUserDisliked))
+}
 
-	var reviews []models.Review
-	result := database.DB.Where("resource_id = ? AND rating >= 4", resourceID).Find(&reviews)
-	if result.Error != nil {
-		return nil, result.Error
+// CalculateWeightedUserSimilarity of two users via the modified Jaccard coefficient generalised to weighted preferences:
+//
+//	S(U1, U2) = sum(w1(B) * w2(B)) / sum(max(|w1(B)|, |w2(B)|))
+//
+// where the numerator sums over the resources both users interacted with, and the denominator over the resources either did.
+// Agreeing preferences add to the similarity and disagreeing ones take away from it, the stronger the more so. Between -1 and 1,
+// rounded to 3 decimal places.
+func CalculateWeightedUserSimilarity(current, other map[uint]float64) float64 {
+	numerator, denominator := 0.0, 0.0
+	for resourceID, weight := range current {
+		otherWeight, ok := other[resourceID]
+		if ok {
+			numerator += weight * otherWeight
+		}
+		denominator += math.Max(math.Abs(weight), math.Abs(otherWeight))
 	}
-
-	for _, review := range reviews {
-		likedByUsers = append(likedByUsers, review.UserID)
+	for resourceID, otherWeight := range other {
+		if _, ok := current[resourceID]; !ok {
+			denominator += math.Abs(otherWeight)
+		}
 	}
 
-	return likedByUsers, nil
-}
-
-func GetUsersWhoDislikedResource(resourceID uint) ([]uint, error) {
-	var dislikedByUsers []uint
-
-	var reviews []models.Review
-	result := database.DB.Where("resource_id = ? AND rating < 4", resourceID).Find(&reviews)
-	if result.Error != nil {
-		return nil, result.Error
+	if numerator == 0 || denominator == 0 {
+		return 0
 	}
 
-	for _, review := range reviews {
-		dislikedByUsers = append(dislikedByUsers, review.UserID)
-	}
+	res, _ := strconv.ParseFloat(formatFloat(numerator/denominator, 3), 64)
 
-	return dislikedByUsers, nil
+	return res
 }
 
-type Recommendation struct {
-	Resource    models.Resource
-	Probability float64
+func binaryPreferences(liked, disliked []uint) map[uint]float64 {
+	preferences := make(map[uint]float64, len(liked)+len(disliked))
+	for _, resourceID := range liked {
+		preferences[resourceID] = 1
+	}
+	for _, resourceID := range disliked {
+		preferences[resourceID] = -1
+	}
+	return preferences
 }
 
-func GetRecommendedResourcesSortedAndPaginated(recommendationProbabilities map[uint]float64, page int, perPage int) []Recommendation {
-	var recommendations []Recommendation
-
-	for resourceID, probability := range recommendationProbabilities {
-		var resource models.Resource
-		database.DB.Model(&models.Resource{}).First(&resource, resourceID)
-		recommendations = append(recommendations, Recommendation{
-			Resource:    resource,
-			Probability: probability,
-		})
+// rankScores sorts scores best first, breaking ties by resource ID, and keeps at most limit of them.
+func rankScores(scores map[uint]float64, limit int) []ScoredItem {
+	ranked := make([]ScoredItem, 0, len(scores))
+	for resourceID, score := range scores {
+		ranked = append(ranked, ScoredItem{ResourceID: resourceID, Score: score})
 	}
 
-	// Sort recommendations by probability in descending order
-	sort.Slice(recommendations, func(i, j int) bool {
-		return recommendations[i].Probability > recommendations[j].Probability
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].Score != ranked[j].Score {
+			return ranked[i].Score > ranked[j].Score
+		}
+		return ranked[i].ResourceID < ranked[j].ResourceID
 	})
 
-	// Paginate the recommendations
-	startIdx := (page - 1) * perPage
-	endIdx := int(math.Min(float64(startIdx+perPage), float64(len(recommendations))))
-
-	if startIdx < len(recommendations) {
-		return recommendations[startIdx:endIdx]
+	if limit > 0 && len(ranked) > limit {
+		ranked = ranked[:limit]
 	}
-	return []Recommendation{}
+	return ranked
 }
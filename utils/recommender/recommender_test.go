@@ -1,6 +1,7 @@
 package recommender
 
 import (
+	"math"
 	"testing"
 )
 
@@ -53,3 +54,26 @@ func TestCalculateUserSimilarity(t *testing.T) {
 		})
 	}
 }
+
+func TestCalculateWeightedUserSimilarity(t *testing.T) {
+	testCases := []struct {
+		name               string
+		current, other     map[uint]float64
+		expectedSimilarity float64
+	}{
+		{"Binary preferences match CalculateUserSimilarity", map[uint]float64{1: 1, 2: 1, 3: -1}, map[uint]float64{1: 1, 3: 1, 4: -1}, 0},
+		{"Strong agreement", map[uint]float64{1: 1, 2: -1}, map[uint]float64{1: 1, 2: -1}, 1},
+		{"Weak agreement counts less", map[uint]float64{1: 1, 2: -1}, map[uint]float64{1: 0.5, 2: -0.5}, 0.5},
+		{"Neutral interactions don't count", map[uint]float64{1: 1, 2: 0}, map[uint]float64{1: 1, 2: 0, 3: 0}, 1},
+		{"Disagreement", map[uint]float64{1: 1}, map[uint]float64{1: -0.5, 2: 1}, -0.25},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := CalculateWeightedUserSimilarity(tc.current, tc.other)
+			if math.Abs(actual-tc.expectedSimilarity) > 1e-6 {
+				t.Errorf("Expected similarity: %f, got: %f", tc.expectedSimilarity, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package recommender
+
+import (
+	"sort"
+	"time"
+
+	"github.com/anirudhgray/mood-harbour-backend/models"
+	"github.com/anirudhgray/mood-harbour-backend/utils/preference"
+)
+
+// Snapshot is an in-memory copy of every user's preferences, used to compute recommendations for many users
+// without querying the database for each similar user and each candidate resource.
+// It implements repository.ReviewDataRepositoryInterface with the same rules as repository.ReviewDataRepository.
+type Snapshot struct {
+	byUser     map[uint]map[uint]float64 // user -> resource -> weight
+	byResource map[uint]map[uint]float64 // resource -> user -> weight
+}
+
+// NewSnapshot builds a snapshot from published reviews, oldest first, bookmarks, dismissals and the IDs of all published resources,
+// weighing them with the preference model as of now. Reviews, bookmarks and dismissals of resources that aren't published are ignored.
+func NewSnapshot(reviews []models.Review, bookmarks []models.Bookmark, dismissals []models.RecommendationDismissal, publishedResourceIDs []uint, model preference.Model, now time.Time) *Snapshot {
+	published := make(map[uint]bool, len(publishedResourceIDs))
+	for _, resourceID := range publishedResourceIDs {
+		published[resourceID] = true
+	}
+
+	var publishedReviews []models.Review
+	for _, review := range reviews {
+		if published[review.ResourceID] && review.Status == models.Published {
+			publishedReviews = append(publishedReviews, review)
+		}
+	}
+	var publishedBookmarks []models.Bookmark
+	for _, bookmark := range bookmarks {
+		if published[bookmark.ResourceID] {
+			publishedBookmarks = append(publishedBookmarks, bookmark)
+		}
+	}
+	var publishedDismissals []models.RecommendationDismissal
+	for _, dismissal := range dismissals {
+		if published[dismissal.ResourceID] {
+			publishedDismissals = append(publishedDismissals, dismissal)
+		}
+	}
+
+	s := &Snapshot{
+		byUser:     make(map[uint]map[uint]float64),
+		byResource: make(map[uint]map[uint]float64),
+	}
+
+	weights := model.Weights(publishedReviews, publishedBookmarks, publishedDismissals, model.UserMeans(publishedReviews), now)
+	for pair, weight := range weights {
+		if s.byUser[pair.UserID] == nil {
+			s.byUser[pair.UserID] = make(map[uint]float64)
+		}
+		s.byUser[pair.UserID][pair.ResourceID] = weight
+
+		if s.byResource[pair.ResourceID] == nil {
+			s.byResource[pair.ResourceID] = make(map[uint]float64)
+		}
+		s.byResource[pair.ResourceID][pair.UserID] = weight
+	}
+
+	return s
+}
+
+// UserIDs returns every user who has interacted with a published resource, in ascending order.
+func (s *Snapshot) UserIDs() []uint {
+	userIDs := make([]uint, 0, len(s.byUser))
+	for userID := range s.byUser {
+		userIDs = append(userIDs, userID)
+	}
+	sort.Slice(userIDs, func(i, j int) bool { return userIDs[i] < userIDs[j] })
+	return userIDs
+}
+
+// GetUserPreferences returns the weight of every resource the user has interacted with. It never fails.
+// The map is shared, and must not be modified.
+func (s *Snapshot) GetUserPreferences(userID uint) (map[uint]float64, error) {
+	if preferences, ok := s.byUser[userID]; ok {
+		return preferences, nil
+	}
+	return map[uint]float64{}, nil
+}
+
+// GetResourcePreferences returns the weight of the resource for every user who has interacted with it. It never fails.
+// The map is shared, and must not be modified.
+func (s *Snapshot) GetResourcePreferences(resourceID uint) (map[uint]float64, error) {
+	if preferences, ok := s.byResource[resourceID]; ok {
+		return preferences, nil
+	}
+	return map[uint]float64{}, nil
+}
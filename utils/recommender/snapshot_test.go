@@ -0,0 +1,174 @@
+package recommender
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/anirudhgray/mood-harbour-backend/infra/database"
+	"github.com/anirudhgray/mood-harbour-backend/models"
+	"github.com/anirudhgray/mood-harbour-backend/repository"
+	"github.com/anirudhgray/mood-harbour-backend/utils/preference"
+	"github.com/anirudhgray/mood-harbour-backend/utils/test_utils"
+	"gorm.io/gorm"
+)
+
+// TestSnapshot_MatchesReviewDataRepository checks that the in-memory snapshot gives the same data, and so the same
+// recommendations, as reading preferences straight from the database, with and without normalization and decay.
+func TestSnapshot_MatchesReviewDataRepository(t *testing.T) {
+	db, err := test_utils.SetupTestDB()
+	if err != nil {
+		t.Fatalf("Failed to set up test database: %v", err)
+	}
+	previousDB := database.DB
+	database.DB = db
+	defer func() { database.DB = previousDB }()
+
+	resources := []models.Resource{
+		{Title: "Breathing for panic", Content: "Slow breathing calms panic attacks", Status: models.Published},
+		{Title: "Sleep hygiene", Content: "Keep a regular sleep schedule", Status: models.Published},
+		{Title: "Box breathing", Content: "Breathing in a square pattern", Status: models.Published},
+		{Title: "Journaling", Content: "Write down your thoughts before sleep", Status: models.Published},
+		{Title: "Grounding", Content: "The 5-4-3-2-1 technique for panic", Status: models.Published},
+		{Title: "Hidden breathing", Content: "Breathing", Status: models.Hidden},
+	}
+	if err := db.Create(&resources).Error; err != nil {
+		t.Fatalf("Failed to create test resources: %v", err)
+	}
+
+	reviews := []models.Review{
+		{UserID: 1, ResourceID: 1, Rating: 5, Status: models.Published},
+		{UserID: 1, ResourceID: 2, Rating: 2, Status: models.Published},
+		{Model: gorm.Model{CreatedAt: time.Now().AddDate(0, 0, -90)}, UserID: 2, ResourceID: 1, Rating: 4, Status: models.Published},
+		{UserID: 2, ResourceID: 3, Rating: 5, Status: models.Published},
+		{UserID: 2, ResourceID: 4, Rating: 1, Status: models.Published},
+		{UserID: 3, ResourceID: 2, Rating: 5, Status: models.Published},
+		{UserID: 3, ResourceID: 4, Rating: 5, Status: models.Published},
+		{UserID: 3, ResourceID: 5, Rating: 3, Status: models.Published},
+		{UserID: 4, ResourceID: 3, Rating: 5, Status: models.Hidden},
+		{UserID: 4, ResourceID: 6, Rating: 5, Status: models.Published},
+	}
+	if err := db.Create(&reviews).Error; err != nil {
+		t.Fatalf("Failed to create test reviews: %v", err)
+	}
+
+	bookmarks := []models.Bookmark{
+		{Model: gorm.Model{CreatedAt: time.Now().AddDate(0, 0, -45)}, UserID: 4, ResourceID: 1},
+		{UserID: 4, ResourceID: 5},
+		{UserID: 1, ResourceID: 1}, // already reviewed, the review wins
+	}
+	if err := db.Create(&bookmarks).Error; err != nil {
+		t.Fatalf("Failed to create test bookmarks: %v", err)
+	}
+
+	dismissals := []models.RecommendationDismissal{
+		{UserID: 1, ResourceID: 3, Reason: models.NotInterested},
+		{UserID: 3, ResourceID: 1, Reason: models.AlreadySeen},
+		{UserID: 2, ResourceID: 1, Reason: models.NotInterested}, // already reviewed, the review wins
+		{UserID: 4, ResourceID: 5, Reason: models.NotInterested}, // already bookmarked, the bookmark wins
+		{UserID: 1, ResourceID: 6, Reason: models.NotInterested}, // hidden resource
+	}
+	if err := db.Create(&dismissals).Error; err != nil {
+		t.Fatalf("Failed to create test dismissals: %v", err)
+	}
+
+	var publishedIDs []uint
+	db.Model(&models.Resource{}).Where("status = ?", models.Published).Pluck("id", &publishedIDs)
+	var publishedReviews []models.Review
+	db.Where("status = ?", models.Published).Find(&publishedReviews)
+
+	content := NewContentIndex(resources)
+
+	normalized := preference.DefaultModel
+	normalized.Normalize = true
+	normalized.NeutralBand = 0.1
+	normalized.HalfLife = 30 * 24 * time.Hour
+
+	for _, model := range []preference.Model{preference.DefaultModel, normalized} {
+		snapshot := NewSnapshot(publishedReviews, bookmarks, dismissals, publishedIDs, model, time.Now())
+		reviewData := repository.NewReviewDataRepository(model)
+
+		for _, userID := range []uint{1, 2, 3, 4} {
+			expectedPreferences, err := reviewData.GetUserPreferences(userID)
+			if err != nil {
+				t.Fatalf("GetUserPreferences returned an error: %v", err)
+			}
+			preferences, _ := snapshot.GetUserPreferences(userID)
+			if userID == 1 && model == preference.DefaultModel && !sameScores(expectedPreferences, map[uint]float64{1: 1, 2: -0.5, 3: -0.5}) {
+				t.Errorf("Expected user 1's dismissal to count as a weak dislike, got: %v", expectedPreferences)
+			}
+			if !sameScores(preferences, expectedPreferences) {
+				t.Errorf("User %d: snapshot preferences %v; expected %v", userID, preferences, expectedPreferences)
+			}
+
+			expectedSimilarities, err := NewUserBased(reviewData).SimilarUsers(userID)
+			if err != nil {
+				t.Fatalf("SimilarUsers returned an error: %v", err)
+			}
+			similarities, _ := NewUserBased(snapshot).SimilarUsers(userID)
+			if !sameScores(similarities, expectedSimilarities) {
+				t.Errorf("User %d: snapshot similarities %v; expected %v", userID, similarities, expectedSimilarities)
+			}
+
+			for _, algorithm := range []string{UserBasedAlgorithm, ItemBasedAlgorithm, ContentBasedAlgorithm, DefaultAlgorithm} {
+				fromDB, _ := New(algorithm, Sources{Reviews: reviewData, Content: content})
+				fromSnapshot, _ := New(algorithm, Sources{Reviews: snapshot, Content: content})
+
+				expected, err := fromDB.Recommend(userID, Options{})
+				if err != nil {
+					t.Fatalf("Recommend returned an error: %v", err)
+				}
+				scored, _ := fromSnapshot.Recommend(userID, Options{})
+				if !sameScores(scoreMap(scored), scoreMap(expected)) {
+					t.Errorf("User %d, %s-based: snapshot recommendations %v; expected %v", userID, algorithm, scored, expected)
+				}
+			}
+		}
+
+		for _, resourceID := range publishedIDs {
+			expectedPreferences, err := reviewData.GetResourcePreferences(resourceID)
+			if err != nil {
+				t.Fatalf("GetResourcePreferences returned an error: %v", err)
+			}
+			preferences, _ := snapshot.GetResourcePreferences(resourceID)
+			if !sameScores(preferences, expectedPreferences) {
+				t.Errorf("Resource %d: snapshot preferences %v; expected %v", resourceID, preferences, expectedPreferences)
+			}
+		}
+	}
+}
+
+func TestRankScores(t *testing.T) {
+	ranked := rankScores(map[uint]float64{1: 0.2, 2: 0.9, 3: 0.2, 4: -0.5}, 3)
+
+	expectedOrder := []uint{2, 1, 3}
+	if len(ranked) != len(expectedOrder) {
+		t.Fatalf("Expected length: %d, got: %d", len(expectedOrder), len(ranked))
+	}
+	for i, resourceID := range expectedOrder {
+		if ranked[i].ResourceID != resourceID {
+			t.Errorf("Expected resource %d at position %d, got: %d", resourceID, i, ranked[i].ResourceID)
+		}
+	}
+}
+
+func sameScores(a, b map[uint]float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for id, score := range a {
+		other, ok := b[id]
+		if !ok || math.Abs(score-other) > 1e-6 { // the database repository decays weights as of a slightly later now
+			return false
+		}
+	}
+	return true
+}
+
+func scoreMap(scored []ScoredItem) map[uint]float64 {
+	scores := make(map[uint]float64)
+	for _, item := range scored {
+		scores[item.ResourceID] = item.Score
+	}
+	return scores
+}
@@ -0,0 +1,145 @@
+package recommender
+
+import (
+	"math"
+	"sync"
+
+	"github.com/anirudhgray/mood-harbour-backend/models"
+	"github.com/anirudhgray/mood-harbour-backend/repository"
+)
+
+// UserBased is user-based collaborative filtering using the weighted modified Jaccard coefficient from CalculateWeightedUserSimilarity.
+//
+// Every user who has interacted with one of the current user's resources is a similar user. For each resource B the current user
+// hasn't interacted with:
+//
+//	P(U, B) = sum(S(U, V) * w(V, B)) / sum(|w(V, B)|)
+//
+// over the similar users V who have interacted with B, where w is their preference weight. With likes weighing 1 and dislikes -1
+// this is (ZL - ZD) / (|ML| + |MD|), ZL and ZD being the sums of similarity coefficients of similar users who liked and disliked
+// the resource and ML and MD their numbers.
+//
+// Produces a value between -1 and 1. Resources only similar users with a neutral preference have interacted with are left out.
+type UserBased struct {
+	data repository.ReviewDataRepositoryInterface
+
+	mu   sync.Mutex
+	last lastSimilarities // the similar users worked out by the latest Recommend, see SimilarUsers
+}
+
+type lastSimilarities struct {
+	userID       uint
+	similarities map[uint]float64
+}
+
+func NewUserBased(data repository.ReviewDataRepositoryInterface) *UserBased {
+	return &UserBased{data: data}
+}
+
+// SimilarUsers returns the similarity coefficient of every user who shares at least one resource with the given user.
+// Asking for the user Recommend was last called for reuses the similarities it worked out, instead of working them out again.
+func (ub *UserBased) SimilarUsers(userID uint) (map[uint]float64, error) {
+	ub.mu.Lock()
+	last := ub.last
+	if last.similarities != nil && last.userID == userID {
+		ub.last = lastSimilarities{}
+	}
+	ub.mu.Unlock()
+	if last.similarities != nil && last.userID == userID {
+		return last.similarities, nil
+	}
+
+	similarities, _, err := ub.similarUsers(userID)
+	return similarities, err
+}
+
+// Recommend ranks resources by P(U, B).
+func (ub *UserBased) Recommend(userID uint, opts Options) ([]ScoredItem, error) {
+	similarities, preferences, err := ub.similarUsers(userID)
+	if err != nil {
+		return nil, err
+	}
+	ub.mu.Lock()
+	ub.last = lastSimilarities{userID, similarities}
+	ub.mu.Unlock()
+	seen := preferences[userID]
+
+	type tally struct {
+		numerator, denominator float64
+		ML, MD                 int
+		likers                 []float64 // similarity coefficients of the similar users who liked it
+	}
+	tallies := make(map[uint]*tally)
+
+	for otherUserID, similarity := range similarities {
+		for resourceID, weight := range preferences[otherUserID] {
+			if _, ok := seen[resourceID]; ok || weight == 0 {
+				continue
+			}
+
+			t := tallies[resourceID]
+			if t == nil {
+				t = &tally{}
+				tallies[resourceID] = t
+			}
+			t.numerator += similarity * weight
+			t.denominator += math.Abs(weight)
+			if weight > 0 {
+				t.ML++
+				t.likers = append(t.likers, similarity)
+			} else {
+				t.MD++
+			}
+		}
+	}
+
+	scores := make(map[uint]float64)
+	for resourceID, t := range tallies {
+		scores[resourceID] = t.numerator / t.denominator
+	}
+
+	ranked := rankScores(scores, opts.Limit)
+	for i := range ranked {
+		t := tallies[ranked[i].ResourceID]
+		ranked[i].Evidence = models.RecommendationEvidence{
+			Algorithm:            UserBasedAlgorithm,
+			SimilarUsersLiked:    t.ML,
+			SimilarUsersDisliked: t.MD,
+			TopSimilarities:      topValues(t.likers, maxEvidence),
+		}
+	}
+	return ranked, nil
+}
+
+// similarUsers finds the similar users and their similarity coefficients, returning the preferences it loaded along the way.
+func (ub *UserBased) similarUsers(userID uint) (map[uint]float64, map[uint]map[uint]float64, error) {
+	own, err := ub.data.GetUserPreferences(userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	preferences := map[uint]map[uint]float64{userID: own}
+	similarities := make(map[uint]float64)
+
+	for resourceID := range own {
+		resourcePreferences, err := ub.data.GetResourcePreferences(resourceID)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for otherUserID := range resourcePreferences {
+			if _, ok := preferences[otherUserID]; ok {
+				continue
+			}
+
+			otherPreferences, err := ub.data.GetUserPreferences(otherUserID)
+			if err != nil {
+				return nil, nil, err
+			}
+			preferences[otherUserID] = otherPreferences
+			similarities[otherUserID] = CalculateWeightedUserSimilarity(own, otherPreferences)
+		}
+	}
+
+	return similarities, preferences, nil
+}
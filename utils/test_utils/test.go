@@ -13,6 +13,6 @@ func SetupTestDB() (*gorm.DB, error) {
 		return nil, err
 	}
 	// automigrate user and authprovider models
-	db.AutoMigrate(&models.User{}, &models.AuthProvider{}, &models.DeletionConfirmation{}, &models.VerificationEntry{}, &models.ForgotPassword{}, &models.PasswordAuth{}, &models.Mood{}, &models.MoodAttribute{}, &models.Attribute{}, &models.Resource{}, &models.Review{})
+	db.AutoMigrate(&models.User{}, &models.AuthProvider{}, &models.OneTimeToken{}, &models.PasswordAuth{}, &models.Mood{}, &models.MoodAttribute{}, &models.Attribute{}, &models.Resource{}, &models.Review{}, &models.ReviewVote{}, &models.ResourceMoodTag{}, &models.Report{}, &models.Bookmark{}, &models.Collection{}, &models.CollectionItem{}, &models.CollectionFollow{}, &models.UserSimilarity{}, &models.UserRecommendation{}, &models.RecommendationStatus{}, &models.RecommendationDismissal{}, &models.CuratedResource{}, &models.FactorizationModel{}, &models.UserFactor{}, &models.ResourceFactor{}, &models.Session{}, &models.RefreshToken{}, &models.TOTPFactor{}, &models.RecoveryCode{}, &models.MFAChallenge{}, &models.SocialLoginCode{}, &models.Passkey{}, &models.PasskeyChallenge{}, &models.AuthThrottle{})
 	return db, nil
 }
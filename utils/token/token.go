@@ -1,9 +1,14 @@
 package token
 
 import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"fmt"
-	"strconv"
+	"math/big"
 	"strings"
 	"time"
 
@@ -13,24 +18,74 @@ import (
 	"github.com/spf13/viper"
 )
 
-func GenerateToken(user models.User) (string, error) {
-
-	tokenLifespan, err := strconv.Atoi(viper.GetString("TOKEN_HOUR_LIFESPAN"))
+// AccessTokenLifespan is how long access tokens are valid for, ACCESS_TOKEN_MINUTE_LIFESPAN minutes.
+// They are short-lived, clients get new ones with a refresh token.
+func AccessTokenLifespan() time.Duration {
+	return time.Duration(viper.GetInt("ACCESS_TOKEN_MINUTE_LIFESPAN")) * time.Minute
+}
 
-	if err != nil {
-		return "", err
-	}
+// RefreshTokenLifespan is how long refresh tokens are valid for, REFRESH_TOKEN_DAY_LIFESPAN days.
+// Every refresh hands out a new refresh token, so users who keep coming back stay logged in.
+func RefreshTokenLifespan() time.Duration {
+	return time.Duration(viper.GetInt("REFRESH_TOKEN_DAY_LIFESPAN")) * 24 * time.Hour
+}
 
+// GenerateToken creates a signed access token for the user, valid for AccessTokenLifespan.
+// Its jti claim identifies the session it belongs to.
+func GenerateToken(user models.User, jti string) (string, error) {
 	claims := jwt.MapClaims{}
 	claims["authorized"] = true
 	claims["id"] = user.ID
-	claims["exp"] = time.Now().Add(time.Hour * time.Duration(tokenLifespan)).Unix()
+	claims["jti"] = jti
+	claims["exp"] = time.Now().Add(AccessTokenLifespan()).Unix()
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 
 	return token.SignedString([]byte(viper.GetString("API_SECRET"))) // secret to sign the JWT
 
 }
 
+// GenerateJTI creates a random identifier for a session, the jti claim of its access tokens.
+func GenerateJTI() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// GenerateOpaqueToken creates a random opaque token, such as a refresh token, returning it along with the hash to store.
+func GenerateOpaqueToken() (string, string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	opaqueToken := base64.RawURLEncoding.EncodeToString(b)
+	return opaqueToken, HashOpaqueToken(opaqueToken), nil
+}
+
+// HashOpaqueToken hashes an opaque token for storage and lookup. Opaque tokens are random, so a fast hash is enough.
+func HashOpaqueToken(opaqueToken string) string {
+	sum := sha256.Sum256([]byte(opaqueToken))
+	return hex.EncodeToString(sum[:])
+}
+
+// GenerateOTP creates a random numeric code with the given number of digits, for users to type in or follow in a link.
+func GenerateOTP(digits int) (string, error) {
+	n, err := rand.Int(rand.Reader, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(digits)), nil))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%0*d", digits, n), nil
+}
+
+// HashOTP hashes a code emailed to a user for storage. There are few possible codes, so it's keyed with API_SECRET rather
+// than a plain hash which could be reversed by trying them all, and bound to the email and purpose it was sent for.
+func HashOTP(otp, email, purpose string) string {
+	mac := hmac.New(sha256.New, []byte(viper.GetString("API_SECRET")))
+	mac.Write([]byte(purpose + "\x00" + email + "\x00" + otp))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
 func getTokenFromRequest(c *gin.Context) string {
 	bearerToken := c.Request.Header.Get("Authorization")
 
@@ -53,18 +108,23 @@ func GetToken(c *gin.Context) (*jwt.Token, error) {
 	return token, err
 }
 
-func ValidateToken(c *gin.Context) (uint, error) {
+// ValidateToken checks the request's access token, returning the ID of the user and its jti claim.
+func ValidateToken(c *gin.Context) (uint, string, error) {
 	token, err := GetToken(c)
 
 	if err != nil {
-		return 0, err
+		return 0, "", err
 	}
 
 	claims, ok := token.Claims.(jwt.MapClaims)
 	if ok && token.Valid {
 		id, _ := claims["id"].(float64) //get the id
-		return uint(id), nil
+		jti, _ := claims["jti"].(string)
+		if jti == "" {
+			return 0, "", errors.New("token has no session")
+		}
+		return uint(id), jti, nil
 	}
 
-	return 0, errors.New("invalid token provided")
+	return 0, "", errors.New("invalid token provided")
 }
@@ -0,0 +1,106 @@
+package totp
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfcSecret is the SHA1 test secret of RFC 6238, "12345678901234567890", base32 encoded.
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestCode_RFC6238(t *testing.T) {
+	// the RFC's 8 digit codes, truncated to their last 6 digits
+	testCases := []struct {
+		unix     int64
+		expected string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	}
+
+	for _, tc := range testCases {
+		code, err := Code(rfcSecret, Step(time.Unix(tc.unix, 0)))
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if code != tc.expected {
+			t.Errorf("At %d expected code %s, got %s", tc.unix, tc.expected, code)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+	current := Step(now)
+	previous, _ := Code(rfcSecret, current-1)
+	tooOld, _ := Code(rfcSecret, current-2)
+
+	if step, ok := Validate(rfcSecret, "005924", now); !ok || step != current {
+		t.Errorf("Expected the current code to match step %d, got %d, %v", current, step, ok)
+	}
+	if step, ok := Validate(rfcSecret, previous, now); !ok || step != current-1 {
+		t.Errorf("Expected the previous code to be accepted, got %d, %v", step, ok)
+	}
+	if _, ok := Validate(rfcSecret, " 005 924 ", now); !ok {
+		t.Error("Expected spaces to be ignored")
+	}
+	if _, ok := Validate(rfcSecret, tooOld, now); ok {
+		t.Error("Expected a code from two periods ago to be rejected")
+	}
+	if _, ok := Validate(rfcSecret, "12345", now); ok {
+		t.Error("Expected a short code to be rejected")
+	}
+}
+
+func TestGenerateSecret(t *testing.T) {
+	secret, err := GenerateSecret()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(secret) != 32 {
+		t.Errorf("Expected a 32 character secret, got %q", secret)
+	}
+	if _, err := Code(secret, 1); err != nil {
+		t.Errorf("Expected the secret to decode, got %v", err)
+	}
+}
+
+func TestURI(t *testing.T) {
+	uri, err := url.Parse(URI(rfcSecret, "Mood Harbour", "test@example.com"))
+	if err != nil {
+		t.Fatalf("Expected a valid URI, got %v", err)
+	}
+	if uri.Scheme != "otpauth" || uri.Host != "totp" || uri.Path != "/Mood Harbour:test@example.com" {
+		t.Errorf("Unexpected URI %s", uri)
+	}
+	if uri.Query().Get("secret") != rfcSecret || uri.Query().Get("issuer") != "Mood Harbour" {
+		t.Errorf("Unexpected query %s", uri.RawQuery)
+	}
+}
+
+func TestRecoveryCodes(t *testing.T) {
+	codes, err := GenerateRecoveryCodes(10)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	seen := make(map[string]bool)
+	for _, code := range codes {
+		if len(code) != 11 || code[5] != '-' {
+			t.Errorf("Unexpected recovery code format %q", code)
+		}
+		if seen[code] {
+			t.Errorf("Duplicate recovery code %q", code)
+		}
+		seen[code] = true
+	}
+
+	if HashRecoveryCode(codes[0]) != HashRecoveryCode(" "+strings.ToUpper(strings.Replace(codes[0], "-", "", 1))) {
+		t.Error("Expected the hash to ignore case, spaces and dashes")
+	}
+}
@@ -0,0 +1,129 @@
+package webauthn
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// maxCBORDepth bounds the nesting of decoded items. Attestation objects and COSE keys are only a couple of levels deep.
+const maxCBORDepth = 8
+
+var errTruncatedCBOR = errors.New("truncated CBOR")
+
+// decodeCBOR decodes the first CBOR item in data, returning it and the number of bytes it took. It supports the subset
+// authenticators use: integers, byte and text strings, arrays, maps and the simple values false, true and null, all of
+// definite length. Integers decode to int64, byte strings to []byte, text to string, arrays to []interface{} and maps
+// to map[interface{}]interface{}.
+func decodeCBOR(data []byte) (interface{}, int, error) {
+	return decodeCBORItem(data, 0)
+}
+
+func decodeCBORItem(data []byte, depth int) (interface{}, int, error) {
+	if depth > maxCBORDepth {
+		return nil, 0, errors.New("CBOR nested too deeply")
+	}
+	if len(data) == 0 {
+		return nil, 0, errTruncatedCBOR
+	}
+
+	major, info := data[0]>>5, data[0]&0x1f
+	if major == 7 {
+		switch info {
+		case 20:
+			return false, 1, nil
+		case 21:
+			return true, 1, nil
+		case 22:
+			return nil, 1, nil
+		}
+		return nil, 0, fmt.Errorf("unsupported CBOR simple value %d", info)
+	}
+
+	arg, n, err := cborArgument(data)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	switch major {
+	case 0:
+		if arg > 1<<63-1 {
+			return nil, 0, errors.New("CBOR integer out of range")
+		}
+		return int64(arg), n, nil
+	case 1:
+		if arg > 1<<63-1 {
+			return nil, 0, errors.New("CBOR integer out of range")
+		}
+		return -1 - int64(arg), n, nil
+	case 2, 3:
+		if uint64(len(data)-n) < arg {
+			return nil, 0, errTruncatedCBOR
+		}
+		end := n + int(arg)
+		if major == 2 {
+			return append([]byte(nil), data[n:end]...), end, nil
+		}
+		return string(data[n:end]), end, nil
+	case 4:
+		// every item takes at least a byte, so longer lengths can't be valid
+		if uint64(len(data)-n) < arg {
+			return nil, 0, errTruncatedCBOR
+		}
+		items := make([]interface{}, 0, arg)
+		for i := uint64(0); i < arg; i++ {
+			item, size, err := decodeCBORItem(data[n:], depth+1)
+			if err != nil {
+				return nil, 0, err
+			}
+			items = append(items, item)
+			n += size
+		}
+		return items, n, nil
+	case 5:
+		if uint64(len(data)-n) < 2*arg {
+			return nil, 0, errTruncatedCBOR
+		}
+		items := make(map[interface{}]interface{}, arg)
+		for i := uint64(0); i < arg; i++ {
+			key, size, err := decodeCBORItem(data[n:], depth+1)
+			if err != nil {
+				return nil, 0, err
+			}
+			n += size
+			switch key.(type) {
+			case int64, string:
+			default:
+				return nil, 0, errors.New("unsupported CBOR map key")
+			}
+			value, size, err := decodeCBORItem(data[n:], depth+1)
+			if err != nil {
+				return nil, 0, err
+			}
+			n += size
+			items[key] = value
+		}
+		return items, n, nil
+	}
+	return nil, 0, fmt.Errorf("unsupported CBOR major type %d", major)
+}
+
+// cborArgument reads the argument of an item's initial byte, returning it and the size of the header.
+func cborArgument(data []byte) (uint64, int, error) {
+	info := data[0] & 0x1f
+	switch {
+	case info < 24:
+		return uint64(info), 1, nil
+	case info == 24 && len(data) >= 2:
+		return uint64(data[1]), 2, nil
+	case info == 25 && len(data) >= 3:
+		return uint64(binary.BigEndian.Uint16(data[1:])), 3, nil
+	case info == 26 && len(data) >= 5:
+		return uint64(binary.BigEndian.Uint32(data[1:])), 5, nil
+	case info == 27 && len(data) >= 9:
+		return binary.BigEndian.Uint64(data[1:]), 9, nil
+	case info >= 24 && info <= 27:
+		return 0, 0, errTruncatedCBOR
+	}
+	return 0, 0, errors.New("unsupported CBOR length")
+}
@@ -0,0 +1,52 @@
+package webauthn
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestDecodeCBOR(t *testing.T) {
+	// {1: 2, 3: -7, "a": h'0102', "b": [true, null]}
+	data := []byte{0xa4, 0x01, 0x02, 0x03, 0x26, 0x61, 'a', 0x42, 0x01, 0x02, 0x61, 'b', 0x82, 0xf5, 0xf6}
+	decoded, n, err := decodeCBOR(append(data, 0xff))
+	if err != nil {
+		t.Fatalf("decodeCBOR returned an error: %v", err)
+	}
+	if n != len(data) {
+		t.Errorf("Expected %d bytes to be read, got %d", len(data), n)
+	}
+
+	m := decoded.(map[interface{}]interface{})
+	if m[int64(1)] != int64(2) || m[int64(3)] != int64(-7) {
+		t.Errorf("Unexpected integers in %v", m)
+	}
+	if !bytes.Equal(m["a"].([]byte), []byte{1, 2}) {
+		t.Errorf("Unexpected byte string %v", m["a"])
+	}
+	if array := m["b"].([]interface{}); len(array) != 2 || array[0] != true || array[1] != nil {
+		t.Errorf("Unexpected array %v", m["b"])
+	}
+}
+
+func TestDecodeCBOR_Invalid(t *testing.T) {
+	testCases := []struct {
+		name string
+		data []byte
+	}{
+		{"Empty", nil},
+		{"Truncated byte string", []byte{0x45, 0x01}},
+		{"Truncated length", []byte{0x59, 0x01}},
+		{"Huge array", []byte{0x9b, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}},
+		{"Indefinite length", []byte{0x5f, 0x41, 0x01, 0xff}},
+		{"Float", []byte{0xf9, 0x3c, 0x00}},
+		{"Too deep", bytes.Repeat([]byte{0x81}, 20)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, _, err := decodeCBOR(tc.data); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,327 @@
+package webauthn
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// Timeout is how long users have to complete a ceremony with their authenticator.
+const Timeout = 5 * time.Minute
+
+// Flags of the authenticator data.
+const (
+	flagUserPresent  = 0x01
+	flagUserVerified = 0x04
+	flagAttested     = 0x40
+)
+
+// RelyingParty is this server, as authenticators know it. Credentials are scoped to the ID, a domain, and only accepted
+// from pages on one of the origins.
+type RelyingParty struct {
+	ID      string
+	Name    string
+	Origins []string
+}
+
+// NewRelyingPartyFromConfig reads WEBAUTHN_RP_ID, WEBAUTHN_RP_NAME and WEBAUTHN_ORIGINS, a comma separated list.
+func NewRelyingPartyFromConfig() RelyingParty {
+	var origins []string
+	for _, origin := range strings.Split(viper.GetString("WEBAUTHN_ORIGINS"), ",") {
+		if origin = strings.TrimSpace(origin); origin != "" {
+			origins = append(origins, origin)
+		}
+	}
+	return RelyingParty{ID: viper.GetString("WEBAUTHN_RP_ID"), Name: viper.GetString("WEBAUTHN_RP_NAME"), Origins: origins}
+}
+
+// User is the account a credential is created for. ID is an opaque handle, returned by authenticators when logging in.
+type User struct {
+	ID          []byte
+	Name        string
+	DisplayName string
+}
+
+// Credential is a public key credential created by an authenticator.
+type Credential struct {
+	ID        []byte
+	PublicKey []byte // COSE_Key
+	SignCount uint32
+}
+
+// CredentialDescriptor identifies a credential in ceremony options.
+type CredentialDescriptor struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+}
+
+// CreationOptions are the options of a registration ceremony, in the JSON form of PublicKeyCredentialCreationOptions
+// which browsers parse with PublicKeyCredential.parseCreationOptionsFromJSON.
+type CreationOptions struct {
+	Challenge string `json:"challenge"`
+	RP        struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"rp"`
+	User struct {
+		ID          string `json:"id"`
+		Name        string `json:"name"`
+		DisplayName string `json:"displayName"`
+	} `json:"user"`
+	PubKeyCredParams       []credentialParameter  `json:"pubKeyCredParams"`
+	ExcludeCredentials     []CredentialDescriptor `json:"excludeCredentials"`
+	AuthenticatorSelection struct {
+		ResidentKey      string `json:"residentKey"`
+		UserVerification string `json:"userVerification"`
+	} `json:"authenticatorSelection"`
+	Attestation string `json:"attestation"`
+	Timeout     int64  `json:"timeout"`
+}
+
+type credentialParameter struct {
+	Type string `json:"type"`
+	Alg  int    `json:"alg"`
+}
+
+// RequestOptions are the options of a login ceremony, in the JSON form of PublicKeyCredentialRequestOptions which
+// browsers parse with PublicKeyCredential.parseRequestOptionsFromJSON. No credentials are allowed explicitly,
+// so the user picks one of the passkeys their authenticator has for the relying party.
+type RequestOptions struct {
+	Challenge        string `json:"challenge"`
+	RPID             string `json:"rpId"`
+	UserVerification string `json:"userVerification"`
+	Timeout          int64  `json:"timeout"`
+}
+
+// AttestationResponse is the JSON form of the credential created in a registration ceremony, with base64url fields.
+type AttestationResponse struct {
+	ID       string `json:"id" binding:"required"`
+	Type     string `json:"type"`
+	Response struct {
+		ClientDataJSON    string `json:"clientDataJSON" binding:"required"`
+		AttestationObject string `json:"attestationObject" binding:"required"`
+	} `json:"response"`
+}
+
+// AssertionResponse is the JSON form of the credential used in a login ceremony, with base64url fields.
+type AssertionResponse struct {
+	ID       string `json:"id" binding:"required"`
+	Type     string `json:"type"`
+	Response struct {
+		ClientDataJSON    string `json:"clientDataJSON" binding:"required"`
+		AuthenticatorData string `json:"authenticatorData" binding:"required"`
+		Signature         string `json:"signature" binding:"required"`
+		UserHandle        string `json:"userHandle"`
+	} `json:"response"`
+}
+
+type clientData struct {
+	Type      string `json:"type"`
+	Challenge string `json:"challenge"`
+	Origin    string `json:"origin"`
+}
+
+// NewChallenge creates a random challenge for a ceremony, base64url encoded.
+func NewChallenge() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return Encode(b), nil
+}
+
+// Encode encodes binary WebAuthn data as base64url, without padding.
+func Encode(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// Decode decodes base64url WebAuthn data, with or without padding.
+func Decode(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+}
+
+// ClientChallenge reads the challenge a response was made for, to look up its ceremony. The response still has to be verified.
+func ClientChallenge(clientDataJSON string) (string, error) {
+	raw, err := Decode(clientDataJSON)
+	if err != nil {
+		return "", err
+	}
+	var data clientData
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return "", err
+	}
+	return data.Challenge, nil
+}
+
+// CreationOptions builds the options to register a passkey for the user, excluding the credentials they already have.
+func (rp RelyingParty) CreationOptions(challenge string, user User, exclude [][]byte) CreationOptions {
+	var options CreationOptions
+	options.Challenge = challenge
+	options.RP.ID = rp.ID
+	options.RP.Name = rp.Name
+	options.User.ID = Encode(user.ID)
+	options.User.Name = user.Name
+	options.User.DisplayName = user.DisplayName
+	for _, alg := range SupportedAlgorithms {
+		options.PubKeyCredParams = append(options.PubKeyCredParams, credentialParameter{"public-key", alg})
+	}
+	options.ExcludeCredentials = []CredentialDescriptor{}
+	for _, id := range exclude {
+		options.ExcludeCredentials = append(options.ExcludeCredentials, CredentialDescriptor{"public-key", Encode(id)})
+	}
+	// passkeys are discoverable, so users can log in without typing their email
+	options.AuthenticatorSelection.ResidentKey = "required"
+	options.AuthenticatorSelection.UserVerification = "required"
+	options.Attestation = "none"
+	options.Timeout = Timeout.Milliseconds()
+	return options
+}
+
+// RequestOptions builds the options to log in with a passkey.
+func (rp RelyingParty) RequestOptions(challenge string) RequestOptions {
+	return RequestOptions{Challenge: challenge, RPID: rp.ID, UserVerification: "required", Timeout: Timeout.Milliseconds()}
+}
+
+// VerifyRegistration verifies the credential created for the challenge, returning it to be stored.
+// Attestation statements aren't verified, since which authenticator users pick is up to them.
+func (rp RelyingParty) VerifyRegistration(challenge string, response AttestationResponse) (Credential, error) {
+	if _, err := rp.verifyClientData(response.Response.ClientDataJSON, "webauthn.create", challenge); err != nil {
+		return Credential{}, err
+	}
+
+	attestationObject, err := Decode(response.Response.AttestationObject)
+	if err != nil {
+		return Credential{}, err
+	}
+	decoded, _, err := decodeCBOR(attestationObject)
+	if err != nil {
+		return Credential{}, err
+	}
+	attestation, ok := decoded.(map[interface{}]interface{})
+	if !ok {
+		return Credential{}, errors.New("attestation object isn't a map")
+	}
+	authData, ok := attestation["authData"].([]byte)
+	if !ok {
+		return Credential{}, errors.New("attestation object has no authenticator data")
+	}
+
+	flags, signCount, err := rp.verifyAuthenticatorData(authData)
+	if err != nil {
+		return Credential{}, err
+	}
+	if flags&flagAttested == 0 || len(authData) < 55 {
+		return Credential{}, errors.New("authenticator data has no credential")
+	}
+
+	// attested credential data: AAGUID, credential ID length and ID, then the COSE_Key
+	idLength := int(binary.BigEndian.Uint16(authData[53:55]))
+	if len(authData) < 55+idLength {
+		return Credential{}, errors.New("truncated credential ID")
+	}
+	credentialID := authData[55 : 55+idLength]
+	_, keyLength, err := decodeCBOR(authData[55+idLength:])
+	if err != nil {
+		return Credential{}, err
+	}
+	publicKey := authData[55+idLength : 55+idLength+keyLength]
+	if _, _, err := parsePublicKey(publicKey); err != nil {
+		return Credential{}, err
+	}
+
+	if rawID, err := Decode(response.ID); err != nil || !bytes.Equal(rawID, credentialID) {
+		return Credential{}, errors.New("credential ID doesn't match the authenticator data")
+	}
+
+	return Credential{
+		ID:        append([]byte(nil), credentialID...),
+		PublicKey: append([]byte(nil), publicKey...),
+		SignCount: signCount,
+	}, nil
+}
+
+// VerifyAssertion verifies a login with the credential for the challenge, returning the credential's new sign count.
+// Authenticators which count signatures must always report a higher count than last time, otherwise the credential
+// may have been cloned.
+func (rp RelyingParty) VerifyAssertion(challenge string, credential Credential, response AssertionResponse) (uint32, error) {
+	clientDataJSON, err := rp.verifyClientData(response.Response.ClientDataJSON, "webauthn.get", challenge)
+	if err != nil {
+		return 0, err
+	}
+
+	authData, err := Decode(response.Response.AuthenticatorData)
+	if err != nil {
+		return 0, err
+	}
+	_, signCount, err := rp.verifyAuthenticatorData(authData)
+	if err != nil {
+		return 0, err
+	}
+
+	signature, err := Decode(response.Response.Signature)
+	if err != nil {
+		return 0, err
+	}
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	if err := verifySignature(credential.PublicKey, append(append([]byte(nil), authData...), clientDataHash[:]...), signature); err != nil {
+		return 0, err
+	}
+
+	if (signCount != 0 || credential.SignCount != 0) && signCount <= credential.SignCount {
+		return 0, errors.New("sign count went backwards, the authenticator may have been cloned")
+	}
+	return signCount, nil
+}
+
+// verifyClientData checks the client data of a response was made for the ceremony, on one of the relying party's origins.
+func (rp RelyingParty) verifyClientData(clientDataJSON, ceremony, challenge string) ([]byte, error) {
+	raw, err := Decode(clientDataJSON)
+	if err != nil {
+		return nil, err
+	}
+	var data clientData
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return nil, err
+	}
+
+	if data.Type != ceremony {
+		return nil, errors.New("wrong ceremony type")
+	}
+	if subtle.ConstantTimeCompare([]byte(data.Challenge), []byte(challenge)) != 1 {
+		return nil, errors.New("wrong challenge")
+	}
+	for _, origin := range rp.Origins {
+		if data.Origin == origin {
+			return raw, nil
+		}
+	}
+	return nil, errors.New("origin not allowed")
+}
+
+// verifyAuthenticatorData checks the authenticator data is for this relying party and the user was verified,
+// returning its flags and sign count.
+func (rp RelyingParty) verifyAuthenticatorData(authData []byte) (byte, uint32, error) {
+	if len(authData) < 37 {
+		return 0, 0, errors.New("truncated authenticator data")
+	}
+
+	rpIDHash := sha256.Sum256([]byte(rp.ID))
+	if subtle.ConstantTimeCompare(authData[:32], rpIDHash[:]) != 1 {
+		return 0, 0, errors.New("credential is for another relying party")
+	}
+
+	flags := authData[32]
+	if flags&flagUserPresent == 0 || flags&flagUserVerified == 0 {
+		return 0, 0, errors.New("user wasn't verified by the authenticator")
+	}
+	return flags, binary.BigEndian.Uint32(authData[33:37]), nil
+}
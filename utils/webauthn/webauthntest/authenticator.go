@@ -0,0 +1,165 @@
+// Package webauthntest provides a software authenticator, to test WebAuthn ceremonies without a browser or security key.
+package webauthntest
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"sort"
+
+	"github.com/anirudhgray/mood-harbour-backend/utils/webauthn"
+)
+
+// Authenticator is a software authenticator with a single ES256 passkey, which always verifies its user.
+type Authenticator struct {
+	RPID       string
+	Origin     string
+	UserHandle []byte
+	SignCount  uint32 // Incremented before every signature, leave at 0 to act as an authenticator which doesn't count
+
+	credentialID []byte
+	key          *ecdsa.PrivateKey
+	counting     bool
+}
+
+// NewAuthenticator creates an authenticator with a new passkey, responding as if from a page on the origin.
+func NewAuthenticator(rpID, origin string) (*Authenticator, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	credentialID := make([]byte, 16)
+	if _, err := rand.Read(credentialID); err != nil {
+		return nil, err
+	}
+	return &Authenticator{RPID: rpID, Origin: origin, credentialID: credentialID, key: key, counting: true}, nil
+}
+
+// CredentialID is the ID of the authenticator's passkey.
+func (a *Authenticator) CredentialID() []byte {
+	return a.credentialID
+}
+
+// StopCounting makes the authenticator report a sign count of 0, like most passkey providers.
+func (a *Authenticator) StopCounting() {
+	a.counting = false
+	a.SignCount = 0
+}
+
+// Register creates the passkey for the user in response to a registration challenge.
+func (a *Authenticator) Register(challenge string, userHandle []byte) webauthn.AttestationResponse {
+	a.UserHandle = userHandle
+
+	x, y := make([]byte, 32), make([]byte, 32)
+	a.key.X.FillBytes(x)
+	a.key.Y.FillBytes(y)
+	coseKey := encode(map[int64]interface{}{1: int64(2), 3: int64(webauthn.AlgES256), -1: int64(1), -2: x, -3: y})
+
+	attested := make([]byte, 18, 18+len(a.credentialID)+len(coseKey))
+	binary.BigEndian.PutUint16(attested[16:], uint16(len(a.credentialID)))
+	attested = append(append(attested, a.credentialID...), coseKey...)
+
+	var response webauthn.AttestationResponse
+	response.ID = webauthn.Encode(a.credentialID)
+	response.Type = "public-key"
+	response.Response.ClientDataJSON = a.clientData("webauthn.create", challenge)
+	response.Response.AttestationObject = webauthn.Encode(encode(map[string]interface{}{
+		"fmt":      "none",
+		"attStmt":  map[string]interface{}{},
+		"authData": a.authenticatorData(0x45, attested),
+	}))
+	return response
+}
+
+// Assert signs in with the passkey in response to a login challenge.
+func (a *Authenticator) Assert(challenge string) webauthn.AssertionResponse {
+	authData := a.authenticatorData(0x05, nil)
+	clientDataJSON := a.clientData("webauthn.get", challenge)
+	raw, _ := webauthn.Decode(clientDataJSON)
+	clientDataHash := sha256.Sum256(raw)
+	digest := sha256.Sum256(append(append([]byte(nil), authData...), clientDataHash[:]...))
+	signature, err := ecdsa.SignASN1(rand.Reader, a.key, digest[:])
+	if err != nil {
+		panic(err)
+	}
+
+	var response webauthn.AssertionResponse
+	response.ID = webauthn.Encode(a.credentialID)
+	response.Type = "public-key"
+	response.Response.ClientDataJSON = clientDataJSON
+	response.Response.AuthenticatorData = webauthn.Encode(authData)
+	response.Response.Signature = webauthn.Encode(signature)
+	response.Response.UserHandle = webauthn.Encode(a.UserHandle)
+	return response
+}
+
+func (a *Authenticator) clientData(ceremony, challenge string) string {
+	raw, _ := json.Marshal(map[string]string{"type": ceremony, "challenge": challenge, "origin": a.Origin})
+	return webauthn.Encode(raw)
+}
+
+// authenticatorData builds authenticator data with the flags, counting the signature, followed by any attested credential data.
+func (a *Authenticator) authenticatorData(flags byte, attested []byte) []byte {
+	if a.counting {
+		a.SignCount++
+	}
+	rpIDHash := sha256.Sum256([]byte(a.RPID))
+	data := append(rpIDHash[:], flags, 0, 0, 0, 0)
+	binary.BigEndian.PutUint32(data[33:], a.SignCount)
+	return append(data, attested...)
+}
+
+// encode encodes the few kinds of values authenticators send as CBOR, with map keys in canonical order.
+func encode(value interface{}) []byte {
+	switch v := value.(type) {
+	case int64:
+		if v < 0 {
+			return header(1, uint64(-1-v))
+		}
+		return header(0, uint64(v))
+	case []byte:
+		return append(header(2, uint64(len(v))), v...)
+	case string:
+		return append(header(3, uint64(len(v))), v...)
+	case map[int64]interface{}:
+		keys := make([]int64, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Slice(keys, func(i, j int) bool { return string(encode(keys[i])) < string(encode(keys[j])) })
+		out := header(5, uint64(len(v)))
+		for _, k := range keys {
+			out = append(append(out, encode(k)...), encode(v[k])...)
+		}
+		return out
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Slice(keys, func(i, j int) bool { return string(encode(keys[i])) < string(encode(keys[j])) })
+		out := header(5, uint64(len(v)))
+		for _, k := range keys {
+			out = append(append(out, encode(k)...), encode(v[k])...)
+		}
+		return out
+	}
+	panic("webauthntest: can't encode value")
+}
+
+func header(major byte, arg uint64) []byte {
+	switch {
+	case arg < 24:
+		return []byte{major<<5 | byte(arg)}
+	case arg < 1<<8:
+		return []byte{major<<5 | 24, byte(arg)}
+	case arg < 1<<16:
+		return []byte{major<<5 | 25, byte(arg >> 8), byte(arg)}
+	}
+	b := []byte{major<<5 | 26, 0, 0, 0, 0}
+	binary.BigEndian.PutUint32(b[1:], uint32(arg))
+	return b
+}
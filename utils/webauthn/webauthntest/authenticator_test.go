@@ -0,0 +1,115 @@
+package webauthntest
+
+import (
+	"testing"
+
+	"github.com/anirudhgray/mood-harbour-backend/utils/webauthn"
+)
+
+var rp = webauthn.RelyingParty{ID: "example.com", Name: "Example", Origins: []string{"https://example.com"}}
+
+func register(t *testing.T, a *Authenticator) webauthn.Credential {
+	challenge, err := webauthn.NewChallenge()
+	if err != nil {
+		t.Fatalf("Failed to create challenge: %v", err)
+	}
+	credential, err := rp.VerifyRegistration(challenge, a.Register(challenge, []byte("7")))
+	if err != nil {
+		t.Fatalf("VerifyRegistration returned an error: %v", err)
+	}
+	return credential
+}
+
+func TestRegistration(t *testing.T) {
+	a, err := NewAuthenticator(rp.ID, "https://example.com")
+	if err != nil {
+		t.Fatalf("Failed to create authenticator: %v", err)
+	}
+
+	credential := register(t, a)
+	if string(credential.ID) != string(a.CredentialID()) || credential.SignCount != 1 || len(credential.PublicKey) == 0 {
+		t.Errorf("Unexpected credential %+v", credential)
+	}
+
+	t.Run("Rejects the wrong challenge", func(t *testing.T) {
+		if _, err := rp.VerifyRegistration("other", a.Register("challenge", []byte("7"))); err == nil {
+			t.Error("Expected an error for the wrong challenge")
+		}
+	})
+
+	t.Run("Rejects other origins", func(t *testing.T) {
+		evil, _ := NewAuthenticator(rp.ID, "https://evil.example")
+		if _, err := rp.VerifyRegistration("challenge", evil.Register("challenge", []byte("7"))); err == nil {
+			t.Error("Expected an error for another origin")
+		}
+	})
+
+	t.Run("Rejects other relying parties", func(t *testing.T) {
+		other, _ := NewAuthenticator("other.com", "https://example.com")
+		if _, err := rp.VerifyRegistration("challenge", other.Register("challenge", []byte("7"))); err == nil {
+			t.Error("Expected an error for another relying party")
+		}
+	})
+
+	t.Run("Rejects login responses", func(t *testing.T) {
+		assertion := a.Assert("challenge")
+		var response webauthn.AttestationResponse
+		response.ID = assertion.ID
+		response.Response.ClientDataJSON = assertion.Response.ClientDataJSON
+		response.Response.AttestationObject = assertion.Response.AuthenticatorData
+		if _, err := rp.VerifyRegistration("challenge", response); err == nil {
+			t.Error("Expected an error for a login response")
+		}
+	})
+}
+
+func TestAssertion(t *testing.T) {
+	a, err := NewAuthenticator(rp.ID, "https://example.com")
+	if err != nil {
+		t.Fatalf("Failed to create authenticator: %v", err)
+	}
+	credential := register(t, a)
+
+	signCount, err := rp.VerifyAssertion("challenge", credential, a.Assert("challenge"))
+	if err != nil || signCount != 2 {
+		t.Fatalf("VerifyAssertion = %d, %v, expected 2", signCount, err)
+	}
+	credential.SignCount = signCount
+
+	t.Run("Rejects the wrong challenge", func(t *testing.T) {
+		if _, err := rp.VerifyAssertion("other", credential, a.Assert("challenge")); err == nil {
+			t.Error("Expected an error for the wrong challenge")
+		}
+	})
+
+	t.Run("Rejects another key's signature", func(t *testing.T) {
+		other, _ := NewAuthenticator(rp.ID, "https://example.com")
+		other.SignCount = 10
+		if _, err := rp.VerifyAssertion("challenge", credential, other.Assert("challenge")); err == nil {
+			t.Error("Expected an error for a signature by another key")
+		}
+	})
+
+	t.Run("Rejects a sign count going backwards", func(t *testing.T) {
+		a.SignCount = 0
+		if _, err := rp.VerifyAssertion("challenge", credential, a.Assert("challenge")); err == nil {
+			t.Error("Expected an error for a cloned authenticator")
+		}
+	})
+
+	t.Run("Accepts authenticators which don't count", func(t *testing.T) {
+		a.StopCounting()
+		credential.SignCount = 0
+		if signCount, err := rp.VerifyAssertion("challenge", credential, a.Assert("challenge")); err != nil || signCount != 0 {
+			t.Errorf("VerifyAssertion = %d, %v, expected 0", signCount, err)
+		}
+	})
+}
+
+func TestClientChallenge(t *testing.T) {
+	a, _ := NewAuthenticator(rp.ID, "https://example.com")
+	challenge, err := webauthn.ClientChallenge(a.Assert("challenge").Response.ClientDataJSON)
+	if err != nil || challenge != "challenge" {
+		t.Errorf("ClientChallenge = %q, %v, expected challenge", challenge, err)
+	}
+}